@@ -0,0 +1,80 @@
+// Package jsonreport maps a database from a JSON report written by norman, so reports
+// can be regenerated or compared without access to the database.
+package jsonreport
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jimbot9k/norman/internal/adapters/reports"
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// urlSchemePattern matches the scheme of a URL. Single letters are Windows drive letters.
+var urlSchemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]+:`)
+
+// JSONReportAdapter rebuilds a database from a JSON report without connecting to a
+// database. "Connecting" reads and decodes the report file.
+type JSONReportAdapter struct {
+	database *dbo.Database
+}
+
+func (a *JSONReportAdapter) Name() string {
+	return "JSON Report"
+}
+
+func (a *JSONReportAdapter) Version() string {
+	return "v1"
+}
+
+func (a *JSONReportAdapter) UniqueSignature() string {
+	return a.Name() + "-" + a.Version()
+}
+
+func (a *JSONReportAdapter) IsConnectionStringCompatible(connString string) bool {
+	return reportPath(connString) != ""
+}
+
+// Connect reads and decodes the report file
+func (a *JSONReportAdapter) Connect(connString string) error {
+	content, err := os.ReadFile(reportPath(connString))
+	if err != nil {
+		return err
+	}
+
+	db, err := reports.ReadJSONReport(content)
+	if err != nil {
+		return err
+	}
+	a.database = db
+	return nil
+}
+
+func (a *JSONReportAdapter) Close() error {
+	a.database = nil
+	return nil
+}
+
+func (a *JSONReportAdapter) IsConnected() bool {
+	return a.database != nil
+}
+
+// MapDatabase returns the database decoded from the report
+func (a *JSONReportAdapter) MapDatabase() (*dbo.Database, []error) {
+	return a.database, nil
+}
+
+// reportPath returns the file path of a "file://path.json" connection string or of a
+// plain path ending in .json. It returns "" for anything else.
+func reportPath(connString string) string {
+	path, ok := strings.CutPrefix(connString, "file://")
+	if !ok && urlSchemePattern.MatchString(connString) {
+		return ""
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return ""
+	}
+	return path
+}
@@ -7,6 +7,13 @@ import (
 
 type MySqlAdapter struct {
 	db *sql.DB
+	// addr names the mapped database when the DSN selects none
+	addr   string
+	filter schemaFilter
+	// mariadb is set when the server turns out to be MariaDB rather than MySQL
+	mariadb bool
+	// version is the major, minor and patch version of the server
+	version [3]int
 }
 
 func (a *MySqlAdapter) Name() string {
@@ -31,6 +38,10 @@ func (a *MySqlAdapter) Connect(connString string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := takeSchemaFilter(cfg)
+	if err != nil {
+		return err
+	}
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
@@ -43,6 +54,8 @@ func (a *MySqlAdapter) Connect(connString string) error {
 	}
 
 	a.db = db
+	a.addr = cfg.Addr
+	a.filter = filter
 	return nil
 }
 
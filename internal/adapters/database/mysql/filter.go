@@ -0,0 +1,80 @@
+package mysql
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// systemSchemas are the server's own databases, which are never mapped
+var systemSchemas = []string{"mysql", "information_schema", "performance_schema", "sys"}
+
+// schemaFilter selects the databases to map by name. Patterns are globs as understood
+// by path.Match, such as "app_*".
+type schemaFilter struct {
+	include []string
+	exclude []string
+}
+
+// takeSchemaFilter removes the include and exclude parameters from the DSN and returns
+// them as a filter. They are norman's own, and the driver would otherwise send them to
+// the server as session variables. Without include patterns, a DSN that names a
+// database maps that database only.
+func takeSchemaFilter(cfg *mysql.Config) (schemaFilter, error) {
+	var filter schemaFilter
+	for param, patterns := range map[string]*[]string{"include": &filter.include, "exclude": &filter.exclude} {
+		value, ok := cfg.Params[param]
+		if !ok {
+			continue
+		}
+		delete(cfg.Params, param)
+
+		for pattern := range strings.SplitSeq(value, ",") {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return schemaFilter{}, fmt.Errorf("invalid %s pattern %q: %w", param, pattern, err)
+			}
+			*patterns = append(*patterns, pattern)
+		}
+	}
+	if len(filter.include) == 0 && cfg.DBName != "" {
+		filter.include = []string{escapePattern(cfg.DBName)}
+	}
+	return filter, nil
+}
+
+// escapePattern escapes the glob metacharacters of a name so that it only matches itself
+func escapePattern(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if strings.ContainsRune(`*?[\`, r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// matches reports whether a database is mapped: it must match an include pattern, if
+// any are given, and no exclude pattern
+func (f schemaFilter) matches(name string) bool {
+	if len(f.include) > 0 && !matchesAny(f.include, name) {
+		return false
+	}
+	return !matchesAny(f.exclude, name)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// Patterns were checked when the filter was built
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestTakeSchemaFilter(t *testing.T) {
+	cfg, err := mysql.ParseDSN("user:pass@tcp(localhost:3306)/?include=app_*,billing&exclude=app_tmp*&parseTime=true&sql_mode=ANSI")
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+	filter, err := takeSchemaFilter(cfg)
+	if err != nil {
+		t.Fatalf("takeSchemaFilter() error = %v", err)
+	}
+
+	if _, ok := cfg.Params["include"]; ok {
+		t.Error("include was left in the DSN parameters")
+	}
+	if _, ok := cfg.Params["exclude"]; ok {
+		t.Error("exclude was left in the DSN parameters")
+	}
+	if cfg.Params["sql_mode"] != "ANSI" {
+		t.Errorf("sql_mode = %q, want ANSI", cfg.Params["sql_mode"])
+	}
+
+	tests := map[string]bool{
+		"app_orders": true,
+		"billing":    true,
+		"app_tmp1":   false,
+		"reporting":  false,
+	}
+	for name, want := range tests {
+		if got := filter.matches(name); got != want {
+			t.Errorf("matches(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestTakeSchemaFilterWithoutParameters(t *testing.T) {
+	cfg, err := mysql.ParseDSN("user:pass@tcp(localhost:3306)/app")
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+	filter, err := takeSchemaFilter(cfg)
+	if err != nil {
+		t.Fatalf("takeSchemaFilter() error = %v", err)
+	}
+	if !filter.matches("app") || filter.matches("other") {
+		t.Error("a filter without patterns should match the database of the DSN only")
+	}
+
+	cfg, err = mysql.ParseDSN("user:pass@tcp(localhost:3306)/")
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+	filter, err = takeSchemaFilter(cfg)
+	if err != nil {
+		t.Fatalf("takeSchemaFilter() error = %v", err)
+	}
+	if !filter.matches("app") || !filter.matches("other") {
+		t.Error("a filter without patterns or database should match every database")
+	}
+}
+
+func TestTakeSchemaFilterIncludeOverridesDatabase(t *testing.T) {
+	cfg, err := mysql.ParseDSN("user:pass@tcp(localhost:3306)/app?include=app,billing")
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+	filter, err := takeSchemaFilter(cfg)
+	if err != nil {
+		t.Fatalf("takeSchemaFilter() error = %v", err)
+	}
+	if !filter.matches("app") || !filter.matches("billing") || filter.matches("other") {
+		t.Error("include patterns should replace the database of the DSN")
+	}
+}
+
+func TestEscapePattern(t *testing.T) {
+	filter := schemaFilter{include: []string{escapePattern("app[1]*")}}
+	if !filter.matches("app[1]*") || filter.matches("app1") || filter.matches("app[1]x") {
+		t.Error("an escaped name should only match itself")
+	}
+}
+
+func TestTakeSchemaFilterInvalidPattern(t *testing.T) {
+	cfg, err := mysql.ParseDSN("user:pass@tcp(localhost:3306)/?exclude=app_[")
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+	if _, err := takeSchemaFilter(cfg); err == nil {
+		t.Error("takeSchemaFilter() should reject a malformed pattern")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strings"
 
 	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
 )
@@ -12,14 +14,27 @@ func (a *MySqlAdapter) MapDatabase() (*dbo.Database, []error) {
 	var errors []error
 	ctx := context.Background()
 
-	// Get current database name
-	var dbName string
+	// Name the model after the current database, or the server when the DSN selects none
+	var dbName sql.NullString
 	err := a.db.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&dbName)
 	if err != nil {
 		return nil, []error{fmt.Errorf("failed to get database name: %w", err)}
 	}
+	name := dbName.String
+	if !dbName.Valid {
+		name = a.addr
+	}
+
+	if err := a.detectFlavour(ctx); err != nil {
+		return nil, []error{err}
+	}
 
-	db := dbo.NewDatabase(dbName, nil)
+	db := dbo.NewDatabase(name, nil)
+	if a.mariadb {
+		db.SetEngine("MariaDB")
+	} else {
+		db.SetEngine(a.Name())
+	}
 
 	// Map schemas (databases in MySQL)
 	schemas, errs := a.mapSchemas(ctx)
@@ -45,6 +60,13 @@ func (a *MySqlAdapter) MapDatabase() (*dbo.Database, []error) {
 			for _, col := range columns {
 				table.AddColumn(col)
 			}
+			if table.IsSystemVersioned() {
+				period, errs := a.mapSystemPeriod(ctx, schema.Name(), table.Name(), table)
+				errors = append(errors, errs...)
+				if period != nil {
+					table.AddPeriod(period)
+				}
+			}
 		}
 	}
 
@@ -70,10 +92,11 @@ func (a *MySqlAdapter) MapDatabase() (*dbo.Database, []error) {
 		}
 	}
 
-	// Map foreign keys
+	// Map foreign keys once every table has its columns, so that references to other
+	// databases can be linked
 	for _, schema := range db.Schemas() {
 		for _, table := range schema.Tables() {
-			fks, errs := a.mapForeignKeys(ctx, schema.Name(), table.Name(), table)
+			fks, errs := a.mapForeignKeys(ctx, db, schema.Name(), table.Name(), table)
 			errors = append(errors, errs...)
 			for _, fk := range fks {
 				table.AddForeignKey(fk)
@@ -92,6 +115,17 @@ func (a *MySqlAdapter) MapDatabase() (*dbo.Database, []error) {
 		}
 	}
 
+	// Map sequences (MariaDB 10.3+)
+	if a.mariadb {
+		for _, schema := range db.Schemas() {
+			sequences, errs := a.mapSequences(ctx, schema.Name())
+			errors = append(errors, errs...)
+			for _, seq := range sequences {
+				schema.AddSequence(seq)
+			}
+		}
+	}
+
 	// Map views
 	for _, schema := range db.Schemas() {
 		views, errs := a.mapViews(ctx, schema.Name())
@@ -130,30 +164,69 @@ func (a *MySqlAdapter) MapDatabase() (*dbo.Database, []error) {
 		}
 	}
 
+	// Map view dependencies. VIEW_TABLE_USAGE and VIEW_ROUTINE_USAGE exist from MySQL
+	// 8.0.13 and not at all on MariaDB.
+	if !a.mariadb && a.atLeast(8, 0, 13) {
+		for _, schema := range schemas {
+			dependencies, errs := a.mapViewDependencies(ctx, schema.Name())
+			errors = append(errors, errs...)
+			for _, dep := range dependencies {
+				db.AddDependency(dep)
+			}
+		}
+	}
+
+	// Map accounts, roles and their privileges
+	roles, grants, errs := a.mapRoles(ctx)
+	errors = append(errors, errs...)
+	for _, role := range roles {
+		db.AddRole(role)
+	}
+	for _, grant := range grants {
+		db.AddGrant(grant)
+	}
+
 	if len(errors) > 0 {
 		return db, errors
 	}
 	return db, nil
 }
 
+// mapSchemas maps every database on the server other than the system ones, as
+// selected by the include and exclude parameters of the DSN
 func (a *MySqlAdapter) mapSchemas(ctx context.Context) ([]*dbo.Schema, []error) {
-	// In MySQL, the current database is treated as a schema
-	var dbName string
-	err := a.db.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&dbName)
+	query := `
+		SELECT SCHEMA_NAME
+		FROM information_schema.SCHEMATA
+		ORDER BY SCHEMA_NAME`
+
+	rows, err := a.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to get current database: %w", err)}
+		return nil, []error{fmt.Errorf("failed to query schemas: %w", err)}
 	}
+	defer rows.Close()
 
-	// MySQL doesn't have schema owners like PostgreSQL, using empty string
-	schemas := []*dbo.Schema{dbo.NewSchema(dbName, "", nil)}
+	var schemas []*dbo.Schema
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return schemas, []error{fmt.Errorf("failed to scan schema: %w", err)}
+		}
+		if slices.Contains(systemSchemas, name) || !a.filter.matches(name) {
+			continue
+		}
+		// MySQL doesn't have schema owners like PostgreSQL, using empty string
+		schemas = append(schemas, dbo.NewSchema(name, "", nil))
+	}
 	return schemas, nil
 }
 
+// mapTables maps the base tables of a schema, including MariaDB system-versioned ones
 func (a *MySqlAdapter) mapTables(ctx context.Context, schemaName string) ([]*dbo.Table, []error) {
 	query := `
-		SELECT TABLE_NAME 
+		SELECT TABLE_NAME, TABLE_TYPE, ENGINE, ROW_FORMAT, TABLE_COLLATION, TABLE_COMMENT 
 		FROM information_schema.TABLES 
-		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'
+		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE IN ('BASE TABLE', 'SYSTEM VERSIONED')
 		ORDER BY TABLE_NAME`
 
 	rows, err := a.db.QueryContext(ctx, query, schemaName)
@@ -164,11 +237,21 @@ func (a *MySqlAdapter) mapTables(ctx context.Context, schemaName string) ([]*dbo
 
 	var tables []*dbo.Table
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
+		var name, tableType string
+		var engine, rowFormat, collation, comment sql.NullString
+		if err := rows.Scan(&name, &tableType, &engine, &rowFormat, &collation, &comment); err != nil {
 			return tables, []error{fmt.Errorf("failed to scan table: %w", err)}
 		}
-		tables = append(tables, dbo.NewTable(name, nil))
+		table := dbo.NewTable(name, nil)
+		table.SetSystemVersioned(tableType == "SYSTEM VERSIONED")
+		table.SetEngine(engine.String)
+		table.SetRowFormat(rowFormat.String)
+		table.SetCollation(collation.String)
+		table.SetCharset(collationCharset(collation.String))
+		if comment.Valid {
+			table.SetComment(comment.String)
+		}
+		tables = append(tables, table)
 	}
 	return tables, nil
 }
@@ -183,7 +266,12 @@ func (a *MySqlAdapter) mapColumns(ctx context.Context, schemaName, tableName str
 			ORDINAL_POSITION,
 			CHARACTER_MAXIMUM_LENGTH,
 			NUMERIC_PRECISION,
-			NUMERIC_SCALE
+			NUMERIC_SCALE,
+			CHARACTER_SET_NAME,
+			COLLATION_NAME,
+			EXTRA,
+			GENERATION_EXPRESSION,
+			COLUMN_COMMENT
 		FROM information_schema.COLUMNS 
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 		ORDER BY ORDINAL_POSITION`
@@ -197,18 +285,26 @@ func (a *MySqlAdapter) mapColumns(ctx context.Context, schemaName, tableName str
 	var columns []*dbo.Column
 	for rows.Next() {
 		var name, dataType, isNullable string
-		var columnDefault sql.NullString
+		var columnDefault, charset, collation, extra, generationExpression, comment sql.NullString
 		var ordinalPosition int
 		var charMaxLength, numericPrecision, numericScale sql.NullInt64
 
-		if err := rows.Scan(&name, &dataType, &isNullable, &columnDefault, &ordinalPosition, &charMaxLength, &numericPrecision, &numericScale); err != nil {
+		if err := rows.Scan(&name, &dataType, &isNullable, &columnDefault, &ordinalPosition, &charMaxLength, &numericPrecision, &numericScale, &charset, &collation, &extra, &generationExpression, &comment); err != nil {
 			return columns, []error{fmt.Errorf("failed to scan column: %w", err)}
 		}
 
 		col := dbo.NewColumn(name, dataType, isNullable == "YES")
 		col.SetOrdinalPosition(ordinalPosition)
 		if columnDefault.Valid {
-			col.SetDefaultValue(columnDefault.String)
+			if !a.mariadb {
+				def := columnDefault.String
+				if hasExtra(extra.String, "DEFAULT_GENERATED") {
+					def = a.expressionDefault(def)
+				}
+				col.SetDefaultValue(def)
+			} else if def, ok := mariaDBDefault(columnDefault.String); ok {
+				col.SetDefaultValue(def)
+			}
 		}
 		if charMaxLength.Valid {
 			col.SetCharMaxLength(int(charMaxLength.Int64))
@@ -219,6 +315,15 @@ func (a *MySqlAdapter) mapColumns(ctx context.Context, schemaName, tableName str
 		if numericScale.Valid {
 			col.SetNumericScale(int(numericScale.Int64))
 		}
+		col.SetCharset(charset.String)
+		col.SetCollation(collation.String)
+		if generation := extraGeneration(extra.String); generation != "" {
+			col.SetGenerated(generation, a.expression(generationExpression.String))
+		}
+		col.SetInvisible(hasExtra(extra.String, "INVISIBLE"))
+		if comment.Valid {
+			col.SetComment(comment.String)
+		}
 		columns = append(columns, col)
 	}
 	return columns, nil
@@ -259,16 +364,27 @@ func (a *MySqlAdapter) mapPrimaryKey(ctx context.Context, schemaName, tableName
 	return pk, nil
 }
 
+// mapIndexes maps the indexes of a table with their prefix lengths. Key parts of MySQL
+// 8.0.13+ functional indexes have no column but an expression. Older MySQL versions have
+// neither functional nor invisible indexes. MariaDB has no functional indexes and names
+// its invisible ones IGNORED from 10.6 only, so it maps neither.
 func (a *MySqlAdapter) mapIndexes(ctx context.Context, schemaName, tableName string, table *dbo.Table) ([]*dbo.Index, []error) {
-	query := `
+	expression, visible := "EXPRESSION", "IS_VISIBLE"
+	if a.mariadb || !a.atLeast(8, 0, 13) {
+		expression, visible = "NULL", "'YES'"
+	}
+	query := fmt.Sprintf(`
 		SELECT 
 			INDEX_NAME,
 			INDEX_TYPE,
 			NON_UNIQUE,
-			COLUMN_NAME
+			COLUMN_NAME,
+			SUB_PART,
+			%s,
+			%s
 		FROM information_schema.STATISTICS
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
-		ORDER BY INDEX_NAME, SEQ_IN_INDEX`
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX`, expression, visible)
 
 	rows, err := a.db.QueryContext(ctx, query, schemaName, tableName)
 	if err != nil {
@@ -280,9 +396,11 @@ func (a *MySqlAdapter) mapIndexes(ctx context.Context, schemaName, tableName str
 	var indexOrder []string
 
 	for rows.Next() {
-		var indexName, indexType, columnName string
+		var indexName, indexType, isVisible string
+		var columnName, keyExpression sql.NullString
 		var nonUnique int
-		if err := rows.Scan(&indexName, &indexType, &nonUnique, &columnName); err != nil {
+		var subPart sql.NullInt64
+		if err := rows.Scan(&indexName, &indexType, &nonUnique, &columnName, &subPart, &keyExpression, &isVisible); err != nil {
 			return nil, []error{fmt.Errorf("failed to scan index: %w", err)}
 		}
 
@@ -292,12 +410,23 @@ func (a *MySqlAdapter) mapIndexes(ctx context.Context, schemaName, tableName str
 			idx = dbo.NewIndex(indexName, table, nil, isUnique)
 			idx.SetPrimary(indexName == "PRIMARY")
 			idx.SetIndexType(dbo.IndexType(indexType))
+			idx.SetInvisible(isVisible == "NO")
 			indexMap[indexName] = idx
 			indexOrder = append(indexOrder, indexName)
 		}
 
-		if col, colExists := table.Columns()[columnName]; colExists {
-			idx.AddColumn(col)
+		if !columnName.Valid {
+			if keyExpression.Valid {
+				idx.AddExpression(a.expression(keyExpression.String))
+			}
+			continue
+		}
+		if col, colExists := table.Columns()[columnName.String]; colExists {
+			if subPart.Valid {
+				idx.AddPrefixColumn(col, int(subPart.Int64))
+			} else {
+				idx.AddColumn(col)
+			}
 		}
 	}
 
@@ -308,7 +437,10 @@ func (a *MySqlAdapter) mapIndexes(ctx context.Context, schemaName, tableName str
 	return indexes, nil
 }
 
-func (a *MySqlAdapter) mapForeignKeys(ctx context.Context, schemaName, tableName string, table *dbo.Table) ([]*dbo.ForeignKey, []error) {
+// mapForeignKeys maps the foreign keys of a table. Referenced columns are those of the
+// mapped database, which may be another one than the table's; references to databases
+// that are not mapped keep a placeholder column.
+func (a *MySqlAdapter) mapForeignKeys(ctx context.Context, db *dbo.Database, schemaName, tableName string, table *dbo.Table) ([]*dbo.ForeignKey, []error) {
 	query := `
 		SELECT 
 			kcu.CONSTRAINT_NAME,
@@ -356,6 +488,13 @@ func (a *MySqlAdapter) mapForeignKeys(ctx context.Context, schemaName, tableName
 			fk.AddColumn(col)
 		}
 		refCol := dbo.NewColumn(refColumn, "", false)
+		if refSchemaObj, ok := db.Schemas()[refSchema]; ok {
+			if refTableObj, ok := refSchemaObj.Tables()[refTable]; ok {
+				if col, ok := refTableObj.Columns()[refColumn]; ok {
+					refCol = col
+				}
+			}
+		}
 		fk.AddReferencedColumn(refCol)
 	}
 
@@ -395,74 +534,138 @@ func (a *MySqlAdapter) mapViews(ctx context.Context, schemaName string) ([]*dbo.
 	return views, nil
 }
 
-func (a *MySqlAdapter) mapFunctions(ctx context.Context, schemaName string) ([]*dbo.Function, []error) {
-	query := `
+// routineQuery selects the routines of one type in a schema with their security
+// context. EXTERNAL_LANGUAGE names the language of MySQL 9 JavaScript routines and is
+// NULL on servers that only have SQL ones.
+const routineQuery = `
 		SELECT 
 			ROUTINE_NAME,
 			ROUTINE_DEFINITION,
-			DTD_IDENTIFIER
+			DTD_IDENTIFIER,
+			COALESCE(EXTERNAL_LANGUAGE, ROUTINE_BODY),
+			SECURITY_TYPE,
+			DEFINER,
+			IS_DETERMINISTIC,
+			SQL_DATA_ACCESS,
+			SQL_MODE
 		FROM information_schema.ROUTINES 
-		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'FUNCTION'
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = ?
 		ORDER BY ROUTINE_NAME`
 
-	rows, err := a.db.QueryContext(ctx, query, schemaName)
+// routine is what functions and procedures share
+type routine interface {
+	AddParameter(param *dbo.FunctionParameter)
+	SetLanguage(language string)
+	SetSecurityType(securityType dbo.SecurityType)
+	SetDefiner(definer string)
+	SetDeterministic(deterministic bool)
+	SetSQLDataAccess(sqlDataAccess string)
+	SetSQLMode(sqlMode string)
+}
+
+// routineRow is a row of routineQuery
+type routineRow struct {
+	name, language, securityType, definer, isDeterministic, sqlDataAccess, sqlMode string
+	definition, returnType                                                         sql.NullString
+}
+
+func (r *routineRow) scan(rows *sql.Rows) error {
+	return rows.Scan(&r.name, &r.definition, &r.returnType, &r.language, &r.securityType, &r.definer,
+		&r.isDeterministic, &r.sqlDataAccess, &r.sqlMode)
+}
+
+// apply sets the language, security context and parameters of a mapped routine
+func (r *routineRow) apply(rt routine, params map[string][]*dbo.FunctionParameter) {
+	rt.SetLanguage(r.language)
+	rt.SetSecurityType(dbo.SecurityType(r.securityType))
+	rt.SetDefiner(definerName(r.definer))
+	rt.SetDeterministic(r.isDeterministic == "YES")
+	rt.SetSQLDataAccess(r.sqlDataAccess)
+	rt.SetSQLMode(r.sqlMode)
+	for _, param := range params[r.name] {
+		rt.AddParameter(param)
+	}
+}
+
+func (a *MySqlAdapter) mapFunctions(ctx context.Context, schemaName string) ([]*dbo.Function, []error) {
+	// Routines whose parameters cannot be read are still mapped, without them
+	params, errs := a.mapRoutineParameters(ctx, schemaName, "FUNCTION")
+
+	rows, err := a.db.QueryContext(ctx, routineQuery, schemaName, "FUNCTION")
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to query functions for schema %s: %w", schemaName, err)}
+		return nil, append(errs, fmt.Errorf("failed to query functions for schema %s: %w", schemaName, err))
 	}
 	defer rows.Close()
 
 	var functions []*dbo.Function
 	for rows.Next() {
-		var name string
-		var definition, returnType sql.NullString
-		if err := rows.Scan(&name, &definition, &returnType); err != nil {
-			return functions, []error{fmt.Errorf("failed to scan function: %w", err)}
+		var row routineRow
+		if err := row.scan(rows); err != nil {
+			return functions, append(errs, fmt.Errorf("failed to scan function: %w", err))
 		}
-		def := ""
-		if definition.Valid {
-			def = definition.String
+		fn := dbo.NewFunction(row.name, row.definition.String)
+		if row.returnType.Valid {
+			fn.SetReturnType(row.returnType.String)
 		}
-		fn := dbo.NewFunction(name, def)
-		if returnType.Valid {
-			fn.SetReturnType(returnType.String)
-		}
-		fn.SetLanguage("SQL")
+		row.apply(fn, params)
 		functions = append(functions, fn)
 	}
-	return functions, nil
+	return functions, errs
 }
 
 func (a *MySqlAdapter) mapProcedures(ctx context.Context, schemaName string) ([]*dbo.Procedure, []error) {
-	query := `
-		SELECT 
-			ROUTINE_NAME,
-			ROUTINE_DEFINITION
-		FROM information_schema.ROUTINES 
-		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'PROCEDURE'
-		ORDER BY ROUTINE_NAME`
+	// Routines whose parameters cannot be read are still mapped, without them
+	params, errs := a.mapRoutineParameters(ctx, schemaName, "PROCEDURE")
 
-	rows, err := a.db.QueryContext(ctx, query, schemaName)
+	rows, err := a.db.QueryContext(ctx, routineQuery, schemaName, "PROCEDURE")
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to query procedures for schema %s: %w", schemaName, err)}
+		return nil, append(errs, fmt.Errorf("failed to query procedures for schema %s: %w", schemaName, err))
 	}
 	defer rows.Close()
 
 	var procedures []*dbo.Procedure
 	for rows.Next() {
-		var name string
-		var definition sql.NullString
-		if err := rows.Scan(&name, &definition); err != nil {
-			return procedures, []error{fmt.Errorf("failed to scan procedure: %w", err)}
-		}
-		def := ""
-		if definition.Valid {
-			def = definition.String
+		var row routineRow
+		if err := row.scan(rows); err != nil {
+			return procedures, append(errs, fmt.Errorf("failed to scan procedure: %w", err))
 		}
-		proc := dbo.NewProcedure(name, def)
-		proc.SetLanguage("SQL")
+		proc := dbo.NewProcedure(row.name, row.definition.String)
+		row.apply(proc, params)
 		procedures = append(procedures, proc)
 	}
-	return procedures, nil
+	return procedures, errs
+}
+
+// mapRoutineParameters maps the parameters of the routines of one type in a schema,
+// keyed by routine name and in declaration order. The row at position 0 of a function
+// describes its return value and is left out.
+func (a *MySqlAdapter) mapRoutineParameters(ctx context.Context, schemaName, routineType string) (map[string][]*dbo.FunctionParameter, []error) {
+	query := `
+		SELECT SPECIFIC_NAME, PARAMETER_MODE, PARAMETER_NAME, DTD_IDENTIFIER
+		FROM information_schema.PARAMETERS
+		WHERE SPECIFIC_SCHEMA = ? AND ROUTINE_TYPE = ? AND ORDINAL_POSITION > 0
+		ORDER BY SPECIFIC_NAME, ORDINAL_POSITION`
+
+	rows, err := a.db.QueryContext(ctx, query, schemaName, routineType)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query routine parameters for schema %s: %w", schemaName, err)}
+	}
+	defer rows.Close()
+
+	params := make(map[string][]*dbo.FunctionParameter)
+	for rows.Next() {
+		var routineName string
+		var mode, name, dataType sql.NullString
+		if err := rows.Scan(&routineName, &mode, &name, &dataType); err != nil {
+			return nil, []error{fmt.Errorf("failed to scan routine parameter: %w", err)}
+		}
+		paramMode := dbo.ParameterModeIn
+		if mode.Valid {
+			paramMode = dbo.ParameterMode(mode.String)
+		}
+		params[routineName] = append(params[routineName], dbo.NewFunctionParameter(name.String, dataType.String, paramMode))
+	}
+	return params, nil
 }
 
 func (a *MySqlAdapter) mapTriggers(ctx context.Context, schemaName, tableName string) ([]*dbo.Trigger, []error) {
@@ -529,6 +732,10 @@ func (a *MySqlAdapter) mapConstraints(ctx context.Context, schemaName, tableName
 }
 
 func (a *MySqlAdapter) mapCheckConstraints(ctx context.Context, schemaName, tableName string, table *dbo.Table) ([]*dbo.Constraint, []error) {
+	if a.mariadb {
+		return a.mapMariaDBCheckConstraints(ctx, schemaName, tableName, table)
+	}
+
 	// CHECK_CONSTRAINTS table available in MySQL 8.0.16+
 	query := `
 		SELECT 
@@ -543,8 +750,11 @@ func (a *MySqlAdapter) mapCheckConstraints(ctx context.Context, schemaName, tabl
 
 	rows, err := a.db.QueryContext(ctx, query, schemaName, tableName)
 	if err != nil {
-		// CHECK_CONSTRAINTS may not exist in older MySQL versions
-		return nil, nil
+		// CHECK_CONSTRAINTS exists from MySQL 8.0.16
+		if isMissingTable(err) {
+			return nil, nil
+		}
+		return nil, []error{fmt.Errorf("failed to query check constraints: %w", err)}
 	}
 	defer rows.Close()
 
@@ -632,6 +842,112 @@ func (a *MySqlAdapter) mapNotNullConstraints(table *dbo.Table) []*dbo.Constraint
 	return constraints
 }
 
+// mapViewDependencies reads the tables, views and functions used by views
+// (VIEW_TABLE_USAGE and VIEW_ROUTINE_USAGE, MySQL 8.0.13+)
+func (a *MySqlAdapter) mapViewDependencies(ctx context.Context, schemaName string) ([]*dbo.Dependency, []error) {
+	query := `
+		SELECT u.VIEW_NAME, u.TABLE_SCHEMA, u.TABLE_NAME, COALESCE(t.TABLE_TYPE, 'BASE TABLE')
+		FROM information_schema.VIEW_TABLE_USAGE u
+		LEFT JOIN information_schema.TABLES t
+			ON t.TABLE_SCHEMA = u.TABLE_SCHEMA AND t.TABLE_NAME = u.TABLE_NAME
+		WHERE u.VIEW_SCHEMA = ?
+		ORDER BY u.VIEW_NAME, u.TABLE_SCHEMA, u.TABLE_NAME`
+
+	rows, err := a.db.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query view dependencies for schema %s: %w", schemaName, err)}
+	}
+	defer rows.Close()
+
+	var dependencies []*dbo.Dependency
+	for rows.Next() {
+		var viewName, refSchema, refName, refType string
+		if err := rows.Scan(&viewName, &refSchema, &refName, &refType); err != nil {
+			return dependencies, []error{fmt.Errorf("failed to scan view dependency: %w", err)}
+		}
+		objectType := dbo.ObjectTypeTable
+		if refType == "VIEW" {
+			objectType = dbo.ObjectTypeView
+		}
+		dependencies = append(dependencies, dbo.NewDependency(
+			dbo.NewObjectReference(dbo.ObjectTypeView, schemaName, viewName),
+			dbo.NewObjectReference(objectType, refSchema, refName),
+		))
+	}
+
+	routineQuery := `
+		SELECT TABLE_NAME, SPECIFIC_SCHEMA, SPECIFIC_NAME
+		FROM information_schema.VIEW_ROUTINE_USAGE
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME, SPECIFIC_SCHEMA, SPECIFIC_NAME`
+
+	routineRows, err := a.db.QueryContext(ctx, routineQuery, schemaName)
+	if err != nil {
+		return dependencies, []error{fmt.Errorf("failed to query view routine usage for schema %s: %w", schemaName, err)}
+	}
+	defer routineRows.Close()
+
+	for routineRows.Next() {
+		var viewName, fnSchema, fnName string
+		if err := routineRows.Scan(&viewName, &fnSchema, &fnName); err != nil {
+			return dependencies, []error{fmt.Errorf("failed to scan view routine usage: %w", err)}
+		}
+		dependencies = append(dependencies, dbo.NewDependency(
+			dbo.NewObjectReference(dbo.ObjectTypeView, schemaName, viewName),
+			dbo.NewObjectReference(dbo.ObjectTypeFunction, fnSchema, fnName),
+		))
+	}
+	return dependencies, nil
+}
+
+// collationCharset returns the character set of a collation, which names it up to the
+// first underscore, as in utf8mb4_0900_ai_ci
+func collationCharset(collation string) string {
+	charset, _, _ := strings.Cut(collation, "_")
+	return charset
+}
+
+// extraGeneration returns how a generated column is kept from the EXTRA of its
+// information_schema row, or "" for a column that is not generated. DEFAULT_GENERATED
+// marks a column with an expression default, not a generated column.
+func extraGeneration(extra string) dbo.Generation {
+	switch {
+	case hasExtra(extra, "VIRTUAL GENERATED"):
+		return dbo.GenerationVirtual
+	case hasExtra(extra, "STORED GENERATED"), hasExtra(extra, "PERSISTENT GENERATED"):
+		return dbo.GenerationStored
+	}
+	return ""
+}
+
+// hasExtra reports whether the EXTRA of an information_schema row holds a flag, such as
+// INVISIBLE in "VIRTUAL GENERATED INVISIBLE"
+func hasExtra(extra, flag string) bool {
+	return strings.Contains(" "+strings.ToUpper(extra)+" ", " "+flag+" ")
+}
+
+// expressionDefault converts a MySQL 8 expression default, which EXTRA marks
+// DEFAULT_GENERATED, to the form SHOW CREATE TABLE gives. information_schema reports it
+// without the parentheses that tell it from a string literal, as in uuid(). The
+// CURRENT_TIMESTAMP default of temporal columns, marked the same way, takes none.
+func (a *MySqlAdapter) expressionDefault(def string) string {
+	upper := strings.ToUpper(def)
+	if strings.HasPrefix(upper, "CURRENT_TIMESTAMP") || strings.HasPrefix(upper, "NOW(") || strings.HasPrefix(upper, "LOCALTIME") {
+		return def
+	}
+	return "(" + a.expression(def) + ")"
+}
+
+// expression converts an expression from information_schema to the form SHOW CREATE
+// TABLE gives. MySQL escapes the quotes of string literals in generation and index
+// expressions, as in _utf8mb4\' \'; MariaDB does not.
+func (a *MySqlAdapter) expression(expr string) string {
+	if a.mariadb {
+		return expr
+	}
+	return strings.ReplaceAll(expr, `\'`, "'")
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
 		(len(s) > 0 && len(substr) > 0 && searchString(s, substr)))
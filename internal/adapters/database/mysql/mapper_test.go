@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+func TestExtraGeneration(t *testing.T) {
+	tests := []struct {
+		extra         string
+		want          dbo.Generation
+		wantInvisible bool
+	}{
+		{"", "", false},
+		{"auto_increment", "", false},
+		{"DEFAULT_GENERATED on update CURRENT_TIMESTAMP", "", false},
+		{"VIRTUAL GENERATED", dbo.GenerationVirtual, false},
+		{"STORED GENERATED", dbo.GenerationStored, false},
+		{"VIRTUAL GENERATED INVISIBLE", dbo.GenerationVirtual, true},
+		{"INVISIBLE", "", true},
+	}
+	for _, tt := range tests {
+		if got := extraGeneration(tt.extra); got != tt.want {
+			t.Errorf("extraGeneration(%q) = %q, want %q", tt.extra, got, tt.want)
+		}
+		if got := hasExtra(tt.extra, "INVISIBLE"); got != tt.wantInvisible {
+			t.Errorf("hasExtra(%q, INVISIBLE) = %v, want %v", tt.extra, got, tt.wantInvisible)
+		}
+	}
+}
+
+func TestExpression(t *testing.T) {
+	expr := `concat(` + "`first`" + `,_utf8mb4\' \',` + "`last`" + `)`
+	want := "concat(`first`,_utf8mb4' ',`last`)"
+	if got := (&MySqlAdapter{}).expression(expr); got != want {
+		t.Errorf("expression() = %q, want %q", got, want)
+	}
+	if got := (&MySqlAdapter{mariadb: true}).expression(want); got != want {
+		t.Errorf("expression() on MariaDB = %q, want it unchanged", got)
+	}
+}
+
+func TestExpressionDefault(t *testing.T) {
+	tests := map[string]string{
+		"uuid()":                  "(uuid())",
+		`concat(_utf8mb4\'a\',1)`: "(concat(_utf8mb4'a',1))",
+		"CURRENT_TIMESTAMP":       "CURRENT_TIMESTAMP",
+		"CURRENT_TIMESTAMP(3)":    "CURRENT_TIMESTAMP(3)",
+	}
+	for def, want := range tests {
+		if got := (&MySqlAdapter{}).expressionDefault(def); got != want {
+			t.Errorf("expressionDefault(%q) = %q, want %q", def, got, want)
+		}
+	}
+}
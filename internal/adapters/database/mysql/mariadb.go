@@ -0,0 +1,228 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// Server error numbers for tables that do not exist: ER_UNKNOWN_TABLE for tables of
+// information_schema and ER_NO_SUCH_TABLE for the others
+const (
+	errUnknownTable = 1109
+	errNoSuchTable  = 1146
+)
+
+// jsonCheckPattern matches the CHECK constraint MariaDB adds to JSON columns, which it
+// stores as LONGTEXT
+var jsonCheckPattern = regexp.MustCompile("(?i)^\\s*json_valid\\(`((?:[^`]|``)+)`\\)\\s*$")
+
+// detectFlavour tells MariaDB from MySQL by the server version, which MariaDB suffixes
+// with -MariaDB
+func (a *MySqlAdapter) detectFlavour(ctx context.Context) error {
+	var version string
+	if err := a.db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return fmt.Errorf("failed to get server version: %w", err)
+	}
+	a.mariadb = strings.Contains(strings.ToLower(version), "mariadb")
+	a.version = parseServerVersion(version)
+	return nil
+}
+
+// parseServerVersion reads the major, minor and patch version from the start of a
+// VERSION() such as 8.0.36-log or 10.11.6-MariaDB. Parts it cannot read are 0.
+func parseServerVersion(version string) [3]int {
+	var parts [3]int
+	for i, part := range strings.SplitN(version, ".", 3) {
+		end := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' })
+		if end < 0 {
+			end = len(part)
+		}
+		parts[i], _ = strconv.Atoi(part[:end])
+	}
+	return parts
+}
+
+// atLeast reports whether the server is of the given version or later
+func (a *MySqlAdapter) atLeast(major, minor, patch int) bool {
+	return slices.Compare(a.version[:], []int{major, minor, patch}) >= 0
+}
+
+// mapSequences maps the native sequences of MariaDB 10.3+. information_schema lists
+// them as tables of type SEQUENCE, whose single row holds the sequence options.
+func (a *MySqlAdapter) mapSequences(ctx context.Context, schemaName string) ([]*dbo.Sequence, []error) {
+	query := `
+		SELECT TABLE_NAME
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'SEQUENCE'
+		ORDER BY TABLE_NAME`
+
+	rows, err := a.db.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query sequences for schema %s: %w", schemaName, err)}
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, []error{fmt.Errorf("failed to scan sequence: %w", err)}
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	var sequences []*dbo.Sequence
+	var errors []error
+	for _, name := range names {
+		var start, minValue, maxValue, increment, cache int64
+		var cycle bool
+		query := fmt.Sprintf("SELECT start_value, minimum_value, maximum_value, increment, cache_size, cycle_option FROM %s.%s",
+			quoteIdentifier(schemaName), quoteIdentifier(name))
+		if err := a.db.QueryRowContext(ctx, query).Scan(&start, &minValue, &maxValue, &increment, &cache, &cycle); err != nil {
+			errors = append(errors, fmt.Errorf("failed to read sequence %s.%s: %w", schemaName, name, err))
+			continue
+		}
+		seq := dbo.NewSequence(name, start, increment)
+		seq.SetMinValue(minValue)
+		seq.SetMaxValue(maxValue)
+		seq.SetCache(cache)
+		seq.SetCycle(cycle)
+		sequences = append(sequences, seq)
+	}
+	return sequences, errors
+}
+
+// mapSystemPeriod maps the SYSTEM_TIME period of a MariaDB system-versioned table from
+// its ROW START and ROW END columns. Tables versioned without explicit period columns
+// have hidden ones, which information_schema does not list, and get no period.
+func (a *MySqlAdapter) mapSystemPeriod(ctx context.Context, schemaName, tableName string, table *dbo.Table) (*dbo.Period, []error) {
+	query := `
+		SELECT COLUMN_NAME, EXTRA
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+			AND (EXTRA LIKE '%ROW START%' OR EXTRA LIKE '%ROW END%')`
+
+	rows, err := a.db.QueryContext(ctx, query, schemaName, tableName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query system period for %s.%s: %w", schemaName, tableName, err)}
+	}
+	defer rows.Close()
+
+	var start, end *dbo.Column
+	for rows.Next() {
+		var columnName, extra string
+		if err := rows.Scan(&columnName, &extra); err != nil {
+			return nil, []error{fmt.Errorf("failed to scan system period column: %w", err)}
+		}
+		col, ok := table.Columns()[columnName]
+		if !ok {
+			continue
+		}
+		if strings.Contains(strings.ToUpper(extra), "ROW START") {
+			start = col
+		} else {
+			end = col
+		}
+	}
+	if start == nil || end == nil {
+		return nil, nil
+	}
+	return dbo.NewPeriod(dbo.PeriodSystemTime, start, end), nil
+}
+
+// mapMariaDBCheckConstraints maps CHECK constraints from MariaDB's CHECK_CONSTRAINTS,
+// which unlike MySQL's has the table name, so that column-level checks of the same
+// name on different tables are not mixed up. The checks MariaDB adds to JSON columns
+// are not mapped; the column is given the json type it was declared with instead.
+func (a *MySqlAdapter) mapMariaDBCheckConstraints(ctx context.Context, schemaName, tableName string, table *dbo.Table) ([]*dbo.Constraint, []error) {
+	query := `
+		SELECT CONSTRAINT_NAME, CHECK_CLAUSE
+		FROM information_schema.CHECK_CONSTRAINTS
+		WHERE CONSTRAINT_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY CONSTRAINT_NAME`
+
+	rows, err := a.db.QueryContext(ctx, query, schemaName, tableName)
+	if err != nil {
+		// CHECK_CONSTRAINTS exists from MariaDB 10.2.22 and 10.3.10
+		if isMissingTable(err) {
+			return nil, nil
+		}
+		return nil, []error{fmt.Errorf("failed to query check constraints: %w", err)}
+	}
+	defer rows.Close()
+
+	var constraints []*dbo.Constraint
+	for rows.Next() {
+		var constraintName, checkClause string
+		if err := rows.Scan(&constraintName, &checkClause); err != nil {
+			return nil, []error{fmt.Errorf("failed to scan check constraint: %w", err)}
+		}
+
+		if name, ok := jsonCheckColumn(constraintName, checkClause); ok {
+			if col, exists := table.Columns()[name]; exists && strings.EqualFold(col.DataType(), "longtext") {
+				col.SetDataType("json")
+				continue
+			}
+		}
+
+		constraint := dbo.NewConstraint(constraintName, dbo.ConstraintType("CHECK"))
+		constraint.SetCheckExpression(checkClause)
+
+		// Column-level checks are named after their column
+		if col, exists := table.Columns()[constraintName]; exists {
+			constraint.AddColumn(col)
+		} else {
+			for colName, col := range table.Columns() {
+				if contains(checkClause, colName) {
+					constraint.AddColumn(col)
+				}
+			}
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints, nil
+}
+
+// jsonCheckColumn returns the column of a check that MariaDB added for a JSON column:
+// json_valid on the column, named after the column
+func jsonCheckColumn(constraintName, checkClause string) (string, bool) {
+	match := jsonCheckPattern.FindStringSubmatch(checkClause)
+	if match == nil {
+		return "", false
+	}
+	name := strings.ReplaceAll(match[1], "``", "`")
+	return name, name == constraintName
+}
+
+// mariaDBDefault converts a MariaDB COLUMN_DEFAULT to what MySQL reports. MariaDB
+// quotes string literals and reports NULL for columns without a default, where MySQL
+// gives unquoted strings and no value.
+func mariaDBDefault(def string) (string, bool) {
+	if def == "NULL" {
+		return "", false
+	}
+	if len(def) >= 2 && strings.HasPrefix(def, "'") && strings.HasSuffix(def, "'") {
+		return strings.ReplaceAll(def[1:len(def)-1], "''", "'"), true
+	}
+	return def, true
+}
+
+// isMissingTable reports whether a query failed because a table it reads does not
+// exist, as catalog tables added in later server versions do not on older ones
+func isMissingTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && (mysqlErr.Number == errUnknownTable || mysqlErr.Number == errNoSuchTable)
+}
+
+// quoteIdentifier quotes a name with backquotes for use in a query
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestJSONCheckColumn(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		clause     string
+		want       string
+		wantOK     bool
+	}{
+		{"json column", "doc", "json_valid(`doc`)", "doc", true},
+		{"quoted name", "a`b", "json_valid(`a``b`)", "a`b", true},
+		{"other constraint name", "doc_check", "json_valid(`doc`)", "doc", false},
+		{"other check", "price", "`price` > 0", "", false},
+		{"json_valid within a larger check", "doc", "json_valid(`doc`) and `doc` <> ''", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := jsonCheckColumn(tt.constraint, tt.clause)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("jsonCheckColumn(%q, %q) = %q, %v, want %q, %v", tt.constraint, tt.clause, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMariaDBDefault(t *testing.T) {
+	tests := []struct {
+		def    string
+		want   string
+		wantOK bool
+	}{
+		{"NULL", "", false},
+		{"'active'", "active", true},
+		{"'it''s'", "it's", true},
+		{"''", "", true},
+		{"0", "0", true},
+		{"current_timestamp()", "current_timestamp()", true},
+	}
+	for _, tt := range tests {
+		got, ok := mariaDBDefault(tt.def)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("mariaDBDefault(%q) = %q, %v, want %q, %v", tt.def, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    [3]int
+	}{
+		{"8.0.36", [3]int{8, 0, 36}},
+		{"8.0.12-log", [3]int{8, 0, 12}},
+		{"10.11.6-MariaDB-1:10.11.6+maria~ubu2204", [3]int{10, 11, 6}},
+		{"5.7", [3]int{5, 7, 0}},
+		{"", [3]int{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		if got := parseServerVersion(tt.version); got != tt.want {
+			t.Errorf("parseServerVersion(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+
+	a := &MySqlAdapter{version: [3]int{8, 0, 13}}
+	if !a.atLeast(8, 0, 13) || a.atLeast(8, 0, 14) || !a.atLeast(5, 7, 44) {
+		t.Errorf("unexpected atLeast results for %v", a.version)
+	}
+}
+
+func TestIsMissingTable(t *testing.T) {
+	missing := fmt.Errorf("query: %w", &mysql.MySQLError{Number: 1109, Message: "Unknown table 'CHECK_CONSTRAINTS' in information_schema"})
+	if !isMissingTable(missing) {
+		t.Error("expected an unknown information_schema table to be missing")
+	}
+	if !isMissingTable(&mysql.MySQLError{Number: 1146, Message: "Table 'mysql.role_edges' doesn't exist"}) {
+		t.Error("expected a table that doesn't exist to be missing")
+	}
+	if isMissingTable(&mysql.MySQLError{Number: 1142, Message: "SELECT command denied"}) || isMissingTable(errors.New("connection reset")) {
+		t.Error("expected permission and connection errors not to be missing tables")
+	}
+}
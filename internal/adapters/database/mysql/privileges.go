@@ -0,0 +1,242 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// privilegesQuery reads the privileges of every level from information_schema, which
+// lists the grants of all accounts to users who can read the mysql schema and only
+// their own to the others
+const privilegesQuery = `
+	SELECT GRANTEE, '' AS TABLE_SCHEMA, '' AS TABLE_NAME, '' AS COLUMN_NAME, PRIVILEGE_TYPE, IS_GRANTABLE
+	FROM information_schema.USER_PRIVILEGES
+	UNION ALL
+	SELECT GRANTEE, TABLE_SCHEMA, '', '', PRIVILEGE_TYPE, IS_GRANTABLE
+	FROM information_schema.SCHEMA_PRIVILEGES
+	UNION ALL
+	SELECT GRANTEE, TABLE_SCHEMA, TABLE_NAME, '', PRIVILEGE_TYPE, IS_GRANTABLE
+	FROM information_schema.TABLE_PRIVILEGES
+	UNION ALL
+	SELECT GRANTEE, TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, PRIVILEGE_TYPE, IS_GRANTABLE
+	FROM information_schema.COLUMN_PRIVILEGES
+	ORDER BY GRANTEE, TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, PRIVILEGE_TYPE`
+
+// mapRoles maps the accounts and roles of the server with their privileges. Roles are
+// named like information_schema grantees, as in 'app'@'%'. Accounts, role memberships
+// and default roles are read from the mysql schema; when the auditing user cannot read
+// it, roles are mapped from the grantees of the privileges it can see, without their
+// login status or memberships, and a warning says so.
+func (a *MySqlAdapter) mapRoles(ctx context.Context) (map[string]*dbo.Role, []*dbo.Grant, []error) {
+	roles, errs := a.mapAccounts(ctx)
+	if a.mariadb {
+		errs = append(errs, a.mapMariaDBRoleMappings(ctx, roles)...)
+	} else {
+		errs = append(errs, a.mapRoleEdges(ctx, roles)...)
+	}
+
+	grants, grantErrs := a.mapGrants(ctx)
+	errs = append(errs, grantErrs...)
+	for _, grant := range grants {
+		role, ok := roles[grant.Grantee()]
+		if !ok {
+			role = dbo.NewRole(grant.Grantee())
+			roles[role.Name()] = role
+		}
+		if grant.Level() != dbo.GrantLevelGlobal {
+			continue
+		}
+		switch grant.Privilege() {
+		case "SUPER":
+			role.SetSuperuser(true)
+		case "CREATE":
+			role.SetCanCreateDB(true)
+		case "CREATE ROLE":
+			role.SetCanCreateRole(true)
+		}
+	}
+	return roles, grants, errs
+}
+
+// mapAccounts maps the accounts of mysql.user. MariaDB keeps its roles there too,
+// marked by is_role, with their default role on the account. The auditing user may not
+// be allowed to read the mysql schema, which is reported as a warning.
+func (a *MySqlAdapter) mapAccounts(ctx context.Context) (map[string]*dbo.Role, []error) {
+	query := `SELECT User, Host, account_locked = 'N', '' FROM mysql.user`
+	if a.mariadb {
+		query = `SELECT User, Host, is_role = 'N' AND account_locked = 'N', default_role FROM mysql.user`
+	}
+
+	roles := make(map[string]*dbo.Role)
+	rows, err := a.db.QueryContext(ctx, query)
+	if err != nil {
+		return roles, []error{fmt.Errorf("cannot read mysql.user; accounts mapped from grants only: %w", err)}
+	}
+	defer rows.Close()
+
+	defaultRoles := make(map[string]string)
+	for rows.Next() {
+		var user, host, defaultRole string
+		var canLogin bool
+		if err := rows.Scan(&user, &host, &canLogin, &defaultRole); err != nil {
+			return roles, []error{fmt.Errorf("failed to scan account: %w", err)}
+		}
+		role := dbo.NewRole(accountName(user, host))
+		role.SetCanLogin(canLogin)
+		roles[role.Name()] = role
+		if defaultRole != "" {
+			defaultRoles[role.Name()] = accountName(defaultRole, "")
+		}
+	}
+
+	for name, defaultRole := range defaultRoles {
+		if role, ok := roles[defaultRole]; ok {
+			roles[name].AddDefaultRole(role)
+		}
+	}
+	return roles, nil
+}
+
+// mapRoleEdges maps the role memberships of MySQL 8.0 from mysql.role_edges, which
+// links a granted role (FROM) to the account it was granted to (TO), and the default
+// roles of accounts from mysql.default_roles
+func (a *MySqlAdapter) mapRoleEdges(ctx context.Context, roles map[string]*dbo.Role) []error {
+	errs := a.mapRoleLinks(ctx, roles, "mysql.role_edges", `
+		SELECT TO_USER, TO_HOST, FROM_USER, FROM_HOST
+		FROM mysql.role_edges
+		ORDER BY TO_USER, TO_HOST, FROM_USER, FROM_HOST`, (*dbo.Role).AddMemberOf)
+	return append(errs, a.mapRoleLinks(ctx, roles, "mysql.default_roles", `
+		SELECT USER, HOST, DEFAULT_ROLE_USER, DEFAULT_ROLE_HOST
+		FROM mysql.default_roles
+		ORDER BY USER, HOST, DEFAULT_ROLE_USER, DEFAULT_ROLE_HOST`, (*dbo.Role).AddDefaultRole)...)
+}
+
+// mapMariaDBRoleMappings maps the role memberships of MariaDB from mysql.roles_mapping.
+// MariaDB roles have no host.
+func (a *MySqlAdapter) mapMariaDBRoleMappings(ctx context.Context, roles map[string]*dbo.Role) []error {
+	return a.mapRoleLinks(ctx, roles, "mysql.roles_mapping", `
+		SELECT User, Host, Role, ''
+		FROM mysql.roles_mapping
+		ORDER BY User, Host, Role`, (*dbo.Role).AddMemberOf)
+}
+
+// mapRoleLinks links the accounts of the rows of a query on a table, given as user and
+// host, to the roles that follow them. A table missing on servers without roles leaves
+// the roles unlinked; one that cannot be read does too, with a warning.
+func (a *MySqlAdapter) mapRoleLinks(ctx context.Context, roles map[string]*dbo.Role, table, query string, link func(*dbo.Role, *dbo.Role)) []error {
+	rows, err := a.db.QueryContext(ctx, query)
+	if err != nil {
+		if isMissingTable(err) {
+			return nil
+		}
+		return []error{fmt.Errorf("cannot read %s; role memberships not mapped: %w", table, err)}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user, host, roleUser, roleHost string
+		if err := rows.Scan(&user, &host, &roleUser, &roleHost); err != nil {
+			return []error{fmt.Errorf("failed to scan %s: %w", table, err)}
+		}
+		account, ok := roles[accountName(user, host)]
+		role, roleOK := roles[accountName(roleUser, roleHost)]
+		if ok && roleOK {
+			link(account, role)
+		}
+	}
+	return nil
+}
+
+// mapGrants maps the privileges of information_schema. USAGE, which stands for no
+// privilege at all, is left out, as are grants on databases the schema filter leaves
+// out. Grants on the system databases are kept: they are how accounts read the
+// credentials and privileges of others.
+func (a *MySqlAdapter) mapGrants(ctx context.Context) ([]*dbo.Grant, []error) {
+	rows, err := a.db.QueryContext(ctx, privilegesQuery)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query privileges: %w", err)}
+	}
+	defer rows.Close()
+
+	var grants []*dbo.Grant
+	for rows.Next() {
+		var grantee, schemaName, tableName, columnName, privilege, isGrantable string
+		if err := rows.Scan(&grantee, &schemaName, &tableName, &columnName, &privilege, &isGrantable); err != nil {
+			return grants, []error{fmt.Errorf("failed to scan privilege: %w", err)}
+		}
+		if privilege == "USAGE" {
+			continue
+		}
+		if schemaName != "" && !slices.Contains(systemSchemas, schemaName) && !a.filter.matches(schemaName) {
+			continue
+		}
+
+		grant := dbo.NewGrant(privilege, "")
+		grant.SetGrantee(accountName(parseGrantee(grantee)))
+		grant.SetPrivilege(privilege)
+		grant.SetObject(schemaName, tableName, columnName)
+		grant.SetGrantable(isGrantable == "YES")
+		grant.SetDefinition(grantDefinition(grant))
+		grants = append(grants, grant)
+	}
+	return grants, nil
+}
+
+// accountName names an account like information_schema grantees, as in 'app'@'%'.
+// Accounts without a host, such as MariaDB roles, are named 'reader'.
+func accountName(user, host string) string {
+	if host == "" {
+		return "'" + user + "'"
+	}
+	return "'" + user + "'@'" + host + "'"
+}
+
+// parseGrantee splits an information_schema grantee such as 'app'@'%' into its user
+// and host. They are split at the '@' between the quoted parts, as user names may hold
+// an @ of their own.
+func parseGrantee(grantee string) (string, string) {
+	i := strings.LastIndex(grantee, "'@'")
+	if i < 0 {
+		return strings.Trim(grantee, "'"), ""
+	}
+	return strings.Trim(grantee[:i+1], "'"), strings.Trim(grantee[i+2:], "'")
+}
+
+// definerName names the definer of a routine, given as user@host, like the account it
+// refers to. The host follows the last @, as user names may hold one.
+func definerName(definer string) string {
+	if definer == "" {
+		return ""
+	}
+	i := strings.LastIndex(definer, "@")
+	if i < 0 {
+		return accountName(definer, "")
+	}
+	return accountName(definer[:i], definer[i+1:])
+}
+
+// grantDefinition renders the GRANT statement of a single privilege
+func grantDefinition(g *dbo.Grant) string {
+	var sb strings.Builder
+	sb.WriteString("GRANT " + g.Privilege())
+	if g.ColumnName() != "" {
+		sb.WriteString(" (" + quoteIdentifier(g.ColumnName()) + ")")
+	}
+	switch g.Level() {
+	case dbo.GrantLevelGlobal:
+		sb.WriteString(" ON *.*")
+	case dbo.GrantLevelSchema:
+		sb.WriteString(" ON " + quoteIdentifier(g.SchemaName()) + ".*")
+	default:
+		sb.WriteString(" ON " + quoteIdentifier(g.SchemaName()) + "." + quoteIdentifier(g.TableName()))
+	}
+	sb.WriteString(" TO " + g.Grantee())
+	if g.IsGrantable() {
+		sb.WriteString(" WITH GRANT OPTION")
+	}
+	return sb.String()
+}
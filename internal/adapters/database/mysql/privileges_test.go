@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+func TestAccountName(t *testing.T) {
+	tests := []struct {
+		grantee string
+		want    string
+	}{
+		{"'app'@'%'", "'app'@'%'"},
+		{"'root'@'localhost'", "'root'@'localhost'"},
+		// MariaDB roles have no host
+		{"'reader'", "'reader'"},
+		{"'reader'@''", "'reader'"},
+		{"'ops@example.com'@'%'", "'ops@example.com'@'%'"},
+	}
+	for _, tt := range tests {
+		if got := accountName(parseGrantee(tt.grantee)); got != tt.want {
+			t.Errorf("accountName(parseGrantee(%q)) = %q, want %q", tt.grantee, got, tt.want)
+		}
+	}
+}
+
+func TestDefinerName(t *testing.T) {
+	tests := []struct {
+		definer string
+		want    string
+	}{
+		{"root@localhost", "'root'@'localhost'"},
+		{"ops@example.com@%", "'ops@example.com'@'%'"},
+		{"reader", "'reader'"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := definerName(tt.definer); got != tt.want {
+			t.Errorf("definerName(%q) = %q, want %q", tt.definer, got, tt.want)
+		}
+	}
+}
+
+func TestGrantDefinition(t *testing.T) {
+	tests := []struct {
+		privilege, schema, table, column string
+		grantable                        bool
+		want                             string
+	}{
+		{"SUPER", "", "", "", true, "GRANT SUPER ON *.* TO 'app'@'%' WITH GRANT OPTION"},
+		{"SELECT", "shop", "", "", false, "GRANT SELECT ON `shop`.* TO 'app'@'%'"},
+		{"DELETE", "shop", "orders", "", false, "GRANT DELETE ON `shop`.`orders` TO 'app'@'%'"},
+		{"UPDATE", "shop", "customers", "email", false, "GRANT UPDATE (`email`) ON `shop`.`customers` TO 'app'@'%'"},
+	}
+	for _, tt := range tests {
+		grant := dbo.NewGrant(tt.privilege, "")
+		grant.SetGrantee("'app'@'%'")
+		grant.SetPrivilege(tt.privilege)
+		grant.SetObject(tt.schema, tt.table, tt.column)
+		grant.SetGrantable(tt.grantable)
+		if got := grantDefinition(grant); got != tt.want {
+			t.Errorf("grantDefinition() = %q, want %q", got, tt.want)
+		}
+	}
+}
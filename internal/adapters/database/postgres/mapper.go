@@ -19,6 +19,7 @@ func (a *PostgresAdapter) MapDatabase() (*dbo.Database, []error) {
 	}
 
 	db := dbo.NewDatabase(dbName, nil)
+	db.SetEngine(a.Name())
 
 	// Map schemas
 	schemas, errs := a.mapSchemas(ctx)
@@ -109,6 +110,15 @@ func (a *PostgresAdapter) MapDatabase() (*dbo.Database, []error) {
 		}
 	}
 
+	// Map enum types
+	for _, schema := range db.Schemas() {
+		enums, errs := a.mapEnums(ctx, schema.Name())
+		errors = append(errors, errs...)
+		for _, enum := range enums {
+			schema.AddEnum(enum)
+		}
+	}
+
 	// Map functions
 	for _, schema := range db.Schemas() {
 		functions, errs := a.mapFunctions(ctx, schema.Name())
@@ -138,6 +148,13 @@ func (a *PostgresAdapter) MapDatabase() (*dbo.Database, []error) {
 		}
 	}
 
+	// Map non-FK dependencies (views, triggers, column defaults, routines)
+	dependencies, errs := a.mapDependencies(ctx)
+	errors = append(errors, errs...)
+	for _, dep := range dependencies {
+		db.AddDependency(dep)
+	}
+
 	if len(errors) > 0 {
 		return db, errors
 	}
@@ -170,7 +187,9 @@ func (a *PostgresAdapter) mapSchemas(ctx context.Context) ([]*dbo.Schema, []erro
 
 func (a *PostgresAdapter) mapTables(ctx context.Context, schemaName string) ([]*dbo.Table, []error) {
 	query := `
-		SELECT table_name 
+		SELECT 
+			table_name,
+			obj_description(format('%I.%I', table_schema, table_name)::regclass, 'pg_class') AS comment
 		FROM information_schema.tables 
 		WHERE table_schema = $1 AND table_type = 'BASE TABLE'
 		ORDER BY table_name`
@@ -184,10 +203,15 @@ func (a *PostgresAdapter) mapTables(ctx context.Context, schemaName string) ([]*
 	var tables []*dbo.Table
 	for rows.Next() {
 		var name string
-		if err := rows.Scan(&name); err != nil {
+		var comment *string
+		if err := rows.Scan(&name, &comment); err != nil {
 			return tables, []error{fmt.Errorf("failed to scan table: %w", err)}
 		}
-		tables = append(tables, dbo.NewTable(name, nil))
+		table := dbo.NewTable(name, nil)
+		if comment != nil {
+			table.SetComment(*comment)
+		}
+		tables = append(tables, table)
 	}
 	return tables, nil
 }
@@ -196,13 +220,14 @@ func (a *PostgresAdapter) mapColumns(ctx context.Context, schemaName, tableName
 	query := `
 		SELECT 
 			column_name,
-			data_type,
+			CASE WHEN data_type = 'USER-DEFINED' THEN udt_schema || '.' || udt_name ELSE data_type END AS data_type,
 			is_nullable,
 			column_default,
 			ordinal_position,
 			character_maximum_length,
 			numeric_precision,
-			numeric_scale
+			numeric_scale,
+			col_description(format('%I.%I', table_schema, table_name)::regclass, ordinal_position::int) AS comment
 		FROM information_schema.columns 
 		WHERE table_schema = $1 AND table_name = $2
 		ORDER BY ordinal_position`
@@ -216,11 +241,11 @@ func (a *PostgresAdapter) mapColumns(ctx context.Context, schemaName, tableName
 	var columns []*dbo.Column
 	for rows.Next() {
 		var name, dataType, isNullable string
-		var columnDefault *string
+		var columnDefault, comment *string
 		var ordinalPosition int
 		var charMaxLength, numericPrecision, numericScale *int
 
-		if err := rows.Scan(&name, &dataType, &isNullable, &columnDefault, &ordinalPosition, &charMaxLength, &numericPrecision, &numericScale); err != nil {
+		if err := rows.Scan(&name, &dataType, &isNullable, &columnDefault, &ordinalPosition, &charMaxLength, &numericPrecision, &numericScale, &comment); err != nil {
 			return columns, []error{fmt.Errorf("failed to scan column: %w", err)}
 		}
 
@@ -238,6 +263,9 @@ func (a *PostgresAdapter) mapColumns(ctx context.Context, schemaName, tableName
 		if numericScale != nil {
 			col.SetNumericScale(*numericScale)
 		}
+		if comment != nil {
+			col.SetComment(*comment)
+		}
 		columns = append(columns, col)
 	}
 	return columns, nil
@@ -475,6 +503,36 @@ func (a *PostgresAdapter) mapSequences(ctx context.Context, schemaName string) (
 	return sequences, nil
 }
 
+func (a *PostgresAdapter) mapEnums(ctx context.Context, schemaName string) ([]*dbo.Enum, []error) {
+	query := `
+		SELECT
+			t.typname,
+			array_agg(e.enumlabel ORDER BY e.enumsortorder)::text[]
+		FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		WHERE n.nspname = $1
+		GROUP BY t.typname
+		ORDER BY t.typname`
+
+	rows, err := a.conn.Query(ctx, query, schemaName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query enums for schema %s: %w", schemaName, err)}
+	}
+	defer rows.Close()
+
+	var enums []*dbo.Enum
+	for rows.Next() {
+		var name string
+		var values []string
+		if err := rows.Scan(&name, &values); err != nil {
+			return enums, []error{fmt.Errorf("failed to scan enum: %w", err)}
+		}
+		enums = append(enums, dbo.NewEnum(name, values))
+	}
+	return enums, nil
+}
+
 func (a *PostgresAdapter) mapFunctions(ctx context.Context, schemaName string) ([]*dbo.Function, []error) {
 	query := `
 		SELECT 
@@ -638,3 +696,212 @@ func (a *PostgresAdapter) mapConstraints(ctx context.Context, schemaName, tableN
 	}
 	return constraints, nil
 }
+
+// pgSystemSchemas lists the schemas whose objects are left out of the dependency graph
+const pgSystemSchemas = "('pg_catalog', 'information_schema', 'pg_toast')"
+
+func (a *PostgresAdapter) mapDependencies(ctx context.Context) ([]*dbo.Dependency, []error) {
+	var dependencies []*dbo.Dependency
+	var errors []error
+
+	for _, mapFn := range []func(context.Context) ([]*dbo.Dependency, []error){
+		a.mapViewDependencies,
+		a.mapTriggerDependencies,
+		a.mapDefaultDependencies,
+		a.mapRoutineDependencies,
+	} {
+		deps, errs := mapFn(ctx)
+		errors = append(errors, errs...)
+		dependencies = append(dependencies, deps...)
+	}
+	return dependencies, errors
+}
+
+// mapViewDependencies reads the relations and columns used by views and materialized
+// views from the dependencies recorded for their rewrite rules
+func (a *PostgresAdapter) mapViewDependencies(ctx context.Context) ([]*dbo.Dependency, []error) {
+	query := `
+		SELECT DISTINCT
+			vn.nspname,
+			v.relname,
+			v.relkind::text,
+			rn.nspname,
+			rc.relname,
+			rc.relkind::text,
+			COALESCE(att.attname, '')
+		FROM pg_rewrite rw
+		JOIN pg_depend d ON d.classid = 'pg_rewrite'::regclass
+			AND d.objid = rw.oid
+			AND d.refclassid = 'pg_class'::regclass
+			AND d.deptype = 'n'
+		JOIN pg_class v ON v.oid = rw.ev_class
+		JOIN pg_namespace vn ON vn.oid = v.relnamespace
+		JOIN pg_class rc ON rc.oid = d.refobjid
+		JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+		LEFT JOIN pg_attribute att ON att.attrelid = d.refobjid AND att.attnum = d.refobjsubid AND d.refobjsubid > 0
+		WHERE v.relkind IN ('v', 'm')
+			AND rc.oid <> v.oid
+			AND vn.nspname NOT IN ` + pgSystemSchemas + `
+			AND rn.nspname NOT IN ` + pgSystemSchemas + `
+		ORDER BY 1, 2, 4, 5, 7`
+
+	rows, err := a.conn.Query(ctx, query)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query view dependencies: %w", err)}
+	}
+	defer rows.Close()
+
+	var dependencies []*dbo.Dependency
+	for rows.Next() {
+		var viewSchema, viewName, viewKind, refSchema, refName, refKind, refColumn string
+		if err := rows.Scan(&viewSchema, &viewName, &viewKind, &refSchema, &refName, &refKind, &refColumn); err != nil {
+			return dependencies, []error{fmt.Errorf("failed to scan view dependency: %w", err)}
+		}
+		dependent := dbo.NewObjectReference(pgRelationType(viewKind), viewSchema, viewName)
+		var referenced *dbo.ObjectReference
+		if refColumn != "" {
+			referenced = dbo.NewObjectReference(dbo.ObjectTypeColumn, refSchema, refColumn)
+			referenced.SetParent(refName)
+		} else {
+			referenced = dbo.NewObjectReference(pgRelationType(refKind), refSchema, refName)
+		}
+		dependencies = append(dependencies, dbo.NewDependency(dependent, referenced))
+	}
+	return dependencies, nil
+}
+
+// mapTriggerDependencies links each user trigger to the function it executes
+func (a *PostgresAdapter) mapTriggerDependencies(ctx context.Context) ([]*dbo.Dependency, []error) {
+	query := `
+		SELECT tn.nspname, c.relname, t.tgname, pn.nspname, p.proname
+		FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid
+		JOIN pg_namespace tn ON tn.oid = c.relnamespace
+		JOIN pg_proc p ON p.oid = t.tgfoid
+		JOIN pg_namespace pn ON pn.oid = p.pronamespace
+		WHERE NOT t.tgisinternal
+			AND tn.nspname NOT IN ` + pgSystemSchemas + `
+		ORDER BY 1, 2, 3`
+
+	rows, err := a.conn.Query(ctx, query)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query trigger dependencies: %w", err)}
+	}
+	defer rows.Close()
+
+	var dependencies []*dbo.Dependency
+	for rows.Next() {
+		var tableSchema, tableName, triggerName, fnSchema, fnName string
+		if err := rows.Scan(&tableSchema, &tableName, &triggerName, &fnSchema, &fnName); err != nil {
+			return dependencies, []error{fmt.Errorf("failed to scan trigger dependency: %w", err)}
+		}
+		trigger := dbo.NewObjectReference(dbo.ObjectTypeTrigger, tableSchema, triggerName)
+		trigger.SetParent(tableName)
+		dependencies = append(dependencies, dbo.NewDependency(trigger, dbo.NewObjectReference(dbo.ObjectTypeFunction, fnSchema, fnName)))
+	}
+	return dependencies, nil
+}
+
+// mapDefaultDependencies reads the sequences and user functions used by column defaults
+func (a *PostgresAdapter) mapDefaultDependencies(ctx context.Context) ([]*dbo.Dependency, []error) {
+	query := `
+		SELECT
+			n.nspname,
+			c.relname,
+			att.attname,
+			CASE WHEN rc.oid IS NOT NULL THEN 'S' ELSE 'f' END,
+			rn.nspname,
+			COALESCE(rc.relname, rp.proname)
+		FROM pg_attrdef ad
+		JOIN pg_depend d ON d.classid = 'pg_attrdef'::regclass
+			AND d.objid = ad.oid
+			AND d.deptype = 'n'
+		JOIN pg_class c ON c.oid = ad.adrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_attribute att ON att.attrelid = ad.adrelid AND att.attnum = ad.adnum
+		LEFT JOIN pg_class rc ON d.refclassid = 'pg_class'::regclass AND rc.oid = d.refobjid AND rc.relkind = 'S'
+		LEFT JOIN pg_proc rp ON d.refclassid = 'pg_proc'::regclass AND rp.oid = d.refobjid
+		JOIN pg_namespace rn ON rn.oid = COALESCE(rc.relnamespace, rp.pronamespace)
+		WHERE n.nspname NOT IN ` + pgSystemSchemas + `
+			AND rn.nspname NOT IN ` + pgSystemSchemas + `
+		ORDER BY 1, 2, 3, 5, 6`
+
+	rows, err := a.conn.Query(ctx, query)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query column default dependencies: %w", err)}
+	}
+	defer rows.Close()
+
+	var dependencies []*dbo.Dependency
+	for rows.Next() {
+		var tableSchema, tableName, columnName, refKind, refSchema, refName string
+		if err := rows.Scan(&tableSchema, &tableName, &columnName, &refKind, &refSchema, &refName); err != nil {
+			return dependencies, []error{fmt.Errorf("failed to scan column default dependency: %w", err)}
+		}
+		column := dbo.NewObjectReference(dbo.ObjectTypeColumn, tableSchema, columnName)
+		column.SetParent(tableName)
+		refType := dbo.ObjectTypeFunction
+		if refKind == "S" {
+			refType = dbo.ObjectTypeSequence
+		}
+		dependencies = append(dependencies, dbo.NewDependency(column, dbo.NewObjectReference(refType, refSchema, refName)))
+	}
+	return dependencies, nil
+}
+
+// mapRoutineDependencies reads the relations used by functions and procedures. Only
+// SQL-standard routine bodies (BEGIN ATOMIC, PostgreSQL 14+) have their references
+// tracked in pg_depend; other bodies are covered by definition analysis in reports.
+func (a *PostgresAdapter) mapRoutineDependencies(ctx context.Context) ([]*dbo.Dependency, []error) {
+	query := `
+		SELECT DISTINCT pn.nspname, p.proname, p.prokind::text, rn.nspname, rc.relname, rc.relkind::text
+		FROM pg_proc p
+		JOIN pg_namespace pn ON pn.oid = p.pronamespace
+		JOIN pg_depend d ON d.classid = 'pg_proc'::regclass
+			AND d.objid = p.oid
+			AND d.refclassid = 'pg_class'::regclass
+			AND d.deptype = 'n'
+		JOIN pg_class rc ON rc.oid = d.refobjid
+		JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+		WHERE rc.relkind IN ('r', 'p', 'v', 'm', 'f')
+			AND pn.nspname NOT IN ` + pgSystemSchemas + `
+			AND rn.nspname NOT IN ` + pgSystemSchemas + `
+		ORDER BY 1, 2, 4, 5`
+
+	rows, err := a.conn.Query(ctx, query)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query routine dependencies: %w", err)}
+	}
+	defer rows.Close()
+
+	var dependencies []*dbo.Dependency
+	for rows.Next() {
+		var routineSchema, routineName, routineKind, refSchema, refName, refKind string
+		if err := rows.Scan(&routineSchema, &routineName, &routineKind, &refSchema, &refName, &refKind); err != nil {
+			return dependencies, []error{fmt.Errorf("failed to scan routine dependency: %w", err)}
+		}
+		routineType := dbo.ObjectTypeFunction
+		if routineKind == "p" {
+			routineType = dbo.ObjectTypeProcedure
+		}
+		dependencies = append(dependencies, dbo.NewDependency(
+			dbo.NewObjectReference(routineType, routineSchema, routineName),
+			dbo.NewObjectReference(pgRelationType(refKind), refSchema, refName),
+		))
+	}
+	return dependencies, nil
+}
+
+// pgRelationType maps a pg_class relkind to the object type used in dependencies
+func pgRelationType(relkind string) dbo.ObjectType {
+	switch relkind {
+	case "v":
+		return dbo.ObjectTypeView
+	case "m":
+		return dbo.ObjectTypeMaterializedView
+	case "S":
+		return dbo.ObjectTypeSequence
+	default:
+		return dbo.ObjectTypeTable
+	}
+}
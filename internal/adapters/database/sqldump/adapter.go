@@ -0,0 +1,73 @@
+package sqldump
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// urlSchemePattern matches the scheme of a URL. Single letters are Windows drive letters.
+var urlSchemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]+:`)
+
+// SQLDumpAdapter maps a schema-only dump written by pg_dump or mysqldump without
+// connecting to a database. "Connecting" reads the dump file.
+type SQLDumpAdapter struct {
+	dump string
+	path string
+}
+
+func (a *SQLDumpAdapter) Name() string {
+	return "SQL Dump"
+}
+
+func (a *SQLDumpAdapter) Version() string {
+	return "v1"
+}
+
+func (a *SQLDumpAdapter) UniqueSignature() string {
+	return a.Name() + "-" + a.Version()
+}
+
+func (a *SQLDumpAdapter) IsConnectionStringCompatible(connString string) bool {
+	return dumpPath(connString) != ""
+}
+
+// Connect reads the dump file into memory
+func (a *SQLDumpAdapter) Connect(connString string) error {
+	path := dumpPath(connString)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	a.dump = string(content)
+	a.path = path
+	return nil
+}
+
+func (a *SQLDumpAdapter) Close() error {
+	a.dump = ""
+	a.path = ""
+	return nil
+}
+
+func (a *SQLDumpAdapter) IsConnected() bool {
+	return a.path != ""
+}
+
+// dumpPath returns the file path of a "file://path" connection string, or of a plain
+// path ending in .sql. It returns "" for anything else, including URLs of other schemes
+// such as sqlite://dump.sql and JSON reports, which the JSON report adapter reads.
+func dumpPath(connString string) string {
+	if path, ok := strings.CutPrefix(connString, "file://"); ok {
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			return ""
+		}
+		return path
+	}
+	if !urlSchemePattern.MatchString(connString) && strings.EqualFold(filepath.Ext(connString), ".sql") {
+		return connString
+	}
+	return ""
+}
@@ -0,0 +1,870 @@
+package sqldump
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// The statements in this file change objects created by earlier statements. Dumps
+// mostly use ALTER TABLE to add constraints once the data is loaded; migrations use
+// ALTER, DROP and RENAME to evolve the schema one step at a time.
+
+// columnPlacement is the MySQL FIRST or AFTER clause of an added or redefined column
+type columnPlacement struct {
+	first bool
+	after string
+}
+
+// parseAlterSchema handles ALTER SCHEMA ... OWNER TO, which pg_dump writes after
+// creating a schema, and ALTER SCHEMA ... RENAME TO
+func (p *dumpParser) parseAlterSchema(c *cursor) error {
+	name := c.next().name()
+	switch {
+	case c.accept("OWNER", "TO"):
+		p.schema(name).owner = c.next().name()
+	case c.accept("RENAME", "TO"):
+		return p.renameSchema(name, c.next().name())
+	}
+	return nil
+}
+
+// renameSchema renames a schema and the foreign keys that reference its tables
+func (p *dumpParser) renameSchema(oldName, newName string) error {
+	s, ok := p.schemas[oldName]
+	if !ok {
+		return fmt.Errorf("unknown schema %s", oldName)
+	}
+	if _, ok := p.schemas[newName]; ok {
+		return fmt.Errorf("schema %s already exists", newName)
+	}
+
+	delete(p.schemas, oldName)
+	s.name = newName
+	p.schemas[newName] = s
+	p.schemaOrder[slices.Index(p.schemaOrder, oldName)] = newName
+	if p.defaultSchema == oldName {
+		p.defaultSchema = newName
+	}
+	for _, fk := range p.foreignKeys() {
+		if fk.ReferencedSchema() == oldName {
+			fk.SetReferencedSchema(newName)
+		}
+	}
+	return nil
+}
+
+// foreignKeys returns the foreign keys of all tables parsed so far
+func (p *dumpParser) foreignKeys() []*dbo.ForeignKey {
+	var fks []*dbo.ForeignKey
+	for _, s := range p.schemas {
+		for _, table := range s.tables {
+			fks = append(fks, table.ForeignKeys()...)
+		}
+	}
+	return fks
+}
+
+// ignoredAlterActions are the first keywords of ALTER TABLE actions that change nothing
+// the model holds, such as ownership, row level security, triggers being enabled,
+// storage parameters, partitions and the MySQL table options other than ENGINE,
+// ROW_FORMAT and COMMENT
+var ignoredAlterActions = map[string]bool{
+	"OWNER": true, "ENABLE": true, "DISABLE": true, "FORCE": true, "NO": true, "CLUSTER": true,
+	"REPLICA": true, "INHERIT": true, "ATTACH": true, "DETACH": true, "VALIDATE": true, "SET": true,
+	"RESET": true, "OF": true, "NOT": true, "AUTO_INCREMENT": true, "DEFAULT": true, "CHARSET": true,
+	"CHARACTER": true, "COLLATE": true, "CONVERT": true, "ALGORITHM": true, "LOCK": true, "ORDER": true,
+	"KEY_BLOCK_SIZE": true, "STATS_PERSISTENT": true, "STATS_AUTO_RECALC": true, "STATS_SAMPLE_PAGES": true,
+	"PACK_KEYS": true, "CHECKSUM": true, "DELAY_KEY_WRITE": true, "MAX_ROWS": true, "MIN_ROWS": true,
+	"AVG_ROW_LENGTH": true, "COMPRESSION": true, "ENCRYPTION": true, "PARTITION": true, "REMOVE": true,
+	"DISCARD": true, "IMPORT": true, "COALESCE": true, "REORGANIZE": true, "TRUNCATE": true,
+	"ANALYZE": true, "OPTIMIZE": true, "REBUILD": true, "REPAIR": true, "CHECK": true,
+}
+
+// parseAlterTable handles ALTER TABLE. Actions that do not change the schema, such as
+// OWNER TO and ENABLE ROW LEVEL SECURITY, are ignored; actions that are not understood
+// are errors.
+func (p *dumpParser) parseAlterTable(c *cursor) error {
+	c.accept("IF", "EXISTS")
+	c.accept("ONLY")
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	table, err := p.table(schema, name)
+	if err != nil {
+		return err
+	}
+
+	for _, action := range splitTokens(c.rest()) {
+		if len(action) == 0 {
+			continue
+		}
+		if err := p.alterTable(p.schema(schema), table, &cursor{src: c.src, toks: action}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterTable applies one action of an ALTER TABLE statement
+func (p *dumpParser) alterTable(s *dumpSchema, table *dbo.Table, c *cursor) error {
+	switch {
+	case c.accept("ADD"):
+		if c.accept("COLUMN") || c.done() || !p.isTableConstraint(c.rest()) {
+			return p.addColumn(table, c)
+		}
+		name := ""
+		if c.accept("CONSTRAINT") {
+			name = c.next().name()
+		}
+		return p.parseTableConstraint(table, name, c)
+	case c.accept("DROP"):
+		return p.parseDropFromTable(table, c)
+	case c.accept("ALTER"):
+		if p.mysql && c.accept("INDEX") {
+			return p.alterIndexVisibility(table, c)
+		}
+		c.accept("COLUMN")
+		return p.parseAlterColumn(table, c)
+	case p.mysql && c.accept("MODIFY"):
+		c.accept("COLUMN")
+		return p.redefineColumn(table, c.peek().name(), c)
+	case p.mysql && c.accept("CHANGE"):
+		c.accept("COLUMN")
+		return p.redefineColumn(table, c.next().name(), c)
+	case c.accept("RENAME"):
+		return p.parseRenameInTable(s, table, c)
+	case c.accept("SET", "SCHEMA"):
+		return p.moveTable(s, table, p.schema(c.next().name()), table.Name())
+	case c.accept("COMMENT"):
+		// MySQL table option
+		c.acceptSymbol("=")
+		table.SetComment(c.next().name())
+	case c.accept("ENGINE"):
+		c.acceptSymbol("=")
+		table.SetEngine(c.next().name())
+	case c.accept("ROW_FORMAT"):
+		c.acceptSymbol("=")
+		table.SetRowFormat(strings.ToUpper(c.next().name()))
+	case ignoredAlterActions[strings.ToUpper(c.peek().text)]:
+	default:
+		// Actions of the other dialect land here too, rather than being misread
+		return fmt.Errorf("unsupported ALTER TABLE action %s", c.peek().text)
+	}
+	return nil
+}
+
+// addColumn handles ADD COLUMN. The column goes last, or where a MySQL FIRST or AFTER
+// clause puts it.
+func (p *dumpParser) addColumn(table *dbo.Table, c *cursor) error {
+	ifNotExists := c.accept("IF", "NOT", "EXISTS")
+	if c.done() {
+		return fmt.Errorf("missing column in %s", table.Name())
+	}
+	name := c.peek().name()
+	if _, ok := table.Columns()[name]; ok {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("column %s.%s already exists", table.Name(), name)
+	}
+
+	placement := p.cutPlacement(c)
+	position := p.droppedPositions[table] + 1
+	for _, col := range table.Columns() {
+		position = max(position, col.OrdinalPosition()+1)
+	}
+	if err := p.parseColumn(table, c, position); err != nil {
+		return err
+	}
+	if p.mysql {
+		inheritCharset(table)
+	}
+	p.placeColumn(table, table.Columns()[name], placement)
+	return nil
+}
+
+// cutPlacement removes a trailing MySQL FIRST or AFTER clause from a column definition,
+// where it would otherwise be read as part of the default expression
+func (p *dumpParser) cutPlacement(c *cursor) columnPlacement {
+	n := len(c.toks)
+	switch {
+	case !p.mysql:
+	case n > c.pos && c.toks[n-1].is("FIRST"):
+		c.toks = c.toks[:n-1]
+		return columnPlacement{first: true}
+	case n > c.pos+1 && c.toks[n-2].is("AFTER"):
+		after := c.toks[n-1].name()
+		c.toks = c.toks[:n-2]
+		return columnPlacement{after: after}
+	}
+	return columnPlacement{}
+}
+
+// placeColumn moves a column to the place a FIRST or AFTER clause names and renumbers
+// the columns of the table
+func (p *dumpParser) placeColumn(table *dbo.Table, col *dbo.Column, placement columnPlacement) {
+	if !placement.first && placement.after == "" {
+		return
+	}
+	columns := slices.DeleteFunc(orderedColumns(table), func(other *dbo.Column) bool { return other == col })
+	at := 0
+	if placement.after != "" {
+		at = len(columns)
+		if i := slices.IndexFunc(columns, func(other *dbo.Column) bool { return other.Name() == placement.after }); i >= 0 {
+			at = i + 1
+		}
+	}
+	columns = slices.Insert(columns, at, col)
+	for i, other := range columns {
+		other.SetOrdinalPosition(i + 1)
+	}
+}
+
+// orderedColumns returns the columns of a table in ordinal order
+func orderedColumns(table *dbo.Table) []*dbo.Column {
+	columns := make([]*dbo.Column, 0, len(table.Columns()))
+	for _, col := range table.Columns() {
+		columns = append(columns, col)
+	}
+	slices.SortFunc(columns, func(a, b *dbo.Column) int { return a.OrdinalPosition() - b.OrdinalPosition() })
+	return columns
+}
+
+// parseAlterColumn handles ALTER COLUMN: defaults, nullability and data types
+func (p *dumpParser) parseAlterColumn(table *dbo.Table, c *cursor) error {
+	name := c.next().name()
+	col, ok := table.Columns()[name]
+	if !ok {
+		return fmt.Errorf("unknown column %s.%s", table.Name(), name)
+	}
+
+	switch {
+	case c.accept("SET", "DEFAULT"):
+		setDefault(col, p.defaultExpression(c.src, c.rest()))
+	case c.accept("DROP", "DEFAULT"):
+		col.ClearDefaultValue()
+	case c.accept("SET", "NOT", "NULL"):
+		col.SetNullable(false)
+	case c.accept("DROP", "NOT", "NULL"):
+		col.SetNullable(true)
+	case c.accept("SET", "INVISIBLE"):
+		col.SetInvisible(true)
+	case c.accept("SET", "VISIBLE"):
+		col.SetInvisible(false)
+	case c.accept("SET", "DATA", "TYPE"), c.accept("TYPE"):
+		start := c.pos
+		for !c.done() && !c.peek().is("USING") && !c.peek().is("COLLATE") {
+			c.next()
+		}
+		dataType, length, precision, scale := parseDataType(c.toks[start:c.pos], p.mysql)
+		col.SetDataType(dataType)
+		setTypeModifiers(col, length, precision, scale)
+	}
+	// Identity, statistics and storage changes are not mapped
+	return nil
+}
+
+// alterIndexVisibility handles MySQL ALTER INDEX ... VISIBLE and INVISIBLE
+func (p *dumpParser) alterIndexVisibility(table *dbo.Table, c *cursor) error {
+	name := c.next().name()
+	for _, idx := range table.Indexes() {
+		if idx.Name() == name {
+			idx.SetInvisible(c.accept("INVISIBLE"))
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown index %s.%s", table.Name(), name)
+}
+
+// setDefault sets a column default as defaultExpression returns it, where "" is no default
+func setDefault(col *dbo.Column, value string) {
+	if value == "" {
+		col.ClearDefaultValue()
+		return
+	}
+	col.SetDefaultValue(value)
+}
+
+// redefineColumn handles MySQL MODIFY and CHANGE, which replace the definition of a
+// column and, for CHANGE, its name. The column object is kept, so the keys and indexes
+// that use it follow the change.
+func (p *dumpParser) redefineColumn(table *dbo.Table, name string, c *cursor) error {
+	col, ok := table.Columns()[name]
+	if !ok {
+		return fmt.Errorf("unknown column %s.%s", table.Name(), name)
+	}
+
+	placement := p.cutPlacement(c)
+	scratch := dbo.NewTable(table.Name(), nil)
+	if err := p.parseColumn(scratch, c, col.OrdinalPosition()); err != nil {
+		return err
+	}
+	var def *dbo.Column
+	for _, scratchCol := range scratch.Columns() {
+		def = scratchCol
+	}
+
+	if def.Name() != name {
+		if _, ok := table.Columns()[def.Name()]; ok {
+			return fmt.Errorf("column %s.%s already exists", table.Name(), def.Name())
+		}
+		table.RenameColumn(name, def.Name())
+	}
+	col.SetDataType(def.DataType())
+	if length := def.CharMaxLength(); length != nil {
+		col.SetCharMaxLength(*length)
+	}
+	if precision := def.NumericPrecision(); precision != nil {
+		col.SetNumericPrecision(*precision)
+		col.SetNumericScale(*def.NumericScale())
+	}
+	col.SetNullable(def.IsNullable())
+	if value := def.DefaultValue(); value != nil {
+		col.SetDefaultValue(*value)
+	} else {
+		col.ClearDefaultValue()
+	}
+	col.SetComment(def.Comment())
+	col.SetGenerated(def.Generation(), def.GenerationExpression())
+	col.SetInvisible(def.IsInvisible())
+	// A redefined column without a character set takes the table's again
+	col.SetCharset(def.Charset())
+	col.SetCollation(def.Collation())
+	if p.mysql {
+		inheritCharset(table)
+	}
+	p.placeColumn(table, col, placement)
+
+	// Inline PRIMARY KEY and UNIQUE attributes add keys to the column
+	if scratch.PrimaryKey() != nil {
+		if err := p.addPrimaryKey(table, "", keyList{columns: []*dbo.Column{col}}); err != nil {
+			return err
+		}
+	}
+	for _, constraint := range scratch.Constraints() {
+		if constraint.Type() == dbo.ConstraintTypeUnique {
+			if err := p.addUnique(table, constraint.Name(), keyList{columns: []*dbo.Column{col}}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseDropFromTable handles the DROP actions of ALTER TABLE
+func (p *dumpParser) parseDropFromTable(table *dbo.Table, c *cursor) error {
+	kind := "column"
+	var drop func(name string) bool
+	switch {
+	case c.accept("PRIMARY", "KEY"):
+		p.dropPrimaryKey(table)
+		return nil
+	case c.accept("CONSTRAINT"), p.mysql && c.accept("CHECK"):
+		kind = "constraint"
+		drop = func(name string) bool { return p.dropConstraint(table, name) }
+	case c.accept("FOREIGN", "KEY"):
+		kind = "foreign key"
+		drop = table.RemoveForeignKey
+	case c.accept("INDEX"), c.accept("KEY"):
+		kind = "index"
+		drop = func(name string) bool {
+			// MySQL unique keys are constraints as well as indexes
+			table.RemoveConstraint(name)
+			return table.RemoveIndex(name)
+		}
+	default:
+		c.accept("COLUMN")
+		drop = func(name string) bool { return p.dropColumn(table, name) }
+	}
+
+	ifExists := c.accept("IF", "EXISTS")
+	name := c.next().name()
+	if !drop(name) && !ifExists {
+		return fmt.Errorf("unknown %s %s.%s", kind, table.Name(), name)
+	}
+	return nil
+}
+
+// dropPrimaryKey drops the primary key of a table and the index backing it
+func (p *dumpParser) dropPrimaryKey(table *dbo.Table) {
+	pk := table.PrimaryKey()
+	if pk == nil {
+		return
+	}
+	table.SetPrimaryKey(nil)
+	for _, idx := range table.Indexes() {
+		if idx.IsPrimary() {
+			table.RemoveIndex(idx.Name())
+			break
+		}
+	}
+}
+
+// dropConstraint drops the key or constraint with the given name, together with the
+// index backing it, and reports whether the table had one
+func (p *dumpParser) dropConstraint(table *dbo.Table, name string) bool {
+	found := false
+	if pk := table.PrimaryKey(); pk != nil && pk.Name() == name {
+		p.dropPrimaryKey(table)
+		found = true
+	}
+	if table.RemoveForeignKey(name) {
+		found = true
+	}
+	if table.RemoveConstraint(name) {
+		table.RemoveIndex(name)
+		found = true
+	}
+	return found
+}
+
+// dropColumn drops a column and what depends on it, and reports whether the table had
+// it. PostgreSQL drops every key, index and constraint that uses the column and does
+// not reuse its position. MySQL removes the column from multi-column keys and indexes
+// instead, drops them once no column is left, and renumbers the remaining columns.
+// Foreign keys of other tables that reference the column are dropped too.
+func (p *dumpParser) dropColumn(table *dbo.Table, name string) bool {
+	col, ok := table.Columns()[name]
+	if !ok {
+		return false
+	}
+	uses := func(columns []*dbo.Column) bool { return slices.Contains(columns, col) }
+	without := func(columns []*dbo.Column) []*dbo.Column {
+		return slices.DeleteFunc(slices.Clone(columns), func(other *dbo.Column) bool { return other == col })
+	}
+
+	if pk := table.PrimaryKey(); pk != nil && uses(pk.Columns()) {
+		if rest := without(pk.Columns()); p.mysql && len(rest) > 0 {
+			table.SetPrimaryKey(dbo.NewPrimaryKey(pk.Name(), table, rest))
+		} else {
+			table.SetPrimaryKey(nil)
+		}
+	}
+	for _, idx := range slices.Clone(table.Indexes()) {
+		if !uses(idx.Columns()) {
+			continue
+		}
+		table.RemoveIndex(idx.Name())
+		if rest := without(idx.Columns()); p.mysql && len(rest)+len(idx.Expressions()) > 0 {
+			narrowed := dbo.NewIndex(idx.Name(), table, nil, idx.IsUnique())
+			narrowed.SetPrimary(idx.IsPrimary())
+			narrowed.SetIndexType(idx.IndexType())
+			narrowed.SetPredicate(idx.Predicate())
+			narrowed.SetInvisible(idx.IsInvisible())
+			for i, other := range idx.Columns() {
+				switch {
+				case other == col:
+				case idx.PrefixLength(i) > 0:
+					narrowed.AddPrefixColumn(other, idx.PrefixLength(i))
+				default:
+					narrowed.AddColumn(other)
+				}
+			}
+			for _, expression := range idx.Expressions() {
+				narrowed.AddExpression(expression)
+			}
+			table.AddIndex(narrowed)
+		}
+	}
+	for _, constraint := range slices.Clone(table.Constraints()) {
+		if !uses(constraint.Columns()) {
+			continue
+		}
+		table.RemoveConstraint(constraint.Name())
+		if rest := without(constraint.Columns()); p.mysql && constraint.Type() == dbo.ConstraintTypeUnique && len(rest) > 0 {
+			narrowed := dbo.NewConstraint(constraint.Name(), constraint.Type())
+			for _, other := range rest {
+				narrowed.AddColumn(other)
+			}
+			table.AddConstraint(narrowed)
+		}
+	}
+	for _, s := range p.schemas {
+		for _, other := range s.tables {
+			for _, fk := range slices.Clone(other.ForeignKeys()) {
+				if other == table && uses(fk.Columns()) || uses(fk.ReferencedColumns()) {
+					other.RemoveForeignKey(fk.Name())
+				}
+			}
+		}
+	}
+
+	table.RemoveColumn(name)
+	if p.mysql {
+		for i, other := range orderedColumns(table) {
+			other.SetOrdinalPosition(i + 1)
+		}
+	} else {
+		p.droppedPositions[table] = max(p.droppedPositions[table], col.OrdinalPosition())
+	}
+	return true
+}
+
+// parseRenameInTable handles the RENAME actions of ALTER TABLE. MySQL spells a table
+// rename without TO and a column rename only with COLUMN; PostgreSQL the other way round.
+func (p *dumpParser) parseRenameInTable(s *dumpSchema, table *dbo.Table, c *cursor) error {
+	switch {
+	case c.accept("CONSTRAINT"), p.mysql && (c.accept("INDEX") || c.accept("KEY")):
+		oldName := c.next().name()
+		c.accept("TO")
+		newName := c.next().name()
+		if !p.renameKey(table, oldName, newName) {
+			return fmt.Errorf("unknown constraint %s.%s", table.Name(), oldName)
+		}
+	case c.accept("COLUMN"), !p.mysql && !c.peek().is("TO"):
+		oldName := c.next().name()
+		c.accept("TO")
+		newName := c.next().name()
+		if _, ok := table.Columns()[newName]; ok {
+			return fmt.Errorf("column %s.%s already exists", table.Name(), newName)
+		}
+		if !table.RenameColumn(oldName, newName) {
+			return fmt.Errorf("unknown column %s.%s", table.Name(), oldName)
+		}
+	default:
+		_ = c.accept("TO") || c.accept("AS")
+		schema, name, err := c.objectName()
+		if err != nil {
+			return err
+		}
+		target := s
+		if schema != "" {
+			target = p.schema(schema)
+		}
+		return p.moveTable(s, table, target, name)
+	}
+	return nil
+}
+
+// renameKey renames the primary key, foreign key, constraint and index with the given
+// name. PostgreSQL keeps a constraint and the index backing it named alike, and MySQL
+// keys are both at once. It reports whether the table had any of them.
+func (p *dumpParser) renameKey(table *dbo.Table, oldName, newName string) bool {
+	found := false
+	if pk := table.PrimaryKey(); pk != nil && pk.Name() == oldName {
+		table.SetPrimaryKey(dbo.NewPrimaryKey(newName, table, pk.Columns()))
+		found = true
+	}
+	for _, fk := range table.ForeignKeys() {
+		if fk.Name() == oldName {
+			fk.SetName(newName)
+			found = true
+		}
+	}
+	for _, constraint := range table.Constraints() {
+		if constraint.Name() == oldName {
+			constraint.SetName(newName)
+			found = true
+		}
+	}
+	for _, idx := range table.Indexes() {
+		if idx.Name() == oldName {
+			idx.SetName(newName)
+			found = true
+		}
+	}
+	return found
+}
+
+// moveTable renames a table or moves it to another schema, and points the foreign keys
+// that reference it at its new name
+func (p *dumpParser) moveTable(from *dumpSchema, table *dbo.Table, to *dumpSchema, name string) error {
+	if existing, ok := to.tables[name]; ok && existing != table {
+		return fmt.Errorf("table %s.%s already exists", to.name, name)
+	}
+
+	oldName := table.Name()
+	delete(from.tables, oldName)
+	table.SetName(name)
+	to.tables[name] = table
+	for _, fk := range p.foreignKeys() {
+		if fk.ReferencedSchema() == from.name && fk.ReferencedTable() == oldName {
+			fk.SetReferencedSchema(to.name)
+			fk.SetReferencedTable(name)
+		}
+	}
+	return nil
+}
+
+// parseRenameTable handles the MySQL RENAME TABLE a TO b, c TO d statement
+func (p *dumpParser) parseRenameTable(c *cursor) error {
+	for !c.done() {
+		schema, name, err := c.objectName()
+		if err != nil {
+			return err
+		}
+		table, err := p.table(schema, name)
+		if err != nil {
+			return err
+		}
+		if !c.accept("TO") {
+			return fmt.Errorf("missing TO in rename of %s", name)
+		}
+		newSchema, newName, err := c.objectName()
+		if err != nil {
+			return err
+		}
+		if newSchema == "" {
+			newSchema = schema
+		}
+		if err := p.moveTable(p.schema(schema), table, p.schema(newSchema), newName); err != nil {
+			return err
+		}
+		if !c.acceptSymbol(",") {
+			break
+		}
+	}
+	return nil
+}
+
+// findIndex returns the table of the index with the given name in a schema, or nil
+func (p *dumpParser) findIndex(s *dumpSchema, name string) *dbo.Table {
+	for _, table := range s.tables {
+		for _, idx := range table.Indexes() {
+			if idx.Name() == name {
+				return table
+			}
+		}
+	}
+	return nil
+}
+
+// parseAlterIndex handles ALTER INDEX ... RENAME TO. Renaming the index of a key or
+// constraint renames the key or constraint as well.
+func (p *dumpParser) parseAlterIndex(c *cursor) error {
+	ifExists := c.accept("IF", "EXISTS")
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	if !c.accept("RENAME", "TO") {
+		return nil
+	}
+	table := p.findIndex(p.schema(schema), name)
+	if table == nil {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("unknown index %s", name)
+	}
+	p.renameKey(table, name, c.next().name())
+	return nil
+}
+
+// parseAlterSequence handles ALTER SEQUENCE ... RENAME TO. Other changes to a sequence
+// are not mapped.
+func (p *dumpParser) parseAlterSequence(c *cursor) error {
+	ifExists := c.accept("IF", "EXISTS")
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	if !c.accept("RENAME", "TO") {
+		return nil
+	}
+	s := p.schema(schema)
+	seq, ok := s.sequences[name]
+	if !ok {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("unknown sequence %s", name)
+	}
+
+	newName := c.next().name()
+	renamed := dbo.NewSequence(newName, seq.StartValue(), seq.Increment())
+	renamed.SetMinValue(seq.MinValue())
+	renamed.SetMaxValue(seq.MaxValue())
+	renamed.SetCache(seq.Cache())
+	renamed.SetCycle(seq.Cycle())
+	delete(s.sequences, name)
+	s.sequences[newName] = renamed
+	return nil
+}
+
+// parseAlterView handles ALTER VIEW ... RENAME TO and the MySQL ALTER VIEW ... AS,
+// which replaces the query of a view
+func (p *dumpParser) parseAlterView(c *cursor) error {
+	ifExists := c.accept("IF", "EXISTS")
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	s := p.schema(schema)
+	view, ok := s.views[name]
+	if !ok {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("unknown view %s", name)
+	}
+
+	if c.peek().kind == tokenGroup {
+		c.next()
+	}
+	switch {
+	case c.accept("RENAME", "TO"):
+		newName := c.next().name()
+		delete(s.views, name)
+		s.views[newName] = dbo.NewView(newName, view.Definition())
+	case c.accept("AS"):
+		view.SetDefinition(sourceText(c.src, c.rest()))
+	}
+	return nil
+}
+
+// parseAlterType handles the ALTER TYPE statements that change an enum: ADD VALUE,
+// RENAME VALUE and RENAME TO. Other types are not mapped.
+func (p *dumpParser) parseAlterType(c *cursor) error {
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	s := p.schema(schema)
+	enum, ok := s.enums[name]
+	if !ok {
+		return nil
+	}
+
+	values := slices.Clone(enum.Values())
+	switch {
+	case c.accept("ADD", "VALUE"):
+		ifNotExists := c.accept("IF", "NOT", "EXISTS")
+		value := c.next().name()
+		if slices.Contains(values, value) {
+			if ifNotExists {
+				return nil
+			}
+			return fmt.Errorf("enum %s already has value %s", name, value)
+		}
+		at := len(values)
+		if before := c.accept("BEFORE"); before || c.accept("AFTER") {
+			neighbour := c.next().name()
+			i := slices.Index(values, neighbour)
+			if i < 0 {
+				return fmt.Errorf("enum %s has no value %s", name, neighbour)
+			}
+			at = i
+			if !before {
+				at = i + 1
+			}
+		}
+		values = slices.Insert(values, at, value)
+	case c.accept("RENAME", "VALUE"):
+		oldValue := c.next().name()
+		c.accept("TO")
+		i := slices.Index(values, oldValue)
+		if i < 0 {
+			return fmt.Errorf("enum %s has no value %s", name, oldValue)
+		}
+		values[i] = c.next().name()
+	case c.accept("RENAME", "TO"):
+		newName := c.next().name()
+		delete(s.enums, name)
+		s.enums[newName] = dbo.NewEnum(newName, values)
+		return nil
+	default:
+		return nil
+	}
+	s.enums[name] = dbo.NewEnum(name, values)
+	return nil
+}
+
+// parseDrop handles DROP statements. mysqldump creates a stand-in table for every view
+// and drops it again when it creates the view, and pg_dump --clean drops every object
+// before creating it, so dropping an object that was never created is not an error.
+func (p *dumpParser) parseDrop(c *cursor) error {
+	if c.accept("MATERIALIZED") {
+		// Materialized views are not mapped
+		return nil
+	}
+	kind := strings.ToUpper(c.next().text)
+	c.accept("CONCURRENTLY")
+	c.accept("IF", "EXISTS")
+
+	for !c.done() {
+		schema, name, err := c.objectName()
+		if err != nil {
+			return err
+		}
+		if c.peek().kind == tokenGroup {
+			// The argument types of a function
+			c.next()
+		}
+		if kind == "SCHEMA" || kind == "DATABASE" {
+			if _, ok := p.schemas[name]; ok && (kind == "SCHEMA" || p.mysql) {
+				delete(p.schemas, name)
+				p.schemaOrder = slices.DeleteFunc(p.schemaOrder, func(other string) bool { return other == name })
+			}
+			if !c.acceptSymbol(",") {
+				break
+			}
+			continue
+		}
+		s := p.schema(schema)
+
+		switch kind {
+		case "TABLE":
+			delete(s.tables, name)
+			for _, other := range p.schemas {
+				for _, table := range other.tables {
+					for _, fk := range slices.Clone(table.ForeignKeys()) {
+						if fk.ReferencedSchema() == s.name && fk.ReferencedTable() == name {
+							table.RemoveForeignKey(fk.Name())
+						}
+					}
+				}
+			}
+		case "VIEW":
+			delete(s.views, name)
+		case "SEQUENCE":
+			delete(s.sequences, name)
+		case "FUNCTION":
+			delete(s.functions, name)
+		case "PROCEDURE":
+			delete(s.procedures, name)
+		case "TYPE":
+			delete(s.enums, name)
+		case "INDEX":
+			table := p.findIndex(s, name)
+			if c.accept("ON") {
+				// MySQL names the table
+				tableSchema, tableName, err := c.objectName()
+				if err != nil {
+					return err
+				}
+				table = p.schema(tableSchema).tables[tableName]
+			}
+			if table != nil {
+				table.RemoveIndex(name)
+				table.RemoveConstraint(name)
+			}
+		case "TRIGGER":
+			if c.accept("ON") {
+				// PostgreSQL names the table
+				tableSchema, tableName, err := c.objectName()
+				if err != nil {
+					return err
+				}
+				if table, ok := p.schema(tableSchema).tables[tableName]; ok {
+					table.RemoveTrigger(name)
+				}
+				break
+			}
+			for _, table := range s.tables {
+				table.RemoveTrigger(name)
+			}
+		default:
+			return nil
+		}
+
+		if !c.acceptSymbol(",") {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package sqldump
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// columnOrder returns the column names of a table in ordinal order
+func columnOrder(table *dbo.Table) []string {
+	var names []string
+	for _, col := range orderedColumns(table) {
+		names = append(names, col.Name())
+	}
+	return names
+}
+
+func TestAlter_Postgres(t *testing.T) {
+	db, errs := mapDump(t, "app.sql", `
+CREATE TABLE customers (id integer PRIMARY KEY, mail varchar(40), legacy text);
+CREATE TABLE orders (
+    id integer PRIMARY KEY,
+    customer_id integer REFERENCES customers (id),
+    note text,
+    total numeric(10,2)
+);
+CREATE INDEX orders_note_idx ON orders (note);
+CREATE TYPE mood AS ENUM ('sad', 'happy');
+
+ALTER TABLE customers RENAME COLUMN mail TO email;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS email varchar(80);
+ALTER TABLE customers ALTER COLUMN email SET NOT NULL, ALTER COLUMN email TYPE varchar(120);
+ALTER TABLE customers ADD CONSTRAINT customers_email_key UNIQUE (email);
+ALTER TABLE customers RENAME CONSTRAINT customers_email_key TO customers_email_uq;
+ALTER TABLE customers DROP COLUMN legacy;
+ALTER TABLE customers ADD COLUMN created_at timestamp DEFAULT now();
+ALTER TABLE customers RENAME TO clients;
+ALTER TABLE orders DROP COLUMN note, ALTER COLUMN total DROP DEFAULT;
+ALTER TABLE orders ALTER COLUMN total SET DEFAULT 0;
+ALTER INDEX orders_pkey RENAME TO orders_pk;
+ALTER TYPE mood ADD VALUE 'meh' BEFORE 'happy';
+ALTER TYPE mood RENAME VALUE 'sad' TO 'blue';
+CREATE TABLE IF NOT EXISTS orders (other integer);
+DROP TYPE IF EXISTS missing;
+`)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	public := db.Schemas()["public"]
+	if public.Tables()["customers"] != nil {
+		t.Error("expected customers to be renamed")
+	}
+	clients := public.Tables()["clients"]
+	if clients == nil {
+		t.Fatal("expected clients table")
+	}
+	if got, want := columnOrder(clients), []string{"id", "email", "created_at"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %v, want %v", got, want)
+	}
+	if positions := []int{clients.Columns()["email"].OrdinalPosition(), clients.Columns()["created_at"].OrdinalPosition()}; !reflect.DeepEqual(positions, []int{2, 4}) {
+		t.Errorf("expected PostgreSQL to keep the gap of the dropped column, got positions %v", positions)
+	}
+	email := clients.Columns()["email"]
+	if email.DataType() != "varchar" || intValue(email.CharMaxLength()) != 120 || email.IsNullable() {
+		t.Errorf("email = %s(%d) nullable=%v, want varchar(120) NOT NULL", email.DataType(), intValue(email.CharMaxLength()), email.IsNullable())
+	}
+	if len(clients.Constraints()) != 1 || clients.Constraints()[0].Name() != "customers_email_uq" || clients.Constraints()[0].Columns()[0] != email {
+		t.Errorf("expected the renamed unique constraint on email, got %v", clients.Constraints())
+	}
+	if stringValue(clients.Columns()["created_at"].DefaultValue()) != "now()" {
+		t.Errorf("expected created_at default now(), got %q", stringValue(clients.Columns()["created_at"].DefaultValue()))
+	}
+
+	orders := public.Tables()["orders"]
+	if len(orders.Columns()) != 3 {
+		t.Errorf("expected CREATE TABLE IF NOT EXISTS to keep orders, got columns %v", columnOrder(orders))
+	}
+	fk := orders.ForeignKeys()[0]
+	if fk.ReferencedTable() != "clients" || fk.ReferencedColumns()[0] != clients.Columns()["id"] {
+		t.Errorf("expected the foreign key to follow the rename, got %s", fk.ReferencedTable())
+	}
+	if stringValue(orders.Columns()["total"].DefaultValue()) != "0" {
+		t.Errorf("expected total default 0, got %q", stringValue(orders.Columns()["total"].DefaultValue()))
+	}
+	var indexes []string
+	for _, idx := range orders.Indexes() {
+		indexes = append(indexes, idx.Name())
+	}
+	if !reflect.DeepEqual(indexes, []string{"orders_pk"}) {
+		t.Errorf("expected the index on the dropped column to go, got %v", indexes)
+	}
+	if orders.PrimaryKey().Name() != "orders_pk" {
+		t.Errorf("expected renaming the index to rename the key, got %s", orders.PrimaryKey().Name())
+	}
+
+	if got := public.Enums()["mood"].Values(); !reflect.DeepEqual(got, []string{"blue", "meh", "happy"}) {
+		t.Errorf("enum values = %v", got)
+	}
+}
+
+func TestAlter_PostgresDrops(t *testing.T) {
+	db, errs := mapDump(t, "app.sql", `
+CREATE SCHEMA audit;
+CREATE TABLE audit.log (id integer);
+CREATE TABLE parents (id integer PRIMARY KEY);
+CREATE TABLE children (id integer, parent_id integer, CONSTRAINT children_parent_fkey FOREIGN KEY (parent_id) REFERENCES parents (id));
+CREATE FUNCTION touch() RETURNS trigger LANGUAGE plpgsql AS $$ BEGIN RETURN NEW; END $$;
+CREATE TRIGGER children_touch BEFORE UPDATE ON children FOR EACH ROW EXECUTE FUNCTION touch();
+CREATE SEQUENCE counter;
+CREATE VIEW parent_ids AS SELECT id FROM parents;
+
+ALTER TABLE parents DROP COLUMN id CASCADE;
+DROP TRIGGER children_touch ON children;
+DROP FUNCTION touch();
+DROP SEQUENCE counter;
+DROP VIEW parent_ids;
+DROP SCHEMA audit CASCADE;
+ALTER TABLE children DROP CONSTRAINT IF EXISTS missing;
+`)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if db.Schemas()["audit"] != nil {
+		t.Error("expected audit schema to be dropped")
+	}
+	public := db.Schemas()["public"]
+	parents := public.Tables()["parents"]
+	if parents.PrimaryKey() != nil || len(parents.Indexes()) != 0 {
+		t.Error("expected the primary key of the dropped column to go")
+	}
+	children := public.Tables()["children"]
+	if len(children.ForeignKeys()) != 0 {
+		t.Error("expected the foreign key referencing the dropped column to go")
+	}
+	if len(children.Triggers()) != 0 || len(public.Functions()) != 0 || len(public.Sequences()) != 0 || len(public.Views()) != 0 {
+		t.Error("expected the trigger, function, sequence and view to be dropped")
+	}
+}
+
+func TestAlter_MySQL(t *testing.T) {
+	db, errs := mapDump(t, "shop.sql", "CREATE TABLE `items` (\n"+
+		"  `id` int NOT NULL,\n"+
+		"  `sku` varchar(20) NOT NULL,\n"+
+		"  `name` varchar(50) DEFAULT NULL,\n"+
+		"  `size` int DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`id`),\n"+
+		"  KEY `idx_sku_size` (`sku`,`size`)\n"+
+		") ENGINE=InnoDB;\n"+
+		"ALTER TABLE `items` ADD COLUMN `code` char(3) DEFAULT 'abc' AFTER `id`;\n"+
+		"ALTER TABLE `items` CHANGE COLUMN `name` `title` varchar(100) NOT NULL DEFAULT 'none';\n"+
+		"ALTER TABLE `items` MODIFY `sku` varchar(30) NOT NULL FIRST;\n"+
+		"ALTER TABLE `items` DROP COLUMN `size`, ADD UNIQUE KEY `uq_title` (`title`);\n"+
+		"ALTER TABLE `items` RENAME INDEX `uq_title` TO `uq_item_title`;\n"+
+		"ALTER TABLE `items` COMMENT = 'catalogue';\n"+
+		"RENAME TABLE `items` TO `products`;\n"+
+		"ALTER TABLE `products` DROP INDEX `uq_item_title`;\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	products := db.Schemas()["shop"].Tables()["products"]
+	if products == nil {
+		t.Fatal("expected products table")
+	}
+	if got, want := columnOrder(products), []string{"sku", "id", "code", "title"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %v, want %v", got, want)
+	}
+	for i, name := range []string{"sku", "id", "code", "title"} {
+		if pos := products.Columns()[name].OrdinalPosition(); pos != i+1 {
+			t.Errorf("expected %s at position %d, got %d", name, i+1, pos)
+		}
+	}
+
+	title := products.Columns()["title"]
+	if intValue(title.CharMaxLength()) != 100 || title.IsNullable() || stringValue(title.DefaultValue()) != "none" {
+		t.Errorf("title = varchar(%d) nullable=%v default=%q", intValue(title.CharMaxLength()), title.IsNullable(), stringValue(title.DefaultValue()))
+	}
+	if intValue(products.Columns()["sku"].CharMaxLength()) != 30 {
+		t.Errorf("expected sku varchar(30), got %d", intValue(products.Columns()["sku"].CharMaxLength()))
+	}
+	if stringValue(products.Columns()["code"].DefaultValue()) != "abc" {
+		t.Errorf("expected code default abc, got %q", stringValue(products.Columns()["code"].DefaultValue()))
+	}
+
+	var indexes []string
+	for _, idx := range products.Indexes() {
+		indexes = append(indexes, idx.Name())
+		if idx.Name() == "idx_sku_size" && (len(idx.Columns()) != 1 || idx.Columns()[0].Name() != "sku") {
+			t.Errorf("expected idx_sku_size narrowed to sku, got %d columns", len(idx.Columns()))
+		}
+	}
+	slices.Sort(indexes)
+	if !reflect.DeepEqual(indexes, []string{"PRIMARY", "idx_sku_size"}) {
+		t.Errorf("indexes = %v", indexes)
+	}
+	if len(products.Constraints()) != 0 {
+		t.Errorf("expected the dropped unique key to go, got %v", products.Constraints())
+	}
+	if products.Comment() != "catalogue" {
+		t.Errorf("expected comment 'catalogue', got %q", products.Comment())
+	}
+}
+
+func TestAlter_ReportsUnknownObjects(t *testing.T) {
+	_, errs := mapDump(t, "app.sql", `
+CREATE TABLE t (a integer);
+ALTER TABLE t DROP COLUMN missing;
+ALTER TABLE t RENAME COLUMN missing TO other;
+ALTER TABLE t ADD COLUMN a integer;
+ALTER TABLE t DROP CONSTRAINT missing;
+`)
+	if len(errs) != 4 {
+		t.Errorf("expected 4 errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestAlter_UnsupportedAction(t *testing.T) {
+	_, errs := mapDump(t, "app.sql", `-- PostgreSQL database dump
+CREATE TABLE users (id integer, email text);
+ALTER TABLE users OWNER TO app;
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users MODIFY email varchar(200);
+`)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "line 5: unsupported ALTER TABLE action MODIFY") {
+		t.Errorf("expected MODIFY to be unsupported in a PostgreSQL dump, got %v", errs)
+	}
+}
+
+func TestAlter_PrimaryKeyColumnsAreNotNull(t *testing.T) {
+	db, errs := mapDump(t, "app.sql", `
+CREATE TABLE employee_department (employee_id integer, department_id integer, PRIMARY KEY (employee_id, department_id));
+CREATE TABLE users (id integer, email text);
+ALTER TABLE users ADD PRIMARY KEY (id);
+`)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	public := db.Schemas()["public"]
+	for _, col := range []*dbo.Column{
+		public.Tables()["employee_department"].Columns()["employee_id"],
+		public.Tables()["employee_department"].Columns()["department_id"],
+		public.Tables()["users"].Columns()["id"],
+	} {
+		if col.IsNullable() {
+			t.Errorf("expected primary key column %s to be NOT NULL", col.Name())
+		}
+	}
+	if !public.Tables()["users"].Columns()["email"].IsNullable() {
+		t.Error("expected email to stay nullable")
+	}
+}
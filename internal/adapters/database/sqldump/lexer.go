@@ -0,0 +1,371 @@
+package sqldump
+
+import (
+	"strings"
+)
+
+// statement is one SQL statement of a dump with its comments removed. psql
+// meta-commands such as \connect are returned as statements of their own.
+type statement struct {
+	text string
+	// line is the line of the dump the statement starts on
+	line int
+}
+
+// splitStatements splits a dump into statements. It follows the quoting rules of the
+// dialect, MySQL DELIMITER commands and the content of MySQL version comments
+// (/*!40101 ... */), which is kept, and skips the data of COPY ... FROM stdin blocks.
+func splitStatements(dump string, mysql bool) []statement {
+	var statements []statement
+	var sb strings.Builder
+	delimiter := ";"
+	line, startLine := 1, 0
+	conditional := 0
+	atLineStart := true
+
+	write := func(s string) {
+		if startLine == 0 && strings.TrimSpace(s) != "" {
+			startLine = line
+		}
+		sb.WriteString(s)
+		line += strings.Count(s, "\n")
+	}
+	flush := func() {
+		if text := strings.TrimSpace(sb.String()); text != "" {
+			statements = append(statements, statement{text: text, line: startLine})
+		}
+		sb.Reset()
+		startLine = 0
+	}
+
+	for i := 0; i < len(dump); {
+		if atLineStart {
+			atLineStart = false
+			end := strings.IndexByte(dump[i:], '\n')
+			if end < 0 {
+				end = len(dump) - i
+			}
+			text := strings.TrimSpace(dump[i : i+end])
+			pending := strings.TrimSpace(sb.String()) != ""
+			if mysql && !pending && len(text) > len("DELIMITER ") && strings.EqualFold(text[:len("DELIMITER ")], "DELIMITER ") {
+				delimiter = strings.TrimSpace(text[len("DELIMITER "):])
+				i += end
+				continue
+			}
+			if !mysql && !pending && strings.HasPrefix(text, "\\") {
+				statements = append(statements, statement{text: text, line: line})
+				i += end
+				continue
+			}
+		}
+
+		c := dump[i]
+		switch {
+		case c == '\n':
+			write("\n")
+			atLineStart = true
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			end := quotedEnd(dump, i, mysql)
+			write(dump[i:end])
+			i = end
+		case !mysql && c == '$' && dollarTag(dump[i:]) != "":
+			tag := dollarTag(dump[i:])
+			end := len(dump)
+			if close := strings.Index(dump[i+len(tag):], tag); close >= 0 {
+				end = i + len(tag) + close + len(tag)
+			}
+			write(dump[i:end])
+			i = end
+		case strings.HasPrefix(dump[i:], "--") || mysql && c == '#':
+			if end := strings.IndexByte(dump[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(dump)
+			}
+		case mysql && strings.HasPrefix(dump[i:], "/*!"):
+			i += len("/*!")
+			for i < len(dump) && dump[i] >= '0' && dump[i] <= '9' {
+				i++
+			}
+			conditional++
+		case strings.HasPrefix(dump[i:], "/*"):
+			end := len(dump)
+			if close := strings.Index(dump[i+2:], "*/"); close >= 0 {
+				end = i + 2 + close + 2
+			}
+			// Keep the line count and the separation between the surrounding tokens
+			write(" " + strings.Repeat("\n", strings.Count(dump[i:end], "\n")))
+			i = end
+		case conditional > 0 && strings.HasPrefix(dump[i:], "*/"):
+			conditional--
+			i += 2
+		case strings.HasPrefix(dump[i:], delimiter):
+			flush()
+			i += len(delimiter)
+			if last := len(statements) - 1; !mysql && last >= 0 && isCopyFromStdin(statements[last].text) {
+				// The rows follow the statement up to a line holding only \.
+				end := strings.Index(dump[i:], "\n\\.")
+				if end < 0 {
+					end = len(dump) - i
+				}
+				line += strings.Count(dump[i:i+end], "\n")
+				i += end
+				if i < len(dump) {
+					i += len("\n\\.")
+					line++
+				}
+			}
+		default:
+			write(dump[i : i+1])
+			i++
+		}
+	}
+	flush()
+	return statements
+}
+
+// isCopyFromStdin reports whether a statement is a COPY whose data follows it in the dump
+func isCopyFromStdin(text string) bool {
+	upper := strings.ToUpper(text)
+	return strings.HasPrefix(upper, "COPY ") && strings.HasSuffix(upper, "FROM STDIN")
+}
+
+// quotedEnd returns the index just past the quoted string or identifier starting at i.
+// Doubled quotes are escaped quotes; MySQL strings also use backslash escapes.
+func quotedEnd(s string, i int, mysql bool) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case mysql && quote != '`' && s[j] == '\\':
+			j++
+		case s[j] == quote:
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// dollarTag returns the PostgreSQL dollar quote ($$ or $tag$) s starts with, or ""
+func dollarTag(s string) string {
+	if len(s) < 2 || s[0] != '$' {
+		return ""
+	}
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '$':
+			return s[:j+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80:
+		case c >= '0' && c <= '9' && j > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// tokenKind classifies the tokens of a statement
+type tokenKind int
+
+const (
+	// tokenWord is a keyword, an unquoted identifier or a number
+	tokenWord tokenKind = iota
+	// tokenIdent is a quoted identifier
+	tokenIdent
+	// tokenString is a string literal, including PostgreSQL dollar-quoted strings
+	tokenString
+	// tokenGroup is a parenthesized group, parentheses included
+	tokenGroup
+	// tokenSymbol is any other character
+	tokenSymbol
+)
+
+// token is a lexical element of a statement. start and end are byte offsets into the
+// statement text.
+type token struct {
+	kind       tokenKind
+	text       string
+	start, end int
+	mysql      bool
+}
+
+// tokenize splits a statement into tokens. Parenthesized groups are kept as single
+// tokens; tokenize them again to look inside.
+func tokenize(s string, mysql bool) []token {
+	var tokens []token
+	add := func(kind tokenKind, start, end int) {
+		tokens = append(tokens, token{kind: kind, text: s[start:end], start: start, end: end, mysql: mysql})
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'':
+			end := quotedEnd(s, i, mysql)
+			add(tokenString, i, end)
+			i = end
+		case c == '`' || c == '"' && !mysql:
+			end := quotedEnd(s, i, mysql)
+			add(tokenIdent, i, end)
+			i = end
+		case c == '"':
+			// Double quotes delimit strings in MySQL unless ANSI_QUOTES is set
+			end := quotedEnd(s, i, mysql)
+			add(tokenString, i, end)
+			i = end
+		case !mysql && c == '$' && dollarTag(s[i:]) != "":
+			tag := dollarTag(s[i:])
+			end := len(s)
+			if close := strings.Index(s[i+len(tag):], tag); close >= 0 {
+				end = i + len(tag) + close + len(tag)
+			}
+			add(tokenString, i, end)
+			i = end
+		case c == '(':
+			end := groupEnd(s, i, mysql)
+			add(tokenGroup, i, end)
+			i = end
+		case isWordByte(c):
+			end := i + 1
+			for end < len(s) && isWordByte(s[end]) {
+				end++
+			}
+			add(tokenWord, i, end)
+			i = end
+		default:
+			add(tokenSymbol, i, i+1)
+			i++
+		}
+	}
+	return tokens
+}
+
+// groupEnd returns the index just past the parenthesis closing the one at i
+func groupEnd(s string, i int, mysql bool) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '\'' || c == '"' || c == '`':
+			j = quotedEnd(s, j, mysql) - 1
+		case !mysql && c == '$' && dollarTag(s[j:]) != "":
+			tag := dollarTag(s[j:])
+			close := strings.Index(s[j+len(tag):], tag)
+			if close < 0 {
+				return len(s)
+			}
+			j += len(tag) + close + len(tag) - 1
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(s)
+}
+
+// isWordByte reports whether c can be part of a keyword, an unquoted identifier or a number
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// is reports whether the token is the given keyword
+func (t token) is(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+// name returns the identifier or string value of the token, unquoted
+func (t token) name() string {
+	switch t.kind {
+	case tokenIdent:
+		quote := t.text[:1]
+		return strings.ReplaceAll(strings.TrimSuffix(t.text[1:], quote), quote+quote, quote)
+	case tokenString:
+		return unquoteString(t.text, t.mysql)
+	}
+	return t.text
+}
+
+// inner returns the text between the parentheses of a group token
+func (t token) inner() string {
+	text := strings.TrimPrefix(t.text, "(")
+	return strings.TrimSuffix(text, ")")
+}
+
+// unquoteString returns the value of a string literal. Doubled quotes are unescaped, as
+// are backslash escapes in MySQL strings.
+func unquoteString(text string, mysql bool) string {
+	if tag := dollarTag(text); tag != "" {
+		return strings.TrimSuffix(strings.TrimPrefix(text, tag), tag)
+	}
+	if len(text) < 2 {
+		return text
+	}
+	quote := text[0]
+	body := text[1 : len(text)-1]
+	if !mysql {
+		return strings.ReplaceAll(body, string(quote)+string(quote), string(quote))
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body):
+			i++
+			switch body[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case '0':
+				sb.WriteByte(0)
+			case 'Z':
+				sb.WriteByte(0x1a)
+			default:
+				sb.WriteByte(body[i])
+			}
+		case c == quote && i+1 < len(body) && body[i+1] == quote:
+			sb.WriteByte(quote)
+			i++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// splitTokens splits tokens at the commas between them
+func splitTokens(tokens []token) [][]token {
+	var parts [][]token
+	start := 0
+	for i, t := range tokens {
+		if t.kind == tokenSymbol && t.text == "," {
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) || len(parts) > 0 {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// sourceText returns the statement text spanned by tokens
+func sourceText(src string, tokens []token) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return src[tokens[0].start:tokens[len(tokens)-1].end]
+}
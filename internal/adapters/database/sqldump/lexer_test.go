@@ -0,0 +1,82 @@
+package sqldump
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		dump  string
+		mysql bool
+		want  []statement
+	}{
+		{
+			name: "semicolons in strings, identifiers and comments",
+			dump: "SELECT 'a;b', \"c;d\"; -- e;f\n/* g;\nh */ SELECT 1;",
+			want: []statement{{"SELECT 'a;b', \"c;d\"", 1}, {"SELECT 1", 3}},
+		},
+		{
+			name: "dollar-quoted bodies",
+			dump: "CREATE FUNCTION f() AS $body$ SELECT 1; $body$;\nSELECT $1;",
+			want: []statement{{"CREATE FUNCTION f() AS $body$ SELECT 1; $body$", 1}, {"SELECT $1", 2}},
+		},
+		{
+			name: "psql meta-commands and COPY data",
+			dump: "\\connect shop\nCOPY t (a) FROM stdin;\n1;2\n\\.\nSELECT 1;",
+			want: []statement{{"\\connect shop", 1}, {"COPY t (a) FROM stdin", 2}, {"SELECT 1", 5}},
+		},
+		{
+			name:  "version comments are unwrapped",
+			dump:  "/*!40101 SET NAMES utf8mb4 */;\n/*!50001 CREATE VIEW `v` AS SELECT 1 */;",
+			mysql: true,
+			want:  []statement{{"SET NAMES utf8mb4", 1}, {"CREATE VIEW `v` AS SELECT 1", 2}},
+		},
+		{
+			name:  "DELIMITER and backslash escapes",
+			dump:  "DELIMITER ;;\nCREATE TRIGGER t BEFORE INSERT ON x FOR EACH ROW BEGIN SET NEW.a = 'it\\'s;'; END ;;\nDELIMITER ;\nSELECT 1;",
+			mysql: true,
+			want: []statement{
+				{"CREATE TRIGGER t BEFORE INSERT ON x FOR EACH ROW BEGIN SET NEW.a = 'it\\'s;'; END", 2},
+				{"SELECT 1", 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.dump, tt.mysql); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDataType(t *testing.T) {
+	tests := []struct {
+		declared                 string
+		mysql                    bool
+		dataType                 string
+		length, precision, scale int
+	}{
+		{"character varying(40)", false, "character varying", 40, 0, 0},
+		{"numeric(12,2)", false, "numeric", 0, 12, 2},
+		{"timestamp(3) with time zone", false, "timestamp with time zone", 0, 0, 0},
+		{"integer[]", false, "ARRAY", 0, 0, 0},
+		{"public.\"Mood\"", false, "public.Mood", 0, 0, 0},
+		{"int(11) unsigned zerofill", true, "int", 0, 0, 0},
+		{"VARCHAR(255)", true, "varchar", 255, 0, 0},
+		{"decimal(10,2)", true, "decimal", 0, 10, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declared, func(t *testing.T) {
+			dataType, length, precision, scale := parseDataType(tokenize(tt.declared, tt.mysql), tt.mysql)
+			if dataType != tt.dataType || length != tt.length || precision != tt.precision || scale != tt.scale {
+				t.Errorf("parseDataType(%q) = (%q, %d, %d, %d), want (%q, %d, %d, %d)", tt.declared,
+					dataType, length, precision, scale, tt.dataType, tt.length, tt.precision, tt.scale)
+			}
+		})
+	}
+}
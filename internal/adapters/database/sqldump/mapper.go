@@ -0,0 +1,800 @@
+package sqldump
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// pgDefaultSchema holds the unqualified objects of a PostgreSQL dump
+const pgDefaultSchema = "public"
+
+var (
+	// mysqlDumpHeaderPattern matches the header comment of mysqldump and mariadb-dump
+	mysqlDumpHeaderPattern = regexp.MustCompile(`(?m)^-- (MySQL|MariaDB) dump`)
+	// pgDumpHeaderPattern matches the header comment of pg_dump
+	pgDumpHeaderPattern = regexp.MustCompile(`(?m)^-- PostgreSQL database dump`)
+	// mysqlEnginePattern matches the ENGINE table option, which only MySQL has
+	mysqlEnginePattern = regexp.MustCompile(`(?i)\)\s*ENGINE\s*=`)
+	// mysqlSyntaxPattern matches other MySQL-only syntax: AUTO_INCREMENT, the MODIFY and
+	// CHANGE actions of ALTER TABLE, and columns added FIRST or AFTER another
+	mysqlSyntaxPattern = regexp.MustCompile(`(?i)\bAUTO_INCREMENT\b|` +
+		`\bALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?\S+\s+(?:MODIFY|CHANGE)\s|` +
+		`\bADD\s+(?:COLUMN\s+)?[^,;()]+\s(?:FIRST|AFTER\s+\S+)\s*[,;]`)
+	// mysqlDumpDatabasePattern matches the "-- Host: localhost    Database: shop" header
+	// line of a single-database mysqldump
+	mysqlDumpDatabasePattern = regexp.MustCompile(`(?m)^-- Host: .*\bDatabase: (\S+)`)
+)
+
+// MapDatabase parses the dump. Statements that cannot be parsed are reported as errors
+// and skipped, so one unusual statement does not hide the rest of the schema.
+func (a *SQLDumpAdapter) MapDatabase() (*dbo.Database, []error) {
+	mysql := isMySQLDump(a.dump)
+
+	// Dumps name their database only sometimes, so the file name is the fallback
+	p := newDumpParser(strings.TrimSuffix(filepath.Base(a.path), filepath.Ext(a.path)), mysql)
+	if m := mysqlDumpDatabasePattern.FindStringSubmatch(a.dump); mysql && m != nil {
+		p.setDatabase(m[1])
+	}
+
+	for _, stmt := range splitStatements(a.dump, mysql) {
+		if err := p.parseStatement(stmt.text); err != nil {
+			p.errors = append(p.errors, fmt.Errorf("failed to parse statement on line %d: %w", stmt.line, err))
+		}
+	}
+	return p.database(), p.errors
+}
+
+// isMySQLDump reports whether a dump was written by mysqldump rather than pg_dump. Dumps
+// without a header are recognised by MySQL-only syntax, such as backquoted names.
+func isMySQLDump(dump string) bool {
+	switch {
+	case mysqlDumpHeaderPattern.MatchString(dump):
+		return true
+	case pgDumpHeaderPattern.MatchString(dump):
+		return false
+	}
+	return strings.Contains(dump, "`") || mysqlEnginePattern.MatchString(dump) || mysqlSyntaxPattern.MatchString(dump)
+}
+
+// dumpSchema collects the objects of a schema while the dump is parsed
+type dumpSchema struct {
+	name       string
+	owner      string
+	tables     map[string]*dbo.Table
+	views      map[string]*dbo.View
+	sequences  map[string]*dbo.Sequence
+	functions  map[string]*dbo.Function
+	procedures map[string]*dbo.Procedure
+	enums      map[string]*dbo.Enum
+}
+
+// dumpParser builds a database from the statements of a dump. Objects are collected per
+// schema and assembled at the end, as statements later in the dump can still change the
+// owner of a schema or replace a table with a view.
+type dumpParser struct {
+	mysql         bool
+	name          string
+	named         bool
+	defaultSchema string
+	schemas       map[string]*dumpSchema
+	schemaOrder   []string
+	errors        []error
+	// droppedPositions holds the highest ordinal position of a column dropped from a
+	// PostgreSQL table, which is not reused
+	droppedPositions map[*dbo.Table]int
+}
+
+func newDumpParser(name string, mysql bool) *dumpParser {
+	p := &dumpParser{
+		mysql:            mysql,
+		name:             name,
+		defaultSchema:    pgDefaultSchema,
+		schemas:          make(map[string]*dumpSchema),
+		droppedPositions: make(map[*dbo.Table]int),
+	}
+	if mysql {
+		// MySQL schemas are databases, so unqualified names belong to the dumped database
+		p.defaultSchema = name
+	}
+	return p
+}
+
+// setDatabase names the database after the first database the dump mentions
+func (p *dumpParser) setDatabase(name string) {
+	if p.named {
+		return
+	}
+	p.name = name
+	p.named = true
+	if p.mysql {
+		p.defaultSchema = name
+	}
+}
+
+// schema returns the schema with the given name, creating it on first use. An empty
+// name stands for the default schema.
+func (p *dumpParser) schema(name string) *dumpSchema {
+	if name == "" {
+		name = p.defaultSchema
+	}
+	if s, ok := p.schemas[name]; ok {
+		return s
+	}
+	s := &dumpSchema{
+		name:       name,
+		tables:     make(map[string]*dbo.Table),
+		views:      make(map[string]*dbo.View),
+		sequences:  make(map[string]*dbo.Sequence),
+		functions:  make(map[string]*dbo.Function),
+		procedures: make(map[string]*dbo.Procedure),
+		enums:      make(map[string]*dbo.Enum),
+	}
+	p.schemas[name] = s
+	p.schemaOrder = append(p.schemaOrder, name)
+	return s
+}
+
+// table returns a table created earlier in the dump
+func (p *dumpParser) table(schema, name string) (*dbo.Table, error) {
+	s := p.schema(schema)
+	if table, ok := s.tables[name]; ok {
+		return table, nil
+	}
+	return nil, fmt.Errorf("unknown table %s.%s", s.name, name)
+}
+
+// database assembles the parsed objects
+func (p *dumpParser) database() *dbo.Database {
+	db := dbo.NewDatabase(p.name, nil)
+	if p.mysql {
+		db.SetEngine("MySQL")
+	} else {
+		db.SetEngine("PostgreSQL")
+	}
+
+	for _, name := range p.schemaOrder {
+		s := p.schemas[name]
+		schema := dbo.NewSchema(s.name, s.owner, nil)
+		db.AddSchema(schema)
+		for _, table := range s.tables {
+			schema.AddTable(table)
+		}
+		for _, view := range s.views {
+			schema.AddView(view)
+		}
+		for _, seq := range s.sequences {
+			schema.AddSequence(seq)
+		}
+		for _, fn := range s.functions {
+			schema.AddFunction(fn)
+		}
+		for _, proc := range s.procedures {
+			schema.AddProcedure(proc)
+		}
+		for _, enum := range s.enums {
+			schema.AddEnum(enum)
+		}
+	}
+	return db
+}
+
+// parseStatement applies one statement to the parser state. Statements that do not
+// describe the schema, such as SET, INSERT and GRANT, are ignored.
+func (p *dumpParser) parseStatement(text string) error {
+	if strings.HasPrefix(text, "\\") {
+		p.parseMetaCommand(text)
+		return nil
+	}
+
+	c := newCursor(text, p.mysql)
+	switch {
+	case c.accept("CREATE"):
+		return p.parseCreate(c)
+	case c.accept("ALTER", "TABLE"):
+		return p.parseAlterTable(c)
+	case c.accept("ALTER", "SCHEMA"):
+		return p.parseAlterSchema(c)
+	case c.accept("ALTER", "INDEX"):
+		return p.parseAlterIndex(c)
+	case c.accept("ALTER", "SEQUENCE"):
+		return p.parseAlterSequence(c)
+	case c.accept("ALTER", "VIEW"):
+		return p.parseAlterView(c)
+	case c.accept("ALTER", "TYPE"):
+		return p.parseAlterType(c)
+	case p.mysql && c.accept("RENAME", "TABLE"):
+		return p.parseRenameTable(c)
+	case c.accept("DROP"):
+		return p.parseDrop(c)
+	case c.accept("COMMENT", "ON"):
+		return p.parseComment(c)
+	case p.mysql && c.accept("USE"):
+		name := c.next().name()
+		p.setDatabase(name)
+		p.defaultSchema = name
+		p.schema(name)
+	}
+	return nil
+}
+
+// parseMetaCommand handles the \connect command pg_dump --create writes after creating
+// the database
+func (p *dumpParser) parseMetaCommand(text string) {
+	fields := strings.Fields(text)
+	if len(fields) < 2 || fields[0] != "\\connect" && fields[0] != "\\c" {
+		return
+	}
+	name := fields[len(fields)-1]
+	if dbname, ok := strings.CutPrefix(strings.Trim(name, "\""), "dbname='"); ok {
+		name = strings.TrimSuffix(dbname, "'")
+	}
+	p.setDatabase(strings.Trim(name, "\""))
+}
+
+// parseCreate dispatches a CREATE statement on the kind of object it creates
+func (p *dumpParser) parseCreate(c *cursor) error {
+	unique := false
+	indexType := ""
+	for !c.done() {
+		t := c.next()
+		switch {
+		case t.is("OR"):
+			c.accept("REPLACE")
+		case t.is("ALGORITHM"):
+			c.acceptSymbol("=")
+			c.next()
+		case t.is("DEFINER"):
+			c.skipDefiner()
+		case t.is("SQL"):
+			c.accept("SECURITY")
+			c.next()
+		case t.is("UNIQUE"):
+			unique = true
+		case t.is("FULLTEXT"), t.is("SPATIAL"):
+			indexType = strings.ToUpper(t.text)
+		case t.is("TEMPORARY"), t.is("TEMP"), t.is("UNLOGGED"), t.is("GLOBAL"), t.is("LOCAL"),
+			t.is("CONSTRAINT"), t.is("RECURSIVE"), t.is("TRUSTED"), t.is("PROCEDURAL"):
+		case t.is("TABLE"):
+			return p.parseCreateTable(c)
+		case t.is("INDEX"):
+			return p.parseCreateIndex(c, unique, indexType)
+		case t.is("VIEW"):
+			return p.parseCreateView(c)
+		case t.is("SEQUENCE"):
+			return p.parseCreateSequence(c)
+		case t.is("FUNCTION"):
+			return p.parseCreateRoutine(c, false)
+		case t.is("PROCEDURE"):
+			return p.parseCreateRoutine(c, true)
+		case t.is("TRIGGER"):
+			return p.parseCreateTrigger(c)
+		case t.is("TYPE"):
+			return p.parseCreateType(c)
+		case t.is("SCHEMA"), t.is("DATABASE"):
+			return p.parseCreateSchema(c, t.is("DATABASE"))
+		default:
+			// Materialized views, extensions, domains, casts and the like are not mapped
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseCreateSchema handles CREATE SCHEMA and CREATE DATABASE. MySQL treats the two as
+// synonyms; in a PostgreSQL dump CREATE DATABASE names the database.
+func (p *dumpParser) parseCreateSchema(c *cursor, database bool) error {
+	c.accept("IF", "NOT", "EXISTS")
+	if c.done() {
+		return fmt.Errorf("missing schema name")
+	}
+	name := c.next().name()
+
+	if database || p.mysql {
+		p.setDatabase(name)
+		if !p.mysql {
+			return nil
+		}
+	}
+	s := p.schema(name)
+	if c.accept("AUTHORIZATION") {
+		s.owner = c.next().name()
+	}
+	return nil
+}
+
+// parseCreateView handles CREATE VIEW. The definition is the query after AS.
+func (p *dumpParser) parseCreateView(c *cursor) error {
+	c.accept("IF", "NOT", "EXISTS")
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	if c.peek().kind == tokenGroup {
+		c.next()
+	}
+	if c.accept("WITH") {
+		c.next()
+	}
+	if !c.accept("AS") {
+		return fmt.Errorf("missing AS in view %s", name)
+	}
+
+	s := p.schema(schema)
+	delete(s.tables, name)
+	s.views[name] = dbo.NewView(name, sourceText(c.src, c.rest()))
+	return nil
+}
+
+// parseCreateSequence handles CREATE SEQUENCE. Options left out take the PostgreSQL
+// defaults for the sequence's data type.
+func (p *dumpParser) parseCreateSequence(c *cursor) error {
+	ifNotExists := c.accept("IF", "NOT", "EXISTS")
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	if _, ok := p.schema(schema).sequences[name]; ok && ifNotExists {
+		return nil
+	}
+
+	dataType := "bigint"
+	increment, cache := int64(1), int64(1)
+	var start, minValue, maxValue *int64
+	cycle := false
+	for !c.done() {
+		var target **int64
+		switch {
+		case c.accept("AS"):
+			dataType = strings.ToLower(c.next().text)
+			continue
+		case c.accept("START"):
+			c.accept("WITH")
+			target = &start
+		case c.accept("INCREMENT"):
+			c.accept("BY")
+			value, err := c.integer()
+			if err != nil {
+				return err
+			}
+			increment = value
+			continue
+		case c.accept("MINVALUE"):
+			target = &minValue
+		case c.accept("MAXVALUE"):
+			target = &maxValue
+		case c.accept("CACHE"):
+			value, err := c.integer()
+			if err != nil {
+				return err
+			}
+			cache = value
+			continue
+		case c.accept("CYCLE"):
+			cycle = true
+			continue
+		case c.accept("NO"):
+			// NO MINVALUE, NO MAXVALUE and NO CYCLE keep the defaults
+			c.next()
+			continue
+		default:
+			c.next()
+			continue
+		}
+		value, err := c.integer()
+		if err != nil {
+			return err
+		}
+		*target = &value
+	}
+
+	typeMax := int64(math.MaxInt64)
+	switch dataType {
+	case "smallint", "int2":
+		typeMax = math.MaxInt16
+	case "integer", "int", "int4":
+		typeMax = math.MaxInt32
+	}
+	low, high := int64(1), typeMax
+	if increment < 0 {
+		low, high = -typeMax-1, -1
+	}
+	if minValue != nil {
+		low = *minValue
+	}
+	if maxValue != nil {
+		high = *maxValue
+	}
+	first := low
+	if increment < 0 {
+		first = high
+	}
+	if start != nil {
+		first = *start
+	}
+
+	seq := dbo.NewSequence(name, first, increment)
+	seq.SetMinValue(low)
+	seq.SetMaxValue(high)
+	seq.SetCache(cache)
+	seq.SetCycle(cycle)
+	p.schema(schema).sequences[name] = seq
+	return nil
+}
+
+// routineAttributes end the return type of a function
+var routineAttributes = map[string]bool{
+	"LANGUAGE": true, "AS": true, "IMMUTABLE": true, "STABLE": true, "VOLATILE": true,
+	"STRICT": true, "SECURITY": true, "DETERMINISTIC": true, "NOT": true, "NO": true,
+	"READS": true, "MODIFIES": true, "CONTAINS": true, "SQL": true, "COMMENT": true,
+	"BEGIN": true, "RETURN": true, "PARALLEL": true, "COST": true, "ROWS": true, "SET": true,
+	"CALLED": true, "LEAKPROOF": true, "WINDOW": true, "SUPPORT": true, "TRANSFORM": true,
+	"EXTERNAL": true, "CHARSET": true, "COLLATE": true,
+}
+
+// multiWordTypes start data types spelled with more than one word, so that an unnamed
+// "double precision" parameter is not taken for a parameter named double
+var multiWordTypes = map[string]bool{
+	"double": true, "character": true, "bit": true, "timestamp": true, "time": true,
+	"interval": true, "national": true,
+}
+
+// parseCreateRoutine handles CREATE FUNCTION and CREATE PROCEDURE. The whole statement
+// is kept as the definition.
+func (p *dumpParser) parseCreateRoutine(c *cursor, procedure bool) error {
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	params := c.next()
+	if params.kind != tokenGroup {
+		return fmt.Errorf("missing parameter list in routine %s", name)
+	}
+
+	returnType, language := "", ""
+	for !c.done() {
+		switch {
+		case returnType == "" && c.accept("RETURNS"):
+			start := c.pos
+			for !c.done() && !(c.peek().kind == tokenWord && routineAttributes[strings.ToUpper(c.peek().text)]) {
+				c.next()
+			}
+			returnType = sourceText(c.src, c.toks[start:c.pos])
+		case language == "" && c.accept("LANGUAGE"):
+			language = c.next().name()
+		default:
+			c.next()
+		}
+	}
+	if language == "" && p.mysql {
+		language = "SQL"
+	}
+
+	s := p.schema(schema)
+	if procedure {
+		proc := dbo.NewProcedure(name, c.src)
+		proc.SetLanguage(language)
+		for _, param := range p.parseParameters(params) {
+			proc.AddParameter(param)
+		}
+		s.procedures[name] = proc
+		return nil
+	}
+
+	fn := dbo.NewFunction(name, c.src)
+	fn.SetReturnType(returnType)
+	fn.SetLanguage(language)
+	for _, param := range p.parseParameters(params) {
+		fn.AddParameter(param)
+	}
+	s.functions[name] = fn
+	return nil
+}
+
+// parseParameters parses the parameter list of a routine. Defaults are dropped.
+func (p *dumpParser) parseParameters(group token) []*dbo.FunctionParameter {
+	src := group.inner()
+	var params []*dbo.FunctionParameter
+	for _, part := range splitTokens(tokenize(src, p.mysql)) {
+		mode := dbo.ParameterModeIn
+		if len(part) > 1 && part[0].kind == tokenWord {
+			switch upper := strings.ToUpper(part[0].text); upper {
+			case "IN", "OUT", "INOUT":
+				mode = dbo.ParameterMode(upper)
+				part = part[1:]
+			case "VARIADIC":
+				// Variadic parameters are input parameters
+				part = part[1:]
+			}
+		}
+		for i, t := range part {
+			if t.is("DEFAULT") || t.kind == tokenSymbol && t.text == "=" {
+				part = part[:i]
+				break
+			}
+		}
+		if len(part) == 0 {
+			continue
+		}
+
+		name := ""
+		if len(part) > 1 && !multiWordTypes[strings.ToLower(part[0].text)] {
+			name = part[0].name()
+			part = part[1:]
+		}
+		params = append(params, dbo.NewFunctionParameter(name, sourceText(src, part), mode))
+	}
+	return params
+}
+
+// parseCreateTrigger handles CREATE TRIGGER. Like the live adapters, the definition is
+// the action: EXECUTE FUNCTION ... for PostgreSQL, the trigger body for MySQL.
+func (p *dumpParser) parseCreateTrigger(c *cursor) error {
+	_, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+
+	trigger := dbo.NewTrigger(name, "")
+	switch {
+	case c.accept("BEFORE"):
+		trigger.SetTiming(dbo.TriggerTimingBefore)
+	case c.accept("AFTER"):
+		trigger.SetTiming(dbo.TriggerTimingAfter)
+	case c.accept("INSTEAD", "OF"):
+		trigger.SetTiming(dbo.TriggerTimingInsteadOf)
+	}
+	for !c.done() && !c.peek().is("ON") {
+		t := c.next()
+		for _, event := range []string{"INSERT", "UPDATE", "DELETE", "TRUNCATE"} {
+			if t.is(event) {
+				trigger.AddEvent(dbo.TriggerEvent(event))
+			}
+		}
+	}
+	if !c.accept("ON") {
+		return fmt.Errorf("missing table in trigger %s", name)
+	}
+	schema, tableName, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	table, err := p.table(schema, tableName)
+	if err != nil {
+		return err
+	}
+
+	trigger.SetForEach("STATEMENT")
+	for !c.done() {
+		switch {
+		case c.accept("FOR"):
+			c.accept("EACH")
+			trigger.SetForEach(strings.ToUpper(c.next().text))
+			if !p.mysql {
+				continue
+			}
+			// The MySQL trigger body follows FOR EACH ROW and an optional ordering clause
+			if c.accept("FOLLOWS") || c.accept("PRECEDES") {
+				c.next()
+			}
+			trigger.SetDefinition(sourceText(c.src, c.rest()))
+			c.pos = len(c.toks)
+		case c.peek().is("EXECUTE"):
+			trigger.SetDefinition(sourceText(c.src, c.rest()))
+			c.next()
+			c.next()
+			fnSchema, fnName, err := c.objectName()
+			if err != nil {
+				return err
+			}
+			if fn, ok := p.schema(fnSchema).functions[fnName]; ok {
+				trigger.SetFunction(fn)
+			}
+			c.pos = len(c.toks)
+		default:
+			c.next()
+		}
+	}
+
+	// CREATE OR REPLACE TRIGGER replaces a trigger of the same name
+	table.RemoveTrigger(name)
+	table.AddTrigger(trigger)
+	return nil
+}
+
+// parseCreateType handles CREATE TYPE ... AS ENUM. Composite and range types are not mapped.
+func (p *dumpParser) parseCreateType(c *cursor) error {
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	if !c.accept("AS", "ENUM") {
+		return nil
+	}
+	group := c.next()
+	if group.kind != tokenGroup {
+		return fmt.Errorf("missing values in enum %s", name)
+	}
+
+	var values []string
+	for _, part := range splitTokens(tokenize(group.inner(), p.mysql)) {
+		if len(part) > 0 {
+			values = append(values, part[0].name())
+		}
+	}
+	p.schema(schema).enums[name] = dbo.NewEnum(name, values)
+	return nil
+}
+
+// parseComment handles COMMENT ON TABLE and COMMENT ON COLUMN
+func (p *dumpParser) parseComment(c *cursor) error {
+	column := c.accept("COLUMN")
+	if !column && !c.accept("TABLE") {
+		return nil
+	}
+	parts, err := c.nameParts()
+	if err != nil {
+		return err
+	}
+	if !c.accept("IS") {
+		return fmt.Errorf("missing IS in comment")
+	}
+	value := c.next()
+	if value.is("E") {
+		value = c.next()
+	}
+	comment := ""
+	if value.kind == tokenString {
+		comment = value.name()
+	}
+
+	columnName := ""
+	if column {
+		if len(parts) < 2 {
+			return fmt.Errorf("missing table in column comment")
+		}
+		columnName = parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+	schema := ""
+	if len(parts) > 1 {
+		schema = parts[len(parts)-2]
+	}
+	table, err := p.table(schema, parts[len(parts)-1])
+	if err != nil {
+		return err
+	}
+
+	if !column {
+		table.SetComment(comment)
+		return nil
+	}
+	col, ok := table.Columns()[columnName]
+	if !ok {
+		return fmt.Errorf("unknown column %s.%s", table.Name(), columnName)
+	}
+	col.SetComment(comment)
+	return nil
+}
+
+// cursor walks the tokens of a statement
+type cursor struct {
+	src  string
+	toks []token
+	pos  int
+}
+
+func newCursor(src string, mysql bool) *cursor {
+	return &cursor{src: src, toks: tokenize(src, mysql)}
+}
+
+func (c *cursor) done() bool {
+	return c.pos >= len(c.toks)
+}
+
+// peek returns the next token without consuming it, or a zero token at the end
+func (c *cursor) peek() token {
+	if c.done() {
+		return token{}
+	}
+	return c.toks[c.pos]
+}
+
+// next consumes the next token, or returns a zero token at the end
+func (c *cursor) next() token {
+	t := c.peek()
+	if !c.done() {
+		c.pos++
+	}
+	return t
+}
+
+// rest returns the tokens not consumed yet
+func (c *cursor) rest() []token {
+	return c.toks[c.pos:]
+}
+
+// accept consumes the given keywords if the next tokens are exactly those keywords
+func (c *cursor) accept(keywords ...string) bool {
+	if c.pos+len(keywords) > len(c.toks) {
+		return false
+	}
+	for i, keyword := range keywords {
+		if !c.toks[c.pos+i].is(keyword) {
+			return false
+		}
+	}
+	c.pos += len(keywords)
+	return true
+}
+
+// acceptSymbol consumes the next token if it is the given symbol
+func (c *cursor) acceptSymbol(symbol string) bool {
+	if t := c.peek(); t.kind == tokenSymbol && t.text == symbol {
+		c.pos++
+		return true
+	}
+	return false
+}
+
+// nameParts consumes a dotted name and returns its unquoted parts
+func (c *cursor) nameParts() ([]string, error) {
+	var parts []string
+	for {
+		t := c.next()
+		if t.kind != tokenWord && t.kind != tokenIdent {
+			return nil, fmt.Errorf("expected a name, found %q", t.text)
+		}
+		parts = append(parts, t.name())
+		if !c.acceptSymbol(".") {
+			return parts, nil
+		}
+	}
+}
+
+// objectName consumes an optionally schema-qualified name. The schema is "" when the
+// name is not qualified.
+func (c *cursor) objectName() (string, string, error) {
+	parts, err := c.nameParts()
+	if err != nil {
+		return "", "", err
+	}
+	if len(parts) > 2 {
+		return "", "", fmt.Errorf("unexpected name %s", strings.Join(parts, "."))
+	}
+	if len(parts) == 2 {
+		return parts[0], parts[1], nil
+	}
+	return "", parts[0], nil
+}
+
+// integer consumes an optionally negative integer
+func (c *cursor) integer() (int64, error) {
+	negative := c.acceptSymbol("-")
+	t := c.next()
+	value, err := strconv.ParseInt(t.text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected an integer, found %q", t.text)
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// skipDefiner consumes the "= user@host" part of a MySQL DEFINER clause
+func (c *cursor) skipDefiner() {
+	c.acceptSymbol("=")
+	c.next()
+	for c.acceptSymbol("@") {
+		c.next()
+	}
+	if c.peek().kind == tokenGroup {
+		c.next()
+	}
+}
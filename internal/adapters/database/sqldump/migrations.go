@@ -0,0 +1,251 @@
+package sqldump
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+var (
+	// migrateUpPattern matches golang-migrate up-migrations: 1_create_users.up.sql
+	migrateUpPattern = regexp.MustCompile(`^(\d+)_(.*)\.up\.sql$`)
+	// migrateDownPattern matches golang-migrate down-migrations, which are not replayed
+	migrateDownPattern = regexp.MustCompile(`^\d+_.*\.down\.sql$`)
+	// flywayVersionedPattern matches Flyway versioned migrations: V1.2__create_users.sql
+	flywayVersionedPattern = regexp.MustCompile(`^V(\d+(?:[._]\d+)*)__(.*)\.sql$`)
+	// flywayRepeatablePattern matches Flyway repeatable migrations: R__users_view.sql
+	flywayRepeatablePattern = regexp.MustCompile(`^R__(.*)\.sql$`)
+	// flywayUndoPattern matches Flyway undo migrations, which are not replayed
+	flywayUndoPattern = regexp.MustCompile(`^U\d+(?:[._]\d+)*__.*\.sql$`)
+	// goosePattern matches goose migrations, which hold both directions: 00001_users.sql
+	goosePattern = regexp.MustCompile(`^(\d+)_(.*)\.sql$`)
+	// gooseAnnotationPattern matches the -- +goose Up and -- +goose Down annotations
+	gooseAnnotationPattern = regexp.MustCompile(`^--\s*\+goose\s+(Up|Down)\b`)
+)
+
+// migration is the up direction of one migration file
+type migration struct {
+	file string
+	// version is the numeric parts of the version, or nil for a Flyway repeatable
+	// migration, which runs after all versioned ones
+	version     []string
+	description string
+	up          string
+}
+
+// MigrationsAdapter replays a directory of migrations into a model without connecting
+// to a database, so a branch's schema can be audited before it is deployed and
+// compared with what production has. golang-migrate, goose and Flyway file names are
+// understood; only the up direction is replayed.
+type MigrationsAdapter struct {
+	dir        string
+	migrations []migration
+	skipped    []string
+	// mysql is the dialect given by the dialect parameter, or nil to detect it
+	mysql *bool
+}
+
+func (a *MigrationsAdapter) Name() string {
+	return "Migrations"
+}
+
+func (a *MigrationsAdapter) Version() string {
+	return "v1"
+}
+
+func (a *MigrationsAdapter) UniqueSignature() string {
+	return a.Name() + "-" + a.Version()
+}
+
+func (a *MigrationsAdapter) IsConnectionStringCompatible(connString string) bool {
+	return strings.HasPrefix(connString, "migrations://")
+}
+
+// Connect reads the migrations of the "migrations://dir" directory and puts them in
+// the order they are applied in. Subdirectories are not read. The dialect is detected
+// from the migrations unless given as "migrations://dir?dialect=mysql" or
+// "?dialect=postgres".
+func (a *MigrationsAdapter) Connect(connString string) error {
+	dir, query, _ := strings.Cut(strings.TrimPrefix(connString, "migrations://"), "?")
+	dir = filepath.Clean(dir)
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return fmt.Errorf("invalid migrations parameters: %w", err)
+	}
+	var mysql *bool
+	switch dialect := strings.ToLower(params.Get("dialect")); dialect {
+	case "":
+	case "mysql", "mariadb":
+		mysql = new(bool)
+		*mysql = true
+	case "postgres", "postgresql":
+		mysql = new(bool)
+	default:
+		return fmt.Errorf("unknown dialect %q, expected mysql or postgres", dialect)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var migrations []migration
+	var skipped []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(name), ".sql") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		m := migration{file: name, up: string(content)}
+		switch {
+		case migrateDownPattern.MatchString(name), flywayUndoPattern.MatchString(name):
+			continue
+		case migrateUpPattern.MatchString(name):
+			match := migrateUpPattern.FindStringSubmatch(name)
+			m.version, m.description = []string{match[1]}, match[2]
+		case flywayVersionedPattern.MatchString(name):
+			match := flywayVersionedPattern.FindStringSubmatch(name)
+			m.version = strings.FieldsFunc(match[1], func(r rune) bool { return r == '.' || r == '_' })
+			m.description = match[2]
+		case flywayRepeatablePattern.MatchString(name):
+			m.description = flywayRepeatablePattern.FindStringSubmatch(name)[1]
+		case goosePattern.MatchString(name):
+			match := goosePattern.FindStringSubmatch(name)
+			m.version, m.description = []string{match[1]}, match[2]
+			m.up = gooseUp(m.up)
+		default:
+			skipped = append(skipped, name)
+			continue
+		}
+		migrations = append(migrations, m)
+	}
+	if len(migrations) == 0 {
+		return fmt.Errorf("no migrations found in %s", dir)
+	}
+
+	slices.SortStableFunc(migrations, compareMigrations)
+	for i := 1; i < len(migrations); i++ {
+		prev, m := migrations[i-1], migrations[i]
+		if m.version != nil && compareVersions(prev.version, m.version) == 0 {
+			return fmt.Errorf("migrations %s and %s have the same version %s", prev.file, m.file, strings.Join(m.version, "."))
+		}
+	}
+
+	a.dir = dir
+	a.migrations = migrations
+	a.skipped = skipped
+	a.mysql = mysql
+	return nil
+}
+
+func (a *MigrationsAdapter) Close() error {
+	a.dir = ""
+	a.migrations = nil
+	a.skipped = nil
+	a.mysql = nil
+	return nil
+}
+
+func (a *MigrationsAdapter) IsConnected() bool {
+	return a.dir != ""
+}
+
+// MapDatabase applies the migrations in order. The database is named after the
+// directory unless a migration creates or selects one. Statements that cannot be
+// applied are reported with their file and line and skipped.
+func (a *MigrationsAdapter) MapDatabase() (*dbo.Database, []error) {
+	var mysql bool
+	if a.mysql != nil {
+		mysql = *a.mysql
+	} else {
+		ups := make([]string, len(a.migrations))
+		for i, m := range a.migrations {
+			ups[i] = m.up
+		}
+		mysql = isMySQLDump(strings.Join(ups, "\n"))
+	}
+
+	p := newDumpParser(filepath.Base(a.dir), mysql)
+	for _, name := range a.skipped {
+		p.errors = append(p.errors, fmt.Errorf("skipped %s: the name follows no supported migration naming scheme", name))
+	}
+	for _, m := range a.migrations {
+		for _, stmt := range splitStatements(m.up, mysql) {
+			if err := p.parseStatement(stmt.text); err != nil {
+				p.errors = append(p.errors, fmt.Errorf("%s: failed to apply statement on line %d: %w", m.file, stmt.line, err))
+			}
+		}
+	}
+	return p.database(), p.errors
+}
+
+// gooseUp returns the -- +goose Up section of a goose migration. Lines outside it are
+// blanked rather than removed, so that statement line numbers still match the file.
+// Files without annotations are returned unchanged.
+func gooseUp(content string) string {
+	lines := strings.Split(content, "\n")
+	annotated := slices.ContainsFunc(lines, func(line string) bool {
+		return gooseAnnotationPattern.MatchString(strings.TrimSpace(line))
+	})
+	if !annotated {
+		return content
+	}
+
+	up := false
+	for i, line := range lines {
+		if match := gooseAnnotationPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			up = match[1] == "Up"
+			lines[i] = ""
+			continue
+		}
+		if !up {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// compareMigrations orders versioned migrations by version, followed by repeatable
+// migrations by description
+func compareMigrations(a, b migration) int {
+	switch {
+	case a.version == nil && b.version == nil:
+		return strings.Compare(a.description, b.description)
+	case a.version == nil:
+		return 1
+	case b.version == nil:
+		return -1
+	}
+	return compareVersions(a.version, b.version)
+}
+
+// compareVersions compares versions part by part as numbers of any size, so that 10
+// follows 9 and 1.10 follows 1.9. Missing parts count as 0, making 1 equal to 1.0.
+func compareVersions(a, b []string) int {
+	for i := range max(len(a), len(b)) {
+		// Leading zeros are trimmed, so 0 is the empty string
+		x, y := "", ""
+		if i < len(a) {
+			x = strings.TrimLeft(a[i], "0")
+		}
+		if i < len(b) {
+			y = strings.TrimLeft(b[i], "0")
+		}
+		if c := cmp.Or(cmp.Compare(len(x), len(y)), strings.Compare(x, y)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,236 @@
+package sqldump
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// replayMigrations writes the migration files to a directory and replays them
+func replayMigrations(t *testing.T, files map[string]string) (*dbo.Database, []error) {
+	t.Helper()
+	return replayMigrationsWith(t, "", files)
+}
+
+// replayMigrationsWith replays migration files with the given connection string
+// parameters, such as "?dialect=mysql"
+func replayMigrationsWith(t *testing.T, params string, files map[string]string) (*dbo.Database, []error) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "shop")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	adapter := &MigrationsAdapter{}
+	if !adapter.IsConnectionStringCompatible("migrations://" + dir) {
+		t.Fatal("expected migrations:// connection string to be compatible")
+	}
+	if err := adapter.Connect("migrations://" + dir + params); err != nil {
+		t.Fatalf("failed to read migrations: %v", err)
+	}
+	t.Cleanup(func() { adapter.Close() })
+	return adapter.MapDatabase()
+}
+
+func TestMigrations_GolangMigrate(t *testing.T) {
+	db, errs := replayMigrations(t, map[string]string{
+		"2_add_email.up.sql":      "ALTER TABLE users ADD COLUMN email text NOT NULL;",
+		"2_add_email.down.sql":    "ALTER TABLE users DROP COLUMN email;",
+		"10_rename.up.sql":        "ALTER TABLE users RENAME TO accounts;",
+		"1_create_users.up.sql":   "CREATE TABLE users (id bigint PRIMARY KEY);",
+		"1_create_users.down.sql": "DROP TABLE users;",
+	})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if db.Name() != "shop" || db.Engine() != "PostgreSQL" {
+		t.Errorf("expected shop/PostgreSQL, got %s/%s", db.Name(), db.Engine())
+	}
+	accounts := db.Schemas()["public"].Tables()["accounts"]
+	if accounts == nil {
+		t.Fatal("expected version 10 to run after version 2")
+	}
+	if got := columnOrder(accounts); !reflect.DeepEqual(got, []string{"id", "email"}) {
+		t.Errorf("columns = %v", got)
+	}
+}
+
+func TestMigrations_Goose(t *testing.T) {
+	db, errs := replayMigrations(t, map[string]string{
+		"00001_users.sql": `-- +goose Up
+CREATE TABLE users (id integer PRIMARY KEY);
+
+-- +goose Down
+DROP TABLE users;
+`,
+		"00002_touch.sql": `-- +goose Up
+-- +goose StatementBegin
+CREATE FUNCTION touch() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    RETURN NEW;
+END;
+$$;
+-- +goose StatementEnd
+ALTER TABLE missing ADD COLUMN note text;
+-- +goose Down
+DROP FUNCTION touch();
+`,
+	})
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "00002_touch.sql: failed to apply statement on line 9") {
+		t.Errorf("expected one error with the file and line, got %v", errs)
+	}
+	public := db.Schemas()["public"]
+	if public.Tables()["users"] == nil || public.Functions()["touch"] == nil {
+		t.Error("expected the up sections to be applied and the down sections skipped")
+	}
+}
+
+func TestMigrations_Flyway(t *testing.T) {
+	db, errs := replayMigrations(t, map[string]string{
+		"V1__create_items.sql":  "CREATE TABLE `items` (`id` int NOT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB;",
+		"V1_1__add_name.sql":    "ALTER TABLE `items` ADD COLUMN `name` varchar(40);",
+		"V1.10__widen_name.sql": "ALTER TABLE `items` MODIFY `name` varchar(80);",
+		"V1.9__rename_name.sql": "ALTER TABLE `items` CHANGE `name` `title` varchar(60);",
+		"U1_1__undo.sql":        "ALTER TABLE `items` DROP COLUMN `name`;",
+		"R__item_titles.sql":    "CREATE OR REPLACE VIEW `item_titles` AS SELECT `title` FROM `items`;",
+		"seed.sql":              "INSERT INTO `items` VALUES (1);",
+		"README.md":             "not a migration",
+	})
+
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), "skipped seed.sql") ||
+		!strings.Contains(errs[1].Error(), "V1.10__widen_name.sql: failed to apply statement on line 1: unknown column items.name") {
+		t.Errorf("expected a warning for seed.sql and an error for V1.10, got %v", errs)
+	}
+	if db.Engine() != "MySQL" {
+		t.Errorf("expected MySQL, got %s", db.Engine())
+	}
+	s := db.Schemas()["shop"]
+	title := s.Tables()["items"].Columns()["title"]
+	if title == nil {
+		t.Fatal("expected V1.9 to rename name to title")
+	}
+	if intValue(title.CharMaxLength()) != 60 {
+		t.Errorf("expected V1.10 to fail on the renamed column, leaving varchar(60), got %d", intValue(title.CharMaxLength()))
+	}
+	if s.Views()["item_titles"] == nil {
+		t.Error("expected the repeatable migration to create the view")
+	}
+}
+
+func TestMigrations_MySQLWithoutEngine(t *testing.T) {
+	db, errs := replayMigrations(t, map[string]string{
+		"1_create_users.up.sql": "CREATE TABLE users (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(50), email VARCHAR(100));",
+		"2_alter_users.up.sql": `ALTER TABLE users MODIFY email VARCHAR(200) NOT NULL;
+ALTER TABLE users CHANGE name full_name VARCHAR(60);
+ALTER TABLE users ADD COLUMN nick VARCHAR(50) AFTER id;`,
+	})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if db.Engine() != "MySQL" {
+		t.Fatalf("expected AUTO_INCREMENT and MODIFY to be read as MySQL, got %s", db.Engine())
+	}
+
+	users := db.Schemas()["shop"].Tables()["users"]
+	if got, want := columnOrder(users), []string{"id", "nick", "full_name", "email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %v, want %v", got, want)
+	}
+	if email := users.Columns()["email"]; email.IsNullable() || intValue(email.CharMaxLength()) != 200 {
+		t.Errorf("expected MODIFY to make email a NOT NULL varchar(200), got %s nullable=%v", email.DataType(), email.IsNullable())
+	}
+	if fullName := users.Columns()["full_name"]; fullName == nil || intValue(fullName.CharMaxLength()) != 60 {
+		t.Error("expected CHANGE to rename name to a varchar(60) full_name")
+	}
+	if nick := users.Columns()["nick"]; nick.DataType() != "varchar" || intValue(nick.CharMaxLength()) != 50 {
+		t.Errorf("expected AFTER to be left out of the varchar(50) type, got %q", nick.DataType())
+	}
+}
+
+func TestMigrations_Dialect(t *testing.T) {
+	files := map[string]string{"1_create_users.up.sql": "CREATE TABLE users (id int);"}
+
+	db, errs := replayMigrationsWith(t, "?dialect=mysql", files)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if db.Engine() != "MySQL" || db.Schemas()["shop"] == nil {
+		t.Errorf("expected the MySQL dialect to map users into shop, got %s", db.Engine())
+	}
+
+	db, _ = replayMigrationsWith(t, "?dialect=postgres", map[string]string{"1_create_users.up.sql": "CREATE TABLE users (id int AUTO_INCREMENT);"})
+	if db.Engine() != "PostgreSQL" {
+		t.Errorf("expected the dialect parameter to override detection, got %s", db.Engine())
+	}
+
+	err := (&MigrationsAdapter{}).Connect("migrations://" + t.TempDir() + "?dialect=oracle")
+	if err == nil || !strings.Contains(err.Error(), "unknown dialect") {
+		t.Errorf("expected an unknown dialect error, got %v", err)
+	}
+}
+
+func TestMigrations_ConnectErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{"no migrations", map[string]string{"notes.txt": ""}, "no migrations found"},
+		{"duplicate versions", map[string]string{"1_a.up.sql": "", "V1.0__b.sql": ""}, "have the same version 1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			adapter := &MigrationsAdapter{}
+			err := adapter.Connect("migrations://" + dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if adapter.IsConnected() {
+				t.Error("expected adapter not to be connected")
+			}
+		})
+	}
+
+	if err := (&MigrationsAdapter{}).Connect("migrations://" + filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"9", "10", -1},
+		{"1.9", "1.10", -1},
+		{"1", "1.0", 0},
+		{"001", "1", 0},
+		{"20240101120000", "20231231235959", 1},
+		{"2.0.1", "2", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := compareVersions(strings.Split(tt.a, "."), strings.Split(tt.b, ".")); got != tt.want {
+				t.Errorf("compareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
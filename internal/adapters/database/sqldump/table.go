@@ -0,0 +1,818 @@
+package sqldump
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// foreignKeyReference is the REFERENCES clause of a foreign key
+type foreignKeyReference struct {
+	schema   string
+	table    string
+	columns  []string
+	onDelete dbo.ReferentialAction
+	onUpdate dbo.ReferentialAction
+}
+
+// parseCreateTable handles CREATE TABLE. Columns are added before the table
+// constraints, which may refer to columns declared after them.
+func (p *dumpParser) parseCreateTable(c *cursor) error {
+	ifNotExists := c.accept("IF", "NOT", "EXISTS")
+	schema, name, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	s := p.schema(schema)
+	if _, ok := s.tables[name]; ok && ifNotExists {
+		return nil
+	}
+	table := dbo.NewTable(name, nil)
+	delete(s.views, name)
+	s.tables[name] = table
+
+	body := c.next()
+	if body.kind != tokenGroup {
+		// Partitions and CREATE TABLE ... AS have no column list
+		return nil
+	}
+	src := body.inner()
+	elements := splitTokens(tokenize(src, p.mysql))
+	position := 0
+	for _, element := range elements {
+		if len(element) == 0 || p.isTableConstraint(element) {
+			continue
+		}
+		position++
+		if err := p.parseColumn(table, newElementCursor(src, element), position); err != nil {
+			return err
+		}
+	}
+	for _, element := range elements {
+		if len(element) == 0 || !p.isTableConstraint(element) {
+			continue
+		}
+		ec := newElementCursor(src, element)
+		name := ""
+		if ec.accept("CONSTRAINT") {
+			name = ec.next().name()
+		}
+		if err := p.parseTableConstraint(table, name, ec); err != nil {
+			return err
+		}
+	}
+
+	// MySQL table options
+	for !c.done() {
+		switch {
+		case c.accept("WITH", "SYSTEM", "VERSIONING"):
+			table.SetSystemVersioned(true)
+		case c.accept("ENGINE"):
+			c.acceptSymbol("=")
+			table.SetEngine(c.next().name())
+		case c.accept("ROW_FORMAT"):
+			c.acceptSymbol("=")
+			table.SetRowFormat(strings.ToUpper(c.next().name()))
+		case c.accept("CHARSET"), c.accept("CHARACTER", "SET"):
+			c.acceptSymbol("=")
+			table.SetCharset(strings.ToLower(c.next().name()))
+		case c.accept("COLLATE"):
+			c.acceptSymbol("=")
+			table.SetCollation(strings.ToLower(c.next().name()))
+		case c.next().is("COMMENT"):
+			c.acceptSymbol("=")
+			table.SetComment(c.next().name())
+		}
+	}
+	if p.mysql {
+		inheritCharset(table)
+	}
+	return nil
+}
+
+// inheritCharset completes the character sets and collations of a MySQL table and its
+// text columns, which a dump only declares where they differ from the defaults
+func inheritCharset(table *dbo.Table) {
+	if table.Charset() == "" {
+		table.SetCharset(collationCharset(table.Collation()))
+	}
+	for _, col := range table.Columns() {
+		if !isTextType(col.DataType()) {
+			continue
+		}
+		if col.Charset() == "" {
+			col.SetCharset(table.Charset())
+		}
+		if col.Collation() == "" && col.Charset() == table.Charset() {
+			col.SetCollation(table.Collation())
+		}
+	}
+}
+
+// collationCharset returns the character set of a MySQL collation, which names it up
+// to the first underscore, as in utf8mb4_0900_ai_ci
+func collationCharset(collation string) string {
+	charset, _, _ := strings.Cut(collation, "_")
+	return charset
+}
+
+// isTextType reports whether a MySQL data type has a character set
+func isTextType(dataType string) bool {
+	switch dataType {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return true
+	}
+	return false
+}
+
+func newElementCursor(src string, element []token) *cursor {
+	return &cursor{src: src, toks: element}
+}
+
+// isTableConstraint reports whether a CREATE TABLE element is a table constraint or
+// index rather than a column. KEY and INDEX only start indexes and PERIOD FOR only starts
+// a MariaDB period in MySQL, where a column with such a name is quoted.
+func (p *dumpParser) isTableConstraint(element []token) bool {
+	first := element[0]
+	for _, keyword := range []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE", "LIKE"} {
+		if first.is(keyword) {
+			return true
+		}
+	}
+	if p.mysql && first.is("PERIOD") && len(element) > 1 && element[1].is("FOR") {
+		return true
+	}
+	return p.mysql && (first.is("KEY") || first.is("INDEX") || first.is("FULLTEXT") || first.is("SPATIAL"))
+}
+
+// isColumnAttribute reports whether the token at i starts a column attribute, which ends
+// the data type and the default expression of a column
+func isColumnAttribute(tokens []token, i int) bool {
+	t := tokens[i]
+	if t.kind != tokenWord {
+		return false
+	}
+	switch strings.ToUpper(t.text) {
+	case "NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK", "CONSTRAINT",
+		"COLLATE", "COMMENT", "AUTO_INCREMENT", "GENERATED", "AS", "CHARSET", "ON", "VISIBLE",
+		"INVISIBLE", "SRID", "STORAGE", "COLUMN_FORMAT":
+		return true
+	case "CHARACTER":
+		// CHARACTER is also a data type
+		return i+1 < len(tokens) && tokens[i+1].is("SET")
+	}
+	return false
+}
+
+// parseColumn parses a column definition and adds the column and its inline
+// constraints to the table
+func (p *dumpParser) parseColumn(table *dbo.Table, c *cursor, position int) error {
+	name := c.next().name()
+	start := c.pos
+	for !c.done() && !isColumnAttribute(c.toks, c.pos) {
+		c.next()
+	}
+	dataType, length, precision, scale := parseDataType(c.toks[start:c.pos], p.mysql)
+
+	nullable := true
+	defaultValue, comment, constraintName := "", "", ""
+	charset, collation := "", ""
+	var generation dbo.Generation
+	generationExpression, invisible := "", false
+	var constraints []func(col *dbo.Column) error
+	for !c.done() {
+		// Constraint names apply to the next constraint only
+		pending := constraintName
+		constraintName = ""
+		switch {
+		case c.accept("CONSTRAINT"):
+			constraintName = c.next().name()
+		case c.accept("NOT", "NULL"):
+			nullable = false
+		case c.accept("NULL"):
+			nullable = true
+		case c.accept("DEFAULT"):
+			defaultStart := c.pos
+			c.next()
+			for !c.done() && !isColumnAttribute(c.toks, c.pos) {
+				c.next()
+			}
+			defaultValue = p.defaultExpression(c.src, c.toks[defaultStart:c.pos])
+		case c.accept("PRIMARY", "KEY"):
+			nullable = false
+			constraints = append(constraints, func(col *dbo.Column) error {
+				return p.addPrimaryKey(table, pending, keyList{columns: []*dbo.Column{col}})
+			})
+		case c.accept("UNIQUE"):
+			c.accept("KEY")
+			constraints = append(constraints, func(col *dbo.Column) error {
+				return p.addUnique(table, pending, keyList{columns: []*dbo.Column{col}})
+			})
+		case c.accept("REFERENCES"):
+			ref, err := p.parseReference(c)
+			if err != nil {
+				return err
+			}
+			constraints = append(constraints, func(col *dbo.Column) error {
+				return p.addForeignKey(table, pending, []*dbo.Column{col}, ref)
+			})
+		case c.accept("CHECK"):
+			group := c.next()
+			constraints = append(constraints, func(col *dbo.Column) error {
+				return p.addCheck(table, pending, group, col.Name())
+			})
+		case c.accept("COMMENT"):
+			comment = c.next().name()
+		case c.accept("COLLATE"):
+			collation = c.next().name()
+		case c.accept("CHARSET"), c.accept("CHARACTER", "SET"):
+			charset = strings.ToLower(c.next().name())
+		case c.accept("GENERATED", "BY", "DEFAULT"):
+			// An identity column, whose DEFAULT is not a default value
+		case c.accept("GENERATED", "ALWAYS", "AS"), c.accept("AS"):
+			// Identity columns and the row start and end of system-versioned tables are
+			// spelled alike but have no expression
+			if c.peek().kind != tokenGroup {
+				continue
+			}
+			generationExpression = strings.TrimSpace(c.next().inner())
+			generation = dbo.GenerationVirtual
+			if c.accept("STORED") || c.accept("PERSISTENT") {
+				generation = dbo.GenerationStored
+			}
+		case c.accept("INVISIBLE"):
+			invisible = true
+		default:
+			// AUTO_INCREMENT, ON UPDATE, identity columns and the like are not mapped
+			c.next()
+		}
+	}
+
+	col := dbo.NewColumn(name, dataType, nullable)
+	col.SetOrdinalPosition(position)
+	setTypeModifiers(col, length, precision, scale)
+	if defaultValue != "" {
+		col.SetDefaultValue(defaultValue)
+	}
+	if comment != "" {
+		col.SetComment(comment)
+	}
+	if generation != "" {
+		col.SetGenerated(generation, generationExpression)
+	}
+	col.SetInvisible(invisible)
+	if p.mysql {
+		collation = strings.ToLower(collation)
+		if charset == "" {
+			charset = collationCharset(collation)
+		}
+	}
+	col.SetCharset(charset)
+	col.SetCollation(collation)
+	table.AddColumn(col)
+
+	for _, add := range constraints {
+		if err := add(col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTypeModifiers sets the length, precision and scale parseDataType found
+func setTypeModifiers(col *dbo.Column, length, precision, scale int) {
+	if length > 0 {
+		col.SetCharMaxLength(length)
+	}
+	if precision > 0 {
+		col.SetNumericPrecision(precision)
+		col.SetNumericScale(scale)
+	}
+}
+
+// parseDataType normalises a declared type the way information_schema reports it: the
+// type name without its arguments, plus the length, precision and scale those carry.
+// PostgreSQL arrays are reported as ARRAY, and MySQL integer attributes are dropped.
+func parseDataType(tokens []token, mysql bool) (string, int, int, int) {
+	var sb strings.Builder
+	var args []string
+	array := false
+	for i, t := range tokens {
+		switch {
+		case t.kind == tokenGroup:
+			if args == nil {
+				for _, arg := range strings.Split(t.inner(), ",") {
+					args = append(args, strings.TrimSpace(arg))
+				}
+			}
+		case t.kind == tokenSymbol && t.text == "[", t.is("ARRAY"):
+			array = true
+		case t.kind == tokenSymbol && t.text == ".":
+			sb.WriteString(".")
+		case t.kind == tokenSymbol:
+		case mysql && (t.is("UNSIGNED") || t.is("SIGNED") || t.is("ZEROFILL")):
+		default:
+			if sb.Len() > 0 && !(i > 0 && tokens[i-1].text == ".") {
+				sb.WriteString(" ")
+			}
+			if t.kind == tokenWord {
+				sb.WriteString(strings.ToLower(t.text))
+			} else {
+				sb.WriteString(t.name())
+			}
+		}
+	}
+	dataType := sb.String()
+	if array {
+		return "ARRAY", 0, 0, 0
+	}
+
+	arg := func(i int) int {
+		if i >= len(args) {
+			return 0
+		}
+		n, _ := strconv.Atoi(args[i])
+		return n
+	}
+	switch {
+	case strings.Contains(dataType, "char"), strings.HasSuffix(dataType, "binary"), dataType == "bit varying":
+		return dataType, arg(0), 0, 0
+	case dataType == "numeric", dataType == "decimal", dataType == "dec", dataType == "fixed":
+		return dataType, 0, arg(0), arg(1)
+	}
+	return dataType, 0, 0, 0
+}
+
+// defaultExpression returns a column default as information_schema reports it. MySQL
+// reports string defaults unquoted, expression defaults without their parentheses and
+// DEFAULT NULL as no default at all.
+func (p *dumpParser) defaultExpression(src string, tokens []token) string {
+	if !p.mysql || len(tokens) != 1 {
+		return sourceText(src, tokens)
+	}
+	switch t := tokens[0]; {
+	case t.is("NULL"):
+		return ""
+	case t.kind == tokenString:
+		return t.name()
+	case t.kind == tokenGroup:
+		return strings.TrimSpace(t.inner())
+	}
+	return sourceText(src, tokens)
+}
+
+// parseTableConstraint parses a table constraint or, in MySQL, an index definition.
+// name is "" when the constraint is not named.
+func (p *dumpParser) parseTableConstraint(table *dbo.Table, name string, c *cursor) error {
+	switch {
+	case c.accept("PRIMARY", "KEY"):
+		p.acceptUsing(c)
+		keys, err := p.indexKeys(table, c.next())
+		if err != nil {
+			return err
+		}
+		return p.addPrimaryKey(table, name, keys)
+	case c.accept("UNIQUE"):
+		_ = c.accept("KEY") || c.accept("INDEX")
+		if t := c.peek(); t.kind == tokenWord && !t.is("USING") || t.kind == tokenIdent {
+			name = c.next().name()
+		}
+		p.acceptUsing(c)
+		keys, err := p.indexKeys(table, c.next())
+		if err != nil {
+			return err
+		}
+		return p.addUnique(table, name, keys)
+	case c.accept("FOREIGN", "KEY"):
+		if t := c.peek(); t.kind != tokenGroup {
+			// MySQL accepts an index name here
+			c.next()
+		}
+		keys, err := p.indexKeys(table, c.next())
+		if err != nil {
+			return err
+		}
+		if !c.accept("REFERENCES") {
+			return fmt.Errorf("missing REFERENCES in foreign key of %s", table.Name())
+		}
+		ref, err := p.parseReference(c)
+		if err != nil {
+			return err
+		}
+		return p.addForeignKey(table, name, keys.columns, ref)
+	case c.accept("CHECK"):
+		return p.addCheck(table, name, c.next(), "")
+	case c.accept("KEY"), c.accept("INDEX"):
+		return p.parseIndexElement(table, c, "")
+	case c.accept("FULLTEXT"), c.accept("SPATIAL"):
+		indexType := strings.ToUpper(c.toks[c.pos-1].text)
+		_ = c.accept("KEY") || c.accept("INDEX")
+		return p.parseIndexElement(table, c, indexType)
+	case c.accept("PERIOD", "FOR"):
+		return p.addPeriod(table, c)
+	}
+	// EXCLUDE constraints and LIKE clauses are not mapped
+	return nil
+}
+
+// addPeriod parses the "name (start, end)" rest of a MariaDB PERIOD FOR element
+func (p *dumpParser) addPeriod(table *dbo.Table, c *cursor) error {
+	name := c.next().name()
+	if strings.EqualFold(name, dbo.PeriodSystemTime) {
+		name = dbo.PeriodSystemTime
+	}
+	keys, err := p.indexKeys(table, c.next())
+	if err != nil {
+		return err
+	}
+	if len(keys.columns) != 2 {
+		return fmt.Errorf("period %s of %s needs a start and an end column", name, table.Name())
+	}
+	table.AddPeriod(dbo.NewPeriod(name, keys.columns[0], keys.columns[1]))
+	return nil
+}
+
+// parseIndexElement parses the "[name] [USING type] (keys) [options]" rest of a MySQL
+// KEY element
+func (p *dumpParser) parseIndexElement(table *dbo.Table, c *cursor, indexType string) error {
+	name := ""
+	if t := c.peek(); t.kind == tokenIdent || t.kind == tokenWord && !t.is("USING") {
+		name = c.next().name()
+	}
+	if method := p.acceptUsing(c); method != "" && indexType == "" {
+		indexType = method
+	}
+	keys := c.next()
+	invisible := false
+	for !c.done() {
+		switch {
+		case c.peek().is("USING"):
+			if method := p.acceptUsing(c); method != "" && indexType == "" {
+				indexType = method
+			}
+		case c.accept("INVISIBLE"):
+			invisible = true
+		default:
+			// COMMENT, KEY_BLOCK_SIZE, VISIBLE and the like are not mapped
+			c.next()
+		}
+	}
+	idx, err := p.addIndex(table, name, keys, false, indexType, "")
+	if err != nil {
+		return err
+	}
+	idx.SetInvisible(invisible)
+	return nil
+}
+
+// acceptUsing consumes a USING clause and returns the index method it names
+func (p *dumpParser) acceptUsing(c *cursor) string {
+	if !c.accept("USING") {
+		return ""
+	}
+	return p.indexMethod(c.next().text)
+}
+
+// indexMethod spells an index method the way the live adapters report it
+func (p *dumpParser) indexMethod(method string) string {
+	if p.mysql {
+		return strings.ToUpper(method)
+	}
+	return strings.ToLower(method)
+}
+
+// parseReference parses the part of a foreign key after REFERENCES
+func (p *dumpParser) parseReference(c *cursor) (foreignKeyReference, error) {
+	schema, name, err := c.objectName()
+	if err != nil {
+		return foreignKeyReference{}, err
+	}
+	if schema == "" {
+		schema = p.defaultSchema
+	}
+	ref := foreignKeyReference{schema: schema, table: name, onDelete: dbo.ActionNoAction, onUpdate: dbo.ActionNoAction}
+
+	if c.peek().kind == tokenGroup {
+		for _, part := range splitTokens(tokenize(c.next().inner(), p.mysql)) {
+			if len(part) > 0 {
+				ref.columns = append(ref.columns, part[0].name())
+			}
+		}
+	}
+	for !c.done() {
+		switch {
+		case c.accept("ON", "DELETE"):
+			ref.onDelete = referentialAction(c)
+		case c.accept("ON", "UPDATE"):
+			ref.onUpdate = referentialAction(c)
+		case c.accept("MATCH"):
+			c.next()
+		default:
+			// NOT DEFERRABLE, INITIALLY IMMEDIATE and the like
+			if !c.peek().is("NOT") && !c.peek().is("DEFERRABLE") && !c.peek().is("INITIALLY") && !c.peek().is("IMMEDIATE") && !c.peek().is("DEFERRED") {
+				return ref, nil
+			}
+			c.next()
+		}
+	}
+	return ref, nil
+}
+
+// referentialAction consumes the action of an ON DELETE or ON UPDATE clause
+func referentialAction(c *cursor) dbo.ReferentialAction {
+	switch {
+	case c.accept("CASCADE"):
+		return dbo.ActionCascade
+	case c.accept("RESTRICT"):
+		return dbo.ActionRestrict
+	case c.accept("SET", "NULL"):
+		return dbo.ActionSetNull
+	case c.accept("SET", "DEFAULT"):
+		return dbo.ActionSetDefault
+	}
+	c.accept("NO", "ACTION")
+	return dbo.ActionNoAction
+}
+
+// keyList is the parsed key list of an index or constraint
+type keyList struct {
+	columns []*dbo.Column
+	// prefixLengths holds the MySQL prefix length of each column, with 0 for a column
+	// indexed whole
+	prefixLengths []int
+	expressions   []string
+}
+
+// addTo adds the keys to an index
+func (k keyList) addTo(idx *dbo.Index) {
+	for i, col := range k.columns {
+		if i < len(k.prefixLengths) && k.prefixLengths[i] > 0 {
+			idx.AddPrefixColumn(col, k.prefixLengths[i])
+		} else {
+			idx.AddColumn(col)
+		}
+	}
+	for _, expression := range k.expressions {
+		idx.AddExpression(expression)
+	}
+}
+
+// indexKeys parses the key list of an index or constraint into the table columns, their
+// MySQL prefix lengths and the expressions it indexes. Sort orders and operator classes
+// are dropped.
+func (p *dumpParser) indexKeys(table *dbo.Table, group token) (keyList, error) {
+	if group.kind != tokenGroup {
+		return keyList{}, fmt.Errorf("missing key list in %s", table.Name())
+	}
+	src := group.inner()
+	var keys keyList
+	for _, part := range splitTokens(tokenize(src, p.mysql)) {
+		if len(part) == 0 {
+			continue
+		}
+		first := part[0]
+		prefixLength := p.mysql && len(part) > 1 && part[1].kind == tokenGroup
+		if (first.kind == tokenWord || first.kind == tokenIdent) && (len(part) == 1 || part[1].kind != tokenGroup || prefixLength) {
+			col, ok := table.Columns()[first.name()]
+			if !ok {
+				return keyList{}, fmt.Errorf("unknown column %s.%s", table.Name(), first.name())
+			}
+			length := 0
+			if prefixLength {
+				length, _ = strconv.Atoi(strings.TrimSpace(part[1].inner()))
+			}
+			keys.columns = append(keys.columns, col)
+			keys.prefixLengths = append(keys.prefixLengths, length)
+			continue
+		}
+
+		for len(part) > 1 && (part[len(part)-1].is("ASC") || part[len(part)-1].is("DESC")) {
+			part = part[:len(part)-1]
+		}
+		expression := sourceText(src, part)
+		if len(part) == 1 && part[0].kind == tokenGroup {
+			// MySQL wraps functional key parts in parentheses
+			expression = strings.TrimSpace(part[0].inner())
+		}
+		keys.expressions = append(keys.expressions, expression)
+	}
+	return keys, nil
+}
+
+// columnNames joins the names of columns for generated constraint names
+func columnNames(columns []*dbo.Column) string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name()
+	}
+	return strings.Join(names, "_")
+}
+
+// addPrimaryKey sets the primary key of a table and adds the index backing it. MySQL
+// always names primary keys PRIMARY; PostgreSQL defaults to <table>_pkey. Both make the
+// key columns NOT NULL.
+func (p *dumpParser) addPrimaryKey(table *dbo.Table, name string, keys keyList) error {
+	switch {
+	case p.mysql:
+		name = "PRIMARY"
+	case name == "":
+		name = table.Name() + "_pkey"
+	}
+	for _, col := range keys.columns {
+		col.SetNullable(false)
+	}
+	table.SetPrimaryKey(dbo.NewPrimaryKey(name, table, keys.columns))
+
+	idx := dbo.NewIndex(name, table, nil, true)
+	idx.SetPrimary(true)
+	idx.SetIndexType(dbo.IndexType(p.indexMethod("btree")))
+	keys.addTo(idx)
+	table.AddIndex(idx)
+	return nil
+}
+
+// addUnique adds a UNIQUE constraint and the unique index backing it. Unnamed MySQL
+// unique keys are named after their first column, PostgreSQL ones <table>_<columns>_key.
+func (p *dumpParser) addUnique(table *dbo.Table, name string, keys keyList) error {
+	if name == "" && len(keys.columns) > 0 {
+		if p.mysql {
+			name = keys.columns[0].Name()
+		} else {
+			name = table.Name() + "_" + columnNames(keys.columns) + "_key"
+		}
+	}
+	constraint := dbo.NewConstraint(name, dbo.ConstraintTypeUnique)
+	for _, col := range keys.columns {
+		constraint.AddColumn(col)
+	}
+	table.AddConstraint(constraint)
+
+	idx := dbo.NewIndex(name, table, nil, true)
+	idx.SetIndexType(dbo.IndexType(p.indexMethod("btree")))
+	keys.addTo(idx)
+	table.AddIndex(idx)
+	return nil
+}
+
+// addIndex adds an index and returns it. group is the parenthesized key list.
+func (p *dumpParser) addIndex(table *dbo.Table, name string, group token, unique bool, indexType, predicate string) (*dbo.Index, error) {
+	keys, err := p.indexKeys(table, group)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" && len(keys.columns) > 0 {
+		name = keys.columns[0].Name()
+	}
+	if indexType == "" {
+		indexType = p.indexMethod("btree")
+	}
+
+	idx := dbo.NewIndex(name, table, nil, unique)
+	idx.SetIndexType(dbo.IndexType(indexType))
+	keys.addTo(idx)
+	idx.SetPredicate(predicate)
+	table.AddIndex(idx)
+	return idx, nil
+}
+
+// addForeignKey adds a foreign key. Unnamed keys get the names the servers would
+// generate: <table>_<columns>_fkey in PostgreSQL, <table>_ibfk_<n> in MySQL.
+func (p *dumpParser) addForeignKey(table *dbo.Table, name string, columns []*dbo.Column, ref foreignKeyReference) error {
+	if name == "" {
+		if p.mysql {
+			name = fmt.Sprintf("%s_ibfk_%d", table.Name(), len(table.ForeignKeys())+1)
+		} else {
+			name = table.Name() + "_" + columnNames(columns) + "_fkey"
+		}
+	}
+
+	fk := dbo.NewForeignKey(name, ref.table)
+	fk.SetReferencedSchema(ref.schema)
+	fk.SetOnDelete(ref.onDelete)
+	fk.SetOnUpdate(ref.onUpdate)
+	for _, col := range columns {
+		fk.AddColumn(col)
+	}
+	// Referenced columns are shared with the referenced table once it exists, so later
+	// renames of the column apply to the key too
+	var refTable *dbo.Table
+	if s, ok := p.schemas[ref.schema]; ok {
+		refTable = s.tables[ref.table]
+	}
+	for _, refColumn := range ref.columns {
+		col := dbo.NewColumn(refColumn, "", false)
+		if refTable != nil && refTable.Columns()[refColumn] != nil {
+			col = refTable.Columns()[refColumn]
+		}
+		fk.AddReferencedColumn(col)
+	}
+	table.AddForeignKey(fk)
+	return nil
+}
+
+// addCheck adds a CHECK constraint. The expression is stored the way the live adapters
+// report it: "CHECK (...)" for PostgreSQL, the parenthesized condition for MySQL. The
+// constraint covers the columns its expression mentions.
+func (p *dumpParser) addCheck(table *dbo.Table, name string, group token, column string) error {
+	if group.kind != tokenGroup {
+		return fmt.Errorf("missing expression in check constraint of %s", table.Name())
+	}
+
+	checks := 0
+	for _, constraint := range table.Constraints() {
+		if constraint.Type() == dbo.ConstraintTypeCheck {
+			checks++
+		}
+	}
+	if name == "" {
+		switch {
+		case p.mysql:
+			name = fmt.Sprintf("%s_chk_%d", table.Name(), checks+1)
+		case column != "":
+			name = table.Name() + "_" + column + "_check"
+		default:
+			name = table.Name() + "_check"
+		}
+	}
+
+	constraint := dbo.NewConstraint(name, dbo.ConstraintTypeCheck)
+	if p.mysql {
+		constraint.SetCheckExpression(group.text)
+	} else {
+		constraint.SetCheckExpression("CHECK " + group.text)
+	}
+	seen := make(map[string]bool)
+	for _, name := range referencedNames(group, p.mysql) {
+		if col, ok := table.Columns()[name]; ok && !seen[name] {
+			seen[name] = true
+			constraint.AddColumn(col)
+		}
+	}
+	table.AddConstraint(constraint)
+	return nil
+}
+
+// referencedNames returns the identifiers used in a parenthesized expression
+func referencedNames(group token, mysql bool) []string {
+	var names []string
+	for _, t := range tokenize(group.inner(), mysql) {
+		switch t.kind {
+		case tokenWord, tokenIdent:
+			names = append(names, t.name())
+		case tokenGroup:
+			names = append(names, referencedNames(t, mysql)...)
+		}
+	}
+	return names
+}
+
+// parseCreateIndex handles CREATE INDEX, including partial and expression indexes
+func (p *dumpParser) parseCreateIndex(c *cursor, unique bool, indexType string) error {
+	c.accept("CONCURRENTLY")
+	ifNotExists := c.accept("IF", "NOT", "EXISTS")
+	name := ""
+	if !c.peek().is("ON") {
+		name = c.next().name()
+	}
+	if method := p.acceptUsing(c); method != "" && indexType == "" {
+		indexType = method
+	}
+	if !c.accept("ON") {
+		return fmt.Errorf("missing table in index %s", name)
+	}
+	c.accept("ONLY")
+	schema, tableName, err := c.objectName()
+	if err != nil {
+		return err
+	}
+	table, err := p.table(schema, tableName)
+	if err != nil {
+		return err
+	}
+	if ifNotExists && p.findIndex(p.schema(schema), name) != nil {
+		return nil
+	}
+	if method := p.acceptUsing(c); method != "" && indexType == "" {
+		indexType = method
+	}
+
+	keys := c.next()
+	predicate := ""
+	invisible := false
+	for !c.done() {
+		t := c.next()
+		if t.is("WHERE") {
+			predicate = sourceText(c.src, c.rest())
+			break
+		}
+		invisible = invisible || p.mysql && t.is("INVISIBLE")
+	}
+	idx, err := p.addIndex(table, name, keys, unique, indexType, predicate)
+	if err != nil {
+		return err
+	}
+	idx.SetInvisible(invisible)
+	return nil
+}
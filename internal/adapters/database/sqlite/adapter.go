@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"database/sql"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// Pure-Go SQLite driver, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteFileExtensions are the file extensions recognised as SQLite databases when the
+// connection string is a plain path
+var sqliteFileExtensions = []string{".db", ".sqlite", ".sqlite3"}
+
+type SQLiteAdapter struct {
+	db   *sql.DB
+	path string
+}
+
+func (a *SQLiteAdapter) Name() string {
+	return "SQLite"
+}
+
+func (a *SQLiteAdapter) Version() string {
+	return "v1"
+}
+
+func (a *SQLiteAdapter) UniqueSignature() string {
+	return a.Name() + "-" + a.Version()
+}
+
+func (a *SQLiteAdapter) IsConnectionStringCompatible(connString string) bool {
+	return databasePath(connString) != ""
+}
+
+// Connect opens the database file read-only. The file must exist, so a mistyped path
+// is reported instead of silently creating an empty database.
+func (a *SQLiteAdapter) Connect(connString string) error {
+	path := databasePath(connString)
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", "file:"+(&url.URL{Path: path}).EscapedPath()+"?mode=ro")
+	if err != nil {
+		return err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return err
+	}
+
+	a.db = db
+	a.path = path
+	return nil
+}
+
+func (a *SQLiteAdapter) Close() error {
+	if a.db != nil {
+		return a.db.Close()
+	}
+	return nil
+}
+
+func (a *SQLiteAdapter) IsConnected() bool {
+	return a.db != nil
+}
+
+// databasePath returns the file path of a "sqlite://path" or "sqlite:path" connection
+// string, or of a plain path with a SQLite file extension. It returns "" for anything else.
+func databasePath(connString string) string {
+	for _, prefix := range []string{"sqlite://", "sqlite:"} {
+		if path, ok := strings.CutPrefix(connString, prefix); ok {
+			return path
+		}
+	}
+	for _, ext := range sqliteFileExtensions {
+		if strings.EqualFold(filepath.Ext(connString), ext) {
+			return connString
+		}
+	}
+	return ""
+}
@@ -0,0 +1,286 @@
+package sqlite
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SQLite keeps the original CREATE statements in sqlite_master but exposes some details,
+// such as CHECK constraints and index predicates, nowhere else. The helpers below take
+// those statements apart just far enough to recover them.
+
+// walkSQL calls visit for every byte of s outside quoted strings, quoted identifiers and
+// comments, passing the parenthesis depth at that byte. An opening parenthesis and its
+// matching closing parenthesis are visited at the same depth. Walking stops when visit
+// returns false.
+func walkSQL(s string, visit func(i, depth int) bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			for i++; i < len(s); i++ {
+				if s[i] != closing {
+					continue
+				}
+				// A doubled quote is an escaped quote
+				if closing != ']' && i+1 < len(s) && s[i+1] == closing {
+					i++
+					continue
+				}
+				break
+			}
+			continue
+		case strings.HasPrefix(s[i:], "--"):
+			if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(s)
+			}
+			continue
+		case strings.HasPrefix(s[i:], "/*"):
+			if end := strings.Index(s[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(s)
+			}
+			continue
+		case c == ')':
+			depth--
+		}
+		if !visit(i, depth) {
+			return
+		}
+		if c == '(' {
+			depth++
+		}
+	}
+}
+
+// isIdentifierByte reports whether c can be part of an unquoted identifier or keyword
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// findKeyword returns the index of the first occurrence of keyword in s at parenthesis
+// depth 0, outside quotes and comments, or -1
+func findKeyword(s, keyword string) int {
+	found := -1
+	walkSQL(s, func(i, depth int) bool {
+		if depth != 0 || i+len(keyword) > len(s) || !strings.EqualFold(s[i:i+len(keyword)], keyword) {
+			return true
+		}
+		if i > 0 && isIdentifierByte(s[i-1]) {
+			return true
+		}
+		if end := i + len(keyword); end < len(s) && isIdentifierByte(s[end]) {
+			return true
+		}
+		found = i
+		return false
+	})
+	return found
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open, or -1
+func matchingParen(s string, open int) int {
+	found := -1
+	walkSQL(s[open:], func(i, depth int) bool {
+		if i > 0 && depth == 0 && s[open+i] == ')' {
+			found = open + i
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// firstParen returns the index of the first parenthesis at depth 0 outside quotes, or -1
+func firstParen(s string) int {
+	found := -1
+	walkSQL(s, func(i, depth int) bool {
+		if s[i] == '(' {
+			found = i
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// splitTopLevel splits s at the commas that are not nested in parentheses or quotes
+func splitTopLevel(s string) []string {
+	var parts []string
+	start := 0
+	walkSQL(s, func(i, depth int) bool {
+		if depth == 0 && s[i] == ',' {
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+		return true
+	})
+	if rest := strings.TrimSpace(s[start:]); rest != "" || len(parts) > 0 {
+		parts = append(parts, rest)
+	}
+	return parts
+}
+
+// leadingIdentifier returns the first identifier of s, unquoted
+func leadingIdentifier(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	if closing := map[byte]byte{'"': '"', '`': '`', '[': ']'}[s[0]]; closing != 0 {
+		if end := strings.IndexByte(s[1:], closing); end >= 0 {
+			return strings.ReplaceAll(s[1:end+1], string(closing)+string(closing), string(closing))
+		}
+		return s[1:]
+	}
+	end := 0
+	for end < len(s) && isIdentifierByte(s[end]) {
+		end++
+	}
+	return s[:end]
+}
+
+// checkConstraint is a CHECK clause found in a CREATE TABLE statement
+type checkConstraint struct {
+	// name is empty when the constraint is not named in the DDL
+	name       string
+	expression string
+	// column is set for column constraints
+	column string
+}
+
+// constraintNamePattern matches a "CONSTRAINT name" prefix at the end of a definition
+var constraintNamePattern = regexp.MustCompile("(?is)\\bCONSTRAINT\\s+(\"(?:[^\"]|\"\")+\"|`[^`]+`|\\[[^\\]]+\\]|[^\\s(]+)\\s*$")
+
+// tableConstraintKeywords start a table constraint rather than a column definition
+var tableConstraintKeywords = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN"}
+
+// parseCheckConstraints returns the CHECK constraints of a CREATE TABLE statement, in
+// the order they are declared
+func parseCheckConstraints(ddl string) []checkConstraint {
+	open := firstParen(ddl)
+	if open < 0 {
+		return nil
+	}
+	closing := matchingParen(ddl, open)
+	if closing < 0 {
+		return nil
+	}
+
+	var checks []checkConstraint
+	for _, definition := range splitTopLevel(ddl[open+1 : closing]) {
+		column := leadingIdentifier(definition)
+		if quoted := strings.IndexAny(definition, "\"`[") == 0; !quoted {
+			for _, keyword := range tableConstraintKeywords {
+				if strings.EqualFold(column, keyword) {
+					column = ""
+					break
+				}
+			}
+		}
+
+		rest := definition
+		for {
+			pos := findKeyword(rest, "CHECK")
+			if pos < 0 {
+				break
+			}
+			paren := pos + len("CHECK")
+			for paren < len(rest) && (rest[paren] == ' ' || rest[paren] == '\t' || rest[paren] == '\n' || rest[paren] == '\r') {
+				paren++
+			}
+			if paren >= len(rest) || rest[paren] != '(' {
+				break
+			}
+			end := matchingParen(rest, paren)
+			if end < 0 {
+				break
+			}
+
+			check := checkConstraint{expression: strings.TrimSpace(rest[paren+1 : end]), column: column}
+			if m := constraintNamePattern.FindStringSubmatch(rest[:pos]); m != nil {
+				check.name = leadingIdentifier(m[1])
+			}
+			checks = append(checks, check)
+			rest = rest[end+1:]
+		}
+	}
+	return checks
+}
+
+// parseIndexDefinition returns the key terms and the WHERE predicate of a CREATE INDEX
+// statement. Sort order keywords are removed from the terms.
+func parseIndexDefinition(ddl string) ([]string, string) {
+	on := findKeyword(ddl, "ON")
+	if on < 0 {
+		return nil, ""
+	}
+	open := firstParen(ddl[on:])
+	if open < 0 {
+		return nil, ""
+	}
+	open += on
+	closing := matchingParen(ddl, open)
+	if closing < 0 {
+		return nil, ""
+	}
+
+	keys := splitTopLevel(ddl[open+1 : closing])
+	for i, key := range keys {
+		for _, order := range []string{"ASC", "DESC"} {
+			if len(key) > len(order) && strings.EqualFold(key[len(key)-len(order):], order) && !isIdentifierByte(key[len(key)-len(order)-1]) {
+				key = strings.TrimSpace(key[:len(key)-len(order)])
+			}
+		}
+		keys[i] = key
+	}
+
+	predicate := ""
+	rest := ddl[closing+1:]
+	if where := findKeyword(rest, "WHERE"); where >= 0 {
+		predicate = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rest[where+len("WHERE"):]), ";"))
+	}
+	return keys, predicate
+}
+
+// viewQuery returns the SELECT statement of a CREATE VIEW statement
+func viewQuery(ddl string) string {
+	as := findKeyword(ddl, "AS")
+	if as < 0 {
+		return ddl
+	}
+	return strings.TrimSpace(ddl[as+len("AS"):])
+}
+
+// triggerTiming returns the timing keyword and the event of a CREATE TRIGGER statement.
+// SQLite triggers fire BEFORE when no timing is given.
+func triggerTiming(ddl string) (string, string) {
+	header := ddl
+	if on := findKeyword(ddl, "ON"); on >= 0 {
+		header = ddl[:on]
+	}
+
+	timing := "BEFORE"
+	switch {
+	case findKeyword(header, "INSTEAD") >= 0:
+		timing = "INSTEAD OF"
+	case findKeyword(header, "AFTER") >= 0:
+		timing = "AFTER"
+	}
+
+	event, first := "", len(header)
+	for _, candidate := range []string{"INSERT", "UPDATE", "DELETE"} {
+		if pos := findKeyword(header, candidate); pos >= 0 && pos < first {
+			event, first = candidate, pos
+		}
+	}
+	return timing, event
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCheckConstraints(t *testing.T) {
+	ddl := `CREATE TABLE "orders" (
+		id INTEGER PRIMARY KEY,
+		qty INTEGER NOT NULL CHECK (qty > 0),
+		"check" TEXT CHECK ("check" <> 'x, y'), -- a comment with CHECK (nothing)
+		price REAL,
+		CONSTRAINT price_positive CHECK (price >= 0 AND (qty * price) < 1000),
+		CHECK(length(note) < 10)
+	)`
+
+	expected := []checkConstraint{
+		{expression: "qty > 0", column: "qty"},
+		{expression: `"check" <> 'x, y'`, column: "check"},
+		{name: "price_positive", expression: "price >= 0 AND (qty * price) < 1000"},
+		{expression: "length(note) < 10"},
+	}
+	if got := parseCheckConstraints(ddl); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestParseIndexDefinition(t *testing.T) {
+	tests := []struct {
+		ddl       string
+		keys      []string
+		predicate string
+	}{
+		{"CREATE INDEX idx ON users (email)", []string{"email"}, ""},
+		{"CREATE UNIQUE INDEX idx ON users(lower(email) DESC, id ASC) WHERE deleted_at IS NULL", []string{"lower(email)", "id"}, "deleted_at IS NULL"},
+		{`CREATE INDEX "on" ON "t (x)" (a COLLATE NOCASE)`, []string{"a COLLATE NOCASE"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ddl, func(t *testing.T) {
+			keys, predicate := parseIndexDefinition(tt.ddl)
+			if !reflect.DeepEqual(keys, tt.keys) || predicate != tt.predicate {
+				t.Errorf("expected %v %q, got %v %q", tt.keys, tt.predicate, keys, predicate)
+			}
+		})
+	}
+}
+
+func TestTriggerTiming(t *testing.T) {
+	tests := []struct {
+		ddl    string
+		timing string
+		event  string
+	}{
+		{"CREATE TRIGGER t AFTER INSERT ON orders BEGIN SELECT 1; END", "AFTER", "INSERT"},
+		{"CREATE TRIGGER t UPDATE OF qty ON orders BEGIN SELECT 1; END", "BEFORE", "UPDATE"},
+		{"CREATE TRIGGER insert_audit INSTEAD OF DELETE ON v BEGIN SELECT 1; END", "INSTEAD OF", "DELETE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ddl, func(t *testing.T) {
+			if timing, event := triggerTiming(tt.ddl); timing != tt.timing || event != tt.event {
+				t.Errorf("expected %s %s, got %s %s", tt.timing, tt.event, timing, event)
+			}
+		})
+	}
+}
+
+func TestDatabasePath(t *testing.T) {
+	tests := []struct {
+		connString string
+		expected   string
+	}{
+		{"sqlite:///var/lib/app.db", "/var/lib/app.db"},
+		{"sqlite:edge", "edge"},
+		{"./data/app.sqlite3", "./data/app.sqlite3"},
+		{"APP.DB", "APP.DB"},
+		{"postgres://localhost/app", ""},
+		{"user:pass@tcp(localhost:3306)/app", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.connString, func(t *testing.T) {
+			if got := databasePath(tt.connString); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,527 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// mainSchema is the name SQLite gives the database file opened by a connection
+const mainSchema = "main"
+
+func (a *SQLiteAdapter) MapDatabase() (*dbo.Database, []error) {
+	var errors []error
+	ctx := context.Background()
+
+	// SQLite databases are unnamed, so the file name stands in for the database name
+	dbName := strings.TrimSuffix(filepath.Base(a.path), filepath.Ext(a.path))
+	db := dbo.NewDatabase(dbName, nil)
+	db.SetEngine(a.Name())
+
+	schema := dbo.NewSchema(mainSchema, "", nil)
+	db.AddSchema(schema)
+
+	// Map tables
+	tables, errs := a.mapTables(ctx)
+	errors = append(errors, errs...)
+	for _, table := range tables {
+		schema.AddTable(table)
+	}
+
+	// Map columns for each table
+	for _, table := range schema.Tables() {
+		columns, errs := a.mapColumns(ctx, table.Name())
+		errors = append(errors, errs...)
+		for _, col := range columns {
+			table.AddColumn(col)
+		}
+	}
+
+	// Map primary keys
+	for _, table := range schema.Tables() {
+		pk, errs := a.mapPrimaryKey(ctx, table.Name(), table)
+		errors = append(errors, errs...)
+		if pk != nil {
+			table.SetPrimaryKey(pk)
+		}
+	}
+
+	// Map indexes
+	for _, table := range schema.Tables() {
+		indexes, errs := a.mapIndexes(ctx, table.Name(), table)
+		errors = append(errors, errs...)
+		for _, idx := range indexes {
+			table.AddIndex(idx)
+		}
+	}
+
+	// Map foreign keys (after primary keys, which implicit references point at)
+	for _, table := range schema.Tables() {
+		fks, errs := a.mapForeignKeys(ctx, table.Name(), table, schema)
+		errors = append(errors, errs...)
+		for _, fk := range fks {
+			table.AddForeignKey(fk)
+		}
+	}
+
+	// Map CHECK constraints
+	for _, table := range schema.Tables() {
+		constraints, errs := a.mapCheckConstraints(ctx, table.Name(), table)
+		errors = append(errors, errs...)
+		for _, c := range constraints {
+			table.AddConstraint(c)
+		}
+	}
+
+	// Map views
+	views, errs := a.mapViews(ctx)
+	errors = append(errors, errs...)
+	for _, view := range views {
+		schema.AddView(view)
+	}
+
+	// Map triggers
+	for _, table := range schema.Tables() {
+		triggers, errs := a.mapTriggers(ctx, table.Name())
+		errors = append(errors, errs...)
+		for _, trigger := range triggers {
+			table.AddTrigger(trigger)
+		}
+	}
+
+	if len(errors) > 0 {
+		return db, errors
+	}
+	return db, nil
+}
+
+func (a *SQLiteAdapter) mapTables(ctx context.Context) ([]*dbo.Table, []error) {
+	query := `
+		SELECT name
+		FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\'
+		ORDER BY name`
+
+	rows, err := a.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query tables: %w", err)}
+	}
+	defer rows.Close()
+
+	var tables []*dbo.Table
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return tables, []error{fmt.Errorf("failed to scan table: %w", err)}
+		}
+		tables = append(tables, dbo.NewTable(name, nil))
+	}
+	return tables, nil
+}
+
+// sqliteColumn is an entry of pragma_table_xinfo
+type sqliteColumn struct {
+	name          string
+	declaredType  string
+	notNull       bool
+	columnDefault sql.NullString
+	pk            int
+}
+
+func (a *SQLiteAdapter) mapColumns(ctx context.Context, tableName string) ([]*dbo.Column, []error) {
+	// table_xinfo also lists generated columns; hidden = 1 marks the hidden columns of
+	// virtual tables
+	query := `
+		SELECT name, type, "notnull", dflt_value, pk
+		FROM pragma_table_xinfo(?)
+		WHERE hidden <> 1
+		ORDER BY cid`
+
+	rows, err := a.db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query columns for %s: %w", tableName, err)}
+	}
+	defer rows.Close()
+
+	var listed []sqliteColumn
+	pkColumns := 0
+	for rows.Next() {
+		var c sqliteColumn
+		if err := rows.Scan(&c.name, &c.declaredType, &c.notNull, &c.columnDefault, &c.pk); err != nil {
+			return nil, []error{fmt.Errorf("failed to scan column: %w", err)}
+		}
+		if c.pk > 0 {
+			pkColumns++
+		}
+		listed = append(listed, c)
+	}
+
+	var columns []*dbo.Column
+	for i, c := range listed {
+		// An INTEGER PRIMARY KEY column is an alias for the rowid and can never be NULL,
+		// although SQLite does not report it as NOT NULL
+		rowidAlias := pkColumns == 1 && c.pk == 1 && strings.EqualFold(c.declaredType, "INTEGER")
+
+		dataType, length, precision, scale := parseDeclaredType(c.declaredType)
+		col := dbo.NewColumn(c.name, dataType, !c.notNull && !rowidAlias)
+		col.SetOrdinalPosition(i + 1)
+		if c.columnDefault.Valid {
+			col.SetDefaultValue(c.columnDefault.String)
+		}
+		if length != nil {
+			col.SetCharMaxLength(*length)
+		}
+		if precision != nil {
+			col.SetNumericPrecision(*precision)
+		}
+		if scale != nil {
+			col.SetNumericScale(*scale)
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func (a *SQLiteAdapter) mapPrimaryKey(ctx context.Context, tableName string, table *dbo.Table) (*dbo.PrimaryKey, []error) {
+	query := `
+		SELECT name
+		FROM pragma_table_info(?)
+		WHERE pk > 0
+		ORDER BY pk`
+
+	rows, err := a.db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query primary key for %s: %w", tableName, err)}
+	}
+	defer rows.Close()
+
+	var pk *dbo.PrimaryKey
+	for rows.Next() {
+		var columnName string
+		if err := rows.Scan(&columnName); err != nil {
+			return pk, []error{fmt.Errorf("failed to scan primary key: %w", err)}
+		}
+
+		// SQLite does not name primary keys
+		if pk == nil {
+			pk = dbo.NewPrimaryKey(tableName+"_pkey", table, nil)
+		}
+
+		if col, exists := table.Columns()[columnName]; exists {
+			pk.AddColumn(col)
+		}
+	}
+	return pk, nil
+}
+
+// sqliteIndex is an entry of pragma_index_list
+type sqliteIndex struct {
+	name       string
+	unique     bool
+	origin     string
+	partial    bool
+	definition string
+}
+
+func (a *SQLiteAdapter) mapIndexes(ctx context.Context, tableName string, table *dbo.Table) ([]*dbo.Index, []error) {
+	query := `
+		SELECT il.name, il."unique", il.origin, il.partial, COALESCE(m.sql, '')
+		FROM pragma_index_list(?) il
+		LEFT JOIN sqlite_master m ON m.type = 'index' AND m.name = il.name
+		ORDER BY il.name`
+
+	rows, err := a.db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query indexes for %s: %w", tableName, err)}
+	}
+	var listed []sqliteIndex
+	for rows.Next() {
+		var idx sqliteIndex
+		if err := rows.Scan(&idx.name, &idx.unique, &idx.origin, &idx.partial, &idx.definition); err != nil {
+			rows.Close()
+			return nil, []error{fmt.Errorf("failed to scan index: %w", err)}
+		}
+		listed = append(listed, idx)
+	}
+	rows.Close()
+
+	var indexes []*dbo.Index
+	var errors []error
+	for _, entry := range listed {
+		idx := dbo.NewIndex(entry.name, table, nil, entry.unique)
+		// origin is "pk" for the index backing a PRIMARY KEY, "u" for a UNIQUE constraint
+		// and "c" for CREATE INDEX
+		idx.SetPrimary(entry.origin == "pk")
+
+		keys, predicate := parseIndexDefinition(entry.definition)
+		if entry.partial {
+			idx.SetPredicate(predicate)
+		}
+
+		// cid is -2 for an expression key and -1 for the rowid
+		keyRows, err := a.db.QueryContext(ctx, `SELECT seqno, cid, COALESCE(name, '') FROM pragma_index_info(?) ORDER BY seqno`, entry.name)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("failed to query columns of index %s: %w", entry.name, err))
+			continue
+		}
+		for keyRows.Next() {
+			var seqno, cid int
+			var columnName string
+			if err := keyRows.Scan(&seqno, &cid, &columnName); err != nil {
+				errors = append(errors, fmt.Errorf("failed to scan index column: %w", err))
+				break
+			}
+			if cid == -2 {
+				if seqno < len(keys) {
+					idx.AddExpression(keys[seqno])
+				}
+				continue
+			}
+			if col, colExists := table.Columns()[columnName]; colExists {
+				idx.AddColumn(col)
+			}
+		}
+		keyRows.Close()
+		indexes = append(indexes, idx)
+	}
+	return indexes, errors
+}
+
+// sqliteForeignKey collects the rows of one foreign key from pragma_foreign_key_list
+type sqliteForeignKey struct {
+	refTable   string
+	onUpdate   string
+	onDelete   string
+	columns    []string
+	refColumns []sql.NullString
+}
+
+func (a *SQLiteAdapter) mapForeignKeys(ctx context.Context, tableName string, table *dbo.Table, schema *dbo.Schema) ([]*dbo.ForeignKey, []error) {
+	query := `
+		SELECT id, "table", "from", "to", on_update, on_delete
+		FROM pragma_foreign_key_list(?)
+		ORDER BY id, seq`
+
+	rows, err := a.db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query foreign keys for %s: %w", tableName, err)}
+	}
+	defer rows.Close()
+
+	fkMap := make(map[int]*sqliteForeignKey)
+	var fkOrder []int
+
+	for rows.Next() {
+		var id int
+		var refTable, columnName, onUpdate, onDelete string
+		var refColumn sql.NullString
+		if err := rows.Scan(&id, &refTable, &columnName, &refColumn, &onUpdate, &onDelete); err != nil {
+			return nil, []error{fmt.Errorf("failed to scan foreign key: %w", err)}
+		}
+
+		entry, exists := fkMap[id]
+		if !exists {
+			entry = &sqliteForeignKey{refTable: refTable, onUpdate: onUpdate, onDelete: onDelete}
+			fkMap[id] = entry
+			fkOrder = append(fkOrder, id)
+		}
+		entry.columns = append(entry.columns, columnName)
+		entry.refColumns = append(entry.refColumns, refColumn)
+	}
+
+	// SQLite does not name foreign keys, so they are named after their columns the way
+	// PostgreSQL names them by default
+	var fks []*dbo.ForeignKey
+	used := make(map[string]bool)
+	for _, id := range fkOrder {
+		entry := fkMap[id]
+		fk := dbo.NewForeignKey(uniqueName(used, tableName+"_"+strings.Join(entry.columns, "_")+"_fkey"), entry.refTable)
+		fk.SetReferencedSchema(mainSchema)
+		fk.SetOnDelete(dbo.ReferentialAction(entry.onDelete))
+		fk.SetOnUpdate(dbo.ReferentialAction(entry.onUpdate))
+
+		for i, columnName := range entry.columns {
+			if col, colExists := table.Columns()[columnName]; colExists {
+				fk.AddColumn(col)
+			}
+
+			// A reference without a column list points at the primary key of the parent table
+			refColumn := entry.refColumns[i].String
+			if !entry.refColumns[i].Valid {
+				if parent := schema.Tables()[entry.refTable]; parent != nil && parent.PrimaryKey() != nil && i < len(parent.PrimaryKey().Columns()) {
+					refColumn = parent.PrimaryKey().Columns()[i].Name()
+				}
+			}
+			fk.AddReferencedColumn(dbo.NewColumn(refColumn, "", false))
+		}
+		fks = append(fks, fk)
+	}
+	return fks, nil
+}
+
+func (a *SQLiteAdapter) mapCheckConstraints(ctx context.Context, tableName string, table *dbo.Table) ([]*dbo.Constraint, []error) {
+	var ddl string
+	err := a.db.QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&ddl)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query definition of %s: %w", tableName, err)}
+	}
+
+	var constraints []*dbo.Constraint
+	used := make(map[string]bool)
+	for _, check := range parseCheckConstraints(ddl) {
+		name := check.name
+		if name == "" {
+			base := tableName + "_check"
+			if check.column != "" {
+				base = tableName + "_" + check.column + "_check"
+			}
+			name = uniqueName(used, base)
+		}
+		used[name] = true
+
+		constraint := dbo.NewConstraint(name, dbo.ConstraintTypeCheck)
+		constraint.SetCheckExpression("CHECK (" + check.expression + ")")
+		if col, exists := table.Columns()[check.column]; exists {
+			constraint.AddColumn(col)
+		} else {
+			for _, col := range referencedColumns(table, check.expression) {
+				constraint.AddColumn(col)
+			}
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints, nil
+}
+
+func (a *SQLiteAdapter) mapViews(ctx context.Context) ([]*dbo.View, []error) {
+	query := `
+		SELECT name, sql
+		FROM sqlite_master
+		WHERE type = 'view'
+		ORDER BY name`
+
+	rows, err := a.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query views: %w", err)}
+	}
+	defer rows.Close()
+
+	var views []*dbo.View
+	for rows.Next() {
+		var name, ddl string
+		if err := rows.Scan(&name, &ddl); err != nil {
+			return views, []error{fmt.Errorf("failed to scan view: %w", err)}
+		}
+		views = append(views, dbo.NewView(name, viewQuery(ddl)))
+	}
+	return views, nil
+}
+
+func (a *SQLiteAdapter) mapTriggers(ctx context.Context, tableName string) ([]*dbo.Trigger, []error) {
+	query := `
+		SELECT name, sql
+		FROM sqlite_master
+		WHERE type = 'trigger' AND tbl_name = ?
+		ORDER BY name`
+
+	rows, err := a.db.QueryContext(ctx, query, tableName)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to query triggers for %s: %w", tableName, err)}
+	}
+	defer rows.Close()
+
+	var triggers []*dbo.Trigger
+	for rows.Next() {
+		var name, ddl string
+		if err := rows.Scan(&name, &ddl); err != nil {
+			return triggers, []error{fmt.Errorf("failed to scan trigger: %w", err)}
+		}
+
+		timing, event := triggerTiming(ddl)
+		trigger := dbo.NewTrigger(name, ddl)
+		trigger.SetTiming(dbo.TriggerTiming(timing))
+		if event != "" {
+			trigger.AddEvent(dbo.TriggerEvent(event))
+		}
+		// SQLite only has row-level triggers
+		trigger.SetForEach("ROW")
+		triggers = append(triggers, trigger)
+	}
+	return triggers, nil
+}
+
+// declaredTypePattern splits a declared column type such as "VARCHAR(255)" or
+// "DECIMAL(10, 2)" into its name and size arguments
+var declaredTypePattern = regexp.MustCompile(`^\s*(.*?)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?\s*$`)
+
+// parseDeclaredType returns the lower-cased type name of a declared column type with its
+// length (for text types) or precision and scale (for everything else)
+func parseDeclaredType(declared string) (string, *int, *int, *int) {
+	m := declaredTypePattern.FindStringSubmatch(declared)
+	if m == nil {
+		return strings.ToLower(declared), nil, nil, nil
+	}
+	dataType := strings.ToLower(m[1])
+	first, second := atoiOrNil(m[2]), atoiOrNil(m[3])
+	if first == nil {
+		return dataType, nil, nil, nil
+	}
+	// SQLite gives a column TEXT affinity when its type contains CHAR, CLOB or TEXT
+	for _, text := range []string{"char", "clob", "text"} {
+		if strings.Contains(dataType, text) {
+			return dataType, first, nil, nil
+		}
+	}
+	return dataType, nil, first, second
+}
+
+// atoiOrNil parses a non-empty decimal string
+func atoiOrNil(s string) *int {
+	if s == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// referencedColumns returns the columns of table named in a CHECK expression, in column order
+func referencedColumns(table *dbo.Table, expression string) []*dbo.Column {
+	var columns []*dbo.Column
+	for _, col := range table.Columns() {
+		pattern := regexp.MustCompile(`(?i)(^|[^\w$])["` + "`" + `\[]?` + regexp.QuoteMeta(col.Name()) + `["` + "`" + `\]]?($|[^\w$])`)
+		if pattern.MatchString(expression) {
+			columns = append(columns, col)
+		}
+	}
+	sortColumns(columns)
+	return columns
+}
+
+// sortColumns orders columns by ordinal position
+func sortColumns(columns []*dbo.Column) {
+	for i := 1; i < len(columns); i++ {
+		for j := i; j > 0 && columns[j].OrdinalPosition() < columns[j-1].OrdinalPosition(); j-- {
+			columns[j], columns[j-1] = columns[j-1], columns[j]
+		}
+	}
+}
+
+// uniqueName returns base, or base with the lowest numeric suffix not yet used, and
+// marks the result as used
+func uniqueName(used map[string]bool, base string) string {
+	name := base
+	for n := 1; used[name]; n++ {
+		name = base + strconv.Itoa(n)
+	}
+	used[name] = true
+	return name
+}
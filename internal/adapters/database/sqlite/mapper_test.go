@@ -0,0 +1,195 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// fixtureSchema covers the objects the adapter maps
+const fixtureSchema = `
+CREATE TABLE customers (
+	id INTEGER PRIMARY KEY,
+	email VARCHAR(255) NOT NULL,
+	deleted_at TEXT,
+	balance DECIMAL(10, 2) DEFAULT 0 CHECK (balance >= 0)
+);
+CREATE UNIQUE INDEX customers_email_key ON customers (lower(email)) WHERE deleted_at IS NULL;
+CREATE INDEX customers_deleted_at_idx ON customers (deleted_at DESC);
+
+CREATE TABLE orders (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	customer_id INTEGER REFERENCES customers ON DELETE CASCADE,
+	qty INTEGER NOT NULL,
+	CONSTRAINT qty_positive CHECK (qty > 0)
+);
+
+CREATE TABLE order_lines (
+	order_id INTEGER NOT NULL,
+	line INTEGER NOT NULL,
+	sku TEXT UNIQUE,
+	PRIMARY KEY (order_id, line),
+	FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+
+CREATE VIEW active_customers AS SELECT id, email FROM customers WHERE deleted_at IS NULL;
+
+CREATE TRIGGER orders_audit AFTER UPDATE OF qty ON orders BEGIN SELECT 1; END;
+`
+
+// newTestAdapter writes the fixture to a database file and connects to it
+func newTestAdapter(t *testing.T) *SQLiteAdapter {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "edge.db")
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(fixtureSchema); err != nil {
+		t.Fatalf("failed to create fixture: %v", err)
+	}
+	conn.Close()
+
+	adapter := &SQLiteAdapter{}
+	if !adapter.IsConnectionStringCompatible("sqlite://" + path) {
+		t.Fatal("expected sqlite:// connection string to be compatible")
+	}
+	if err := adapter.Connect("sqlite://" + path); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { adapter.Close() })
+	return adapter
+}
+
+func TestSQLiteAdapter_MapDatabase(t *testing.T) {
+	adapter := newTestAdapter(t)
+
+	db, errs := adapter.MapDatabase()
+	if len(errs) > 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	if db.Name() != "edge" || db.Engine() != "SQLite" {
+		t.Errorf("unexpected database %s (%s)", db.Name(), db.Engine())
+	}
+	schema := db.Schemas()["main"]
+	if schema == nil {
+		t.Fatal("expected main schema")
+	}
+	if _, exists := schema.Tables()["sqlite_sequence"]; exists {
+		t.Error("expected internal tables to be skipped")
+	}
+
+	customers := schema.Tables()["customers"]
+	orders := schema.Tables()["orders"]
+	lines := schema.Tables()["order_lines"]
+	if customers == nil || orders == nil || lines == nil {
+		t.Fatalf("expected all tables, got %d", len(schema.Tables()))
+	}
+
+	t.Run("columns", func(t *testing.T) {
+		id := customers.Columns()["id"]
+		if id.IsNullable() {
+			t.Error("expected INTEGER PRIMARY KEY to be NOT NULL")
+		}
+		email := customers.Columns()["email"]
+		if email.DataType() != "varchar" || email.CharMaxLength() == nil || *email.CharMaxLength() != 255 || email.IsNullable() {
+			t.Errorf("unexpected email column %s %v", email.DataType(), email.CharMaxLength())
+		}
+		balance := customers.Columns()["balance"]
+		if balance.NumericPrecision() == nil || *balance.NumericPrecision() != 10 || *balance.NumericScale() != 2 {
+			t.Error("expected decimal precision and scale")
+		}
+		if balance.DefaultValue() == nil || *balance.DefaultValue() != "0" {
+			t.Error("expected default value")
+		}
+	})
+
+	t.Run("primary keys", func(t *testing.T) {
+		pk := lines.PrimaryKey()
+		if pk == nil || len(pk.Columns()) != 2 || pk.Columns()[0].Name() != "order_id" || pk.Columns()[1].Name() != "line" {
+			t.Errorf("expected composite primary key, got %v", pk)
+		}
+	})
+
+	t.Run("indexes", func(t *testing.T) {
+		indexes := make(map[string]*dbo.Index)
+		for _, idx := range customers.Indexes() {
+			indexes[idx.Name()] = idx
+		}
+		email := indexes["customers_email_key"]
+		if email == nil || !email.IsUnique() || email.Predicate() != "deleted_at IS NULL" {
+			t.Fatalf("expected partial unique index, got %v", email)
+		}
+		if len(email.Columns()) != 0 || len(email.Expressions()) != 1 || email.Expressions()[0] != "lower(email)" {
+			t.Errorf("expected expression key, got columns %d expressions %v", len(email.Columns()), email.Expressions())
+		}
+		deleted := indexes["customers_deleted_at_idx"]
+		if deleted == nil || deleted.IsPartial() || len(deleted.Columns()) != 1 {
+			t.Errorf("expected plain index, got %v", deleted)
+		}
+
+		var primary, unique bool
+		for _, idx := range lines.Indexes() {
+			primary = primary || idx.IsPrimary()
+			unique = unique || (idx.IsUnique() && !idx.IsPrimary() && len(idx.Columns()) == 1 && idx.Columns()[0].Name() == "sku")
+		}
+		if !primary || !unique {
+			t.Errorf("expected primary key and unique constraint indexes, got primary=%v unique=%v", primary, unique)
+		}
+	})
+
+	t.Run("foreign keys", func(t *testing.T) {
+		if len(orders.ForeignKeys()) != 1 {
+			t.Fatalf("expected 1 foreign key, got %d", len(orders.ForeignKeys()))
+		}
+		fk := orders.ForeignKeys()[0]
+		if fk.Name() != "orders_customer_id_fkey" || fk.ReferencedTable() != "customers" || fk.OnDelete() != dbo.ActionCascade {
+			t.Errorf("unexpected foreign key %s -> %s (%s)", fk.Name(), fk.ReferencedTable(), fk.OnDelete())
+		}
+		if len(fk.ReferencedColumns()) != 1 || fk.ReferencedColumns()[0].Name() != "id" {
+			t.Error("expected implicit reference to resolve to the parent primary key")
+		}
+	})
+
+	t.Run("check constraints", func(t *testing.T) {
+		checks := make(map[string]*dbo.Constraint)
+		for _, c := range append(customers.Constraints(), orders.Constraints()...) {
+			checks[c.Name()] = c
+		}
+		balance := checks["customers_balance_check"]
+		if balance == nil || balance.CheckExpression() != "CHECK (balance >= 0)" || len(balance.Columns()) != 1 {
+			t.Errorf("expected column check, got %v", balance)
+		}
+		qty := checks["qty_positive"]
+		if qty == nil || len(qty.Columns()) != 1 || qty.Columns()[0].Name() != "qty" {
+			t.Errorf("expected named table check on qty, got %v", qty)
+		}
+	})
+
+	t.Run("views", func(t *testing.T) {
+		view := schema.Views()["active_customers"]
+		if view == nil || !strings.HasPrefix(view.Definition(), "SELECT id, email FROM customers") {
+			t.Errorf("expected view query, got %v", view)
+		}
+	})
+
+	t.Run("triggers", func(t *testing.T) {
+		if len(orders.Triggers()) != 1 {
+			t.Fatalf("expected 1 trigger, got %d", len(orders.Triggers()))
+		}
+		trigger := orders.Triggers()[0]
+		if trigger.Timing() != dbo.TriggerTimingAfter || len(trigger.Events()) != 1 || trigger.Events()[0] != dbo.TriggerEventUpdate {
+			t.Errorf("unexpected trigger %s %v", trigger.Timing(), trigger.Events())
+		}
+	})
+}
+
+func TestSQLiteAdapter_ConnectMissingFile(t *testing.T) {
+	adapter := &SQLiteAdapter{}
+	if err := adapter.Connect(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Error("expected error for a missing database file")
+	}
+}
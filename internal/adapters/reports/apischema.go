@@ -0,0 +1,557 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// APISchemaReportWriter generates API contracts for every table and view: a JSON Schema
+// (draft 2020-12) document with one definition per relation, and an OpenAPI 3.1 document
+// holding the same schemas under components.schemas
+type APISchemaReportWriter struct{}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *APISchemaReportWriter) GetReportKeys() []string {
+	return []string{"jsonschema", "openapi"}
+}
+
+// GetReportFileExtension returns the file extension for API schema reports
+func (w *APISchemaReportWriter) GetReportFileExtension() string {
+	return "json"
+}
+
+// GetReportName returns the name of the API schema report
+func (w *APISchemaReportWriter) GetReportName() string {
+	return "API Schema"
+}
+
+// WriteInventoryReport writes the JSON Schema document to the given file path and the
+// OpenAPI document next to it ("<path without extension>.openapi.json")
+func (w *APISchemaReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	jsonSchema, err := GenerateJSONSchema(db)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, jsonSchema, 0600); err != nil {
+		return err
+	}
+	openAPI, err := GenerateOpenAPIComponents(db)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(strings.TrimSuffix(filePath, ".json")+".openapi.json", openAPI, 0600)
+}
+
+// apiSchemaDocument is the JSON Schema document written by the API schema report
+type apiSchemaDocument struct {
+	Schema string                      `json:"$schema"`
+	Title  string                      `json:"title"`
+	Defs   map[string]*apiObjectSchema `json:"$defs"`
+}
+
+// openAPIDocument is the OpenAPI document written by the API schema report
+type openAPIDocument struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths      map[string]any `json:"paths"`
+	Components struct {
+		Schemas map[string]*apiObjectSchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// apiObjectSchema describes the rows of a table or view
+type apiObjectSchema struct {
+	Type                 string        `json:"type"`
+	Title                string        `json:"title"`
+	Description          string        `json:"description,omitempty"`
+	ReadOnly             bool          `json:"readOnly,omitempty"`
+	Properties           apiProperties `json:"properties"`
+	Required             []string      `json:"required,omitempty"`
+	AdditionalProperties bool          `json:"additionalProperties"`
+}
+
+// apiPropertySchema describes the values of a single column
+type apiPropertySchema struct {
+	Type             any          `json:"type,omitempty"`
+	Format           string       `json:"format,omitempty"`
+	ContentEncoding  string       `json:"contentEncoding,omitempty"`
+	Description      string       `json:"description,omitempty"`
+	Default          any          `json:"default,omitempty"`
+	Enum             []any        `json:"enum,omitempty"`
+	Minimum          *json.Number `json:"minimum,omitempty"`
+	ExclusiveMinimum *json.Number `json:"exclusiveMinimum,omitempty"`
+	Maximum          *json.Number `json:"maximum,omitempty"`
+	ExclusiveMaximum *json.Number `json:"exclusiveMaximum,omitempty"`
+	MinLength        *int         `json:"minLength,omitempty"`
+	MaxLength        *int         `json:"maxLength,omitempty"`
+}
+
+// apiProperty is a named column schema
+type apiProperty struct {
+	name   string
+	schema *apiPropertySchema
+}
+
+// apiProperties keeps column schemas in column order when marshalled
+type apiProperties []apiProperty
+
+// MarshalJSON writes the properties as a JSON object in column order
+func (p apiProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(prop.name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(prop.schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// GenerateJSONSchema generates a JSON Schema (draft 2020-12) document with one
+// definition per table and view, keyed by "schema.relation"
+func GenerateJSONSchema(db *dbo.Database) ([]byte, error) {
+	doc := apiSchemaDocument{
+		Schema: "https://json-schema.org/draft/2020-12/schema",
+		Title:  db.Name(),
+		Defs:   apiRelationSchemas(db),
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// GenerateOpenAPIComponents generates an OpenAPI 3.1 document without paths whose
+// components.schemas hold one schema per table and view
+func GenerateOpenAPIComponents(db *dbo.Database) ([]byte, error) {
+	doc := openAPIDocument{OpenAPI: "3.1.0", Paths: map[string]any{}}
+	doc.Info.Title = db.Name()
+	doc.Info.Version = "1.0.0"
+	doc.Components.Schemas = apiRelationSchemas(db)
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// apiRelationSchemas builds the object schemas of all tables and views
+func apiRelationSchemas(db *dbo.Database) map[string]*apiObjectSchema {
+	schemas := make(map[string]*apiObjectSchema)
+	for _, schema := range sortedSchemas(db) {
+		for _, table := range sortedTables(schema) {
+			schemas[apiSchemaName(table.FullyQualifiedName())] = apiTableSchema(table)
+		}
+		for _, view := range sortedViews(schema) {
+			schemas[apiSchemaName(view.FullyQualifiedName())] = apiViewSchema(view)
+		}
+	}
+	return schemas
+}
+
+// apiTableSchema builds the object schema of a table. Columns that are NOT NULL and
+// have no default must be supplied and are required; CHECK constraints narrow the
+// values of the columns they compare.
+func apiTableSchema(table *dbo.Table) *apiObjectSchema {
+	object := &apiObjectSchema{
+		Type:        "object",
+		Title:       table.FullyQualifiedName(),
+		Description: table.Comment(),
+		Properties:  apiProperties{},
+	}
+	columns := sortedColumns(table)
+	properties := make(map[string]*apiPropertySchema, len(columns))
+	for _, col := range columns {
+		prop := apiColumnSchema(table.Schema(), col)
+		properties[col.Name()] = prop
+		object.Properties = append(object.Properties, apiProperty{name: col.Name(), schema: prop})
+		if !col.IsNullable() && col.DefaultValue() == nil {
+			object.Required = append(object.Required, col.Name())
+		}
+	}
+
+	for _, constraint := range table.Constraints() {
+		if constraint.Type() != dbo.ConstraintTypeCheck {
+			continue
+		}
+		for _, term := range splitCheckConjunction(checkExpressionBody(constraint.CheckExpression())) {
+			applyCheckTerm(properties, term)
+		}
+	}
+	for _, col := range columns {
+		if prop := properties[col.Name()]; col.IsNullable() && prop.Enum != nil && !slices.Contains(prop.Enum, nil) {
+			prop.Enum = append(prop.Enum, nil)
+		}
+	}
+	return object
+}
+
+// apiViewSchema builds the read-only object schema of a view. Every column is part of
+// each row, so the non-nullable ones are required.
+func apiViewSchema(view *dbo.View) *apiObjectSchema {
+	object := &apiObjectSchema{
+		Type:       "object",
+		Title:      view.FullyQualifiedName(),
+		ReadOnly:   true,
+		Properties: apiProperties{},
+	}
+	for _, col := range view.Columns() {
+		object.Properties = append(object.Properties, apiProperty{name: col.Name(), schema: apiColumnSchema(view.Schema(), col)})
+		if !col.IsNullable() {
+			object.Required = append(object.Required, col.Name())
+		}
+	}
+	return object
+}
+
+// apiColumnSchema maps a column type to a JSON type and format. Columns of an enum
+// type of the home schema's database list its values; types without a JSON
+// counterpart (e.g. json or user-defined types) accept any value.
+func apiColumnSchema(home *dbo.Schema, col *dbo.Column) *apiPropertySchema {
+	prop := &apiPropertySchema{Description: col.Comment()}
+	jsonType, format := apiTypeOf(col.DataType())
+	if e := columnEnum(home, col); e != nil {
+		jsonType, format = "string", ""
+		for _, value := range e.Values() {
+			prop.Enum = append(prop.Enum, value)
+		}
+		if col.IsNullable() {
+			prop.Enum = append(prop.Enum, nil)
+		}
+	}
+	switch jsonType {
+	case "":
+		return prop
+	case "binary":
+		jsonType, prop.ContentEncoding = "string", "base64"
+	}
+	prop.Format = format
+	if jsonType == "string" && col.CharMaxLength() != nil && apiIsCharacterType(col.DataType()) {
+		maxLength := *col.CharMaxLength()
+		prop.MaxLength = &maxLength
+	}
+	if col.IsNullable() {
+		prop.Type = []string{jsonType, "null"}
+	} else {
+		prop.Type = jsonType
+	}
+	if col.DefaultValue() != nil {
+		prop.Default = apiDefaultValue(*col.DefaultValue(), jsonType)
+	}
+	return prop
+}
+
+// apiTypeOf returns the JSON type ("binary" for base64-encoded strings) and format of
+// a database type, or an empty type when values cannot be described
+func apiTypeOf(dataType string) (string, string) {
+	base := strings.ToLower(strings.TrimSpace(dataType))
+	if i := strings.Index(base, "("); i >= 0 {
+		base = strings.TrimSpace(base[:i])
+	}
+	base = strings.TrimSuffix(base, " unsigned")
+	if strings.HasSuffix(base, "[]") || base == "array" {
+		return "array", ""
+	}
+
+	switch base {
+	case "smallint", "int2", "tinyint", "mediumint", "integer", "int", "int4", "serial", "smallserial", "year":
+		return "integer", "int32"
+	case "bigint", "int8", "bigserial":
+		return "integer", "int64"
+	case "real", "float4", "float":
+		return "number", "float"
+	case "double precision", "float8", "double":
+		return "number", "double"
+	case "numeric", "decimal", "money":
+		return "number", ""
+	case "boolean", "bool":
+		return "boolean", ""
+	case "uuid":
+		return "string", "uuid"
+	case "date":
+		return "string", "date"
+	case "timestamp", "timestamp without time zone", "timestamp with time zone", "timestamptz", "datetime":
+		return "string", "date-time"
+	case "time", "time without time zone", "time with time zone", "timetz":
+		return "string", "time"
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return "binary", ""
+	case "json", "jsonb", "user-defined":
+		return "", ""
+	default:
+		return "string", ""
+	}
+}
+
+// apiIsCharacterType reports whether a database type stores character strings whose
+// maximum length is measured in characters
+func apiIsCharacterType(dataType string) bool {
+	lower := strings.ToLower(dataType)
+	return strings.Contains(lower, "char") || strings.Contains(lower, "text")
+}
+
+// apiDefaultValue converts a literal column default to a JSON value of the column's
+// type, or returns nil for expressions such as nextval(...) or now()
+func apiDefaultValue(def string, jsonType string) any {
+	value := strings.TrimSpace(def)
+	if literal, ok := unquoteSQLLiteral(value); ok {
+		value = literal
+		if jsonType == "string" {
+			return literal
+		}
+	}
+	switch jsonType {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return json.Number(value)
+		}
+	case "boolean":
+		switch strings.ToLower(value) {
+		case "true", "1":
+			return true
+		case "false", "0":
+			return false
+		}
+	}
+	return nil
+}
+
+// apiSchemaName turns a qualified relation name into a component name accepted by
+// OpenAPI ([A-Za-z0-9._-])
+func apiSchemaName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+var (
+	// checkTypeCast matches a PostgreSQL cast such as ::numeric or ::character varying(20)
+	checkTypeCast = regexp.MustCompile(`::(?:character varying|double precision|(?:timestamp|time) with(?:out)? time zone|[\w."]+)(?:\(\d+(?:,\s*\d+)?\))?(?:\[\])?`)
+	// checkCharsetIntroducer matches a MySQL character set introducer such as _utf8mb4'
+	checkCharsetIntroducer = regexp.MustCompile(`\b_[a-z0-9]+'`)
+
+	checkIdentifier = "[`\"]?(\\w+)[`\"]?"
+	checkNumber     = `\(?'?(-?\d+(?:\.\d+)?)'?\)?`
+	checkOperator   = `(>=|<=|>|<)`
+
+	checkComparison        = regexp.MustCompile(`^` + checkIdentifier + `\s*` + checkOperator + `\s*` + checkNumber + `$`)
+	checkReversed          = regexp.MustCompile(`^` + checkNumber + `\s*` + checkOperator + `\s*` + checkIdentifier + `$`)
+	checkLengthComparison  = regexp.MustCompile(`(?i)^(?:char_length|character_length|length)\(\s*\(?` + checkIdentifier + `\)?\s*\)\s*` + checkOperator + `\s*` + checkNumber + `$`)
+	checkBetween           = regexp.MustCompile(`(?i)^` + checkIdentifier + `\s+between\s+` + checkNumber + `\s+and\s+` + checkNumber + `$`)
+	checkInList            = regexp.MustCompile(`(?i)^` + checkIdentifier + `\s+in\s*\((.*)\)$`)
+	checkAnyArray          = regexp.MustCompile(`(?i)^` + checkIdentifier + `\s*=\s*any\s*\(\s*\(?array\s*\[(.*)\]\)?\s*\)$`)
+	checkConjunctionMarker = regexp.MustCompile(`(?i)\s(and|between)\s`)
+)
+
+// splitCheckConjunction splits a CHECK expression into the terms of its top-level AND,
+// keeping the AND of a BETWEEN inside its term. Casts and MySQL character set
+// introducers are removed so that terms compare bare literals.
+func splitCheckConjunction(expression string) []string {
+	expression = checkTypeCast.ReplaceAllString(expression, "")
+	expression = stripOuterParentheses(checkCharsetIntroducer.ReplaceAllString(expression, "'"))
+
+	var terms []string
+	depth, start, pendingBetween := 0, 0, 0
+	inQuote := false
+	for i := 0; i < len(expression); i++ {
+		switch c := expression[i]; {
+		case c == '\'':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && (c == ' ' || c == '\t' || c == '\n'):
+			loc := checkConjunctionMarker.FindStringSubmatchIndex(expression[i:])
+			if loc == nil || loc[0] != 0 {
+				continue
+			}
+			if strings.EqualFold(expression[i+loc[2]:i+loc[3]], "between") {
+				pendingBetween++
+			} else if pendingBetween > 0 {
+				pendingBetween--
+			} else {
+				terms = append(terms, expression[start:i])
+				start = i + loc[1]
+			}
+			i += loc[1] - 2
+		}
+	}
+	terms = append(terms, expression[start:])
+
+	for i, term := range terms {
+		terms[i] = stripOuterParentheses(term)
+	}
+	return terms
+}
+
+// stripOuterParentheses removes whitespace and redundant parentheses around an expression
+func stripOuterParentheses(expr string) string {
+	expr = strings.TrimSpace(expr)
+	for len(expr) >= 2 && expr[0] == '(' && expr[len(expr)-1] == ')' && isBalancedGroup(expr[1:len(expr)-1]) {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// applyCheckTerm narrows the schema of the column a CHECK term constrains. Terms that
+// are not a numeric bound, a length bound or a list of allowed values are ignored.
+func applyCheckTerm(properties map[string]*apiPropertySchema, term string) {
+	if m := checkComparison.FindStringSubmatch(term); m != nil {
+		applyNumericBound(properties[m[1]], m[2], m[3])
+	} else if m := checkReversed.FindStringSubmatch(term); m != nil {
+		// "0 < price" bounds price the other way round
+		flipped := map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}[m[2]]
+		applyNumericBound(properties[m[3]], flipped, m[1])
+	} else if m := checkBetween.FindStringSubmatch(term); m != nil {
+		applyNumericBound(properties[m[1]], ">=", m[2])
+		applyNumericBound(properties[m[1]], "<=", m[3])
+	} else if m := checkLengthComparison.FindStringSubmatch(term); m != nil {
+		applyLengthBound(properties[m[1]], m[2], m[3])
+	} else if m := checkInList.FindStringSubmatch(term); m != nil {
+		applyEnum(properties[m[1]], m[2])
+	} else if m := checkAnyArray.FindStringSubmatch(term); m != nil {
+		applyEnum(properties[m[1]], m[2])
+	}
+}
+
+// applyNumericBound records a numeric bound, keeping the tighter of two bounds on the
+// same side
+func applyNumericBound(prop *apiPropertySchema, operator string, value string) {
+	if prop == nil || !apiIsNumeric(prop) {
+		return
+	}
+	number := json.Number(value)
+	tighter := func(current *json.Number, lower bool) bool {
+		if current == nil {
+			return true
+		}
+		a, _ := number.Float64()
+		b, _ := current.Float64()
+		if lower {
+			return a > b
+		}
+		return a < b
+	}
+	switch operator {
+	case ">":
+		if tighter(prop.ExclusiveMinimum, true) {
+			prop.ExclusiveMinimum = &number
+		}
+	case ">=":
+		if tighter(prop.Minimum, true) {
+			prop.Minimum = &number
+		}
+	case "<":
+		if tighter(prop.ExclusiveMaximum, false) {
+			prop.ExclusiveMaximum = &number
+		}
+	case "<=":
+		if tighter(prop.Maximum, false) {
+			prop.Maximum = &number
+		}
+	}
+}
+
+// applyLengthBound records a minLength or maxLength from a length(col) comparison
+func applyLengthBound(prop *apiPropertySchema, operator string, value string) {
+	n, err := strconv.Atoi(value)
+	if prop == nil || err != nil || !apiHasType(prop, "string") {
+		return
+	}
+	switch operator {
+	case ">":
+		n++
+		fallthrough
+	case ">=":
+		if prop.MinLength == nil || n > *prop.MinLength {
+			prop.MinLength = &n
+		}
+	case "<":
+		n--
+		fallthrough
+	case "<=":
+		if prop.MaxLength == nil || n < *prop.MaxLength {
+			prop.MaxLength = &n
+		}
+	}
+}
+
+// applyEnum records the allowed values of a comma-separated literal list
+func applyEnum(prop *apiPropertySchema, list string) {
+	if prop == nil || prop.Type == nil {
+		return
+	}
+	var values []any
+	for _, item := range splitCheckList(list) {
+		item = stripOuterParentheses(item)
+		if literal, ok := unquoteSQLLiteral(item); ok {
+			if !apiHasType(prop, "string") {
+				return
+			}
+			values = append(values, literal)
+		} else if _, err := strconv.ParseFloat(item, 64); err == nil && apiIsNumeric(prop) {
+			values = append(values, json.Number(item))
+		} else {
+			return
+		}
+	}
+	prop.Enum = values
+}
+
+// splitCheckList splits a list of literals on the commas outside quotes
+func splitCheckList(list string) []string {
+	var items []string
+	inQuote, start := false, 0
+	for i := 0; i < len(list); i++ {
+		switch list[i] {
+		case '\'':
+			inQuote = !inQuote
+		case ',':
+			if !inQuote {
+				items = append(items, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(items, list[start:])
+}
+
+// apiHasType reports whether a property schema allows the given JSON type
+func apiHasType(prop *apiPropertySchema, jsonType string) bool {
+	switch t := prop.Type.(type) {
+	case string:
+		return t == jsonType
+	case []string:
+		return len(t) > 0 && t[0] == jsonType
+	}
+	return false
+}
+
+// apiIsNumeric reports whether a property schema describes integers or numbers
+func apiIsNumeric(prop *apiPropertySchema) bool {
+	return apiHasType(prop, "integer") || apiHasType(prop, "number")
+}
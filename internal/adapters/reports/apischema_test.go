@@ -0,0 +1,257 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// newAPISchemaTestDatabase builds a products table whose CHECK constraints bound and
+// enumerate its columns, and a view over it
+func newAPISchemaTestDatabase() *dbo.Database {
+	db := dbo.NewDatabase("shop", nil)
+	schema := dbo.NewSchema("public", "owner", nil)
+	db.AddSchema(schema)
+
+	products := dbo.NewTable("products", nil)
+	column := func(name, dataType string, nullable bool, position int) *dbo.Column {
+		col := dbo.NewColumn(name, dataType, nullable)
+		col.SetOrdinalPosition(position)
+		products.AddColumn(col)
+		return col
+	}
+	id := column("id", "bigint", false, 1)
+	id.SetDefaultValue("nextval('products_id_seq'::regclass)")
+	column("sku", "character varying", false, 2).SetCharMaxLength(32)
+	column("price", "numeric", false, 3)
+	column("quantity", "integer", false, 4).SetDefaultValue("0")
+	column("status", "text", true, 5).SetDefaultValue("'draft'::text")
+	column("rating", "smallint", true, 6)
+	column("released_on", "date", true, 7)
+	column("attributes", "jsonb", true, 8)
+	column("active", "boolean", false, 9).SetDefaultValue("true")
+	column("image", "bytea", true, 10)
+	column("colour", "public.colour", true, 11)
+	schema.AddEnum(dbo.NewEnum("colour", []string{"red", "blue"}))
+	products.SetPrimaryKey(dbo.NewPrimaryKey("products_pkey", products, []*dbo.Column{id}))
+
+	for name, expression := range map[string]string{
+		"products_price_check":    "CHECK ((price > (0)::numeric))",
+		"products_quantity_check": "CHECK (((quantity >= 0) AND (quantity <= 1000)))",
+		"products_status_check":   "CHECK ((status = ANY (ARRAY['draft'::text, 'live'::text, 'retired'::text])))",
+		"products_rating_check":   "CHECK ((rating BETWEEN 1 AND 5))",
+		"products_sku_check":      "CHECK ((char_length((sku)::text) >= 4))",
+	} {
+		check := dbo.NewConstraint(name, dbo.ConstraintTypeCheck)
+		check.SetCheckExpression(expression)
+		products.AddConstraint(check)
+	}
+	schema.AddTable(products)
+
+	view := dbo.NewView("live_products", "SELECT id, sku FROM products WHERE status = 'live'")
+	view.AddColumn(dbo.NewColumn("id", "bigint", false))
+	view.AddColumn(dbo.NewColumn("sku", "character varying", true))
+	schema.AddView(view)
+	return db
+}
+
+// compileAPISchema compiles a definition of a generated JSON Schema document
+func compileAPISchema(t *testing.T, data []byte, definition string) *jsonschema.Schema {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("api.schema.json", doc); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := compiler.Compile("api.schema.json#/$defs/" + definition)
+	if err != nil {
+		t.Fatalf("failed to compile %s: %v", definition, err)
+	}
+	return schema
+}
+
+func TestAPISchemaReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes json schema and openapi files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "shop_API_Schema.json")
+
+		writer := &APISchemaReportWriter{}
+		if err := writer.WriteInventoryReport(filePath, newAPISchemaTestDatabase()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		for _, name := range []string{"shop_API_Schema.json", "shop_API_Schema.openapi.json"} {
+			data, err := os.ReadFile(filepath.Join(tmpDir, name))
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", name, err)
+			}
+			if !json.Valid(data) {
+				t.Errorf("expected valid JSON in %s", name)
+			}
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &APISchemaReportWriter{}
+		if err := writer.WriteInventoryReport("/nonexistent/path/file.json", dbo.NewDatabase("testdb", nil)); err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	data, err := GenerateJSONSchema(newAPISchemaTestDatabase())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var doc struct {
+		Defs map[string]struct {
+			ReadOnly   bool                       `json:"readOnly"`
+			Properties map[string]json.RawMessage `json:"properties"`
+			Required   []string                   `json:"required"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	products := doc.Defs["public.products"]
+	if !reflect.DeepEqual(products.Required, []string{"sku", "price"}) {
+		t.Errorf("expected NOT NULL columns without default to be required, got %v", products.Required)
+	}
+	expected := map[string]string{
+		"id":          `{"type":"integer","format":"int64"}`,
+		"sku":         `{"type":"string","minLength":4,"maxLength":32}`,
+		"price":       `{"type":"number","exclusiveMinimum":0}`,
+		"quantity":    `{"type":"integer","format":"int32","default":0,"minimum":0,"maximum":1000}`,
+		"status":      `{"type":["string","null"],"default":"draft","enum":["draft","live","retired",null]}`,
+		"rating":      `{"type":["integer","null"],"format":"int32","minimum":1,"maximum":5}`,
+		"released_on": `{"type":["string","null"],"format":"date"}`,
+		"attributes":  `{}`,
+		"active":      `{"type":"boolean","default":true}`,
+		"image":       `{"type":["string","null"],"contentEncoding":"base64"}`,
+		"colour":      `{"type":["string","null"],"enum":["red","blue",null]}`,
+	}
+	for name, want := range expected {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, products.Properties[name]); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if compact.String() != want {
+			t.Errorf("%s: expected %s, got %s", name, want, compact.String())
+		}
+	}
+	if !strings.Contains(string(data), "\"id\": {") || strings.Index(string(data), "\"id\": {") > strings.Index(string(data), "\"image\": {") {
+		t.Error("expected properties in column order")
+	}
+
+	view := doc.Defs["public.live_products"]
+	if !view.ReadOnly || !reflect.DeepEqual(view.Required, []string{"id"}) {
+		t.Errorf("expected read-only view with id required, got %+v", view)
+	}
+
+	t.Run("rows validate against the definition", func(t *testing.T) {
+		schema := compileAPISchema(t, data, "public.products")
+		valid := `{"sku":"ABCD-1","price":9.5,"quantity":3,"status":"live","rating":null}`
+		invalid := map[string]string{
+			"missing required": `{"sku":"ABCD-1"}`,
+			"price bound":      `{"sku":"ABCD-1","price":0}`,
+			"enum":             `{"sku":"ABCD-1","price":1,"status":"deleted"}`,
+			"max length":       `{"sku":"` + strings.Repeat("x", 33) + `","price":1}`,
+			"unknown column":   `{"sku":"ABCD-1","price":1,"size":"L"}`,
+			"enum type":        `{"sku":"ABCD-1","price":1,"colour":"green"}`,
+		}
+		row, _ := jsonschema.UnmarshalJSON(strings.NewReader(valid))
+		if err := schema.Validate(row); err != nil {
+			t.Errorf("expected valid row, got %v", err)
+		}
+		for name, doc := range invalid {
+			row, _ := jsonschema.UnmarshalJSON(strings.NewReader(doc))
+			if schema.Validate(row) == nil {
+				t.Errorf("%s: expected row to be rejected", name)
+			}
+		}
+	})
+}
+
+func TestGenerateOpenAPIComponents(t *testing.T) {
+	data, err := GenerateOpenAPIComponents(newAPISchemaTestDatabase())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("expected OpenAPI 3.1.0, got %q", doc.OpenAPI)
+	}
+	if len(doc.Components.Schemas) != 2 || doc.Components.Schemas["public.products"] == nil {
+		t.Errorf("expected products and live_products components, got %v", doc.Components.Schemas)
+	}
+}
+
+func TestSplitCheckConjunction(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   []string
+	}{
+		{"((qty >= 0) AND (qty <= 10))", []string{"qty >= 0", "qty <= 10"}},
+		{"qty between 1 and 5 and price > 0", []string{"qty between 1 and 5", "price > 0"}},
+		{"(`status` in (_utf8mb4'a and b',_utf8mb4'c'))", []string{"`status` in ('a and b','c')"}},
+		{"(price > (0)::numeric)", []string{"price > (0)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			if got := splitCheckConjunction(tt.expression); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestApplyCheckTerm(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType string
+		term     string
+		expected string
+	}{
+		{"reversed comparison", "integer", "0 < amount", `{"type":"integer","format":"int32","exclusiveMinimum":0}`},
+		{"mysql in list", "varchar", "`amount` in ('a','b')", `{"type":"string","enum":["a","b"]}`},
+		{"numeric in list", "integer", "amount IN (1, 2)", `{"type":"integer","format":"int32","enum":[1,2]}`},
+		{"mixed list ignored", "integer", "amount IN (1, 'x')", `{"type":"integer","format":"int32"}`},
+		{"bound on text ignored", "text", "amount > 3", `{"type":"string"}`},
+		{"length below", "text", "length(amount) < 10", `{"type":"string","maxLength":9}`},
+		{"other column", "integer", "other > 3", `{"type":"integer","format":"int32"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := apiColumnSchema(nil, dbo.NewColumn("amount", tt.dataType, false))
+			applyCheckTerm(map[string]*apiPropertySchema{"amount": prop}, tt.term)
+			got, _ := json.Marshal(prop)
+			if string(got) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package reports
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// Nullable column styles of generated Go structs
+const (
+	// GoNullableSQL uses the sql.Null* types (sql.Null[T] where no named type exists)
+	GoNullableSQL = "sql"
+	// GoNullablePointer uses pointers to the column type
+	GoNullablePointer = "pointer"
+)
+
+// CodegenReportWriter generates Go structs and TypeScript interfaces for every table and
+// view, and named types for every enum of the mapped database
+type CodegenReportWriter struct {
+	// GoPackage is the package clause of the generated Go file, "models" when empty
+	GoPackage string
+	// GoNullable is GoNullableSQL (the default) or GoNullablePointer
+	GoNullable string
+}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *CodegenReportWriter) GetReportKeys() []string {
+	return []string{"codegen"}
+}
+
+// GetReportFileExtension returns the file extension of the generated Go file
+func (w *CodegenReportWriter) GetReportFileExtension() string {
+	return "go"
+}
+
+// GetReportName returns the name of the codegen report
+func (w *CodegenReportWriter) GetReportName() string {
+	return "Generated Types"
+}
+
+// WriteInventoryReport writes the Go types to the given file path and the TypeScript
+// types next to it ("<path without extension>.ts")
+func (w *CodegenReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	goSource, err := GenerateGoTypes(db, w.GoPackage, w.GoNullable)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, goSource, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(strings.TrimSuffix(filePath, ".go")+".ts", []byte(GenerateTypeScriptTypes(db)), 0600)
+}
+
+// codegenKind is the language-neutral kind of value a column holds
+type codegenKind int
+
+const (
+	codegenString codegenKind = iota
+	codegenInt8
+	codegenInt16
+	codegenInt32
+	codegenInt64
+	codegenUint64
+	codegenFloat32
+	codegenFloat64
+	codegenDecimal
+	codegenBool
+	codegenTime
+	codegenJSON
+	codegenBytes
+	codegenUnknown
+)
+
+// codegenKindOf maps a column type to a value kind. Types are first simplified as for
+// the Mermaid ERD; the few types whose meaning differs between engines are resolved
+// by dialect.
+func codegenKindOf(dialect SQLDialect, dataType string) codegenKind {
+	dt := normalizeMermaidDataType(dataType)
+	switch {
+	case dt == "smallint", dt == "year", dt == "smallserial":
+		return codegenInt16
+	case dt == "int", dt == "mediumint", dt == "serial":
+		return codegenInt32
+	case dt == "bigint", dt == "bigserial":
+		return codegenInt64
+	case dt == "tinyint":
+		return codegenInt8
+	case dt == "decimal", dt == "money":
+		return codegenDecimal
+	case dt == "real", dt == "float4":
+		return codegenFloat32
+	case dt == "float":
+		// MySQL FLOAT is single precision, PostgreSQL reports float as double precision
+		if dialect == SQLDialectMySQL {
+			return codegenFloat32
+		}
+		return codegenFloat64
+	case dt == "double precision", dt == "double", dt == "float8":
+		return codegenFloat64
+	case dt == "bool":
+		return codegenBool
+	case dt == "bit":
+		// MySQL BIT(n) holds up to 64 bits, PostgreSQL bit strings are text
+		if dialect == SQLDialectMySQL {
+			return codegenUint64
+		}
+		return codegenString
+	case dt == "timestamp", dt == "date", dt == "datetime", strings.HasPrefix(dt, "time"):
+		return codegenTime
+	case dt == "json":
+		return codegenJSON
+	case dt == "bytea", strings.HasSuffix(dt, "blob"), dt == "binary", dt == "varbinary":
+		return codegenBytes
+	case dt == "array", dt == "user-defined", strings.HasSuffix(dt, "[]"):
+		return codegenUnknown
+	default:
+		return codegenString
+	}
+}
+
+// codegenNames assigns type names to the enums, tables and views of a database.
+// Names are derived from the object name and qualified with the schema when the
+// same name appears in several schemas.
+type codegenNames struct {
+	names map[string]string
+}
+
+// newCodegenNames assigns the type names of every enum, table and view of db
+func newCodegenNames(db *dbo.Database) *codegenNames {
+	counts := make(map[string]int)
+	for _, schema := range sortedSchemas(db) {
+		for _, e := range sortedEnums(schema) {
+			counts[goIdentifier(e.Name())]++
+		}
+		for _, t := range sortedTables(schema) {
+			counts[goIdentifier(t.Name())]++
+		}
+		for _, v := range sortedViews(schema) {
+			counts[goIdentifier(v.Name())]++
+		}
+	}
+
+	n := &codegenNames{names: make(map[string]string)}
+	assign := func(schema *dbo.Schema, name string) {
+		typeName := goIdentifier(name)
+		if counts[typeName] > 1 {
+			typeName = goIdentifier(schema.Name() + "_" + name)
+		}
+		n.names[schema.Name()+"."+name] = typeName
+	}
+	for _, schema := range sortedSchemas(db) {
+		for _, e := range sortedEnums(schema) {
+			assign(schema, e.Name())
+		}
+		for _, t := range sortedTables(schema) {
+			assign(schema, t.Name())
+		}
+		for _, v := range sortedViews(schema) {
+			assign(schema, v.Name())
+		}
+	}
+	return n
+}
+
+// of returns the type name of the object with the given name in schema
+func (n *codegenNames) of(schema *dbo.Schema, name string) string {
+	return n.names[schema.Name()+"."+name]
+}
+
+// GenerateGoTypes generates a gofmt-formatted Go file declaring a string type with
+// constants per enum and a struct per table and view, with db and json tags
+func GenerateGoTypes(db *dbo.Database, packageName string, nullable string) ([]byte, error) {
+	if packageName == "" {
+		packageName = "models"
+	}
+	if nullable == "" {
+		nullable = GoNullableSQL
+	}
+	if nullable != GoNullableSQL && nullable != GoNullablePointer {
+		return nil, fmt.Errorf("unknown Go nullable style %q, use %s or %s", nullable, GoNullableSQL, GoNullablePointer)
+	}
+
+	dialect := SQLDialectForEngine(db.Engine())
+	names := newCodegenNames(db)
+	imports := make(map[string]bool)
+	var body strings.Builder
+
+	for _, schema := range sortedSchemas(db) {
+		for _, e := range sortedEnums(schema) {
+			typeName := names.of(schema, e.Name())
+			fmt.Fprintf(&body, "\n// %s is the %s enum type\ntype %s string\n", typeName, e.FullyQualifiedName(), typeName)
+			if len(e.Values()) > 0 {
+				body.WriteString("\nconst (\n")
+				for _, value := range e.Values() {
+					fmt.Fprintf(&body, "%s%s %s = %s\n", typeName, goIdentifier(value), typeName, strconv.Quote(value))
+				}
+				body.WriteString(")\n")
+			}
+		}
+		for _, t := range sortedTables(schema) {
+			writeGoStruct(&body, names.of(schema, t.Name()), "table", t.FullyQualifiedName(), t.Comment(),
+				sortedColumns(t), schema, dialect, names, nullable, imports)
+		}
+		for _, v := range sortedViews(schema) {
+			writeGoStruct(&body, names.of(schema, v.Name()), "view", v.FullyQualifiedName(), "",
+				v.Columns(), schema, dialect, names, nullable, imports)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by norman from database %s. DO NOT EDIT.\n\npackage %s\n", db.Name(), packageName)
+	if len(imports) > 0 {
+		sb.WriteString("\nimport (\n")
+		for _, path := range []string{"database/sql", "encoding/json", "time"} {
+			if imports[path] {
+				sb.WriteString(strconv.Quote(path) + "\n")
+			}
+		}
+		sb.WriteString(")\n")
+	}
+	sb.WriteString(body.String())
+
+	source, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go code: %w", err)
+	}
+	return source, nil
+}
+
+// writeGoStruct writes the struct of a table or view
+func writeGoStruct(sb *strings.Builder, typeName, kind, qualifiedName, comment string, columns []*dbo.Column,
+	schema *dbo.Schema, dialect SQLDialect, names *codegenNames, nullable string, imports map[string]bool) {
+	fmt.Fprintf(sb, "\n// %s is a row of the %s %s\n", typeName, qualifiedName, kind)
+	writeCodegenComment(sb, "// ", comment)
+	fmt.Fprintf(sb, "type %s struct {\n", typeName)
+	for _, col := range columns {
+		writeCodegenComment(sb, "// ", col.Comment())
+		fmt.Fprintf(sb, "%s %s `db:%s json:%s`\n", goIdentifier(col.Name()),
+			goColumnType(col, schema, dialect, names, nullable, imports), strconv.Quote(col.Name()), strconv.Quote(col.Name()))
+	}
+	sb.WriteString("}\n")
+}
+
+// goColumnType returns the Go type of a column, recording the imports it needs
+func goColumnType(col *dbo.Column, schema *dbo.Schema, dialect SQLDialect, names *codegenNames, nullable string, imports map[string]bool) string {
+	var goType string
+	if e := columnEnum(schema, col); e != nil {
+		goType = names.of(e.Schema(), e.Name())
+	} else {
+		switch codegenKindOf(dialect, col.DataType()) {
+		case codegenInt8:
+			goType = "int8"
+		case codegenInt16:
+			goType = "int16"
+		case codegenInt32:
+			goType = "int32"
+		case codegenInt64:
+			goType = "int64"
+		case codegenUint64:
+			goType = "uint64"
+		case codegenFloat32:
+			goType = "float32"
+		case codegenFloat64:
+			goType = "float64"
+		case codegenBool:
+			goType = "bool"
+		case codegenTime:
+			goType = "time.Time"
+			imports["time"] = true
+		case codegenJSON:
+			imports["encoding/json"] = true
+			return "json.RawMessage"
+		case codegenBytes:
+			return "[]byte"
+		case codegenUnknown:
+			return "any"
+		default:
+			// Decimals stay strings so that no precision is lost
+			goType = "string"
+		}
+	}
+	if !col.IsNullable() {
+		return goType
+	}
+	if nullable == GoNullablePointer {
+		return "*" + goType
+	}
+
+	imports["database/sql"] = true
+	switch goType {
+	case "string":
+		return "sql.NullString"
+	case "int16":
+		return "sql.NullInt16"
+	case "int32":
+		return "sql.NullInt32"
+	case "int64":
+		return "sql.NullInt64"
+	case "float64":
+		return "sql.NullFloat64"
+	case "bool":
+		return "sql.NullBool"
+	case "time.Time":
+		return "sql.NullTime"
+	default:
+		return "sql.Null[" + goType + "]"
+	}
+}
+
+// GenerateTypeScriptTypes generates TypeScript declarations: a string literal union
+// per enum and an interface per table and view. Values are typed as they appear in
+// JSON, so decimals, times and binary data are strings.
+func GenerateTypeScriptTypes(db *dbo.Database) string {
+	dialect := SQLDialectForEngine(db.Engine())
+	names := newCodegenNames(db)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// Code generated by norman from database %s. DO NOT EDIT.\n", db.Name())
+
+	for _, schema := range sortedSchemas(db) {
+		for _, e := range sortedEnums(schema) {
+			values := make([]string, len(e.Values()))
+			for i, value := range e.Values() {
+				values[i] = strconv.Quote(value)
+			}
+			union := strings.Join(values, " | ")
+			if union == "" {
+				union = "never"
+			}
+			fmt.Fprintf(&sb, "\n/** The %s enum type */\nexport type %s = %s;\n", e.FullyQualifiedName(), names.of(schema, e.Name()), union)
+		}
+		for _, t := range sortedTables(schema) {
+			writeTypeScriptInterface(&sb, names.of(schema, t.Name()), "table", t.FullyQualifiedName(), t.Comment(),
+				sortedColumns(t), schema, dialect, names)
+		}
+		for _, v := range sortedViews(schema) {
+			writeTypeScriptInterface(&sb, names.of(schema, v.Name()), "view", v.FullyQualifiedName(), "",
+				v.Columns(), schema, dialect, names)
+		}
+	}
+	return sb.String()
+}
+
+// writeTypeScriptInterface writes the interface of a table or view
+func writeTypeScriptInterface(sb *strings.Builder, typeName, kind, qualifiedName, comment string, columns []*dbo.Column,
+	schema *dbo.Schema, dialect SQLDialect, names *codegenNames) {
+	fmt.Fprintf(sb, "\n/**\n * A row of the %s %s\n", qualifiedName, kind)
+	writeCodegenComment(sb, " * ", comment)
+	fmt.Fprintf(sb, " */\nexport interface %s {\n", typeName)
+	for _, col := range columns {
+		if col.Comment() != "" {
+			fmt.Fprintf(sb, "  /** %s */\n", strings.ReplaceAll(strings.ReplaceAll(col.Comment(), "*/", "* /"), "\n", " "))
+		}
+		tsType := typeScriptColumnType(col, schema, dialect, names)
+		if col.IsNullable() && tsType != "unknown" {
+			tsType += " | null"
+		}
+		fmt.Fprintf(sb, "  %s: %s;\n", typeScriptPropertyName(col.Name()), tsType)
+	}
+	sb.WriteString("}\n")
+}
+
+// typeScriptColumnType returns the TypeScript type of a column's JSON value
+func typeScriptColumnType(col *dbo.Column, schema *dbo.Schema, dialect SQLDialect, names *codegenNames) string {
+	if e := columnEnum(schema, col); e != nil {
+		return names.of(e.Schema(), e.Name())
+	}
+	switch codegenKindOf(dialect, col.DataType()) {
+	case codegenInt8, codegenInt16, codegenInt32, codegenInt64, codegenUint64, codegenFloat32, codegenFloat64:
+		return "number"
+	case codegenBool:
+		return "boolean"
+	case codegenJSON, codegenUnknown:
+		return "unknown"
+	default:
+		return "string"
+	}
+}
+
+// typeScriptIdentifier matches property names that need no quotes
+var typeScriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptPropertyName returns a column name usable as an interface property
+func typeScriptPropertyName(name string) string {
+	if typeScriptIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// goInitialisms are name parts written in upper case, following Go naming conventions
+var goInitialisms = map[string]bool{
+	"api": true, "db": true, "html": true, "http": true, "id": true, "ip": true, "json": true,
+	"sql": true, "ssn": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// goIdentifier turns a database name such as "order_items" or "user-id" into an
+// exported Go identifier ("OrderItems", "UserID")
+func goIdentifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var sb strings.Builder
+	for _, part := range parts {
+		if goInitialisms[strings.ToLower(part)] {
+			sb.WriteString(strings.ToUpper(part))
+			continue
+		}
+		first, size := utf8.DecodeRuneInString(part)
+		sb.WriteRune(unicode.ToUpper(first))
+		sb.WriteString(part[size:])
+	}
+	identifier := sb.String()
+	if first, _ := utf8.DecodeRuneInString(identifier); identifier == "" || !unicode.IsLetter(first) {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
+
+// writeCodegenComment writes a possibly multi-line comment with the given line prefix
+func writeCodegenComment(sb *strings.Builder, prefix string, comment string) {
+	if comment == "" {
+		return
+	}
+	comment = strings.ReplaceAll(comment, "*/", "* /")
+	for _, line := range strings.Split(comment, "\n") {
+		sb.WriteString(strings.TrimRight(prefix+line, " "))
+		sb.WriteString("\n")
+	}
+}
@@ -0,0 +1,230 @@
+package reports
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// newCodegenTestDatabase builds a database with an enum, a table using it and a view,
+// plus a table whose name also exists in a second schema
+func newCodegenTestDatabase() *dbo.Database {
+	db := dbo.NewDatabase("shop", nil)
+	db.SetEngine("PostgreSQL")
+	public := dbo.NewSchema("public", "owner", nil)
+	db.AddSchema(public)
+	public.AddEnum(dbo.NewEnum("order_status", []string{"pending", "in-progress", "shipped"}))
+
+	orders := dbo.NewTable("orders", nil)
+	orders.SetComment("customer orders")
+	column := func(name, dataType string, nullable bool, position int) *dbo.Column {
+		col := dbo.NewColumn(name, dataType, nullable)
+		col.SetOrdinalPosition(position)
+		orders.AddColumn(col)
+		return col
+	}
+	column("id", "bigint", false, 1)
+	column("status", "public.order_status", false, 2)
+	column("previous_status", "public.order_status", true, 3)
+	column("total", "numeric", false, 4)
+	column("note", "text", true, 5).SetComment("free text\nfrom the customer")
+	column("placed_at", "timestamp with time zone", false, 6)
+	column("shipped_at", "timestamp without time zone", true, 7)
+	column("weight", "real", true, 8)
+	column("metadata", "jsonb", true, 9)
+	column("tracking url", "character varying", true, 10)
+	public.AddTable(orders)
+
+	view := dbo.NewView("open_orders", "SELECT id FROM orders")
+	view.AddColumn(dbo.NewColumn("id", "bigint", false))
+	view.AddColumn(dbo.NewColumn("gift", "boolean", true))
+	public.AddView(view)
+
+	public.AddTable(dbo.NewTable("events", nil))
+	audit := dbo.NewSchema("audit", "owner", nil)
+	db.AddSchema(audit)
+	audit.AddTable(dbo.NewTable("events", nil))
+	return db
+}
+
+func TestCodegenReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes go and typescript files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "shop_Generated_Types.go")
+
+		writer := &CodegenReportWriter{GoPackage: "shopdb"}
+		if err := writer.WriteInventoryReport(filePath, newCodegenTestDatabase()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		goSource, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read go file: %v", err)
+		}
+		if !strings.Contains(string(goSource), "package shopdb\n") {
+			t.Errorf("expected configured package, got:\n%s", goSource)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "shop_Generated_Types.ts")); err != nil {
+			t.Errorf("expected typescript file: %v", err)
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &CodegenReportWriter{}
+		if err := writer.WriteInventoryReport("/nonexistent/path/file.go", dbo.NewDatabase("testdb", nil)); err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+
+	t.Run("returns error for unknown nullable style", func(t *testing.T) {
+		writer := &CodegenReportWriter{GoNullable: "optional"}
+		if err := writer.WriteInventoryReport(filepath.Join(t.TempDir(), "x.go"), dbo.NewDatabase("testdb", nil)); err == nil {
+			t.Error("expected error for unknown nullable style")
+		}
+	})
+}
+
+// collapseSpaces replaces runs of spaces and tabs with a single space, so that
+// expectations do not depend on gofmt's column alignment
+func collapseSpaces(s string) string {
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == '\t' }), " ")
+}
+
+func TestGenerateGoTypes(t *testing.T) {
+	db := newCodegenTestDatabase()
+
+	t.Run("sql nullable types", func(t *testing.T) {
+		source, err := GenerateGoTypes(db, "", GoNullableSQL)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "models.go", source, parser.AllErrors); err != nil {
+			t.Fatalf("generated code does not parse: %v\n%s", err, source)
+		}
+
+		result := collapseSpaces(string(source))
+		for _, expected := range []string{
+			"// Code generated by norman from database shop. DO NOT EDIT.\n\npackage models\n",
+			"\"database/sql\"\n\t\"encoding/json\"\n\t\"time\"\n",
+			"type OrderStatus string\n",
+			"OrderStatusInProgress OrderStatus = \"in-progress\"\n",
+			"// Orders is a row of the public.orders table\n// customer orders\ntype Orders struct {\n",
+			"ID             int64                  `db:\"id\" json:\"id\"`\n",
+			"Status         OrderStatus            `db:\"status\" json:\"status\"`\n",
+			"PreviousStatus sql.Null[OrderStatus]  `db:\"previous_status\" json:\"previous_status\"`\n",
+			"Total          string                 `db:\"total\" json:\"total\"`\n",
+			"\t// free text\n\t// from the customer\n\tNote           sql.NullString",
+			"PlacedAt       time.Time              `db:\"placed_at\"",
+			"ShippedAt      sql.NullTime ",
+			"Weight         sql.Null[float32] ",
+			"Metadata       json.RawMessage ",
+			"TrackingURL    sql.NullString         `db:\"tracking url\" json:\"tracking url\"`\n",
+			"// OpenOrders is a row of the public.open_orders view\n",
+			"Gift sql.NullBool ",
+			"type AuditEvents struct",
+			"type PublicEvents struct",
+		} {
+			if !strings.Contains(result, collapseSpaces(expected)) {
+				t.Errorf("expected %q in:\n%s", expected, result)
+			}
+		}
+	})
+
+	t.Run("pointer nullable types", func(t *testing.T) {
+		source, err := GenerateGoTypes(db, "models", GoNullablePointer)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		result := collapseSpaces(string(source))
+		for _, expected := range []string{"PreviousStatus *OrderStatus ", "Note           *string ", "Metadata       json.RawMessage "} {
+			if !strings.Contains(result, collapseSpaces(expected)) {
+				t.Errorf("expected %q in:\n%s", expected, result)
+			}
+		}
+		if strings.Contains(result, "database/sql") {
+			t.Error("expected no database/sql import with pointer types")
+		}
+	})
+
+	t.Run("empty database", func(t *testing.T) {
+		source, err := GenerateGoTypes(dbo.NewDatabase("empty", nil), "models", "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if strings.Contains(string(source), "import") {
+			t.Errorf("expected no imports, got:\n%s", source)
+		}
+	})
+}
+
+func TestGenerateTypeScriptTypes(t *testing.T) {
+	result := GenerateTypeScriptTypes(newCodegenTestDatabase())
+
+	for _, expected := range []string{
+		"/** The public.order_status enum type */\nexport type OrderStatus = \"pending\" | \"in-progress\" | \"shipped\";\n",
+		"/**\n * A row of the public.orders table\n * customer orders\n */\nexport interface Orders {\n",
+		"  id: number;\n",
+		"  status: OrderStatus;\n",
+		"  previous_status: OrderStatus | null;\n",
+		"  total: string;\n",
+		"  /** free text from the customer */\n  note: string | null;\n",
+		"  placed_at: string;\n",
+		"  metadata: unknown;\n",
+		"  \"tracking url\": string | null;\n",
+		"export interface OpenOrders {\n  id: number;\n  gift: boolean | null;\n}\n",
+		"export interface AuditEvents {\n}\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected %q in:\n%s", expected, result)
+		}
+	}
+}
+
+func TestCodegenKindOf(t *testing.T) {
+	tests := []struct {
+		dialect  SQLDialect
+		dataType string
+		expected codegenKind
+	}{
+		{SQLDialectPostgres, "integer", codegenInt32},
+		{SQLDialectPostgres, "double precision", codegenFloat64},
+		{SQLDialectPostgres, "bit", codegenString},
+		{SQLDialectMySQL, "bit", codegenUint64},
+		{SQLDialectMySQL, "float", codegenFloat32},
+		{SQLDialectMySQL, "tinyint", codegenInt8},
+		{SQLDialectMySQL, "datetime", codegenTime},
+		{SQLDialectMySQL, "mediumblob", codegenBytes},
+		{SQLDialectMySQL, "enum", codegenString},
+		{SQLDialectPostgres, "time without time zone", codegenTime},
+		{SQLDialectPostgres, "ARRAY", codegenUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect)+" "+tt.dataType, func(t *testing.T) {
+			if got := codegenKindOf(tt.dialect, tt.dataType); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGoIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"order_items": "OrderItems",
+		"user-id":     "UserID",
+		"api_url":     "APIURL",
+		"2fa_secret":  "X2faSecret",
+		"":            "X",
+		"größe":       "Größe",
+	}
+	for input, expected := range tests {
+		if got := goIdentifier(input); got != expected {
+			t.Errorf("goIdentifier(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
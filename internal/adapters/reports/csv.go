@@ -0,0 +1,190 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// CSVReportWriter generates a normalized CSV inventory, one file per object kind.
+// Rows reference each other through stable, name-derived IDs (e.g. "public.users.id")
+// so the files can be joined in a spreadsheet or loaded into another tool.
+type CSVReportWriter struct{}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *CSVReportWriter) GetReportKeys() []string {
+	return []string{"csv"}
+}
+
+// GetReportFileExtension returns the file extension for CSV reports
+func (w *CSVReportWriter) GetReportFileExtension() string {
+	return "csv"
+}
+
+// GetReportName returns the name of the CSV report
+func (w *CSVReportWriter) GetReportName() string {
+	return "CSV Inventory"
+}
+
+// WriteInventoryReport writes the CSV bundle next to the given file path. Each object
+// kind is written to "<path without extension>_<kind>.csv".
+func (w *CSVReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	base := strings.TrimSuffix(filePath, ".csv")
+	for _, file := range GenerateCSVInventory(db) {
+		data, err := file.encode()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(base+"_"+file.Kind+".csv", data, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CSVFile is a single table of the CSV inventory bundle
+type CSVFile struct {
+	Kind   string
+	Header []string
+	Rows   [][]string
+}
+
+// encode serializes the file with its header row
+func (f *CSVFile) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(f.Header); err != nil {
+		return nil, err
+	}
+	if err := writer.WriteAll(f.Rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateCSVInventory builds the normalized inventory tables for a database.
+// Files are returned in a fixed order and rows are ordered by schema and object name.
+func GenerateCSVInventory(db *dbo.Database) []*CSVFile {
+	schemas := &CSVFile{Kind: "schemas", Header: []string{"schema_id", "name", "owner"}}
+	tables := &CSVFile{Kind: "tables", Header: []string{"table_id", "schema_id", "name", "primary_key", "comment"}}
+	columns := &CSVFile{Kind: "columns", Header: []string{"column_id", "table_id", "name", "ordinal_position", "data_type", "nullable", "default_value", "char_max_length", "numeric_precision", "numeric_scale", "is_primary_key", "comment"}}
+	indexes := &CSVFile{Kind: "indexes", Header: []string{"index_id", "table_id", "name", "index_type", "is_unique", "is_primary"}}
+	indexColumns := &CSVFile{Kind: "index_columns", Header: []string{"index_id", "position", "column_id"}}
+	foreignKeys := &CSVFile{Kind: "foreign_keys", Header: []string{"fk_id", "table_id", "name", "referenced_table_id", "on_delete", "on_update"}}
+	fkColumns := &CSVFile{Kind: "fk_columns", Header: []string{"fk_id", "position", "column_id", "referenced_column_id"}}
+	constraints := &CSVFile{Kind: "constraints", Header: []string{"constraint_id", "table_id", "name", "type", "columns", "check_expression"}}
+	triggers := &CSVFile{Kind: "triggers", Header: []string{"trigger_id", "table_id", "name", "timing", "events", "for_each", "function_id", "definition"}}
+	functions := &CSVFile{Kind: "functions", Header: []string{"function_id", "schema_id", "name", "language", "return_type", "parameters", "definition"}}
+
+	for _, schema := range sortedSchemas(db) {
+		schemaID := dbo.ObjectID("", schema.Name())
+		schemas.Rows = append(schemas.Rows, []string{schemaID, schema.Name(), schema.Owner()})
+
+		for _, table := range sortedTables(schema) {
+			tableID := dbo.ObjectID(schemaID, table.Name())
+			pkName := ""
+			if table.PrimaryKey() != nil {
+				pkName = table.PrimaryKey().Name()
+			}
+			tables.Rows = append(tables.Rows, []string{tableID, schemaID, table.Name(), pkName, table.Comment()})
+
+			for _, col := range sortedColumns(table) {
+				columns.Rows = append(columns.Rows, []string{
+					dbo.ObjectID(tableID, col.Name()),
+					tableID,
+					col.Name(),
+					strconv.Itoa(col.OrdinalPosition()),
+					col.DataType(),
+					strconv.FormatBool(col.IsNullable()),
+					csvOptionalString(col.DefaultValue()),
+					csvOptionalInt(col.CharMaxLength()),
+					csvOptionalInt(col.NumericPrecision()),
+					csvOptionalInt(col.NumericScale()),
+					strconv.FormatBool(isPrimaryKeyColumn(table, col.Name())),
+					col.Comment(),
+				})
+			}
+
+			for _, idx := range table.Indexes() {
+				indexID := dbo.ObjectID(tableID, idx.Name())
+				indexes.Rows = append(indexes.Rows, []string{
+					indexID, tableID, idx.Name(), string(idx.IndexType()),
+					strconv.FormatBool(idx.IsUnique()), strconv.FormatBool(idx.IsPrimary()),
+				})
+				for i, col := range idx.Columns() {
+					indexColumns.Rows = append(indexColumns.Rows, []string{indexID, strconv.Itoa(i + 1), dbo.ObjectID(tableID, col.Name())})
+				}
+			}
+
+			for _, fk := range table.ForeignKeys() {
+				fkID := dbo.ObjectID(tableID, fk.Name())
+				refTableID := dbo.ObjectID(dbo.ObjectID("", referencedSchemaName(fk)), fk.ReferencedTable())
+				foreignKeys.Rows = append(foreignKeys.Rows, []string{
+					fkID, tableID, fk.Name(), refTableID, string(fk.OnDelete()), string(fk.OnUpdate()),
+				})
+				for i, col := range fk.Columns() {
+					refColumnID := ""
+					if i < len(fk.ReferencedColumns()) {
+						refColumnID = dbo.ObjectID(refTableID, fk.ReferencedColumns()[i].Name())
+					}
+					fkColumns.Rows = append(fkColumns.Rows, []string{fkID, strconv.Itoa(i + 1), dbo.ObjectID(tableID, col.Name()), refColumnID})
+				}
+			}
+
+			for _, c := range table.Constraints() {
+				constraints.Rows = append(constraints.Rows, []string{
+					dbo.ObjectID(tableID, c.Name()), tableID, c.Name(), string(c.Type()),
+					strings.Join(columnNames(c.Columns()), ";"), c.CheckExpression(),
+				})
+			}
+
+			for _, trigger := range table.Triggers() {
+				events := make([]string, len(trigger.Events()))
+				for i, e := range trigger.Events() {
+					events[i] = string(e)
+				}
+				functionID := ""
+				if fn := trigger.Function(); fn != nil {
+					functionID = dbo.RoutineID(dbo.ObjectID("", schemaNameOf(fn.Schema())), fn.Name(), fn.Parameters())
+				}
+				triggers.Rows = append(triggers.Rows, []string{
+					dbo.ObjectID(tableID, trigger.Name()), tableID, trigger.Name(), string(trigger.Timing()),
+					strings.Join(events, ";"), trigger.ForEach(), functionID, trigger.Definition(),
+				})
+			}
+		}
+
+		for _, fn := range sortedFunctions(schema) {
+			params := make([]string, len(fn.Parameters()))
+			for i, p := range fn.Parameters() {
+				params[i] = strings.TrimSpace(string(p.Mode()) + " " + strings.TrimSpace(p.Name()+" "+p.DataType()))
+			}
+			functions.Rows = append(functions.Rows, []string{
+				dbo.RoutineID(schemaID, fn.Name(), fn.Parameters()), schemaID, fn.Name(), fn.Language(), fn.ReturnType(),
+				strings.Join(params, ";"), fn.Definition(),
+			})
+		}
+	}
+
+	return []*CSVFile{schemas, tables, columns, indexes, indexColumns, foreignKeys, fkColumns, constraints, triggers, functions}
+}
+
+// csvOptionalString renders an optional string, leaving the cell empty when unset
+func csvOptionalString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// csvOptionalInt renders an optional integer, leaving the cell empty when unset
+func csvOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
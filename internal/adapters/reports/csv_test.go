@@ -0,0 +1,159 @@
+package reports
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// csvFileByKind returns the bundle file of the given kind, failing the test if absent
+func csvFileByKind(t *testing.T, files []*CSVFile, kind string) *CSVFile {
+	t.Helper()
+	for _, f := range files {
+		if f.Kind == kind {
+			return f
+		}
+	}
+	t.Fatalf("expected %s file in bundle", kind)
+	return nil
+}
+
+func TestCSVReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes one file per object kind", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "shop_CSV_Inventory.csv")
+
+		writer := &CSVReportWriter{}
+		err := writer.WriteInventoryReport(filePath, newDDLTestDatabase("PostgreSQL"))
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		kinds := []string{"schemas", "tables", "columns", "indexes", "index_columns", "foreign_keys", "fk_columns", "constraints", "triggers", "functions"}
+		for _, kind := range kinds {
+			path := filepath.Join(tmpDir, "shop_CSV_Inventory_"+kind+".csv")
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatalf("expected %s to exist: %v", path, err)
+			}
+			records, err := csv.NewReader(f).ReadAll()
+			f.Close()
+			if err != nil {
+				t.Fatalf("expected valid CSV in %s: %v", path, err)
+			}
+			if len(records) == 0 {
+				t.Errorf("expected header row in %s", path)
+			}
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &CSVReportWriter{}
+		db := dbo.NewDatabase("testdb", nil)
+
+		err := writer.WriteInventoryReport("/nonexistent/path/file.csv", db)
+
+		if err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+}
+
+func TestGenerateCSVInventory(t *testing.T) {
+	files := GenerateCSVInventory(newDDLTestDatabase("PostgreSQL"))
+
+	t.Run("tables reference their schema", func(t *testing.T) {
+		tables := csvFileByKind(t, files, "tables")
+		if len(tables.Rows) != 3 {
+			t.Fatalf("expected 3 tables, got %d", len(tables.Rows))
+		}
+		first := tables.Rows[0]
+		if first[0] != "public.orders" || first[1] != "public" || first[3] != "orders_pkey" {
+			t.Errorf("unexpected first table row %v", first)
+		}
+	})
+
+	t.Run("columns are ordered by ordinal position", func(t *testing.T) {
+		columns := csvFileByKind(t, files, "columns")
+		var userColumns []string
+		for _, row := range columns.Rows {
+			if row[1] == "public.users" {
+				userColumns = append(userColumns, row[2])
+			}
+		}
+		if strings.Join(userColumns, ",") != "id,email,status" {
+			t.Errorf("expected id,email,status, got %v", userColumns)
+		}
+	})
+
+	t.Run("fk columns join to referenced columns", func(t *testing.T) {
+		fkColumns := csvFileByKind(t, files, "fk_columns")
+		found := false
+		for _, row := range fkColumns.Rows {
+			if row[0] == "public.orders.orders_user_fk" {
+				found = true
+				if row[2] != "public.orders.user_id" || row[3] != "public.users.id" {
+					t.Errorf("unexpected fk column row %v", row)
+				}
+			}
+		}
+		if !found {
+			t.Error("expected fk column row for orders_user_fk")
+		}
+	})
+
+	t.Run("index columns reference column ids", func(t *testing.T) {
+		indexColumns := csvFileByKind(t, files, "index_columns")
+		if len(indexColumns.Rows) != 1 {
+			t.Fatalf("expected 1 index column, got %d", len(indexColumns.Rows))
+		}
+		row := indexColumns.Rows[0]
+		if row[0] != "public.users.users_email_key" || row[1] != "1" || row[2] != "public.users.email" {
+			t.Errorf("unexpected index column row %v", row)
+		}
+	})
+
+	t.Run("triggers list events", func(t *testing.T) {
+		triggers := csvFileByKind(t, files, "triggers")
+		if len(triggers.Rows) != 1 || triggers.Rows[0][4] != "INSERT;UPDATE" {
+			t.Errorf("unexpected trigger rows %v", triggers.Rows)
+		}
+	})
+
+	t.Run("every row matches its header width", func(t *testing.T) {
+		for _, f := range files {
+			for _, row := range f.Rows {
+				if len(row) != len(f.Header) {
+					t.Errorf("%s: expected %d cells, got %d", f.Kind, len(f.Header), len(row))
+				}
+			}
+		}
+	})
+}
+
+func TestGenerateCSVInventory_IDs(t *testing.T) {
+	db := dbo.NewDatabase("shop", nil)
+	schema := dbo.NewSchema("sales.eu", "", nil)
+	db.AddSchema(schema)
+	table := dbo.NewTable("orders", nil)
+	table.AddColumn(dbo.NewColumn("id", "int", false))
+	schema.AddTable(table)
+	fn := dbo.NewFunction("area", "")
+	fn.AddParameter(dbo.NewFunctionParameter("w", "int", dbo.ParameterModeIn))
+	fn.AddParameter(dbo.NewFunctionParameter("h", "int", dbo.ParameterModeIn))
+	fn.AddParameter(dbo.NewFunctionParameter("result", "int", dbo.ParameterModeOut))
+	schema.AddFunction(fn)
+
+	files := GenerateCSVInventory(db)
+	if id := csvFileByKind(t, files, "columns").Rows[0][0]; id != `"sales.eu".orders.id` {
+		t.Errorf("expected the dotted schema name to be quoted, got %s", id)
+	}
+	if id := csvFileByKind(t, files, "functions").Rows[0][0]; id != `"sales.eu".area(int,int)` {
+		t.Errorf("expected the function ID to carry its argument types, got %s", id)
+	}
+}
@@ -0,0 +1,299 @@
+package reports
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// DBMLReportWriter generates DBML (dbdiagram.io) schema definitions from database schemas
+type DBMLReportWriter struct{}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *DBMLReportWriter) GetReportKeys() []string {
+	return []string{"dbml"}
+}
+
+// GetReportFileExtension returns the file extension for DBML reports
+func (w *DBMLReportWriter) GetReportFileExtension() string {
+	return "dbml"
+}
+
+// GetReportName returns the name of the DBML report
+func (w *DBMLReportWriter) GetReportName() string {
+	return "DBML Schema"
+}
+
+// WriteInventoryReport writes a DBML schema definition to the specified file
+func (w *DBMLReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	dbml := GenerateDBML(db)
+	return os.WriteFile(filePath, []byte(dbml), 0600)
+}
+
+// GenerateDBML generates a DBML document string from a database.
+// Tables are ordered by schema and name, references are written after all tables.
+func GenerateDBML(db *dbo.Database) string {
+	var sb strings.Builder
+	sb.WriteString("// Database: ")
+	sb.WriteString(db.Name())
+	sb.WriteString("\n")
+
+	for _, schema := range sortedSchemas(db) {
+		for _, table := range sortedTables(schema) {
+			sb.WriteString("\n")
+			writeDBMLTable(&sb, table)
+		}
+	}
+
+	var refs []string
+	for _, schema := range sortedSchemas(db) {
+		for _, table := range sortedTables(schema) {
+			for _, fk := range table.ForeignKeys() {
+				if ref := formatDBMLRef(db, table, fk); ref != "" {
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+	if len(refs) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, ref := range refs {
+		sb.WriteString(ref)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// writeDBMLTable writes a single table block with its columns, indexes and note
+func writeDBMLTable(sb *strings.Builder, table *dbo.Table) {
+	sb.WriteString("Table ")
+	sb.WriteString(dbmlTableName(table.Schema(), table.Name()))
+	sb.WriteString(" {\n")
+
+	for _, col := range sortedColumns(table) {
+		writeDBMLColumn(sb, table, col)
+	}
+
+	var indexLines []string
+	if pk := table.PrimaryKey(); pk != nil && len(pk.Columns()) > 1 {
+		indexLines = append(indexLines, dbmlIndexColumns(pk.Columns())+" [pk]")
+	}
+	for _, idx := range table.Indexes() {
+		if idx.IsPrimary() || len(idx.Columns()) == 0 {
+			continue
+		}
+		settings := []string{}
+		if idx.IsUnique() {
+			settings = append(settings, "unique")
+		}
+		settings = append(settings, "name: "+dbmlString(idx.Name()))
+		if idxType := strings.ToLower(string(idx.IndexType())); idxType == "btree" || idxType == "hash" {
+			settings = append(settings, "type: "+idxType)
+		}
+		indexLines = append(indexLines, dbmlIndexColumns(idx.Columns())+" ["+strings.Join(settings, ", ")+"]")
+	}
+	if len(indexLines) > 0 {
+		sb.WriteString("\n  Indexes {\n")
+		for _, line := range indexLines {
+			sb.WriteString("    ")
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("  }\n")
+	}
+
+	if lines := tableNoteLines(table); len(lines) > 0 {
+		sb.WriteString("\n  Note: ")
+		sb.WriteString(dbmlString(strings.Join(lines, "\n")))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("}\n")
+}
+
+// writeDBMLColumn writes a single column line with its settings
+func writeDBMLColumn(sb *strings.Builder, table *dbo.Table, col *dbo.Column) {
+	sb.WriteString("  ")
+	sb.WriteString(dbmlIdentifier(col.Name()))
+	sb.WriteString(" ")
+	sb.WriteString(dbmlType(formatColumnType(col)))
+
+	var settings []string
+	pk := table.PrimaryKey()
+	isPK := pk != nil && len(pk.Columns()) == 1 && pk.Columns()[0].Name() == col.Name()
+	if isPK {
+		settings = append(settings, "pk")
+	}
+	if col.IsNullable() {
+		settings = append(settings, "null")
+	} else {
+		settings = append(settings, "not null")
+	}
+	if !isPK && isUniqueColumn(table, col) {
+		settings = append(settings, "unique")
+	}
+	if col.DefaultValue() != nil {
+		def := *col.DefaultValue()
+		if strings.HasPrefix(strings.ToLower(def), "nextval(") {
+			settings = append(settings, "increment")
+		} else {
+			settings = append(settings, "default: "+dbmlDefault(def))
+		}
+	}
+	if col.Comment() != "" {
+		settings = append(settings, "note: "+dbmlString(col.Comment()))
+	}
+
+	sb.WriteString(" [")
+	sb.WriteString(strings.Join(settings, ", "))
+	sb.WriteString("]\n")
+}
+
+// formatDBMLRef formats a foreign key as a DBML reference. Unique FK columns are
+// written as one-to-one ('-'), all others as many-to-one ('>'). Returns an empty
+// string if the referenced columns cannot be determined.
+func formatDBMLRef(db *dbo.Database, table *dbo.Table, fk *dbo.ForeignKey) string {
+	refColumns := fk.ReferencedColumns()
+	if len(refColumns) == 0 {
+		if refTable := resolveReferencedTable(db, fk); refTable != nil && refTable.PrimaryKey() != nil {
+			refColumns = refTable.PrimaryKey().Columns()
+		}
+	}
+	if len(fk.Columns()) == 0 || len(refColumns) == 0 {
+		return ""
+	}
+
+	relation := ">"
+	if foreignKeyCardinality(fk).childUnique {
+		relation = "-"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Ref ")
+	sb.WriteString(dbmlIdentifier(fk.Name()))
+	sb.WriteString(": ")
+	sb.WriteString(dbmlTableName(table.Schema(), table.Name()))
+	sb.WriteString(".")
+	sb.WriteString(dbmlRefColumns(fk.Columns()))
+	sb.WriteString(" ")
+	sb.WriteString(relation)
+	sb.WriteString(" ")
+	sb.WriteString(dbmlIdentifier(referencedSchemaName(fk)))
+	sb.WriteString(".")
+	sb.WriteString(dbmlIdentifier(fk.ReferencedTable()))
+	sb.WriteString(".")
+	sb.WriteString(dbmlRefColumns(refColumns))
+
+	var actions []string
+	if fk.OnDelete() != "" && fk.OnDelete() != dbo.ActionNoAction {
+		actions = append(actions, "delete: "+strings.ToLower(string(fk.OnDelete())))
+	}
+	if fk.OnUpdate() != "" && fk.OnUpdate() != dbo.ActionNoAction {
+		actions = append(actions, "update: "+strings.ToLower(string(fk.OnUpdate())))
+	}
+	if len(actions) > 0 {
+		sb.WriteString(" [")
+		sb.WriteString(strings.Join(actions, ", "))
+		sb.WriteString("]")
+	}
+	return sb.String()
+}
+
+// dbmlTableName returns the schema-qualified, quoted name of a table
+func dbmlTableName(schema *dbo.Schema, tableName string) string {
+	if schema == nil || schema.Name() == "" {
+		return dbmlIdentifier(tableName)
+	}
+	return dbmlIdentifier(schema.Name()) + "." + dbmlIdentifier(tableName)
+}
+
+// dbmlIdentifier quotes an identifier so that any name is accepted by DBML parsers
+func dbmlIdentifier(name string) string {
+	return "\"" + strings.ReplaceAll(name, "\"", "\\\"") + "\""
+}
+
+// dbmlRefColumns formats the column part of a reference endpoint
+func dbmlRefColumns(columns []*dbo.Column) string {
+	if len(columns) == 1 {
+		return dbmlIdentifier(columns[0].Name())
+	}
+	return dbmlIndexColumns(columns)
+}
+
+// dbmlIndexColumns formats a column list as used by composite indexes and references
+func dbmlIndexColumns(columns []*dbo.Column) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = dbmlIdentifier(col.Name())
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+// dbmlType returns a column type, quoted when it contains characters DBML does not
+// accept in a bare type name (e.g. "timestamp with time zone")
+func dbmlType(dataType string) string {
+	for _, r := range dataType {
+		if r == '_' || r == '(' || r == ')' || r == ',' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			continue
+		}
+		return dbmlIdentifier(dataType)
+	}
+	return dataType
+}
+
+// dbmlString returns a DBML string literal, using a multi-line literal when needed
+func dbmlString(s string) string {
+	if strings.Contains(s, "\n") {
+		return "'''" + strings.ReplaceAll(s, "'''", "\\'''") + "'''"
+	}
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return "'" + strings.ReplaceAll(s, "'", "\\'") + "'"
+}
+
+// dbmlDefault formats a column default. Numbers and booleans are written as-is,
+// quoted literals (with any type cast removed) as strings, and anything else as
+// an expression.
+func dbmlDefault(def string) string {
+	value := strings.TrimSpace(def)
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	switch strings.ToLower(value) {
+	case "true", "false", "null":
+		return strings.ToLower(value)
+	}
+	if literal, ok := unquoteSQLLiteral(value); ok {
+		return dbmlString(literal)
+	}
+	return "`" + strings.ReplaceAll(value, "`", "'") + "`"
+}
+
+// unquoteSQLLiteral extracts the value of a single-quoted SQL string literal,
+// ignoring a trailing PostgreSQL type cast such as 'active'::character varying
+func unquoteSQLLiteral(value string) (string, bool) {
+	if !strings.HasPrefix(value, "'") {
+		return "", false
+	}
+	var sb strings.Builder
+	for i := 1; i < len(value); i++ {
+		if value[i] != '\'' {
+			sb.WriteByte(value[i])
+			continue
+		}
+		if i+1 < len(value) && value[i+1] == '\'' {
+			sb.WriteByte('\'')
+			i++
+			continue
+		}
+		rest := value[i+1:]
+		if rest == "" || strings.HasPrefix(rest, "::") {
+			return sb.String(), true
+		}
+		return "", false
+	}
+	return "", false
+}
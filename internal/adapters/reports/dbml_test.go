@@ -0,0 +1,154 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+func TestDBMLReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes dbml file successfully", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "test.dbml")
+
+		writer := &DBMLReportWriter{}
+		err := writer.WriteInventoryReport(filePath, newDiagramTestDatabase())
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+
+		if !strings.Contains(string(content), "Table \"public\".\"users\" {") {
+			t.Errorf("expected users table, got %s", string(content))
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &DBMLReportWriter{}
+		db := dbo.NewDatabase("testdb", nil)
+
+		err := writer.WriteInventoryReport("/nonexistent/path/file.dbml", db)
+
+		if err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+}
+
+func TestGenerateDBML(t *testing.T) {
+	t.Run("empty database", func(t *testing.T) {
+		result := GenerateDBML(dbo.NewDatabase("testdb", nil))
+
+		if result != "// Database: testdb\n" {
+			t.Errorf("expected header only, got %q", result)
+		}
+	})
+
+	t.Run("tables, columns, indexes and notes", func(t *testing.T) {
+		result := GenerateDBML(newDiagramTestDatabase())
+
+		expectations := []string{
+			"  \"id\" integer [pk, not null]\n",
+			"  \"email\" \"character varying(255)\" [not null, unique]\n",
+			"  \"status\" text [null, default: 'active']\n",
+			"    (\"email\") [unique, name: 'users_email_key', type: btree]\n",
+			"  Note: '''registered accounts\nCHECK users_status_check: status <> ''::text'''\n",
+		}
+		for _, expected := range expectations {
+			if !strings.Contains(result, expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", expected, result)
+			}
+		}
+	})
+
+	t.Run("references with cardinality and actions", func(t *testing.T) {
+		result := GenerateDBML(newDiagramTestDatabase())
+
+		expectations := []string{
+			"Ref \"orders_user_fk\": \"public\".\"orders\".\"user_id\" > \"public\".\"users\".\"id\" [delete: cascade]\n",
+			"Ref \"orders_coupon_fk\": \"public\".\"orders\".\"coupon_id\" > \"public\".\"users\".\"id\"\n",
+			"Ref \"profiles_user_fk\": \"public\".\"profiles\".\"user_id\" - \"public\".\"users\".\"id\"\n",
+		}
+		for _, expected := range expectations {
+			if !strings.Contains(result, expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", expected, result)
+			}
+		}
+	})
+
+	t.Run("composite primary key becomes an index", func(t *testing.T) {
+		db := dbo.NewDatabase("testdb", nil)
+		schema := dbo.NewSchema("public", "owner", nil)
+		db.AddSchema(schema)
+		table := dbo.NewTable("order_items", nil)
+		a := dbo.NewColumn("order_id", "integer", false)
+		b := dbo.NewColumn("product_id", "integer", false)
+		table.AddColumn(a)
+		table.AddColumn(b)
+		table.SetPrimaryKey(dbo.NewPrimaryKey("order_items_pkey", table, []*dbo.Column{a, b}))
+		schema.AddTable(table)
+
+		result := GenerateDBML(db)
+
+		if !strings.Contains(result, "(\"order_id\", \"product_id\") [pk]") {
+			t.Errorf("expected composite pk index, got:\n%s", result)
+		}
+		if strings.Contains(result, "\"order_id\" integer [pk") {
+			t.Errorf("expected composite pk columns not to be marked pk individually, got:\n%s", result)
+		}
+	})
+}
+
+func TestDBMLDefault(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0", "0"},
+		{"1.5", "1.5"},
+		{"TRUE", "true"},
+		{"'active'::character varying", "'active'"},
+		{"'it''s'", "'it\\'s'"},
+		{"now()", "`now()`"},
+		{"CURRENT_TIMESTAMP", "`CURRENT_TIMESTAMP`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := dbmlDefault(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDBMLType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"integer", "integer"},
+		{"varchar(255)", "varchar(255)"},
+		{"numeric(10,2)", "numeric(10,2)"},
+		{"timestamp with time zone", "\"timestamp with time zone\""},
+		{"integer[]", "\"integer[]\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := dbmlType(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package reports
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// DependencyReportWriter generates the non-foreign-key dependency graph of a database:
+// views on tables and columns, triggers on functions, column defaults on sequences and
+// functions, and routines on the tables they use
+type DependencyReportWriter struct{}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *DependencyReportWriter) GetReportKeys() []string {
+	return []string{"dependencies"}
+}
+
+// GetReportFileExtension returns the file extension for dependency reports
+func (w *DependencyReportWriter) GetReportFileExtension() string {
+	return "json"
+}
+
+// GetReportName returns the name of the dependency report
+func (w *DependencyReportWriter) GetReportName() string {
+	return "Dependency Graph"
+}
+
+// WriteInventoryReport writes the graph as JSON to the given file path and as a
+// Mermaid flowchart next to it ("<path without extension>.mmd")
+func (w *DependencyReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	data, err := GenerateDependencyGraphJSON(db)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(strings.TrimSuffix(filePath, ".json")+".mmd", []byte(GenerateDependencyMermaid(db)), 0600)
+}
+
+// dependencyNodeJSON is a node of the JSON dependency graph
+type dependencyNodeJSON struct {
+	ID     string         `json:"id"`
+	Type   dbo.ObjectType `json:"type"`
+	Schema string         `json:"schema,omitempty"`
+	Parent string         `json:"parent,omitempty"`
+	Name   string         `json:"name"`
+}
+
+// dependencyEdgeJSON is an edge of the JSON dependency graph, pointing from the
+// dependent object to the object it uses
+type dependencyEdgeJSON struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Inferred bool   `json:"inferred,omitempty"`
+}
+
+// dependencyGraphJSON is the document written by the dependency report
+type dependencyGraphJSON struct {
+	Database string               `json:"database"`
+	Nodes    []dependencyNodeJSON `json:"nodes"`
+	Edges    []dependencyEdgeJSON `json:"edges"`
+}
+
+// GenerateDependencyGraphJSON generates the dependency graph as indented JSON
+func GenerateDependencyGraphJSON(db *dbo.Database) ([]byte, error) {
+	graph := dependencyGraphJSON{Database: db.Name(), Nodes: []dependencyNodeJSON{}, Edges: []dependencyEdgeJSON{}}
+	seen := make(map[string]bool)
+	addNode := func(ref *dbo.ObjectReference) string {
+		id := dependencyNodeID(ref)
+		if !seen[id] {
+			seen[id] = true
+			graph.Nodes = append(graph.Nodes, dependencyNodeJSON{
+				ID: id, Type: ref.Type(), Schema: ref.Schema(), Parent: ref.Parent(), Name: ref.Name(),
+			})
+		}
+		return id
+	}
+	for _, dep := range CollectDependencies(db) {
+		graph.Edges = append(graph.Edges, dependencyEdgeJSON{
+			From:     addNode(dep.Dependent()),
+			To:       addNode(dep.Referenced()),
+			Inferred: dep.Inferred(),
+		})
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+	return json.MarshalIndent(graph, "", "  ")
+}
+
+// GenerateDependencyMermaid generates the dependency graph as a Mermaid flowchart.
+// Columns are folded into their tables and listed on the edge label; edges inferred
+// from routine definitions are dotted.
+func GenerateDependencyMermaid(db *dbo.Database) string {
+	type edge struct {
+		from, to string
+		inferred bool
+		columns  []string
+	}
+	nodes := make(map[string]*dbo.ObjectReference)
+	edges := make(map[string]*edge)
+	var edgeOrder []string
+
+	for _, dep := range CollectDependencies(db) {
+		from, fromColumn := dependencyDiagramNode(dep.Dependent())
+		to, toColumn := dependencyDiagramNode(dep.Referenced())
+		fromID, toID := dependencyNodeID(from), dependencyNodeID(to)
+		if fromID == toID {
+			continue
+		}
+		nodes[fromID] = from
+		nodes[toID] = to
+
+		key := fromID + "|" + toID
+		e, ok := edges[key]
+		if !ok {
+			e = &edge{from: fromID, to: toID, inferred: dep.Inferred()}
+			edges[key] = e
+			edgeOrder = append(edgeOrder, key)
+		}
+		e.inferred = e.inferred && dep.Inferred()
+		for _, col := range []string{fromColumn, toColumn} {
+			if col != "" && !slices.Contains(e.columns, col) {
+				e.columns = append(e.columns, col)
+			}
+		}
+	}
+
+	nodeIDs := make([]string, 0, len(nodes))
+	for id := range nodes {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Strings(nodeIDs)
+	aliases := make(map[string]string, len(nodeIDs))
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for i, id := range nodeIDs {
+		aliases[id] = "n" + strconv.Itoa(i+1)
+		sb.WriteString("    ")
+		sb.WriteString(aliases[id])
+		sb.WriteString(dependencyNodeShape(nodes[id]))
+		sb.WriteString("\n")
+	}
+	for _, key := range edgeOrder {
+		e := edges[key]
+		sb.WriteString("    ")
+		sb.WriteString(aliases[e.from])
+		arrow := " --> "
+		if e.inferred {
+			arrow = " -.-> "
+		}
+		if len(e.columns) > 0 {
+			arrow = strings.TrimSuffix(arrow, " ") + "|\"" + strings.Join(e.columns, ", ") + "\"| "
+		}
+		sb.WriteString(arrow)
+		sb.WriteString(aliases[e.to])
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// CollectDependencies returns the dependencies recorded on the database together with
+// those inferred from function and procedure definitions, without duplicates and
+// ordered by dependent and referenced object
+func CollectDependencies(db *dbo.Database) []*dbo.Dependency {
+	var dependencies []*dbo.Dependency
+	seen := make(map[string]bool)
+	add := func(dep *dbo.Dependency) {
+		key := dependencyNodeID(dep.Dependent()) + "|" + dependencyNodeID(dep.Referenced())
+		if !seen[key] {
+			seen[key] = true
+			dependencies = append(dependencies, dep)
+		}
+	}
+	for _, dep := range db.Dependencies() {
+		add(dep)
+	}
+	for _, dep := range inferRoutineDependencies(db) {
+		add(dep)
+	}
+
+	sort.SliceStable(dependencies, func(i, j int) bool {
+		a, b := dependencyNodeID(dependencies[i].Dependent()), dependencyNodeID(dependencies[j].Dependent())
+		if a != b {
+			return a < b
+		}
+		return dependencyNodeID(dependencies[i].Referenced()) < dependencyNodeID(dependencies[j].Referenced())
+	})
+	return dependencies
+}
+
+// routineTableReference matches the relation following FROM, JOIN, INTO or UPDATE,
+// optionally schema-qualified and quoted
+var routineTableReference = regexp.MustCompile("(?i)\\b(?:from|join|into|update)\\s+((?:[`\"]?[\\w$]+[`\"]?\\s*\\.\\s*)?[`\"]?[\\w$]+[`\"]?)")
+
+// inferRoutineDependencies scans function and procedure definitions for the tables and
+// views they read or modify. Unqualified names resolve against the routine's own schema
+// first and then against any schema holding a relation of that name.
+func inferRoutineDependencies(db *dbo.Database) []*dbo.Dependency {
+	var dependencies []*dbo.Dependency
+	for _, schema := range sortedSchemas(db) {
+		var routines []*dbo.ObjectReference
+		var definitions []string
+		for _, fn := range sortedFunctions(schema) {
+			routines = append(routines, dbo.NewObjectReference(dbo.ObjectTypeFunction, schema.Name(), fn.Name()))
+			definitions = append(definitions, fn.Definition())
+		}
+		for _, proc := range sortedProcedures(schema) {
+			routines = append(routines, dbo.NewObjectReference(dbo.ObjectTypeProcedure, schema.Name(), proc.Name()))
+			definitions = append(definitions, proc.Definition())
+		}
+
+		for i, routine := range routines {
+			seen := make(map[string]bool)
+			for _, match := range routineTableReference.FindAllStringSubmatch(definitions[i], -1) {
+				ref := resolveRelationName(db, schema, match[1])
+				if ref == nil {
+					continue
+				}
+				if id := dependencyNodeID(ref); !seen[id] {
+					seen[id] = true
+					dep := dbo.NewDependency(routine, ref)
+					dep.SetInferred(true)
+					dependencies = append(dependencies, dep)
+				}
+			}
+		}
+	}
+	return dependencies
+}
+
+// resolveRelationName resolves a possibly qualified and quoted relation name used
+// inside a routine of the given schema to a mapped table or view
+func resolveRelationName(db *dbo.Database, home *dbo.Schema, name string) *dbo.ObjectReference {
+	name = strings.NewReplacer("\"", "", "`", "", " ", "", "\t", "", "\n", "").Replace(name)
+	lookup := func(schema *dbo.Schema, relation string) *dbo.ObjectReference {
+		if _, ok := schema.Tables()[relation]; ok {
+			return dbo.NewObjectReference(dbo.ObjectTypeTable, schema.Name(), relation)
+		}
+		if _, ok := schema.Views()[relation]; ok {
+			return dbo.NewObjectReference(dbo.ObjectTypeView, schema.Name(), relation)
+		}
+		return nil
+	}
+
+	if schemaName, relation, ok := strings.Cut(name, "."); ok {
+		if schema, exists := db.Schemas()[schemaName]; exists {
+			return lookup(schema, relation)
+		}
+		return nil
+	}
+	if ref := lookup(home, name); ref != nil {
+		return ref
+	}
+	var found *dbo.ObjectReference
+	for _, schema := range sortedSchemas(db) {
+		if ref := lookup(schema, name); ref != nil {
+			if found != nil {
+				// Ambiguous without a search path, leave it out
+				return nil
+			}
+			found = ref
+		}
+	}
+	return found
+}
+
+// dependencyDiagramNode folds a column reference into a reference to its table,
+// returning the column name separately
+func dependencyDiagramNode(ref *dbo.ObjectReference) (*dbo.ObjectReference, string) {
+	if ref.Type() == dbo.ObjectTypeColumn && ref.Parent() != "" {
+		return dbo.NewObjectReference(dbo.ObjectTypeTable, ref.Schema(), ref.Parent()), ref.Name()
+	}
+	return ref, ""
+}
+
+// dependencyNodeID returns a stable identifier for an object, e.g. "view:public.active_users"
+func dependencyNodeID(ref *dbo.ObjectReference) string {
+	return strings.ToLower(strings.ReplaceAll(string(ref.Type()), " ", "_")) + ":" + ref.FullyQualifiedName()
+}
+
+// dependencyNodeShape returns the Mermaid node shape and label for an object type
+func dependencyNodeShape(ref *dbo.ObjectReference) string {
+	label := "\"" + strings.ReplaceAll(ref.FullyQualifiedName(), "\"", "'") + "\""
+	switch ref.Type() {
+	case dbo.ObjectTypeView:
+		return "([" + label + "])"
+	case dbo.ObjectTypeMaterializedView:
+		return "[[" + label + "]]"
+	case dbo.ObjectTypeSequence:
+		return "[(" + label + ")]"
+	case dbo.ObjectTypeFunction, dbo.ObjectTypeProcedure:
+		return "{{" + label + "}}"
+	case dbo.ObjectTypeTrigger:
+		return ">" + label + "]"
+	case dbo.ObjectTypeColumn:
+		return "(" + label + ")"
+	default:
+		return "[" + label + "]"
+	}
+}
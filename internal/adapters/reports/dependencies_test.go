@@ -0,0 +1,221 @@
+package reports
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// newDependencyTestDatabase extends the DDL fixture with catalog dependencies and
+// routines whose bodies reference tables
+func newDependencyTestDatabase() *dbo.Database {
+	db := newDDLTestDatabase("PostgreSQL")
+	schema := db.Schemas()["public"]
+
+	schema.AddFunction(dbo.NewFunction("order_count", "CREATE FUNCTION public.order_count(uid integer) RETURNS bigint AS $$ SELECT count(*) FROM \"orders\" o JOIN public.users u ON u.id = o.user_id WHERE u.id = uid $$ LANGUAGE sql"))
+	schema.AddProcedure(dbo.NewProcedure("purge_profiles", "CREATE PROCEDURE public.purge_profiles() AS $$ DELETE FROM profiles; UPDATE missing_table SET x = 1; $$ LANGUAGE sql"))
+
+	column := func(table, name string) *dbo.ObjectReference {
+		ref := dbo.NewObjectReference(dbo.ObjectTypeColumn, "public", name)
+		ref.SetParent(table)
+		return ref
+	}
+	view := dbo.NewObjectReference(dbo.ObjectTypeView, "public", "recent_users")
+	db.AddDependency(dbo.NewDependency(view, column("users", "id")))
+	db.AddDependency(dbo.NewDependency(view, column("users", "status")))
+	db.AddDependency(dbo.NewDependency(
+		dbo.NewObjectReference(dbo.ObjectTypeView, "public", "active_users"),
+		dbo.NewObjectReference(dbo.ObjectTypeView, "public", "recent_users"),
+	))
+	db.AddDependency(dbo.NewDependency(column("orders", "id"), dbo.NewObjectReference(dbo.ObjectTypeSequence, "public", "orders_id_seq")))
+	trigger := dbo.NewObjectReference(dbo.ObjectTypeTrigger, "public", "users_touch")
+	trigger.SetParent("users")
+	db.AddDependency(dbo.NewDependency(trigger, dbo.NewObjectReference(dbo.ObjectTypeFunction, "public", "touch")))
+	return db
+}
+
+func TestDependencyReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes json and mermaid files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "shop_Dependency_Graph.json")
+
+		writer := &DependencyReportWriter{}
+		if err := writer.WriteInventoryReport(filePath, newDependencyTestDatabase()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read json file: %v", err)
+		}
+		if !json.Valid(data) {
+			t.Errorf("expected valid JSON, got %s", data)
+		}
+
+		diagram, err := os.ReadFile(filepath.Join(tmpDir, "shop_Dependency_Graph.mmd"))
+		if err != nil {
+			t.Fatalf("failed to read mermaid file: %v", err)
+		}
+		if !strings.HasPrefix(string(diagram), "flowchart LR\n") {
+			t.Errorf("expected flowchart, got %s", diagram)
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &DependencyReportWriter{}
+		if err := writer.WriteInventoryReport("/nonexistent/path/file.json", dbo.NewDatabase("testdb", nil)); err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+}
+
+func TestCollectDependencies(t *testing.T) {
+	db := newDependencyTestDatabase()
+	// A duplicate of a catalog dependency is collapsed
+	db.AddDependency(dbo.NewDependency(
+		dbo.NewObjectReference(dbo.ObjectTypeView, "public", "active_users"),
+		dbo.NewObjectReference(dbo.ObjectTypeView, "public", "recent_users"),
+	))
+
+	var edges []string
+	for _, dep := range CollectDependencies(db) {
+		edge := dependencyNodeID(dep.Dependent()) + " -> " + dependencyNodeID(dep.Referenced())
+		if dep.Inferred() {
+			edge += " (inferred)"
+		}
+		edges = append(edges, edge)
+	}
+
+	expected := []string{
+		"column:public.orders.id -> sequence:public.orders_id_seq",
+		"function:public.order_count -> table:public.orders (inferred)",
+		"function:public.order_count -> table:public.users (inferred)",
+		"procedure:public.purge_profiles -> table:public.profiles (inferred)",
+		"trigger:public.users.users_touch -> function:public.touch",
+		"view:public.active_users -> view:public.recent_users",
+		"view:public.recent_users -> column:public.users.id",
+		"view:public.recent_users -> column:public.users.status",
+	}
+	if strings.Join(edges, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected edges:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(edges, "\n"))
+	}
+}
+
+func TestResolveRelationName(t *testing.T) {
+	db := newDependencyTestDatabase()
+	other := dbo.NewSchema("archive", "owner", nil)
+	other.AddTable(dbo.NewTable("old_orders", nil))
+	other.AddTable(dbo.NewTable("users", nil))
+	db.AddSchema(other)
+	home := db.Schemas()["public"]
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"own schema first", "users", "table:public.users"},
+		{"view", "recent_users", "view:public.recent_users"},
+		{"qualified and quoted", "\"archive\" . \"users\"", "table:archive.users"},
+		{"unique in another schema", "old_orders", "table:archive.old_orders"},
+		{"backtick quoted", "`orders`", "table:public.orders"},
+		{"unknown", "missing", ""},
+		{"unknown schema", "nope.users", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := resolveRelationName(db, home, tt.input)
+			got := ""
+			if ref != nil {
+				got = dependencyNodeID(ref)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateDependencyGraphJSON(t *testing.T) {
+	data, err := GenerateDependencyGraphJSON(newDependencyTestDatabase())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var graph dependencyGraphJSON
+	if err := json.Unmarshal(data, &graph); err != nil {
+		t.Fatalf("failed to unmarshal json: %v", err)
+	}
+	if graph.Database != "shop" {
+		t.Errorf("expected database 'shop', got %q", graph.Database)
+	}
+	if len(graph.Edges) != 8 {
+		t.Errorf("expected 8 edges, got %d", len(graph.Edges))
+	}
+
+	nodes := make(map[string]dependencyNodeJSON)
+	for i, node := range graph.Nodes {
+		if i > 0 && graph.Nodes[i-1].ID >= node.ID {
+			t.Errorf("expected nodes ordered by id, got %q before %q", graph.Nodes[i-1].ID, node.ID)
+		}
+		nodes[node.ID] = node
+	}
+	column, ok := nodes["column:public.users.status"]
+	if !ok || column.Type != dbo.ObjectTypeColumn || column.Parent != "users" || column.Name != "status" {
+		t.Errorf("expected column node with parent table, got %+v", column)
+	}
+	for _, edge := range graph.Edges {
+		if _, ok := nodes[edge.From]; !ok {
+			t.Errorf("edge source %q has no node", edge.From)
+		}
+		if _, ok := nodes[edge.To]; !ok {
+			t.Errorf("edge target %q has no node", edge.To)
+		}
+	}
+
+	t.Run("empty database", func(t *testing.T) {
+		data, err := GenerateDependencyGraphJSON(dbo.NewDatabase("empty", nil))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !strings.Contains(string(data), "\"nodes\": []") || !strings.Contains(string(data), "\"edges\": []") {
+			t.Errorf("expected empty node and edge lists, got %s", data)
+		}
+	})
+}
+
+func TestGenerateDependencyMermaid(t *testing.T) {
+	result := GenerateDependencyMermaid(newDependencyTestDatabase())
+
+	for _, expected := range []string{
+		"flowchart LR\n",
+		"([\"public.recent_users\"])",
+		"[(\"public.orders_id_seq\")]",
+		"{{\"public.touch\"}}",
+		">\"public.users.users_touch\"]",
+		"[\"public.users\"]",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected %q in:\n%s", expected, result)
+		}
+	}
+
+	// Column references are folded into their table with the columns on the label
+	if !strings.Contains(result, "-->|\"id, status\"|") {
+		t.Errorf("expected folded column edge, got:\n%s", result)
+	}
+	if strings.Contains(result, "public.users.id\"") {
+		t.Errorf("expected no column nodes, got:\n%s", result)
+	}
+	if !strings.Contains(result, " -.-> ") {
+		t.Errorf("expected dotted inferred edge, got:\n%s", result)
+	}
+	if GenerateDependencyMermaid(newDependencyTestDatabase()) != result {
+		t.Error("expected deterministic output")
+	}
+}
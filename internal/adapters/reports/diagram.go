@@ -0,0 +1,237 @@
+package reports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// DiagramOptions selects the part of the database rendered by the diagram writers
+type DiagramOptions struct {
+	// PerSchema writes one diagram per schema instead of a single diagram
+	PerSchema bool
+	// Focus limits the diagram to the neighbourhood of a table, given as "schema.table"
+	// or as a bare table name when it is unique across schemas
+	Focus string
+	// Depth is the number of foreign key hops, in either direction, rendered around
+	// the focus table
+	Depth int
+}
+
+// diagramSubset is the set of tables rendered by a single diagram. Tables outside the
+// subset that share a foreign key with it are drawn as stubs so that cut-off edges
+// stay visible.
+type diagramSubset struct {
+	// name distinguishes the diagram when several are written, empty for a single one
+	name     string
+	tables   []*dbo.Table
+	included map[*dbo.Table]bool
+	edges    []diagramEdge
+	stubs    []diagramStub
+}
+
+// diagramEdge is a foreign key drawn in a diagram
+type diagramEdge struct {
+	table *dbo.Table
+	fk    *dbo.ForeignKey
+}
+
+// diagramStub is a table that is referenced by the diagram but not rendered in it
+type diagramStub struct {
+	schemaName string
+	tableName  string
+	// context is a schema of the database the stub belongs to, used to name it
+	context *dbo.Schema
+}
+
+// label returns the qualified display name of the stub
+func (s diagramStub) label() string {
+	if s.schemaName == "" {
+		return s.tableName
+	}
+	return s.schemaName + "." + s.tableName
+}
+
+// newDiagramSubset builds a subset from the given tables, collecting every foreign key
+// with at least one end inside the subset and a stub for each end outside of it
+func newDiagramSubset(db *dbo.Database, name string, tables []*dbo.Table) *diagramSubset {
+	subset := &diagramSubset{name: name, tables: tables, included: make(map[*dbo.Table]bool, len(tables))}
+	for _, table := range tables {
+		subset.included[table] = true
+	}
+
+	seenStubs := make(map[string]bool)
+	addStub := func(context *dbo.Schema, schemaName, tableName string) {
+		key := schemaName + "." + tableName
+		if !seenStubs[key] {
+			seenStubs[key] = true
+			subset.stubs = append(subset.stubs, diagramStub{schemaName: schemaName, tableName: tableName, context: context})
+		}
+	}
+
+	candidates := tables
+	if db != nil {
+		candidates = nil
+		for _, schema := range sortedSchemas(db) {
+			candidates = append(candidates, sortedTables(schema)...)
+		}
+	}
+	for _, table := range candidates {
+		for _, fk := range table.ForeignKeys() {
+			target := resolveReferencedTable(db, fk)
+			sourceIn := subset.included[table]
+			targetIn := target != nil && subset.included[target]
+			switch {
+			case sourceIn && !targetIn:
+				addStub(table.Schema(), referencedSchemaName(fk), fk.ReferencedTable())
+			case !sourceIn && targetIn:
+				addStub(table.Schema(), schemaNameOf(table.Schema()), table.Name())
+			case !sourceIn && !targetIn:
+				continue
+			}
+			subset.edges = append(subset.edges, diagramEdge{table: table, fk: fk})
+		}
+	}
+
+	sort.SliceStable(subset.stubs, func(i, j int) bool {
+		if subset.stubs[i].schemaName != subset.stubs[j].schemaName {
+			return subset.stubs[i].schemaName < subset.stubs[j].schemaName
+		}
+		return subset.stubs[i].tableName < subset.stubs[j].tableName
+	})
+	return subset
+}
+
+// diagramSubsets splits a database into the diagrams selected by the options
+func diagramSubsets(db *dbo.Database, opts DiagramOptions) ([]*diagramSubset, error) {
+	var tables []*dbo.Table
+	if opts.Focus != "" {
+		focused, err := focusTables(db, opts.Focus, opts.Depth)
+		if err != nil {
+			return nil, err
+		}
+		tables = focused
+	} else {
+		for _, schema := range sortedSchemas(db) {
+			tables = append(tables, sortedTables(schema)...)
+		}
+	}
+
+	if !opts.PerSchema {
+		return []*diagramSubset{newDiagramSubset(db, "", tables)}, nil
+	}
+
+	var subsets []*diagramSubset
+	for _, schema := range sortedSchemas(db) {
+		var schemaTables []*dbo.Table
+		for _, table := range tables {
+			if table.Schema() == schema {
+				schemaTables = append(schemaTables, table)
+			}
+		}
+		if len(schemaTables) > 0 {
+			subsets = append(subsets, newDiagramSubset(db, schema.Name(), schemaTables))
+		}
+	}
+	return subsets, nil
+}
+
+// focusTables returns the tables within depth foreign key hops of the focus table,
+// following references in both directions, ordered by schema and name
+func focusTables(db *dbo.Database, focus string, depth int) ([]*dbo.Table, error) {
+	if depth < 0 {
+		return nil, fmt.Errorf("focus depth must not be negative, got %d", depth)
+	}
+	start, err := findFocusTable(db, focus)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbours := make(map[*dbo.Table][]*dbo.Table)
+	for _, schema := range sortedSchemas(db) {
+		for _, table := range sortedTables(schema) {
+			for _, fk := range table.ForeignKeys() {
+				target := resolveReferencedTable(db, fk)
+				if target == nil || target == table {
+					continue
+				}
+				neighbours[table] = append(neighbours[table], target)
+				neighbours[target] = append(neighbours[target], table)
+			}
+		}
+	}
+
+	visited := map[*dbo.Table]bool{start: true}
+	frontier := []*dbo.Table{start}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []*dbo.Table
+		for _, table := range frontier {
+			for _, neighbour := range neighbours[table] {
+				if !visited[neighbour] {
+					visited[neighbour] = true
+					next = append(next, neighbour)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	var tables []*dbo.Table
+	for _, schema := range sortedSchemas(db) {
+		for _, table := range sortedTables(schema) {
+			if visited[table] {
+				tables = append(tables, table)
+			}
+		}
+	}
+	return tables, nil
+}
+
+// findFocusTable resolves a "schema.table" or bare table name to a mapped table
+func findFocusTable(db *dbo.Database, focus string) (*dbo.Table, error) {
+	if schemaName, tableName, ok := strings.Cut(focus, "."); ok {
+		if schema, exists := db.Schemas()[schemaName]; exists {
+			if table, exists := schema.Tables()[tableName]; exists {
+				return table, nil
+			}
+		}
+		return nil, fmt.Errorf("focus table %q not found", focus)
+	}
+
+	var matches []*dbo.Table
+	for _, schema := range sortedSchemas(db) {
+		if table, exists := schema.Tables()[focus]; exists {
+			matches = append(matches, table)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("focus table %q not found", focus)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("focus table %q exists in several schemas, qualify it as schema.table", focus)
+	}
+}
+
+// writeDiagramFiles renders each subset and writes it to disk. A single unnamed subset
+// is written to filePath; named subsets are written next to it as
+// "<path without extension>_<name><extension>".
+func writeDiagramFiles(filePath string, subsets []*diagramSubset, render func(*diagramSubset) string) error {
+	ext := filepath.Ext(filePath)
+	base := strings.TrimSuffix(filePath, ext)
+	for _, subset := range subsets {
+		path := filePath
+		if subset.name != "" {
+			path = base + "_" + strings.ReplaceAll(subset.name, " ", "_") + ext
+		}
+		if err := os.WriteFile(path, []byte(render(subset)), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// newNeighbourhoodTestDatabase builds a chain of tables across two schemas:
+// audit.events -> sales.order_items -> sales.orders -> sales.customers -> sales.regions
+func newNeighbourhoodTestDatabase() *dbo.Database {
+	db := dbo.NewDatabase("shop", nil)
+	sales := dbo.NewSchema("sales", "owner", nil)
+	db.AddSchema(sales)
+	audit := dbo.NewSchema("audit", "owner", nil)
+	db.AddSchema(audit)
+
+	addTable := func(schema *dbo.Schema, name, references string) {
+		table := dbo.NewTable(name, nil)
+		id := dbo.NewColumn("id", "integer", false)
+		id.SetOrdinalPosition(1)
+		table.AddColumn(id)
+		table.SetPrimaryKey(dbo.NewPrimaryKey(name+"_pkey", table, []*dbo.Column{id}))
+		if references != "" {
+			refSchema, refTable, _ := strings.Cut(references, ".")
+			col := dbo.NewColumn(refTable+"_id", "integer", false)
+			col.SetOrdinalPosition(2)
+			table.AddColumn(col)
+			fk := dbo.NewForeignKey(name+"_"+refTable+"_fk", refTable)
+			fk.SetReferencedSchema(refSchema)
+			fk.AddColumn(col)
+			table.AddForeignKey(fk)
+		}
+		schema.AddTable(table)
+	}
+	addTable(sales, "regions", "")
+	addTable(sales, "customers", "sales.regions")
+	addTable(sales, "orders", "sales.customers")
+	addTable(sales, "order_items", "sales.orders")
+	addTable(audit, "events", "sales.order_items")
+	return db
+}
+
+func TestFocusTables(t *testing.T) {
+	db := newNeighbourhoodTestDatabase()
+
+	tests := []struct {
+		name     string
+		focus    string
+		depth    int
+		expected []string
+	}{
+		{"depth zero is the table alone", "sales.orders", 0, []string{"orders"}},
+		{"one hop in both directions", "sales.orders", 1, []string{"customers", "order_items", "orders"}},
+		{"two hops cross schemas", "sales.orders", 2, []string{"events", "customers", "order_items", "orders", "regions"}},
+		{"bare name resolves when unique", "customers", 1, []string{"customers", "orders", "regions"}},
+		{"depth beyond the graph stops", "audit.events", 10, []string{"events", "customers", "order_items", "orders", "regions"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := focusTables(db, tt.focus, tt.depth)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			var names []string
+			for _, table := range tables {
+				names = append(names, table.Name())
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		ambiguous := newNeighbourhoodTestDatabase()
+		ambiguous.Schemas()["audit"].AddTable(dbo.NewTable("orders", nil))
+
+		cases := []struct {
+			db    *dbo.Database
+			focus string
+			depth int
+			want  string
+		}{
+			{db, "sales.missing", 1, "not found"},
+			{db, "missing", 1, "not found"},
+			{db, "sales.orders", -1, "must not be negative"},
+			{ambiguous, "orders", 1, "several schemas"},
+		}
+		for _, c := range cases {
+			_, err := focusTables(c.db, c.focus, c.depth)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("focus %q depth %d: expected error containing %q, got %v", c.focus, c.depth, c.want, err)
+			}
+		}
+	})
+}
+
+func TestNewDiagramSubset(t *testing.T) {
+	db := newNeighbourhoodTestDatabase()
+	tables, err := focusTables(db, "sales.orders", 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	subset := newDiagramSubset(db, "", tables)
+
+	var stubs []string
+	for _, stub := range subset.stubs {
+		stubs = append(stubs, stub.label())
+	}
+	if strings.Join(stubs, ",") != "audit.events,sales.regions" {
+		t.Errorf("expected stubs at both cut-off ends, got %v", stubs)
+	}
+
+	var edges []string
+	for _, edge := range subset.edges {
+		edges = append(edges, edge.fk.Name())
+	}
+	expected := "events_order_items_fk,customers_regions_fk,order_items_orders_fk,orders_customers_fk"
+	if strings.Join(edges, ",") != expected {
+		t.Errorf("expected edges %s, got %v", expected, edges)
+	}
+}
+
+func TestDiagramSubsets(t *testing.T) {
+	db := newNeighbourhoodTestDatabase()
+
+	t.Run("single diagram by default", func(t *testing.T) {
+		subsets, err := diagramSubsets(db, DiagramOptions{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(subsets) != 1 || subsets[0].name != "" || len(subsets[0].tables) != 5 || len(subsets[0].stubs) != 0 {
+			t.Errorf("expected one unnamed diagram of every table without stubs, got %+v", subsets)
+		}
+	})
+
+	t.Run("one diagram per schema", func(t *testing.T) {
+		subsets, err := diagramSubsets(db, DiagramOptions{PerSchema: true})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(subsets) != 2 || subsets[0].name != "audit" || subsets[1].name != "sales" {
+			t.Fatalf("expected audit and sales diagrams, got %+v", subsets)
+		}
+		if len(subsets[0].stubs) != 1 || subsets[0].stubs[0].label() != "sales.order_items" {
+			t.Errorf("expected cross-schema reference as stub, got %+v", subsets[0].stubs)
+		}
+		if len(subsets[1].stubs) != 1 || subsets[1].stubs[0].label() != "audit.events" {
+			t.Errorf("expected incoming cross-schema reference as stub, got %+v", subsets[1].stubs)
+		}
+	})
+
+	t.Run("focus error is returned", func(t *testing.T) {
+		if _, err := diagramSubsets(db, DiagramOptions{Focus: "nope"}); err == nil {
+			t.Error("expected error for unknown focus table")
+		}
+	})
+}
+
+func TestDiagramWriters_Options(t *testing.T) {
+	t.Run("per-schema files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writer := &MermaidReportWriter{Options: DiagramOptions{PerSchema: true}}
+
+		if err := writer.WriteInventoryReport(filepath.Join(tmpDir, "shop.mmd"), newNeighbourhoodTestDatabase()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, "shop_audit.mmd"))
+		if err != nil {
+			t.Fatalf("expected audit diagram: %v", err)
+		}
+		if !strings.Contains(string(content), "    order_items[\"sales.order_items (stub)\"]\n") {
+			t.Errorf("expected stub entity, got %s", content)
+		}
+		if !strings.Contains(string(content), "events }o--|| order_items") {
+			t.Errorf("expected relationship to stub, got %s", content)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "shop_sales.mmd")); err != nil {
+			t.Errorf("expected sales diagram: %v", err)
+		}
+	})
+
+	t.Run("focused plantuml diagram", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "shop.puml")
+		writer := &PlantUMLReportWriter{Options: DiagramOptions{Focus: "sales.orders", Depth: 1}}
+
+		if err := writer.WriteInventoryReport(filePath, newNeighbourhoodTestDatabase()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		result := string(content)
+		for _, expected := range []string{
+			"entity \"orders\" as sales_orders {",
+			"entity \"sales.regions\" as sales_regions <<stub>>",
+			"entity \"audit.events\" as audit_events <<stub>>",
+			"sales_customers }o--|| sales_regions : customers_regions_fk",
+		} {
+			if !strings.Contains(result, expected) {
+				t.Errorf("expected %q in %s", expected, result)
+			}
+		}
+		if strings.Contains(result, "entity \"regions\"") {
+			t.Errorf("expected regions to be a stub only, got %s", result)
+		}
+	})
+
+	t.Run("unknown focus table", func(t *testing.T) {
+		writer := &MermaidReportWriter{Options: DiagramOptions{Focus: "sales.nope"}}
+		if err := writer.WriteInventoryReport(filepath.Join(t.TempDir(), "shop.mmd"), newNeighbourhoodTestDatabase()); err == nil {
+			t.Error("expected error for unknown focus table")
+		}
+	})
+}
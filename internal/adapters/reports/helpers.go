@@ -0,0 +1,306 @@
+package reports
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// sortedSchemas returns the schemas of a database ordered by name
+func sortedSchemas(db *dbo.Database) []*dbo.Schema {
+	schemas := make([]*dbo.Schema, 0, len(db.Schemas()))
+	for _, s := range db.Schemas() {
+		schemas = append(schemas, s)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Name() < schemas[j].Name()
+	})
+	return schemas
+}
+
+// sortedTables returns the tables of a schema ordered by name
+func sortedTables(schema *dbo.Schema) []*dbo.Table {
+	tables := make([]*dbo.Table, 0, len(schema.Tables()))
+	for _, t := range schema.Tables() {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name() < tables[j].Name()
+	})
+	return tables
+}
+
+// sortedViews returns the views of a schema ordered by name
+func sortedViews(schema *dbo.Schema) []*dbo.View {
+	views := make([]*dbo.View, 0, len(schema.Views()))
+	for _, v := range schema.Views() {
+		views = append(views, v)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name() < views[j].Name()
+	})
+	return views
+}
+
+// schemaNameOf returns the name of a schema, or an empty string for nil
+func schemaNameOf(schema *dbo.Schema) string {
+	if schema == nil {
+		return ""
+	}
+	return schema.Name()
+}
+
+// sortedSequences returns the sequences of a schema ordered by name
+func sortedSequences(schema *dbo.Schema) []*dbo.Sequence {
+	sequences := make([]*dbo.Sequence, 0, len(schema.Sequences()))
+	for _, s := range schema.Sequences() {
+		sequences = append(sequences, s)
+	}
+	sort.Slice(sequences, func(i, j int) bool {
+		return sequences[i].Name() < sequences[j].Name()
+	})
+	return sequences
+}
+
+// sortedFunctions returns the functions of a schema ordered by name
+func sortedFunctions(schema *dbo.Schema) []*dbo.Function {
+	functions := make([]*dbo.Function, 0, len(schema.Functions()))
+	for _, f := range schema.Functions() {
+		functions = append(functions, f)
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		return functions[i].Name() < functions[j].Name()
+	})
+	return functions
+}
+
+// sortedProcedures returns the procedures of a schema ordered by name
+func sortedProcedures(schema *dbo.Schema) []*dbo.Procedure {
+	procedures := make([]*dbo.Procedure, 0, len(schema.Procedures()))
+	for _, p := range schema.Procedures() {
+		procedures = append(procedures, p)
+	}
+	sort.Slice(procedures, func(i, j int) bool {
+		return procedures[i].Name() < procedures[j].Name()
+	})
+	return procedures
+}
+
+// sortedColumns returns the columns of a table ordered by ordinal position.
+// Columns without a position (or sharing one) fall back to name order.
+func sortedColumns(table *dbo.Table) []*dbo.Column {
+	columns := make([]*dbo.Column, 0, len(table.Columns()))
+	for _, c := range table.Columns() {
+		columns = append(columns, c)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		if columns[i].OrdinalPosition() != columns[j].OrdinalPosition() {
+			return columns[i].OrdinalPosition() < columns[j].OrdinalPosition()
+		}
+		return columns[i].Name() < columns[j].Name()
+	})
+	return columns
+}
+
+// columnNames returns the names of the given columns in order
+func columnNames(columns []*dbo.Column) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name()
+	}
+	return names
+}
+
+// formatColumnType returns the column data type including its length or
+// precision when the adapter reported them separately from the type name
+func formatColumnType(col *dbo.Column) string {
+	dataType := col.DataType()
+	if strings.Contains(dataType, "(") {
+		return dataType
+	}
+	if col.CharMaxLength() != nil {
+		return dataType + "(" + strconv.Itoa(*col.CharMaxLength()) + ")"
+	}
+	lower := strings.ToLower(dataType)
+	if col.NumericPrecision() != nil && (strings.HasPrefix(lower, "numeric") || strings.HasPrefix(lower, "decimal")) {
+		if col.NumericScale() != nil {
+			return dataType + "(" + strconv.Itoa(*col.NumericPrecision()) + "," + strconv.Itoa(*col.NumericScale()) + ")"
+		}
+		return dataType + "(" + strconv.Itoa(*col.NumericPrecision()) + ")"
+	}
+	return dataType
+}
+
+// referencedSchemaName returns the schema of the table referenced by a foreign key.
+// Foreign keys without an explicit referenced schema point into their own schema.
+func referencedSchemaName(fk *dbo.ForeignKey) string {
+	if fk.ReferencedSchema() != "" {
+		return fk.ReferencedSchema()
+	}
+	if fk.Table() != nil && fk.Table().Schema() != nil {
+		return fk.Table().Schema().Name()
+	}
+	return ""
+}
+
+// resolveReferencedTable looks up the table referenced by a foreign key, or nil
+// if it is not part of the mapped database. Without a database, only the FK's own
+// schema is searched.
+func resolveReferencedTable(db *dbo.Database, fk *dbo.ForeignKey) *dbo.Table {
+	if db == nil {
+		if fk.Table() == nil || fk.Table().Schema() == nil {
+			return nil
+		}
+		schema := fk.Table().Schema()
+		if schema.Database() != nil {
+			return resolveReferencedTable(schema.Database(), fk)
+		}
+		if schema.Name() != referencedSchemaName(fk) {
+			return nil
+		}
+		return schema.Tables()[fk.ReferencedTable()]
+	}
+	schema, ok := db.Schemas()[referencedSchemaName(fk)]
+	if !ok {
+		return nil
+	}
+	return schema.Tables()[fk.ReferencedTable()]
+}
+
+// isUniqueColumnSet reports whether the given columns are guaranteed unique
+// within the table, i.e. a primary key, unique index or unique constraint is
+// fully contained in the set
+func isUniqueColumnSet(table *dbo.Table, columns []*dbo.Column) bool {
+	if len(columns) == 0 {
+		return false
+	}
+	set := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		set[col.Name()] = true
+	}
+	covers := func(key []*dbo.Column) bool {
+		if len(key) == 0 {
+			return false
+		}
+		for _, col := range key {
+			if !set[col.Name()] {
+				return false
+			}
+		}
+		return true
+	}
+
+	if pk := table.PrimaryKey(); pk != nil && covers(pk.Columns()) {
+		return true
+	}
+	for _, idx := range table.Indexes() {
+		// A partial or expression index only constrains some rows or derived values. A
+		// unique prefix or invisible index still makes the whole values unique.
+		if idx.IsPartial() || len(idx.Expressions()) > 0 {
+			continue
+		}
+		if (idx.IsUnique() || idx.IsPrimary()) && covers(idx.Columns()) {
+			return true
+		}
+	}
+	for _, c := range table.Constraints() {
+		if c.Type() == dbo.ConstraintTypeUnique && covers(c.Columns()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUniqueColumn reports whether a single column is unique on its own
+func isUniqueColumn(table *dbo.Table, col *dbo.Column) bool {
+	return isUniqueColumnSet(table, []*dbo.Column{col})
+}
+
+// fkCardinality describes both ends of a foreign key relationship
+type fkCardinality struct {
+	// childUnique is true when each referenced row can be referenced at most once
+	childUnique bool
+	// parentOptional is true when a referencing row may have no parent (nullable FK columns)
+	parentOptional bool
+}
+
+// foreignKeyCardinality derives the relationship cardinality of a foreign key
+// from the uniqueness of its columns and their nullability
+func foreignKeyCardinality(fk *dbo.ForeignKey) fkCardinality {
+	card := fkCardinality{}
+	for _, col := range fk.Columns() {
+		if col.IsNullable() {
+			card.parentOptional = true
+		}
+	}
+	if fk.Table() != nil {
+		card.childUnique = isUniqueColumnSet(fk.Table(), fk.Columns())
+	}
+	return card
+}
+
+// checkExpressionBody strips the leading CHECK keyword that some adapters keep
+// in the constraint definition along with redundant outer parentheses,
+// e.g. "CHECK ((price > 0))" becomes "price > 0"
+func checkExpressionBody(expression string) string {
+	expr := strings.TrimSpace(expression)
+	if len(expr) >= 5 && strings.EqualFold(expr[:5], "CHECK") {
+		expr = strings.TrimSpace(expr[5:])
+	}
+	for len(expr) >= 2 && expr[0] == '(' && expr[len(expr)-1] == ')' && isBalancedGroup(expr[1:len(expr)-1]) {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// isBalancedGroup reports whether every parenthesis in s is matched within s
+func isBalancedGroup(s string) bool {
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// sortedEnums returns the enum types of a schema ordered by name
+func sortedEnums(schema *dbo.Schema) []*dbo.Enum {
+	enums := make([]*dbo.Enum, 0, len(schema.Enums()))
+	for _, e := range schema.Enums() {
+		enums = append(enums, e)
+	}
+	sort.Slice(enums, func(i, j int) bool {
+		return enums[i].Name() < enums[j].Name()
+	})
+	return enums
+}
+
+// columnEnum returns the enum type a column is declared with, or nil. Adapters report
+// enum columns by their type name, qualified with the schema when they know it.
+func columnEnum(home *dbo.Schema, col *dbo.Column) *dbo.Enum {
+	if home == nil {
+		return nil
+	}
+	schemaName, name, qualified := strings.Cut(col.DataType(), ".")
+	if !qualified {
+		return home.Enums()[col.DataType()]
+	}
+	if db := home.Database(); db != nil {
+		if schema, ok := db.Schemas()[schemaName]; ok {
+			return schema.Enums()[name]
+		}
+	}
+	if schemaName == home.Name() {
+		return home.Enums()[name]
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package reports
+
+import (
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+func TestSortedColumns(t *testing.T) {
+	table := dbo.NewTable("users", nil)
+	for i, name := range []string{"id", "email", "name", "created_at"} {
+		col := dbo.NewColumn(name, "text", true)
+		col.SetOrdinalPosition(i + 1)
+		table.AddColumn(col)
+	}
+
+	for run := 0; run < 10; run++ {
+		result := columnNames(sortedColumns(table))
+		expected := []string{"id", "email", "name", "created_at"}
+		for i := range expected {
+			if result[i] != expected[i] {
+				t.Fatalf("expected %v, got %v", expected, result)
+			}
+		}
+	}
+}
+
+func TestFormatColumnType(t *testing.T) {
+	varchar := dbo.NewColumn("email", "character varying", false)
+	varchar.SetCharMaxLength(255)
+
+	numeric := dbo.NewColumn("price", "numeric", false)
+	numeric.SetNumericPrecision(10)
+	numeric.SetNumericScale(2)
+
+	integer := dbo.NewColumn("id", "integer", false)
+	integer.SetNumericPrecision(32)
+	integer.SetNumericScale(0)
+
+	explicit := dbo.NewColumn("code", "varchar(10)", false)
+	explicit.SetCharMaxLength(10)
+
+	tests := []struct {
+		col      *dbo.Column
+		expected string
+	}{
+		{varchar, "character varying(255)"},
+		{numeric, "numeric(10,2)"},
+		{integer, "integer"},
+		{explicit, "varchar(10)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.col.Name(), func(t *testing.T) {
+			result := formatColumnType(tt.col)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsUniqueColumnSet(t *testing.T) {
+	table := dbo.NewTable("memberships", nil)
+	userID := dbo.NewColumn("user_id", "integer", false)
+	groupID := dbo.NewColumn("group_id", "integer", false)
+	badge := dbo.NewColumn("badge", "text", true)
+	table.AddColumn(userID)
+	table.AddColumn(groupID)
+	table.AddColumn(badge)
+	table.SetPrimaryKey(dbo.NewPrimaryKey("memberships_pkey", table, []*dbo.Column{userID, groupID}))
+	unique := dbo.NewConstraint("memberships_badge_key", dbo.ConstraintTypeUnique)
+	unique.AddColumn(badge)
+	table.AddConstraint(unique)
+
+	if isUniqueColumnSet(table, []*dbo.Column{userID}) {
+		t.Error("expected part of a composite key not to be unique")
+	}
+	if !isUniqueColumnSet(table, []*dbo.Column{userID, groupID}) {
+		t.Error("expected the full primary key to be unique")
+	}
+	if !isUniqueColumnSet(table, []*dbo.Column{badge}) {
+		t.Error("expected column with unique constraint to be unique")
+	}
+	if isUniqueColumnSet(table, nil) {
+		t.Error("expected empty column set not to be unique")
+	}
+
+	partial := dbo.NewIndex("memberships_group_active_key", table, []*dbo.Column{groupID}, true)
+	partial.SetPredicate("badge IS NOT NULL")
+	table.AddIndex(partial)
+	if isUniqueColumnSet(table, []*dbo.Column{groupID}) {
+		t.Error("expected a partial unique index not to make the column unique")
+	}
+}
+
+func TestForeignKeyCardinality(t *testing.T) {
+	db := newDiagramTestDatabase()
+	orders := db.Schemas()["public"].Tables()["orders"]
+	profiles := db.Schemas()["public"].Tables()["profiles"]
+
+	mandatory := foreignKeyCardinality(orders.ForeignKeys()[0])
+	if mandatory.childUnique || mandatory.parentOptional {
+		t.Errorf("expected mandatory many-to-one, got %+v", mandatory)
+	}
+
+	optional := foreignKeyCardinality(orders.ForeignKeys()[1])
+	if optional.childUnique || !optional.parentOptional {
+		t.Errorf("expected optional many-to-one, got %+v", optional)
+	}
+
+	oneToOne := foreignKeyCardinality(profiles.ForeignKeys()[0])
+	if !oneToOne.childUnique || oneToOne.parentOptional {
+		t.Errorf("expected mandatory one-to-one, got %+v", oneToOne)
+	}
+}
+
+func TestResolveReferencedTable(t *testing.T) {
+	db := newDiagramTestDatabase()
+	profiles := db.Schemas()["public"].Tables()["profiles"]
+
+	// profiles_user_fk has no explicit referenced schema and resolves within its own schema
+	refTable := resolveReferencedTable(db, profiles.ForeignKeys()[0])
+	if refTable == nil || refTable.Name() != "users" {
+		t.Errorf("expected users table, got %v", refTable)
+	}
+
+	missing := dbo.NewForeignKey("fk_missing", "nowhere")
+	profiles.AddForeignKey(missing)
+	if resolveReferencedTable(db, missing) != nil {
+		t.Error("expected nil for a table outside the mapped database")
+	}
+}
+
+func TestCheckExpressionBody(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"CHECK ((price > 0))", "price > 0"},
+		{"CHECK ((a > 0) AND (b > 0))", "(a > 0) AND (b > 0)"},
+		{"(`qty` >= 0)", "`qty` >= 0"},
+		{"price > 0", "price > 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := checkExpressionBody(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
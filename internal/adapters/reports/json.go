@@ -3,11 +3,21 @@ package reports
 
 import (
 	"encoding/json"
+	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 
 	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+	"github.com/jimbot9k/norman/internal/version"
 )
 
+// JSONReportFormatVersion is the version of the JSON report format. It is bumped
+// whenever a field is added, removed or changes meaning, together with the
+// published JSON Schema returned by JSONReportSchema.
+const JSONReportFormatVersion = "1.3"
+
 // JSONReportWriter generates JSON format inventory reports.
 // It implements the ReportWriter interface for JSON output.
 type JSONReportWriter struct{}
@@ -28,7 +38,8 @@ func (w *JSONReportWriter) GetReportName() string {
 }
 
 // WriteInventoryReport writes the database inventory to a JSON file.
-// The output is formatted with indentation for readability.
+// The inventory is wrapped in a versioned envelope and formatted with
+// indentation for readability.
 func (w *JSONReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
 	data, err := marshalDatabaseIndent(db, "", "  ")
 	if err != nil {
@@ -41,14 +52,20 @@ func (w *JSONReportWriter) WriteInventoryReport(filePath string, db *dbo.Databas
 
 // columnJSON represents a database column in JSON format.
 type columnJSON struct {
-	Name             string  `json:"name"`
-	DataType         string  `json:"dataType"`
-	Nullable         bool    `json:"nullable"`
-	DefaultValue     *string `json:"defaultValue,omitempty"`
-	OrdinalPosition  int     `json:"ordinalPosition"`
-	CharMaxLength    *int    `json:"charMaxLength,omitempty"`
-	NumericPrecision *int    `json:"numericPrecision,omitempty"`
-	NumericScale     *int    `json:"numericScale,omitempty"`
+	Name                 string         `json:"name"`
+	DataType             string         `json:"dataType"`
+	Nullable             bool           `json:"nullable"`
+	DefaultValue         *string        `json:"defaultValue,omitempty"`
+	OrdinalPosition      int            `json:"ordinalPosition"`
+	CharMaxLength        *int           `json:"charMaxLength,omitempty"`
+	NumericPrecision     *int           `json:"numericPrecision,omitempty"`
+	NumericScale         *int           `json:"numericScale,omitempty"`
+	Charset              string         `json:"charset,omitempty"`
+	Collation            string         `json:"collation,omitempty"`
+	Generation           dbo.Generation `json:"generation,omitempty"`
+	GenerationExpression string         `json:"generationExpression,omitempty"`
+	Invisible            bool           `json:"invisible,omitempty"`
+	Comment              string         `json:"comment,omitempty"`
 }
 
 // constraintJSON represents a table constraint in JSON format.
@@ -79,20 +96,48 @@ type functionParameterJSON struct {
 
 // functionJSON represents a database function in JSON format.
 type functionJSON struct {
-	Name       string                  `json:"name"`
-	Definition string                  `json:"definition"`
-	ReturnType string                  `json:"returnType"`
-	Parameters []functionParameterJSON `json:"parameters,omitempty"`
-	Language   string                  `json:"language"`
+	Name          string                  `json:"name"`
+	Definition    string                  `json:"definition"`
+	ReturnType    string                  `json:"returnType"`
+	Parameters    []functionParameterJSON `json:"parameters,omitempty"`
+	Language      string                  `json:"language"`
+	SecurityType  dbo.SecurityType        `json:"securityType,omitempty"`
+	Definer       string                  `json:"definer,omitempty"`
+	Deterministic bool                    `json:"deterministic,omitempty"`
+	SQLDataAccess string                  `json:"sqlDataAccess,omitempty"`
+	SQLMode       string                  `json:"sqlMode,omitempty"`
+}
+
+// grantJSON represents a privilege held by a role in JSON format.
+type grantJSON struct {
+	Name        string `json:"name"`
+	Definition  string `json:"definition"`
+	Grantee     string `json:"grantee,omitempty"`
+	Privilege   string `json:"privilege,omitempty"`
+	Schema      string `json:"schema,omitempty"`
+	Table       string `json:"table,omitempty"`
+	Column      string `json:"column,omitempty"`
+	IsGrantable bool   `json:"isGrantable,omitempty"`
 }
 
 // indexJSON represents a database index in JSON format.
 type indexJSON struct {
-	Name      string        `json:"name"`
-	Columns   []string      `json:"columns"`
-	IsUnique  bool          `json:"isUnique"`
-	IsPrimary bool          `json:"isPrimary"`
-	IndexType dbo.IndexType `json:"indexType"`
+	Name          string        `json:"name"`
+	Columns       []string      `json:"columns"`
+	IsUnique      bool          `json:"isUnique"`
+	IsPrimary     bool          `json:"isPrimary"`
+	IndexType     dbo.IndexType `json:"indexType"`
+	Predicate     string        `json:"predicate,omitempty"`
+	Expressions   []string      `json:"expressions,omitempty"`
+	PrefixLengths []int         `json:"prefixLengths,omitempty"`
+	Invisible     bool          `json:"invisible,omitempty"`
+}
+
+// periodJSON represents a table period in JSON format.
+type periodJSON struct {
+	Name        string `json:"name"`
+	StartColumn string `json:"startColumn"`
+	EndColumn   string `json:"endColumn"`
 }
 
 // primaryKeyJSON represents a primary key in JSON format.
@@ -103,10 +148,26 @@ type primaryKeyJSON struct {
 
 // procedureJSON represents a stored procedure in JSON format.
 type procedureJSON struct {
-	Name       string                  `json:"name"`
-	Definition string                  `json:"definition"`
-	Parameters []functionParameterJSON `json:"parameters,omitempty"`
-	Language   string                  `json:"language"`
+	Name          string                  `json:"name"`
+	Definition    string                  `json:"definition"`
+	Parameters    []functionParameterJSON `json:"parameters,omitempty"`
+	Language      string                  `json:"language"`
+	SecurityType  dbo.SecurityType        `json:"securityType,omitempty"`
+	Definer       string                  `json:"definer,omitempty"`
+	Deterministic bool                    `json:"deterministic,omitempty"`
+	SQLDataAccess string                  `json:"sqlDataAccess,omitempty"`
+	SQLMode       string                  `json:"sqlMode,omitempty"`
+}
+
+// roleJSON represents a role or account in JSON format.
+type roleJSON struct {
+	Name          string   `json:"name"`
+	IsSuperuser   bool     `json:"isSuperuser"`
+	CanLogin      bool     `json:"canLogin"`
+	CanCreateDB   bool     `json:"canCreateDB"`
+	CanCreateRole bool     `json:"canCreateRole"`
+	MemberOf      []string `json:"memberOf,omitempty"`
+	DefaultRoles  []string `json:"defaultRoles,omitempty"`
 }
 
 // sequenceJSON represents a database sequence in JSON format.
@@ -139,13 +200,20 @@ type viewJSON struct {
 
 // tableJSON represents a database table in JSON format.
 type tableJSON struct {
-	Name        string           `json:"name"`
-	Columns     []columnJSON     `json:"columns"`
-	PrimaryKey  *primaryKeyJSON  `json:"primaryKey,omitempty"`
-	ForeignKeys []foreignKeyJSON `json:"foreignKeys,omitempty"`
-	Indexes     []indexJSON      `json:"indexes,omitempty"`
-	Constraints []constraintJSON `json:"constraints,omitempty"`
-	Triggers    []triggerJSON    `json:"triggers,omitempty"`
+	Name            string           `json:"name"`
+	Columns         []columnJSON     `json:"columns"`
+	PrimaryKey      *primaryKeyJSON  `json:"primaryKey,omitempty"`
+	ForeignKeys     []foreignKeyJSON `json:"foreignKeys,omitempty"`
+	Indexes         []indexJSON      `json:"indexes,omitempty"`
+	Constraints     []constraintJSON `json:"constraints,omitempty"`
+	Triggers        []triggerJSON    `json:"triggers,omitempty"`
+	Periods         []periodJSON     `json:"periods,omitempty"`
+	SystemVersioned bool             `json:"systemVersioned,omitempty"`
+	Engine          string           `json:"engine,omitempty"`
+	RowFormat       string           `json:"rowFormat,omitempty"`
+	Charset         string           `json:"charset,omitempty"`
+	Collation       string           `json:"collation,omitempty"`
+	Comment         string           `json:"comment,omitempty"`
 }
 
 // schemaJSON represents a database schema in JSON format.
@@ -159,10 +227,27 @@ type schemaJSON struct {
 	Sequences  []sequenceJSON  `json:"sequences"`
 }
 
+// generatorJSON identifies the program that produced a report.
+type generatorJSON struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// reportJSON is the versioned envelope around the database inventory.
+type reportJSON struct {
+	FormatVersion string        `json:"formatVersion"`
+	Generator     generatorJSON `json:"generator"`
+	Database      databaseJSON  `json:"database"`
+}
+
 // databaseJSON represents a database in JSON format.
 type databaseJSON struct {
 	Name    string       `json:"name"`
+	Engine  string       `json:"engine,omitempty"`
 	Schemas []schemaJSON `json:"schemas"`
+	Roles   []roleJSON   `json:"roles,omitempty"`
+	Grants  []grantJSON  `json:"grants,omitempty"`
 }
 
 // Conversion functions from domain objects to JSON structs
@@ -170,14 +255,20 @@ type databaseJSON struct {
 // columnToJSON converts a Column domain object to its JSON representation.
 func columnToJSON(c *dbo.Column) columnJSON {
 	return columnJSON{
-		Name:             c.Name(),
-		DataType:         c.DataType(),
-		Nullable:         c.IsNullable(),
-		DefaultValue:     c.DefaultValue(),
-		OrdinalPosition:  c.OrdinalPosition(),
-		CharMaxLength:    c.CharMaxLength(),
-		NumericPrecision: c.NumericPrecision(),
-		NumericScale:     c.NumericScale(),
+		Name:                 c.Name(),
+		DataType:             c.DataType(),
+		Nullable:             c.IsNullable(),
+		DefaultValue:         c.DefaultValue(),
+		OrdinalPosition:      c.OrdinalPosition(),
+		CharMaxLength:        c.CharMaxLength(),
+		NumericPrecision:     c.NumericPrecision(),
+		NumericScale:         c.NumericScale(),
+		Charset:              c.Charset(),
+		Collation:            c.Collation(),
+		Generation:           c.Generation(),
+		GenerationExpression: c.GenerationExpression(),
+		Invisible:            c.IsInvisible(),
+		Comment:              c.Comment(),
 	}
 }
 
@@ -232,11 +323,30 @@ func functionToJSON(f *dbo.Function) functionJSON {
 		params[i] = functionParameterToJSON(p)
 	}
 	return functionJSON{
-		Name:       f.Name(),
-		Definition: f.Definition(),
-		ReturnType: f.ReturnType(),
-		Parameters: params,
-		Language:   f.Language(),
+		Name:          f.Name(),
+		Definition:    f.Definition(),
+		ReturnType:    f.ReturnType(),
+		Parameters:    params,
+		Language:      f.Language(),
+		SecurityType:  f.SecurityType(),
+		Definer:       f.Definer(),
+		Deterministic: f.IsDeterministic(),
+		SQLDataAccess: f.SQLDataAccess(),
+		SQLMode:       f.SQLMode(),
+	}
+}
+
+// grantToJSON converts a Grant domain object to its JSON representation.
+func grantToJSON(g *dbo.Grant) grantJSON {
+	return grantJSON{
+		Name:        g.Name(),
+		Definition:  g.Definition(),
+		Grantee:     g.Grantee(),
+		Privilege:   g.Privilege(),
+		Schema:      g.SchemaName(),
+		Table:       g.TableName(),
+		Column:      g.ColumnName(),
+		IsGrantable: g.IsGrantable(),
 	}
 }
 
@@ -247,11 +357,15 @@ func indexToJSON(i *dbo.Index) indexJSON {
 		columnNames[idx] = col.Name()
 	}
 	return indexJSON{
-		Name:      i.Name(),
-		Columns:   columnNames,
-		IsUnique:  i.IsUnique(),
-		IsPrimary: i.IsPrimary(),
-		IndexType: i.IndexType(),
+		Name:          i.Name(),
+		Columns:       columnNames,
+		IsUnique:      i.IsUnique(),
+		IsPrimary:     i.IsPrimary(),
+		IndexType:     i.IndexType(),
+		Predicate:     i.Predicate(),
+		Expressions:   i.Expressions(),
+		PrefixLengths: i.PrefixLengths(),
+		Invisible:     i.IsInvisible(),
 	}
 }
 
@@ -278,10 +392,35 @@ func procedureToJSON(p *dbo.Procedure) procedureJSON {
 		params[i] = functionParameterToJSON(param)
 	}
 	return procedureJSON{
-		Name:       p.Name(),
-		Definition: p.Definition(),
-		Parameters: params,
-		Language:   p.Language(),
+		Name:          p.Name(),
+		Definition:    p.Definition(),
+		Parameters:    params,
+		Language:      p.Language(),
+		SecurityType:  p.SecurityType(),
+		Definer:       p.Definer(),
+		Deterministic: p.IsDeterministic(),
+		SQLDataAccess: p.SQLDataAccess(),
+		SQLMode:       p.SQLMode(),
+	}
+}
+
+// roleToJSON converts a Role domain object to its JSON representation.
+func roleToJSON(r *dbo.Role) roleJSON {
+	var memberOf, defaultRoles []string
+	for _, role := range r.MemberOf() {
+		memberOf = append(memberOf, role.Name())
+	}
+	for _, role := range r.DefaultRoles() {
+		defaultRoles = append(defaultRoles, role.Name())
+	}
+	return roleJSON{
+		Name:          r.Name(),
+		IsSuperuser:   r.IsSuperuser(),
+		CanLogin:      r.CanLogin(),
+		CanCreateDB:   r.CanCreateDB(),
+		CanCreateRole: r.CanCreateRole(),
+		MemberOf:      memberOf,
+		DefaultRoles:  defaultRoles,
 	}
 }
 
@@ -354,14 +493,26 @@ func tableToJSON(t *dbo.Table) tableJSON {
 		triggers[i] = triggerToJSON(tr)
 	}
 
+	periods := make([]periodJSON, len(t.Periods()))
+	for i, p := range t.Periods() {
+		periods[i] = periodJSON{Name: p.Name(), StartColumn: p.StartColumn().Name(), EndColumn: p.EndColumn().Name()}
+	}
+
 	return tableJSON{
-		Name:        t.Name(),
-		Columns:     columns,
-		PrimaryKey:  primaryKeyToJSON(t.PrimaryKey()),
-		ForeignKeys: foreignKeys,
-		Indexes:     indexes,
-		Constraints: constraints,
-		Triggers:    triggers,
+		Name:            t.Name(),
+		Columns:         columns,
+		PrimaryKey:      primaryKeyToJSON(t.PrimaryKey()),
+		ForeignKeys:     foreignKeys,
+		Indexes:         indexes,
+		Constraints:     constraints,
+		Triggers:        triggers,
+		Periods:         periods,
+		SystemVersioned: t.IsSystemVersioned(),
+		Engine:          t.Engine(),
+		RowFormat:       t.RowFormat(),
+		Charset:         t.Charset(),
+		Collation:       t.Collation(),
+		Comment:         t.Comment(),
 	}
 }
 
@@ -409,13 +560,71 @@ func databaseToJSON(d *dbo.Database) databaseJSON {
 	for _, s := range d.Schemas() {
 		schemas = append(schemas, schemaToJSON(s))
 	}
+	var roles []roleJSON
+	for _, name := range slices.Sorted(maps.Keys(d.Roles())) {
+		roles = append(roles, roleToJSON(d.Roles()[name]))
+	}
+	var grants []grantJSON
+	for _, g := range d.Grants() {
+		grants = append(grants, grantToJSON(g))
+	}
 	return databaseJSON{
 		Name:    d.Name(),
+		Engine:  d.Engine(),
 		Schemas: schemas,
+		Roles:   roles,
+		Grants:  grants,
 	}
 }
 
-// marshalDatabaseIndent serializes a Database to indented JSON bytes.
+// reportToJSON wraps the JSON representation of a Database in the report envelope.
+func reportToJSON(d *dbo.Database) reportJSON {
+	return reportJSON{
+		FormatVersion: JSONReportFormatVersion,
+		Generator: generatorJSON{
+			Name:    "norman",
+			Version: version.Version,
+			Commit:  version.Commit,
+		},
+		Database: databaseToJSON(d),
+	}
+}
+
+// marshalDatabaseIndent serializes a Database report envelope to indented JSON bytes.
 func marshalDatabaseIndent(db *dbo.Database, prefix, indent string) ([]byte, error) {
-	return json.MarshalIndent(databaseToJSON(db), prefix, indent)
+	return json.MarshalIndent(reportToJSON(db), prefix, indent)
+}
+
+// ReadJSONReport rebuilds a fully linked Database from a JSON report. Parent pointers,
+// key and index columns, foreign key referenced columns and trigger functions are
+// restored, as are the roles accounts are members of. Documents without a formatVersion are read as the JSON of a Database
+// itself, as written before the report envelope existed. Reports of a newer major
+// format version are rejected.
+func ReadJSONReport(data []byte) (*dbo.Database, error) {
+	var envelope struct {
+		FormatVersion string          `json:"formatVersion"`
+		Database      json.RawMessage `json:"database"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+
+	content := data
+	if envelope.FormatVersion != "" {
+		major, _, _ := strings.Cut(envelope.FormatVersion, ".")
+		supported, _, _ := strings.Cut(JSONReportFormatVersion, ".")
+		if major != supported {
+			return nil, fmt.Errorf("unsupported JSON report format version %s, expected %s.x", envelope.FormatVersion, supported)
+		}
+		if len(envelope.Database) == 0 {
+			return nil, fmt.Errorf("JSON report has no database")
+		}
+		content = envelope.Database
+	}
+
+	db := &dbo.Database{}
+	if err := json.Unmarshal(content, db); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+	return db, nil
 }
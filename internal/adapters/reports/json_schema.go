@@ -0,0 +1,12 @@
+package reports
+
+import _ "embed"
+
+//go:embed schema/json-report.schema.json
+var jsonReportSchema []byte
+
+// JSONReportSchema returns the JSON Schema (draft 2020-12) describing the output of
+// JSONReportWriter for the current JSONReportFormatVersion.
+func JSONReportSchema() []byte {
+	return jsonReportSchema
+}
@@ -1,15 +1,48 @@
 package reports
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
 	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
 )
 
+// validateJSONReport validates report bytes against the published JSON Schema
+func validateJSONReport(t *testing.T, data []byte) {
+	t.Helper()
+	if err := checkJSONReportSchema(data); err != nil {
+		t.Errorf("report does not match schema: %v", err)
+	}
+}
+
+// checkJSONReportSchema compiles the published JSON Schema and validates data against it
+func checkJSONReportSchema(data []byte) error {
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(JSONReportSchema()))
+	if err != nil {
+		return err
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("json-report.schema.json", schemaDoc); err != nil {
+		return err
+	}
+	schema, err := compiler.Compile("json-report.schema.json")
+	if err != nil {
+		return err
+	}
+
+	report, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return schema.Validate(report)
+}
+
 func TestJSONReportWriter_WriteInventoryReport(t *testing.T) {
 	t.Run("writes JSON file successfully", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -37,10 +70,16 @@ func TestJSONReportWriter_WriteInventoryReport(t *testing.T) {
 			t.Errorf("expected valid JSON, got error: %v", err)
 		}
 
-		// Check content has name
-		if result["name"] != "testdb" {
-			t.Errorf("expected name 'testdb', got %v", result["name"])
+		// Check envelope and content
+		if result["formatVersion"] != JSONReportFormatVersion {
+			t.Errorf("expected formatVersion %q, got %v", JSONReportFormatVersion, result["formatVersion"])
 		}
+		database := result["database"].(map[string]interface{})
+		if database["name"] != "testdb" {
+			t.Errorf("expected name 'testdb', got %v", database["name"])
+		}
+
+		validateJSONReport(t, content)
 	})
 
 	t.Run("returns error for invalid path", func(t *testing.T) {
@@ -1185,14 +1224,161 @@ func TestMarshalDatabaseIndent(t *testing.T) {
 		}
 
 		// Verify structure
-		if result["name"] != "complex_db" {
-			t.Errorf("expected name 'complex_db', got %v", result["name"])
+		database := result["database"].(map[string]interface{})
+		if database["name"] != "complex_db" {
+			t.Errorf("expected name 'complex_db', got %v", database["name"])
 		}
 
-		schemas := result["schemas"].([]interface{})
+		schemas := database["schemas"].([]interface{})
 		if len(schemas) != 1 {
 			t.Errorf("expected 1 schema, got %d", len(schemas))
 		}
+
+		validateJSONReport(t, data)
+	})
+}
+
+func TestReportToJSON(t *testing.T) {
+	result := reportToJSON(dbo.NewDatabase("testdb", nil))
+
+	if result.FormatVersion != JSONReportFormatVersion {
+		t.Errorf("expected formatVersion %q, got %q", JSONReportFormatVersion, result.FormatVersion)
+	}
+	if result.Generator.Name != "norman" {
+		t.Errorf("expected generator name 'norman', got %q", result.Generator.Name)
+	}
+	if result.Generator.Version == "" || result.Generator.Commit == "" {
+		t.Errorf("expected generator version and commit, got %+v", result.Generator)
+	}
+	if result.Database.Name != "testdb" {
+		t.Errorf("expected database name 'testdb', got %q", result.Database.Name)
+	}
+}
+
+func TestJSONReportSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(JSONReportSchema(), &schema); err != nil {
+		t.Fatalf("expected schema to be valid JSON: %v", err)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	formatVersion := properties["formatVersion"].(map[string]interface{})
+	if formatVersion["const"] != JSONReportFormatVersion {
+		t.Errorf("expected schema to pin formatVersion %q, got %v", JSONReportFormatVersion, formatVersion["const"])
+	}
+
+	t.Run("accepts a fully populated report", func(t *testing.T) {
+		data, err := marshalDatabaseIndent(newDDLTestDatabase("PostgreSQL"), "", "  ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		validateJSONReport(t, data)
+	})
+
+	t.Run("accepts a system-versioned table", func(t *testing.T) {
+		db := newDDLTestDatabase("MariaDB")
+		table := db.Schemas()["public"].Tables()["users"]
+		start := dbo.NewColumn("row_start", "timestamp", false)
+		end := dbo.NewColumn("row_end", "timestamp", false)
+		table.AddColumn(start)
+		table.AddColumn(end)
+		table.AddPeriod(dbo.NewPeriod(dbo.PeriodSystemTime, start, end))
+		table.SetSystemVersioned(true)
+
+		data, err := marshalDatabaseIndent(db, "", "  ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		validateJSONReport(t, data)
+		if !strings.Contains(string(data), `"startColumn": "row_start"`) || !strings.Contains(string(data), `"systemVersioned": true`) {
+			t.Errorf("expected period and system versioning in the report, got %s", data)
+		}
+	})
+
+	t.Run("accepts generated columns and prefix indexes", func(t *testing.T) {
+		db := newDDLTestDatabase("MySQL")
+		table := db.Schemas()["public"].Tables()["users"]
+		initial := dbo.NewColumn("initial", "char", true)
+		initial.SetGenerated(dbo.GenerationStored, "left(`email`,1)")
+		initial.SetInvisible(true)
+		table.AddColumn(initial)
+		idx := dbo.NewIndex("users_email_prefix", table, nil, false)
+		idx.AddPrefixColumn(table.Columns()["email"], 10)
+		idx.SetInvisible(true)
+		table.AddIndex(idx)
+
+		data, err := marshalDatabaseIndent(db, "", "  ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		validateJSONReport(t, data)
+		if !strings.Contains(string(data), `"generation": "stored"`) || !strings.Contains(string(data), `"prefixLengths": [`) {
+			t.Errorf("expected generated column and prefix lengths in the report, got %s", data)
+		}
+	})
+
+	t.Run("accepts roles and grants", func(t *testing.T) {
+		db := newDDLTestDatabase("MySQL")
+		reader := dbo.NewRole("'reader'@'%'")
+		app := dbo.NewRole("'app'@'%'")
+		app.SetCanLogin(true)
+		app.AddMemberOf(reader)
+		db.AddRole(reader)
+		db.AddRole(app)
+		grant := dbo.NewGrant("SELECT", "GRANT SELECT ON `public`.`users` TO 'reader'@'%'")
+		grant.SetGrantee("'reader'@'%'")
+		grant.SetPrivilege("SELECT")
+		grant.SetObject("public", "users", "")
+		db.AddGrant(grant)
+
+		data, err := marshalDatabaseIndent(db, "", "  ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		validateJSONReport(t, data)
+
+		read, err := ReadJSONReport(data)
+		if err != nil {
+			t.Fatalf("expected the report to be read back, got %v", err)
+		}
+		roles := read.Roles()
+		if len(roles) != 2 || len(roles["'app'@'%'"].MemberOf()) != 1 || roles["'app'@'%'"].MemberOf()[0] != roles["'reader'@'%'"] {
+			t.Errorf("expected app linked to the reader role, got %v", roles)
+		}
+		if len(read.Grants()) != 1 || read.Grants()[0].Level() != dbo.GrantLevelTable {
+			t.Errorf("expected the table grant to be read back, got %v", read.Grants())
+		}
+	})
+
+	t.Run("accepts routine security context", func(t *testing.T) {
+		db := newDDLTestDatabase("MySQL")
+		fn := dbo.NewFunction("next_code", "RETURN UUID()")
+		fn.SetSecurityType(dbo.SecurityDefiner)
+		fn.SetDefiner("'app'@'%'")
+		fn.SetSQLDataAccess("NO SQL")
+		fn.SetSQLMode("STRICT_TRANS_TABLES")
+		db.Schemas()["public"].AddFunction(fn)
+
+		data, err := marshalDatabaseIndent(db, "", "  ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		validateJSONReport(t, data)
+		if !strings.Contains(string(data), `"securityType": "DEFINER"`) {
+			t.Errorf("expected the security type in the report, got %s", data)
+		}
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		data, err := marshalDatabaseIndent(dbo.NewDatabase("testdb", nil), "", "  ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		tampered := strings.Replace(string(data), `"name": "testdb"`, `"name": "testdb", "unexpected": true`, 1)
+
+		if checkJSONReportSchema([]byte(tampered)) == nil {
+			t.Error("expected schema validation to fail for unknown field")
+		}
 	})
 }
 
@@ -1273,12 +1459,15 @@ func TestIntegration_FullJSONReport(t *testing.T) {
 			t.Fatalf("invalid JSON: %v", err)
 		}
 
+		validateJSONReport(t, content)
+
 		// Validate structure
-		if result["name"] != "ecommerce" {
-			t.Errorf("expected name 'ecommerce', got %v", result["name"])
+		database := result["database"].(map[string]interface{})
+		if database["name"] != "ecommerce" {
+			t.Errorf("expected name 'ecommerce', got %v", database["name"])
 		}
 
-		schemas := result["schemas"].([]interface{})
+		schemas := database["schemas"].([]interface{})
 		if len(schemas) != 1 {
 			t.Errorf("expected 1 schema, got %d", len(schemas))
 		}
@@ -1309,3 +1498,106 @@ func TestIntegration_FullJSONReport(t *testing.T) {
 		}
 	})
 }
+
+func TestReadJSONReport(t *testing.T) {
+	db := dbo.NewDatabase("ecommerce", nil)
+	db.SetEngine("PostgreSQL")
+	schema := dbo.NewSchema("public", "admin", nil)
+	db.AddSchema(schema)
+
+	users := dbo.NewTable("users", nil)
+	userID := dbo.NewColumn("id", "serial", false)
+	users.AddColumn(userID)
+	users.SetPrimaryKey(dbo.NewPrimaryKey("users_pkey", users, []*dbo.Column{userID}))
+	schema.AddTable(users)
+
+	orders := dbo.NewTable("orders", nil)
+	orderUser := dbo.NewColumn("user_id", "integer", false)
+	orders.AddColumn(orderUser)
+	fk := dbo.NewForeignKey("fk_orders_user", "users")
+	fk.AddColumn(orderUser)
+	fk.AddReferencedColumn(userID)
+	orders.AddForeignKey(fk)
+	schema.AddTable(orders)
+
+	fn := dbo.NewFunction("touch", "BEGIN RETURN NEW; END")
+	fn.SetLanguage("plpgsql")
+	schema.AddFunction(fn)
+	trigger := dbo.NewTrigger("orders_touch", "")
+	trigger.SetFunction(fn)
+	orders.AddTrigger(trigger)
+
+	report, err := marshalDatabaseIndent(db, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal report: %v", err)
+	}
+	legacy, err := json.Marshal(db)
+	if err != nil {
+		t.Fatalf("failed to marshal database: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "report envelope", data: report},
+		{name: "database without envelope", data: legacy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadJSONReport(tt.data)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got.Name() != "ecommerce" || got.Engine() != "PostgreSQL" {
+				t.Errorf("expected ecommerce/PostgreSQL, got %s/%s", got.Name(), got.Engine())
+			}
+
+			public := got.Schemas()["public"]
+			if public == nil || public.Database() != got {
+				t.Fatal("expected public schema linked to the database")
+			}
+			gotOrders := public.Tables()["orders"]
+			if gotOrders == nil || gotOrders.Schema() != public {
+				t.Fatal("expected orders linked to the public schema")
+			}
+
+			gotFK := gotOrders.ForeignKeys()[0]
+			if gotFK.Table() != gotOrders || gotFK.Columns()[0] != gotOrders.Columns()["user_id"] {
+				t.Error("expected foreign key linked to its table and column")
+			}
+			if gotFK.ReferencedColumns()[0] != public.Tables()["users"].Columns()["id"] {
+				t.Error("expected foreign key linked to the referenced column")
+			}
+			if gotOrders.Triggers()[0].Function() != public.Functions()["touch"] {
+				t.Error("expected trigger linked to its function")
+			}
+			if lang := public.Functions()["touch"].Language(); lang != "plpgsql" {
+				t.Errorf("expected language plpgsql, got %s", lang)
+			}
+		})
+	}
+}
+
+func TestReadJSONReportErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "invalid JSON", data: `{"formatVersion":`, wantErr: "failed to parse JSON report"},
+		{name: "newer major version", data: `{"formatVersion": "2.0", "database": {"name": "db"}}`, wantErr: "unsupported JSON report format version 2.0"},
+		{name: "missing database", data: `{"formatVersion": "1.0"}`, wantErr: "no database"},
+		{name: "malformed database", data: `{"formatVersion": "1.0", "database": {"schemas": 3}}`, wantErr: "failed to parse JSON report"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadJSONReport([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
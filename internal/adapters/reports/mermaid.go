@@ -1,14 +1,15 @@
 package reports
 
 import (
-	"os"
 	"strings"
 
 	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
 )
 
 // MermaidReportWriter generates Mermaid ERD diagrams from database schemas
-type MermaidReportWriter struct{}
+type MermaidReportWriter struct {
+	Options DiagramOptions
+}
 
 // GetReportKeys returns the report keys supported by this writer
 func (w *MermaidReportWriter) GetReportKeys() []string {
@@ -25,47 +26,66 @@ func (w *MermaidReportWriter) GetReportName() string {
 	return "Mermaid ERD"
 }
 
-// WriteInventoryReport writes a Mermaid ERD diagram to the specified file
+// WriteInventoryReport writes the Mermaid ERD diagrams selected by the writer options
 func (w *MermaidReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
-	mermaid := GenerateMermaidERD(db)
-	return os.WriteFile(filePath, []byte(mermaid), 0600)
+	subsets, err := diagramSubsets(db, w.Options)
+	if err != nil {
+		return err
+	}
+	return writeDiagramFiles(filePath, subsets, generateMermaidDiagram)
 }
 
-// GenerateMermaidERD generates a Mermaid ERD diagram string from a database
+// GenerateMermaidERD generates a Mermaid ERD diagram string from a database.
+// Schemas, tables and columns are written in a stable order so that repeated runs
+// produce identical output.
 func GenerateMermaidERD(db *dbo.Database) string {
+	subsets, _ := diagramSubsets(db, DiagramOptions{})
+	return generateMermaidDiagram(subsets[0])
+}
+
+// generateMermaidDiagram generates a Mermaid ERD diagram string for a subset of tables
+func generateMermaidDiagram(subset *diagramSubset) string {
 	var sb strings.Builder
 	sb.WriteString("erDiagram\n")
-
-	for _, schema := range db.Schemas() {
-		writeSchemaEntities(&sb, schema)
-	}
-
+	writeMermaidEntities(&sb, subset)
 	return sb.String()
 }
 
-// writeSchemaEntities writes all table entities and relationships for a schema
-func writeSchemaEntities(sb *strings.Builder, schema *dbo.Schema) {
+// writeMermaidEntities writes the table entities, stubs and relationships of a subset
+func writeMermaidEntities(sb *strings.Builder, subset *diagramSubset) {
 	// First pass: write all table entities
-	for _, table := range schema.Tables() {
+	for _, table := range subset.tables {
 		writeTableEntity(sb, table)
 	}
+	for _, stub := range subset.stubs {
+		writeMermaidStub(sb, stub)
+	}
 
 	// Second pass: write relationships (after all entities are defined)
-	relationships := collectRelationships(schema)
+	relationships := collectRelationships(subset)
 	for _, rel := range relationships {
 		sb.WriteString(rel)
 		sb.WriteString("\n")
 	}
 }
 
-// writeTableEntity writes a single table entity with its columns
+// writeMermaidStub writes an attribute-less entity for a table outside the diagram,
+// labelled with its qualified name
+func writeMermaidStub(sb *strings.Builder, stub diagramStub) {
+	sb.WriteString("    ")
+	sb.WriteString(mermaidEntityName(stub.context, stub.schemaName, stub.tableName))
+	sb.WriteString("[\"")
+	sb.WriteString(stub.label())
+	sb.WriteString(" (stub)\"]\n")
+}
+
+// writeTableEntity writes a single table entity with its columns in ordinal order
 func writeTableEntity(sb *strings.Builder, table *dbo.Table) {
-	tableName := sanitizeMermaidName(table.Name())
 	sb.WriteString("    ")
-	sb.WriteString(tableName)
+	sb.WriteString(mermaidEntityName(table.Schema(), schemaNameOf(table.Schema()), table.Name()))
 	sb.WriteString(" {\n")
 
-	for _, col := range table.Columns() {
+	for _, col := range sortedColumns(table) {
 		writeColumnDefinition(sb, table, col)
 	}
 
@@ -98,40 +118,53 @@ func writeColumnDefinition(sb *strings.Builder, table *dbo.Table, col *dbo.Colum
 	sb.WriteString("\n")
 }
 
-// collectRelationships extracts unique relationships from foreign keys
-func collectRelationships(schema *dbo.Schema) []string {
+// collectRelationships extracts unique relationships from the foreign keys of a subset
+func collectRelationships(subset *diagramSubset) []string {
 	seen := make(map[string]bool)
 	var relationships []string
 
-	for _, table := range schema.Tables() {
-		for _, fk := range table.ForeignKeys() {
-			rel := formatMermaidRelationship(table, fk)
-			if !seen[rel] {
-				seen[rel] = true
-				relationships = append(relationships, rel)
-			}
+	for _, edge := range subset.edges {
+		rel := formatMermaidRelationship(edge.table, edge.fk)
+		if !seen[rel] {
+			seen[rel] = true
+			relationships = append(relationships, rel)
 		}
 	}
 
 	return relationships
 }
 
-// formatMermaidRelationship formats a foreign key as a Mermaid relationship
-// Cardinality notation:
-//   - ||--|| : one-to-one
-//   - ||--o{ : one-to-many (zero or more)
-//   - }o--|| : many-to-one (the FK side can have many records pointing to one)
-//   - ||--o| : one-to-zero-or-one
+// formatMermaidRelationship formats a foreign key as a Mermaid relationship, written
+// from the referencing table to the referenced one. The cardinality is derived
+// from the FK columns:
+//   - }o : many referencing rows (FK columns are not unique)
+//   - |o : at most one referencing row (FK columns are unique, one-to-one)
+//   - || : exactly one referenced row (FK columns are NOT NULL)
+//   - o| : zero or one referenced row (any FK column is nullable)
 func formatMermaidRelationship(table *dbo.Table, fk *dbo.ForeignKey) string {
-	fromTable := sanitizeMermaidName(table.Name())
-	toTable := sanitizeMermaidName(fk.ReferencedTable())
+	card := foreignKeyCardinality(fk)
+
+	childEnd := "}o"
+	if card.childUnique {
+		childEnd = "|o"
+	}
+	parentEnd := "||"
+	if card.parentOptional {
+		parentEnd = "o|"
+	}
+
+	fromTable := mermaidEntityName(table.Schema(), schemaNameOf(table.Schema()), table.Name())
+	toTable := mermaidEntityName(table.Schema(), referencedSchemaName(fk), fk.ReferencedTable())
 	label := sanitizeMermaidName(fk.Name())
 
-	// Many-to-one: the table with FK has many rows pointing to one referenced row
 	var sb strings.Builder
 	sb.WriteString("    ")
 	sb.WriteString(fromTable)
-	sb.WriteString(" }o--|| ")
+	sb.WriteString(" ")
+	sb.WriteString(childEnd)
+	sb.WriteString("--")
+	sb.WriteString(parentEnd)
+	sb.WriteString(" ")
 	sb.WriteString(toTable)
 	sb.WriteString(" : \"")
 	sb.WriteString(label)
@@ -140,6 +173,24 @@ func formatMermaidRelationship(table *dbo.Table, fk *dbo.ForeignKey) string {
 	return sb.String()
 }
 
+// mermaidEntityName returns the diagram name of a table. Bare table names are used
+// unless a table of the same name exists in another schema of the database the
+// context schema belongs to, in which case the name is qualified and quoted
+// (e.g. "sales.orders") so that each schema's table gets its own entity.
+func mermaidEntityName(context *dbo.Schema, schemaName, tableName string) string {
+	if context != nil && context.Database() != nil && schemaName != "" {
+		for _, other := range context.Database().Schemas() {
+			if other.Name() == schemaName {
+				continue
+			}
+			if _, ok := other.Tables()[tableName]; ok {
+				return "\"" + schemaName + "." + tableName + "\""
+			}
+		}
+	}
+	return sanitizeMermaidName(tableName)
+}
+
 // sanitizeMermaidName replaces characters that Mermaid doesn't handle well
 func sanitizeMermaidName(name string) string {
 	// Replace hyphens and spaces with underscores
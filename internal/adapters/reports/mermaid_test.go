@@ -107,7 +107,7 @@ func TestGenerateMermaidERD(t *testing.T) {
 	})
 }
 
-func TestWriteSchemaEntities(t *testing.T) {
+func TestWriteMermaidEntities(t *testing.T) {
 	t.Run("schema with tables and relationships", func(t *testing.T) {
 		schema := dbo.NewSchema("public", "owner", nil)
 
@@ -133,7 +133,7 @@ func TestWriteSchemaEntities(t *testing.T) {
 		schema.AddTable(childTable)
 
 		var sb strings.Builder
-		writeSchemaEntities(&sb, schema)
+		writeMermaidEntities(&sb, newDiagramSubset(nil, "", sortedTables(schema)))
 		result := sb.String()
 
 		if !strings.Contains(result, "departments") {
@@ -151,7 +151,7 @@ func TestWriteSchemaEntities(t *testing.T) {
 		schema := dbo.NewSchema("empty", "owner", nil)
 
 		var sb strings.Builder
-		writeSchemaEntities(&sb, schema)
+		writeMermaidEntities(&sb, newDiagramSubset(nil, "", sortedTables(schema)))
 		result := sb.String()
 
 		if result != "" {
@@ -291,7 +291,7 @@ func TestCollectRelationships(t *testing.T) {
 		childTable.AddForeignKey(fk)
 		schema.AddTable(childTable)
 
-		relationships := collectRelationships(schema)
+		relationships := collectRelationships(newDiagramSubset(nil, "", sortedTables(schema)))
 
 		if len(relationships) != 1 {
 			t.Errorf("expected 1 relationship, got %d", len(relationships))
@@ -328,7 +328,7 @@ func TestCollectRelationships(t *testing.T) {
 
 		schema.AddTable(childTable)
 
-		relationships := collectRelationships(schema)
+		relationships := collectRelationships(newDiagramSubset(nil, "", sortedTables(schema)))
 
 		if len(relationships) != 1 {
 			t.Errorf("expected 1 unique relationship (deduped), got %d", len(relationships))
@@ -341,7 +341,7 @@ func TestCollectRelationships(t *testing.T) {
 		table.AddColumn(dbo.NewColumn("id", "integer", false))
 		schema.AddTable(table)
 
-		relationships := collectRelationships(schema)
+		relationships := collectRelationships(newDiagramSubset(nil, "", sortedTables(schema)))
 
 		if len(relationships) != 0 {
 			t.Errorf("expected 0 relationships, got %d", len(relationships))
@@ -393,6 +393,81 @@ func TestFormatMermaidRelationship(t *testing.T) {
 	})
 }
 
+func TestFormatMermaidRelationshipCardinality(t *testing.T) {
+	tests := []struct {
+		name     string
+		nullable bool
+		unique   bool
+		expected string
+	}{
+		{"required many-to-one", false, false, "orders }o--|| users"},
+		{"optional many-to-one", true, false, "orders }o--o| users"},
+		{"required one-to-one", false, true, "orders |o--|| users"},
+		{"optional one-to-one", true, true, "orders |o--o| users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := dbo.NewTable("orders", nil)
+			col := dbo.NewColumn("user_id", "integer", tt.nullable)
+			table.AddColumn(col)
+			if tt.unique {
+				table.AddIndex(dbo.NewIndex("orders_user_id_key", table, []*dbo.Column{col}, true))
+			}
+
+			fk := dbo.NewForeignKey("fk_user", "users")
+			fk.SetTable(table)
+			fk.AddColumn(col)
+
+			result := formatMermaidRelationship(table, fk)
+
+			if !strings.Contains(result, tt.expected) {
+				t.Errorf("expected %q in %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMermaidEntityName(t *testing.T) {
+	db := dbo.NewDatabase("testdb", nil)
+	sales := dbo.NewSchema("sales", "owner", nil)
+	sales.AddTable(dbo.NewTable("orders", nil))
+	sales.AddTable(dbo.NewTable("customers", nil))
+	db.AddSchema(sales)
+	archive := dbo.NewSchema("archive", "owner", nil)
+	archive.AddTable(dbo.NewTable("orders", nil))
+	db.AddSchema(archive)
+
+	tests := []struct {
+		name       string
+		schemaName string
+		tableName  string
+		expected   string
+	}{
+		{"unique table name stays bare", "sales", "customers", "customers"},
+		{"colliding table name is qualified", "sales", "orders", "\"sales.orders\""},
+		{"other schema is qualified too", "archive", "orders", "\"archive.orders\""},
+		{"unmapped table sharing a name is qualified", "legacy", "orders", "\"legacy.orders\""},
+		{"unmapped table with unique name stays bare", "legacy", "invoices", "invoices"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mermaidEntityName(sales, tt.schemaName, tt.tableName)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("schema without database", func(t *testing.T) {
+		schema := dbo.NewSchema("public", "owner", nil)
+		if result := mermaidEntityName(schema, "public", "user-roles"); result != "user_roles" {
+			t.Errorf("expected user_roles, got %q", result)
+		}
+	})
+}
+
 func TestSanitizeMermaidName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -744,3 +819,101 @@ func TestIntegration_FullERD(t *testing.T) {
 		}
 	})
 }
+
+func TestGenerateMermaidERD_Deterministic(t *testing.T) {
+	t.Run("columns follow ordinal position", func(t *testing.T) {
+		db := dbo.NewDatabase("testdb", nil)
+		schema := dbo.NewSchema("public", "owner", nil)
+		db.AddSchema(schema)
+
+		table := dbo.NewTable("users", nil)
+		for i, name := range []string{"id", "email", "name", "created_at"} {
+			col := dbo.NewColumn(name, "text", false)
+			col.SetOrdinalPosition(i + 1)
+			table.AddColumn(col)
+		}
+		schema.AddTable(table)
+
+		expected := "erDiagram\n" +
+			"    users {\n" +
+			"        text id\n" +
+			"        text email\n" +
+			"        text name\n" +
+			"        text created_at\n" +
+			"    }\n"
+		for i := 0; i < 10; i++ {
+			if result := GenerateMermaidERD(db); result != expected {
+				t.Fatalf("run %d: expected %q, got %q", i, expected, result)
+			}
+		}
+	})
+
+	t.Run("schemas and tables are ordered by name", func(t *testing.T) {
+		db := dbo.NewDatabase("testdb", nil)
+		for _, schemaName := range []string{"zeta", "alpha"} {
+			schema := dbo.NewSchema(schemaName, "owner", nil)
+			for _, tableName := range []string{schemaName + "_b", schemaName + "_a"} {
+				schema.AddTable(dbo.NewTable(tableName, nil))
+			}
+			db.AddSchema(schema)
+		}
+
+		result := GenerateMermaidERD(db)
+
+		order := []string{"alpha_a {", "alpha_b {", "zeta_a {", "zeta_b {"}
+		last := -1
+		for _, entity := range order {
+			idx := strings.Index(result, entity)
+			if idx <= last {
+				t.Fatalf("expected %q after previous entities in %q", entity, result)
+			}
+			last = idx
+		}
+	})
+}
+
+func TestGenerateMermaidERD_CrossSchema(t *testing.T) {
+	db := dbo.NewDatabase("testdb", nil)
+
+	sales := dbo.NewSchema("sales", "owner", nil)
+	db.AddSchema(sales)
+	salesOrders := dbo.NewTable("orders", nil)
+	salesOrderID := dbo.NewColumn("id", "integer", false)
+	salesOrders.AddColumn(salesOrderID)
+	sales.AddTable(salesOrders)
+
+	archive := dbo.NewSchema("archive", "owner", nil)
+	db.AddSchema(archive)
+	archiveOrders := dbo.NewTable("orders", nil)
+	archiveOrderID := dbo.NewColumn("id", "integer", false)
+	archiveOrders.AddColumn(archiveOrderID)
+	archive.AddTable(archiveOrders)
+
+	// archive.notes references sales.orders across schemas
+	notes := dbo.NewTable("notes", nil)
+	noteOrderID := dbo.NewColumn("order_id", "integer", true)
+	notes.AddColumn(noteOrderID)
+	fk := dbo.NewForeignKey("notes_order_fk", "orders")
+	fk.SetReferencedSchema("sales")
+	fk.SetTable(notes)
+	fk.AddColumn(noteOrderID)
+	fk.AddReferencedColumn(salesOrderID)
+	notes.AddForeignKey(fk)
+	archive.AddTable(notes)
+
+	result := GenerateMermaidERD(db)
+
+	for _, expected := range []string{
+		"    \"archive.orders\" {\n",
+		"    \"sales.orders\" {\n",
+		"    notes {\n",
+		"    notes }o--o| \"sales.orders\" : \"notes_order_fk\"",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected %q in %q", expected, result)
+		}
+	}
+	if strings.Contains(result, "\n    orders {") {
+		t.Errorf("expected colliding tables to be qualified, got %q", result)
+	}
+}
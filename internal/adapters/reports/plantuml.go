@@ -0,0 +1,286 @@
+package reports
+
+import (
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// PlantUMLReportWriter generates PlantUML entity relationship diagrams from database schemas
+type PlantUMLReportWriter struct {
+	Options DiagramOptions
+}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *PlantUMLReportWriter) GetReportKeys() []string {
+	return []string{"plantuml"}
+}
+
+// GetReportFileExtension returns the file extension for PlantUML reports
+func (w *PlantUMLReportWriter) GetReportFileExtension() string {
+	return "puml"
+}
+
+// GetReportName returns the name of the PlantUML report
+func (w *PlantUMLReportWriter) GetReportName() string {
+	return "PlantUML ERD"
+}
+
+// WriteInventoryReport writes the PlantUML ERD diagrams selected by the writer options
+func (w *PlantUMLReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	subsets, err := diagramSubsets(db, w.Options)
+	if err != nil {
+		return err
+	}
+	return writeDiagramFiles(filePath, subsets, generatePlantUMLDiagram)
+}
+
+// GeneratePlantUMLERD generates a PlantUML ERD diagram string from a database.
+// Entities are grouped into one package per schema and ordered by name.
+func GeneratePlantUMLERD(db *dbo.Database) string {
+	subsets, _ := diagramSubsets(db, DiagramOptions{})
+	return generatePlantUMLDiagram(subsets[0])
+}
+
+// generatePlantUMLDiagram generates a PlantUML ERD diagram string for a subset of tables
+func generatePlantUMLDiagram(subset *diagramSubset) string {
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	sb.WriteString("hide circle\n")
+	sb.WriteString("skinparam linetype ortho\n")
+
+	// Subset tables are ordered by schema, so consecutive runs form one package each
+	for start := 0; start < len(subset.tables); {
+		end := start + 1
+		for end < len(subset.tables) && subset.tables[end].Schema() == subset.tables[start].Schema() {
+			end++
+		}
+		writePlantUMLSchema(&sb, schemaNameOf(subset.tables[start].Schema()), subset.tables[start:end])
+		start = end
+	}
+
+	if len(subset.stubs) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, stub := range subset.stubs {
+		sb.WriteString("entity \"")
+		sb.WriteString(stub.label())
+		sb.WriteString("\" as ")
+		sb.WriteString(plantUMLAliasFor(stub.schemaName, stub.tableName))
+		sb.WriteString(" <<stub>>\n")
+	}
+
+	// Relationships are written last so that every entity is already declared
+	var relationships []string
+	seen := make(map[string]bool)
+	for _, edge := range subset.edges {
+		rel := formatPlantUMLRelationship(edge.table, edge.fk)
+		if !seen[rel] {
+			seen[rel] = true
+			relationships = append(relationships, rel)
+		}
+	}
+	if len(relationships) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, rel := range relationships {
+		sb.WriteString(rel)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+// writePlantUMLSchema writes a package containing the given table entities of a schema,
+// followed by the notes attached to those entities
+func writePlantUMLSchema(sb *strings.Builder, schemaName string, tables []*dbo.Table) {
+	if len(tables) == 0 {
+		return
+	}
+
+	sb.WriteString("\npackage \"")
+	sb.WriteString(schemaName)
+	sb.WriteString("\" {\n")
+	for _, table := range tables {
+		writePlantUMLEntity(sb, table)
+	}
+	sb.WriteString("}\n")
+
+	for _, table := range tables {
+		writePlantUMLNote(sb, table)
+	}
+}
+
+// writePlantUMLEntity writes a single table entity. Primary key columns are listed
+// above the separator, followed by the remaining columns and the table's indexes.
+func writePlantUMLEntity(sb *strings.Builder, table *dbo.Table) {
+	sb.WriteString("  entity \"")
+	sb.WriteString(table.Name())
+	sb.WriteString("\" as ")
+	sb.WriteString(plantUMLAlias(table.Schema(), table.Name()))
+	sb.WriteString(" {\n")
+
+	var keyColumns, otherColumns []*dbo.Column
+	for _, col := range sortedColumns(table) {
+		if isPrimaryKeyColumn(table, col.Name()) {
+			keyColumns = append(keyColumns, col)
+		} else {
+			otherColumns = append(otherColumns, col)
+		}
+	}
+
+	for _, col := range keyColumns {
+		writePlantUMLColumn(sb, table, col)
+	}
+	if len(keyColumns) > 0 {
+		sb.WriteString("    --\n")
+	}
+	for _, col := range otherColumns {
+		writePlantUMLColumn(sb, table, col)
+	}
+
+	var indexes []*dbo.Index
+	for _, idx := range table.Indexes() {
+		if !idx.IsPrimary() {
+			indexes = append(indexes, idx)
+		}
+	}
+	if len(indexes) > 0 {
+		sb.WriteString("    .. indexes ..\n")
+		for _, idx := range indexes {
+			sb.WriteString("    ")
+			sb.WriteString(idx.Name())
+			sb.WriteString(" (")
+			sb.WriteString(strings.Join(columnNames(idx.Columns()), ", "))
+			sb.WriteString(")")
+			if idx.IsUnique() {
+				sb.WriteString(" <<unique>>")
+			}
+			if idx.IndexType() != "" && idx.IndexType() != dbo.IndexTypeBTree {
+				sb.WriteString(" <<")
+				sb.WriteString(strings.ToLower(string(idx.IndexType())))
+				sb.WriteString(">>")
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("  }\n")
+}
+
+// writePlantUMLColumn writes a single column line. Mandatory (NOT NULL) columns are
+// prefixed with '*' and constraints are rendered as stereotypes.
+func writePlantUMLColumn(sb *strings.Builder, table *dbo.Table, col *dbo.Column) {
+	sb.WriteString("    ")
+	if !col.IsNullable() {
+		sb.WriteString("* ")
+	}
+	sb.WriteString(col.Name())
+	sb.WriteString(" : ")
+	sb.WriteString(formatColumnType(col))
+
+	isPK := isPrimaryKeyColumn(table, col.Name())
+	if isPK {
+		sb.WriteString(" <<PK>>")
+	}
+	if isForeignKeyColumn(table, col.Name()) {
+		sb.WriteString(" <<FK>>")
+	}
+	if !isPK && isUniqueColumn(table, col) {
+		sb.WriteString(" <<unique>>")
+	}
+	if col.DefaultValue() != nil {
+		sb.WriteString(" <<default: ")
+		sb.WriteString(*col.DefaultValue())
+		sb.WriteString(">>")
+	}
+	sb.WriteString("\n")
+}
+
+// writePlantUMLNote attaches a note holding the table comment and CHECK constraints
+func writePlantUMLNote(sb *strings.Builder, table *dbo.Table) {
+	lines := tableNoteLines(table)
+	if len(lines) == 0 {
+		return
+	}
+	sb.WriteString("note right of ")
+	sb.WriteString(plantUMLAlias(table.Schema(), table.Name()))
+	sb.WriteString("\n")
+	for _, line := range lines {
+		sb.WriteString("  ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("end note\n")
+}
+
+// formatPlantUMLRelationship formats a foreign key as a PlantUML relationship.
+// The referencing side is '|o' when the FK columns are unique and '}o' otherwise;
+// the referenced side is 'o|' when the FK columns are nullable and '||' otherwise.
+func formatPlantUMLRelationship(table *dbo.Table, fk *dbo.ForeignKey) string {
+	card := foreignKeyCardinality(fk)
+
+	childEnd := "}o"
+	if card.childUnique {
+		childEnd = "|o"
+	}
+	parentEnd := "||"
+	if card.parentOptional {
+		parentEnd = "o|"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(plantUMLAlias(table.Schema(), table.Name()))
+	sb.WriteString(" ")
+	sb.WriteString(childEnd)
+	sb.WriteString("--")
+	sb.WriteString(parentEnd)
+	sb.WriteString(" ")
+	sb.WriteString(plantUMLAliasFor(referencedSchemaName(fk), fk.ReferencedTable()))
+	sb.WriteString(" : ")
+	sb.WriteString(fk.Name())
+	return sb.String()
+}
+
+// plantUMLAlias returns the diagram alias of a table, qualified by its schema
+func plantUMLAlias(schema *dbo.Schema, tableName string) string {
+	schemaName := ""
+	if schema != nil {
+		schemaName = schema.Name()
+	}
+	return plantUMLAliasFor(schemaName, tableName)
+}
+
+// plantUMLAliasFor builds an alias from a schema and table name, replacing any
+// character PlantUML does not accept in identifiers
+func plantUMLAliasFor(schemaName, tableName string) string {
+	name := tableName
+	if schemaName != "" {
+		name = schemaName + "." + tableName
+	}
+	var sb strings.Builder
+	for _, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// tableNoteLines returns the lines of a table note: the table comment followed by
+// one line per CHECK constraint
+func tableNoteLines(table *dbo.Table) []string {
+	var lines []string
+	if table.Comment() != "" {
+		lines = append(lines, strings.Split(table.Comment(), "\n")...)
+	}
+	for _, c := range table.Constraints() {
+		if c.Type() == dbo.ConstraintTypeCheck && c.CheckExpression() != "" {
+			lines = append(lines, "CHECK "+c.Name()+": "+checkExpressionBody(c.CheckExpression()))
+		}
+	}
+	return lines
+}
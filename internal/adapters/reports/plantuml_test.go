@@ -0,0 +1,203 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// newDiagramTestDatabase builds a small schema with a mandatory, an optional and a
+// one-to-one foreign key
+func newDiagramTestDatabase() *dbo.Database {
+	db := dbo.NewDatabase("shop", nil)
+	schema := dbo.NewSchema("public", "owner", nil)
+	db.AddSchema(schema)
+
+	users := dbo.NewTable("users", nil)
+	userID := dbo.NewColumn("id", "integer", false)
+	userID.SetOrdinalPosition(1)
+	email := dbo.NewColumn("email", "character varying", false)
+	email.SetOrdinalPosition(2)
+	email.SetCharMaxLength(255)
+	status := dbo.NewColumn("status", "text", true)
+	status.SetOrdinalPosition(3)
+	status.SetDefaultValue("'active'::text")
+	users.AddColumn(userID)
+	users.AddColumn(email)
+	users.AddColumn(status)
+	users.SetPrimaryKey(dbo.NewPrimaryKey("users_pkey", users, []*dbo.Column{userID}))
+	users.AddIndex(dbo.NewIndex("users_email_key", users, []*dbo.Column{email}, true))
+	check := dbo.NewConstraint("users_status_check", dbo.ConstraintTypeCheck)
+	check.AddColumn(status)
+	check.SetCheckExpression("CHECK ((status <> ''::text))")
+	users.AddConstraint(check)
+	users.SetComment("registered accounts")
+	schema.AddTable(users)
+
+	orders := dbo.NewTable("orders", nil)
+	orderID := dbo.NewColumn("id", "integer", false)
+	orderID.SetOrdinalPosition(1)
+	orderUserID := dbo.NewColumn("user_id", "integer", false)
+	orderUserID.SetOrdinalPosition(2)
+	couponID := dbo.NewColumn("coupon_id", "integer", true)
+	couponID.SetOrdinalPosition(3)
+	orders.AddColumn(orderID)
+	orders.AddColumn(orderUserID)
+	orders.AddColumn(couponID)
+	orders.SetPrimaryKey(dbo.NewPrimaryKey("orders_pkey", orders, []*dbo.Column{orderID}))
+	fkUser := dbo.NewForeignKey("orders_user_fk", "users")
+	fkUser.SetReferencedSchema("public")
+	fkUser.AddColumn(orderUserID)
+	fkUser.AddReferencedColumn(userID)
+	fkUser.SetOnDelete(dbo.ActionCascade)
+	orders.AddForeignKey(fkUser)
+	fkCoupon := dbo.NewForeignKey("orders_coupon_fk", "users")
+	fkCoupon.SetReferencedSchema("public")
+	fkCoupon.AddColumn(couponID)
+	fkCoupon.AddReferencedColumn(userID)
+	orders.AddForeignKey(fkCoupon)
+	schema.AddTable(orders)
+
+	profiles := dbo.NewTable("profiles", nil)
+	profileUserID := dbo.NewColumn("user_id", "integer", false)
+	profileUserID.SetOrdinalPosition(1)
+	profiles.AddColumn(profileUserID)
+	profiles.SetPrimaryKey(dbo.NewPrimaryKey("profiles_pkey", profiles, []*dbo.Column{profileUserID}))
+	fkProfile := dbo.NewForeignKey("profiles_user_fk", "users")
+	fkProfile.AddColumn(profileUserID)
+	fkProfile.AddReferencedColumn(userID)
+	profiles.AddForeignKey(fkProfile)
+	schema.AddTable(profiles)
+
+	return db
+}
+
+func TestPlantUMLReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes plantuml file successfully", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "test.puml")
+
+		writer := &PlantUMLReportWriter{}
+		err := writer.WriteInventoryReport(filePath, newDiagramTestDatabase())
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+
+		if !strings.HasPrefix(string(content), "@startuml") {
+			t.Errorf("expected file to start with @startuml, got %s", string(content))
+		}
+		if !strings.HasSuffix(string(content), "@enduml\n") {
+			t.Errorf("expected file to end with @enduml, got %s", string(content))
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &PlantUMLReportWriter{}
+		db := dbo.NewDatabase("testdb", nil)
+
+		err := writer.WriteInventoryReport("/nonexistent/path/file.puml", db)
+
+		if err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+}
+
+func TestGeneratePlantUMLERD(t *testing.T) {
+	t.Run("empty database", func(t *testing.T) {
+		result := GeneratePlantUMLERD(dbo.NewDatabase("testdb", nil))
+
+		expected := "@startuml\nhide circle\nskinparam linetype ortho\n@enduml\n"
+		if result != expected {
+			t.Errorf("expected %q, got %q", expected, result)
+		}
+	})
+
+	t.Run("entities, columns and notes", func(t *testing.T) {
+		result := GeneratePlantUMLERD(newDiagramTestDatabase())
+
+		expectations := []string{
+			"package \"public\" {",
+			"entity \"users\" as public_users {",
+			"* id : integer <<PK>>\n    --\n",
+			"* email : character varying(255) <<unique>>",
+			"status : text <<default: 'active'::text>>",
+			"* user_id : integer <<FK>>",
+			"users_email_key (email) <<unique>>",
+			"note right of public_users\n  registered accounts\n  CHECK users_status_check: status <> ''::text\nend note",
+		}
+		for _, expected := range expectations {
+			if !strings.Contains(result, expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", expected, result)
+			}
+		}
+	})
+
+	t.Run("is deterministic", func(t *testing.T) {
+		db := newDiagramTestDatabase()
+		first := GeneratePlantUMLERD(db)
+		for i := 0; i < 10; i++ {
+			if GeneratePlantUMLERD(db) != first {
+				t.Fatal("expected identical output across runs")
+			}
+		}
+	})
+}
+
+func TestFormatPlantUMLRelationship(t *testing.T) {
+	db := newDiagramTestDatabase()
+	schema := db.Schemas()["public"]
+	orders := schema.Tables()["orders"]
+	profiles := schema.Tables()["profiles"]
+
+	tests := []struct {
+		name     string
+		table    *dbo.Table
+		fk       *dbo.ForeignKey
+		expected string
+	}{
+		{"mandatory many-to-one", orders, orders.ForeignKeys()[0], "public_orders }o--|| public_users : orders_user_fk"},
+		{"optional many-to-one", orders, orders.ForeignKeys()[1], "public_orders }o--o| public_users : orders_coupon_fk"},
+		{"one-to-one", profiles, profiles.ForeignKeys()[0], "public_profiles |o--|| public_users : profiles_user_fk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatPlantUMLRelationship(tt.table, tt.fk)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPlantUMLAliasFor(t *testing.T) {
+	tests := []struct {
+		schema   string
+		table    string
+		expected string
+	}{
+		{"public", "users", "public_users"},
+		{"", "users", "users"},
+		{"sales", "order-items", "sales_order_items"},
+		{"my schema", "t", "my_schema_t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			result := plantUMLAliasFor(tt.schema, tt.table)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
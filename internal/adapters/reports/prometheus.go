@@ -0,0 +1,196 @@
+package reports
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+	"github.com/jimbot9k/norman/internal/core/rules"
+	"github.com/jimbot9k/norman/internal/version"
+)
+
+// PrometheusReportWriter writes inventory metrics in the text exposition format read by
+// the node_exporter textfile collector
+type PrometheusReportWriter struct {
+	mappingDuration time.Duration
+	mappingErrors   int
+	mapped          bool
+	findings        []rules.Finding
+	evaluated       bool
+}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *PrometheusReportWriter) GetReportKeys() []string {
+	return []string{"prometheus"}
+}
+
+// GetReportFileExtension returns the file extension expected by the textfile collector
+func (w *PrometheusReportWriter) GetReportFileExtension() string {
+	return "prom"
+}
+
+// GetReportName returns the name of the Prometheus report
+func (w *PrometheusReportWriter) GetReportName() string {
+	return "Prometheus Metrics"
+}
+
+// SetMappingStats records how long mapping took and how many errors it reported
+func (w *PrometheusReportWriter) SetMappingStats(duration time.Duration, errs []error) {
+	w.mappingDuration = duration
+	w.mappingErrors = len(errs)
+	w.mapped = true
+}
+
+// SetFindings records the findings of the audit rules
+func (w *PrometheusReportWriter) SetFindings(findings []rules.Finding) {
+	w.findings = findings
+	w.evaluated = true
+}
+
+// WriteInventoryReport writes the metrics to a temporary file next to filePath and
+// renames it into place, so the collector never reads a partially written file
+func (w *PrometheusReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	metrics := GeneratePrometheusMetrics(db, PrometheusRunStats{
+		MappingDuration: w.mappingDuration,
+		MappingErrors:   w.mappingErrors,
+		Mapped:          w.mapped,
+		Findings:        w.findings,
+		Evaluated:       w.evaluated,
+		GeneratedAt:     time.Now(),
+	})
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(metrics), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// PrometheusRunStats describes the run that produced the inventory
+type PrometheusRunStats struct {
+	MappingDuration time.Duration
+	MappingErrors   int
+	// Mapped is false when the stats are unknown, in which case the mapping metrics are left out
+	Mapped   bool
+	Findings []rules.Finding
+	// Evaluated is false when the rules were not run, in which case the finding metrics are left out
+	Evaluated   bool
+	GeneratedAt time.Time
+}
+
+// GeneratePrometheusMetrics generates the metrics of a database inventory in the
+// Prometheus text exposition format
+func GeneratePrometheusMetrics(db *dbo.Database, stats PrometheusRunStats) string {
+	var sb strings.Builder
+	database := prometheusLabel("database", db.Name())
+
+	writePrometheusFamily(&sb, "norman_info", "Norman build that produced the metrics.")
+	fmt.Fprintf(&sb, "norman_info{%s,%s,%s} 1\n", database,
+		prometheusLabel("version", version.Version), prometheusLabel("commit", version.Commit))
+
+	writePrometheusFamily(&sb, "norman_objects", "Number of mapped database objects by schema and kind.")
+	for _, schema := range sortedSchemas(db) {
+		labels := database + "," + prometheusLabel("schema", schema.Name())
+		for _, count := range schemaObjectCounts(schema) {
+			fmt.Fprintf(&sb, "norman_objects{%s,%s} %d\n", labels, prometheusLabel("kind", count.kind), count.count)
+		}
+	}
+
+	if stats.Mapped {
+		writePrometheusFamily(&sb, "norman_mapping_duration_seconds", "Time spent mapping the database.")
+		fmt.Fprintf(&sb, "norman_mapping_duration_seconds{%s} %s\n", database, strconv.FormatFloat(stats.MappingDuration.Seconds(), 'f', -1, 64))
+		writePrometheusFamily(&sb, "norman_mapping_errors", "Number of errors reported while mapping the database.")
+		fmt.Fprintf(&sb, "norman_mapping_errors{%s} %d\n", database, stats.MappingErrors)
+	}
+
+	if stats.Evaluated {
+		writePrometheusFamily(&sb, "norman_findings", "Number of findings by rule and severity.")
+		for _, count := range findingCounts(stats.Findings) {
+			fmt.Fprintf(&sb, "norman_findings{%s,%s,%s} %d\n", database,
+				prometheusLabel("rule", count.rule), prometheusLabel("severity", string(count.severity)), count.count)
+		}
+	}
+
+	if !stats.GeneratedAt.IsZero() {
+		writePrometheusFamily(&sb, "norman_last_run_timestamp_seconds", "Unix time the inventory was generated.")
+		fmt.Fprintf(&sb, "norman_last_run_timestamp_seconds{%s} %d\n", database, stats.GeneratedAt.Unix())
+	}
+	return sb.String()
+}
+
+// objectCount is the number of objects of one kind in a schema
+type objectCount struct {
+	kind  string
+	count int
+}
+
+// schemaObjectCounts counts the objects of a schema by kind, in a fixed kind order
+func schemaObjectCounts(schema *dbo.Schema) []objectCount {
+	var columns, indexes, foreignKeys, constraints, triggers int
+	for _, table := range schema.Tables() {
+		columns += len(table.Columns())
+		indexes += len(table.Indexes())
+		foreignKeys += len(table.ForeignKeys())
+		constraints += len(table.Constraints())
+		triggers += len(table.Triggers())
+	}
+	return []objectCount{
+		{"table", len(schema.Tables())},
+		{"column", columns},
+		{"index", indexes},
+		{"foreign_key", foreignKeys},
+		{"constraint", constraints},
+		{"trigger", triggers},
+		{"view", len(schema.Views())},
+		{"sequence", len(schema.Sequences())},
+		{"function", len(schema.Functions())},
+		{"procedure", len(schema.Procedures())},
+		{"enum", len(schema.Enums())},
+	}
+}
+
+// findingCount is the number of findings of one rule and severity
+type findingCount struct {
+	rule     string
+	severity rules.Severity
+	count    int
+}
+
+// findingCounts counts findings by rule and severity, ordered by rule and severity
+func findingCounts(findings []rules.Finding) []findingCount {
+	var counts []findingCount
+	index := make(map[findingCount]int)
+	for _, f := range findings {
+		key := findingCount{rule: f.Rule, severity: f.Severity}
+		if i, ok := index[key]; ok {
+			counts[i].count++
+			continue
+		}
+		index[key] = len(counts)
+		key.count = 1
+		counts = append(counts, key)
+	}
+	slices.SortFunc(counts, func(a, b findingCount) int {
+		return cmp.Or(cmp.Compare(a.rule, b.rule), cmp.Compare(a.severity, b.severity))
+	})
+	return counts
+}
+
+// writePrometheusFamily writes the HELP and TYPE lines of a gauge metric family
+func writePrometheusFamily(sb *strings.Builder, name, help string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// prometheusLabel formats a label pair, escaping backslashes, quotes and newlines
+func prometheusLabel(name, value string) string {
+	value = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(value)
+	return name + "=\"" + value + "\""
+}
@@ -0,0 +1,121 @@
+package reports
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+	"github.com/jimbot9k/norman/internal/core/rules"
+)
+
+func TestPrometheusReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes metrics file with mapping stats", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "norman_shop.prom")
+
+		writer := &PrometheusReportWriter{}
+		writer.SetMappingStats(1500*time.Millisecond, []error{errors.New("a"), errors.New("b")})
+		if err := writer.WriteInventoryReport(filePath, newDiagramTestDatabase()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		for _, expected := range []string{
+			"norman_mapping_duration_seconds{database=\"shop\"} 1.5\n",
+			"norman_mapping_errors{database=\"shop\"} 2\n",
+			"norman_last_run_timestamp_seconds{database=\"shop\"} ",
+		} {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("expected %q in:\n%s", expected, content)
+			}
+		}
+		if _, err := os.Stat(filePath + ".tmp"); !os.IsNotExist(err) {
+			t.Error("expected temporary file to be renamed")
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &PrometheusReportWriter{}
+		if err := writer.WriteInventoryReport("/nonexistent/path/file.prom", dbo.NewDatabase("testdb", nil)); err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+}
+
+func TestGeneratePrometheusMetrics(t *testing.T) {
+	db := newDiagramTestDatabase()
+	db.Schemas()["public"].AddView(dbo.NewView("recent_users", "SELECT 1"))
+
+	result := GeneratePrometheusMetrics(db, PrometheusRunStats{})
+
+	for _, expected := range []string{
+		"# HELP norman_objects Number of mapped database objects by schema and kind.\n# TYPE norman_objects gauge\n",
+		"norman_objects{database=\"shop\",schema=\"public\",kind=\"table\"} 3\n",
+		"norman_objects{database=\"shop\",schema=\"public\",kind=\"column\"} 7\n",
+		"norman_objects{database=\"shop\",schema=\"public\",kind=\"foreign_key\"} 3\n",
+		"norman_objects{database=\"shop\",schema=\"public\",kind=\"view\"} 1\n",
+		"norman_objects{database=\"shop\",schema=\"public\",kind=\"enum\"} 0\n",
+		"norman_info{database=\"shop\",version=\"",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected %q in:\n%s", expected, result)
+		}
+	}
+	for _, absent := range []string{"norman_mapping_duration_seconds", "norman_last_run_timestamp_seconds"} {
+		if strings.Contains(result, absent) {
+			t.Errorf("expected no %s without run stats", absent)
+		}
+	}
+
+	// Every sample line belongs to a family declared before it
+	declared := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(result), "\n") {
+		if name, ok := strings.CutPrefix(line, "# TYPE "); ok {
+			declared[strings.Fields(name)[0]] = true
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, _, _ := strings.Cut(line, "{")
+		if !declared[name] {
+			t.Errorf("sample %q has no TYPE line", line)
+		}
+	}
+}
+
+func TestGeneratePrometheusMetrics_Findings(t *testing.T) {
+	db := newDiagramTestDatabase()
+	findings := []rules.Finding{
+		{Rule: "mysql-non-transactional-engine", Severity: rules.SeverityCritical, Object: "public.orders"},
+		{Rule: "mysql-deprecated-utf8mb3", Severity: rules.SeverityWarning, Object: "public.users"},
+		{Rule: "mysql-deprecated-utf8mb3", Severity: rules.SeverityWarning, Object: "public.orders"},
+	}
+
+	result := GeneratePrometheusMetrics(db, PrometheusRunStats{Findings: findings, Evaluated: true})
+	for _, expected := range []string{
+		"# TYPE norman_findings gauge\n",
+		"norman_findings{database=\"shop\",rule=\"mysql-deprecated-utf8mb3\",severity=\"warning\"} 2\n",
+		"norman_findings{database=\"shop\",rule=\"mysql-non-transactional-engine\",severity=\"critical\"} 1\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected %q in:\n%s", expected, result)
+		}
+	}
+
+	if result := GeneratePrometheusMetrics(db, PrometheusRunStats{}); strings.Contains(result, "norman_findings") {
+		t.Error("expected no finding metrics when the rules were not evaluated")
+	}
+}
+
+func TestPrometheusLabel(t *testing.T) {
+	if got := prometheusLabel("schema", "a\"b\\c\nd"); got != `schema="a\"b\\c\nd"` {
+		t.Errorf("unexpected escaping %s", got)
+	}
+}
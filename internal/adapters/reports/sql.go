@@ -0,0 +1,744 @@
+package reports
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// SQLDialect identifies the SQL flavour a DDL script is written in
+type SQLDialect string
+
+const (
+	SQLDialectPostgres SQLDialect = "postgres"
+	SQLDialectMySQL    SQLDialect = "mysql"
+)
+
+// SQLReportWriter generates a dependency-ordered CREATE script from database schemas.
+// When Dialect is empty the dialect is derived from the engine of the mapped database.
+type SQLReportWriter struct {
+	Dialect SQLDialect
+}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *SQLReportWriter) GetReportKeys() []string {
+	return []string{"sql"}
+}
+
+// GetReportFileExtension returns the file extension for SQL reports
+func (w *SQLReportWriter) GetReportFileExtension() string {
+	return "sql"
+}
+
+// GetReportName returns the name of the SQL report
+func (w *SQLReportWriter) GetReportName() string {
+	return "SQL DDL"
+}
+
+// WriteInventoryReport writes a DDL script recreating the database schema to the specified file
+func (w *SQLReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	dialect := w.Dialect
+	if dialect == "" {
+		dialect = SQLDialectForEngine(db.Engine())
+	}
+	ddl := GenerateSQLDDL(db, dialect)
+	return os.WriteFile(filePath, []byte(ddl), 0600)
+}
+
+// SQLDialectForEngine returns the DDL dialect matching a database engine name,
+// defaulting to PostgreSQL for unknown engines
+func SQLDialectForEngine(engine string) SQLDialect {
+	lower := strings.ToLower(engine)
+	if strings.Contains(lower, "mysql") || strings.Contains(lower, "mariadb") {
+		return SQLDialectMySQL
+	}
+	return SQLDialectPostgres
+}
+
+// GenerateSQLDDL generates a schema-only CREATE script for a database in the given dialect.
+// Statements are ordered so the script can be replayed into an empty server: schemas,
+// sequences, functions (which defaults, checks, generated columns and views may call),
+// tables (with columns, defaults and constraints), indexes, foreign keys, views,
+// procedures and finally triggers. PostgreSQL scripts turn off check_function_bodies,
+// so that functions may refer to tables created after them.
+func GenerateSQLDDL(db *dbo.Database, dialect SQLDialect) string {
+	b := &ddlBuilder{dialect: dialect}
+	schemas := sortedSchemas(db)
+
+	b.comment("Schema DDL for database " + db.Name() + " (" + string(dialect) + ")")
+	if b.dialect == SQLDialectPostgres {
+		// As pg_dump does: functions come before the tables their bodies may query
+		b.statement("SET check_function_bodies = false")
+	}
+
+	b.section("Schemas")
+	for _, schema := range schemas {
+		b.writeSchema(schema)
+	}
+
+	if b.dialect == SQLDialectPostgres && hasEnums(schemas) {
+		b.section("Types")
+		for _, schema := range schemas {
+			for _, enum := range sortedEnums(schema) {
+				b.writeEnum(enum)
+			}
+		}
+	}
+
+	b.section("Sequences")
+	for _, schema := range schemas {
+		for _, seq := range sortedSequences(schema) {
+			b.writeSequence(seq)
+		}
+	}
+
+	b.section("Functions")
+	for _, schema := range schemas {
+		for _, fn := range sortedFunctions(schema) {
+			b.writeFunction(fn)
+		}
+	}
+
+	b.section("Tables")
+	for _, schema := range schemas {
+		for _, table := range sortedTables(schema) {
+			b.writeTable(table)
+		}
+	}
+
+	b.section("Indexes")
+	for _, schema := range schemas {
+		for _, table := range sortedTables(schema) {
+			for _, idx := range table.Indexes() {
+				b.writeIndex(table, idx)
+			}
+		}
+	}
+
+	b.section("Foreign keys")
+	for _, schema := range schemas {
+		for _, table := range sortedTables(schema) {
+			for _, fk := range table.ForeignKeys() {
+				b.writeForeignKey(table, fk)
+			}
+		}
+	}
+
+	b.section("Views")
+	var views []*dbo.View
+	for _, schema := range schemas {
+		views = append(views, sortedViews(schema)...)
+	}
+	for _, view := range orderViewsByDependency(views) {
+		b.writeView(view)
+	}
+
+	b.section("Procedures")
+	for _, schema := range schemas {
+		for _, proc := range sortedProcedures(schema) {
+			b.writeProcedure(proc)
+		}
+	}
+
+	b.section("Triggers")
+	for _, schema := range schemas {
+		for _, table := range sortedTables(schema) {
+			for _, trigger := range table.Triggers() {
+				b.writeTrigger(table, trigger)
+			}
+		}
+	}
+
+	return b.sb.String()
+}
+
+// ddlBuilder accumulates DDL statements for a single dialect
+type ddlBuilder struct {
+	dialect SQLDialect
+	sb      strings.Builder
+}
+
+// comment writes a single-line SQL comment
+func (b *ddlBuilder) comment(text string) {
+	b.sb.WriteString("-- ")
+	b.sb.WriteString(text)
+	b.sb.WriteString("\n")
+}
+
+// section writes a section header comment
+func (b *ddlBuilder) section(title string) {
+	b.sb.WriteString("\n")
+	b.comment(title)
+}
+
+// statement writes a statement terminated by a semicolon
+func (b *ddlBuilder) statement(sql string) {
+	b.sb.WriteString(sql)
+	b.sb.WriteString(";\n")
+}
+
+// compoundStatement writes a statement that may contain semicolons in its body.
+// MySQL clients need a temporary delimiter for such statements.
+func (b *ddlBuilder) compoundStatement(sql string) {
+	if b.dialect == SQLDialectMySQL {
+		b.sb.WriteString("DELIMITER $$\n")
+		b.sb.WriteString(sql)
+		b.sb.WriteString(" $$\nDELIMITER ;\n")
+		return
+	}
+	b.statement(sql)
+}
+
+// ident quotes an identifier for the dialect
+func (b *ddlBuilder) ident(name string) string {
+	if b.dialect == SQLDialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return "\"" + strings.ReplaceAll(name, "\"", "\"\"") + "\""
+}
+
+// qualified returns a schema-qualified, quoted object name
+func (b *ddlBuilder) qualified(schemaName, name string) string {
+	if schemaName == "" {
+		return b.ident(name)
+	}
+	return b.ident(schemaName) + "." + b.ident(name)
+}
+
+// identList quotes and joins column names
+func (b *ddlBuilder) identList(columns []*dbo.Column) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = b.ident(col.Name())
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// indexKeyList quotes and joins the columns of an index, with the length of MySQL
+// prefix key parts
+func (b *ddlBuilder) indexKeyList(idx *dbo.Index) string {
+	quoted := make([]string, len(idx.Columns()))
+	for i, col := range idx.Columns() {
+		quoted[i] = b.ident(col.Name())
+		if length := idx.PrefixLength(i); length > 0 && b.dialect == SQLDialectMySQL {
+			quoted[i] += "(" + strconv.Itoa(length) + ")"
+		}
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// keyList renders the columns of a primary key or unique constraint, taking MySQL
+// prefix lengths from the index of the same name that backs it
+func (b *ddlBuilder) keyList(table *dbo.Table, name string, columns []*dbo.Column) string {
+	if b.dialect == SQLDialectMySQL {
+		for _, idx := range table.Indexes() {
+			if idx.Name() == name && idx.PrefixLengths() != nil && len(idx.Columns()) == len(columns) {
+				return b.indexKeyList(idx)
+			}
+		}
+	}
+	return b.identList(columns)
+}
+
+func (b *ddlBuilder) writeSchema(schema *dbo.Schema) {
+	if b.dialect == SQLDialectMySQL {
+		b.statement("CREATE DATABASE IF NOT EXISTS " + b.ident(schema.Name()))
+		return
+	}
+	b.statement("CREATE SCHEMA IF NOT EXISTS " + b.ident(schema.Name()))
+}
+
+// writeEnum writes a PostgreSQL CREATE TYPE ... AS ENUM statement
+func (b *ddlBuilder) writeEnum(enum *dbo.Enum) {
+	values := make([]string, len(enum.Values()))
+	for i, value := range enum.Values() {
+		values[i] = sqlStringLiteral(value)
+	}
+	b.statement("CREATE TYPE " + b.qualified(schemaNameOf(enum.Schema()), enum.Name()) + " AS ENUM (" + strings.Join(values, ", ") + ")")
+}
+
+func (b *ddlBuilder) writeSequence(seq *dbo.Sequence) {
+	var sb strings.Builder
+	sb.WriteString("CREATE SEQUENCE ")
+	sb.WriteString(b.qualified(schemaNameOf(seq.Schema()), seq.Name()))
+	sb.WriteString(" START WITH ")
+	sb.WriteString(strconv.FormatInt(seq.StartValue(), 10))
+	sb.WriteString(" INCREMENT BY ")
+	sb.WriteString(strconv.FormatInt(seq.Increment(), 10))
+	sb.WriteString(" MINVALUE ")
+	sb.WriteString(strconv.FormatInt(seq.MinValue(), 10))
+	sb.WriteString(" MAXVALUE ")
+	sb.WriteString(strconv.FormatInt(seq.MaxValue(), 10))
+	sb.WriteString(" CACHE ")
+	sb.WriteString(strconv.FormatInt(seq.Cache(), 10))
+	if seq.Cycle() {
+		sb.WriteString(" CYCLE")
+	} else {
+		sb.WriteString(" NO CYCLE")
+	}
+	b.statement(sb.String())
+}
+
+// writeTable writes a CREATE TABLE statement with inline column definitions,
+// primary key, unique and check constraints. Foreign keys are added separately
+// so that tables can be created in any order.
+func (b *ddlBuilder) writeTable(table *dbo.Table) {
+	var lines []string
+	for _, col := range sortedColumns(table) {
+		lines = append(lines, "    "+b.columnDefinition(col))
+	}
+
+	if pk := table.PrimaryKey(); pk != nil && len(pk.Columns()) > 0 {
+		if b.dialect == SQLDialectMySQL || pk.Name() == "" {
+			lines = append(lines, "    PRIMARY KEY ("+b.keyList(table, pk.Name(), pk.Columns())+")")
+		} else {
+			lines = append(lines, "    CONSTRAINT "+b.ident(pk.Name())+" PRIMARY KEY ("+b.identList(pk.Columns())+")")
+		}
+	}
+
+	for _, c := range table.Constraints() {
+		switch c.Type() {
+		case dbo.ConstraintTypeUnique:
+			if len(c.Columns()) > 0 {
+				lines = append(lines, "    CONSTRAINT "+b.ident(c.Name())+" UNIQUE ("+b.keyList(table, c.Name(), c.Columns())+")")
+			}
+		case dbo.ConstraintTypeCheck:
+			if c.CheckExpression() != "" {
+				lines = append(lines, "    CONSTRAINT "+b.ident(c.Name())+" CHECK ("+checkExpressionBody(c.CheckExpression())+")")
+			}
+		case dbo.ConstraintTypeNotNull:
+			// Rendered as part of the column definition
+		}
+	}
+
+	// Periods and system versioning are MariaDB features with no PostgreSQL equivalent
+	if b.dialect == SQLDialectMySQL {
+		for _, period := range table.Periods() {
+			// SYSTEM_TIME is a keyword; quoted, it would name an application-time period
+			name := b.ident(period.Name())
+			if period.IsSystemTime() {
+				name = period.Name()
+			}
+			lines = append(lines, "    PERIOD FOR "+name+" ("+b.identList([]*dbo.Column{period.StartColumn(), period.EndColumn()})+")")
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE TABLE ")
+	sb.WriteString(b.qualified(schemaNameOf(table.Schema()), table.Name()))
+	sb.WriteString(" (\n")
+	sb.WriteString(strings.Join(lines, ",\n"))
+	sb.WriteString("\n)")
+	if b.dialect == SQLDialectMySQL {
+		sb.WriteString(mysqlTableOptions(table))
+	}
+	if b.dialect == SQLDialectMySQL && table.IsSystemVersioned() {
+		sb.WriteString(" WITH SYSTEM VERSIONING")
+	}
+	if b.dialect == SQLDialectMySQL && table.Comment() != "" {
+		sb.WriteString(" COMMENT=")
+		sb.WriteString(sqlStringLiteral(table.Comment()))
+	}
+	b.statement(sb.String())
+
+	if b.dialect == SQLDialectPostgres {
+		qualified := b.qualified(schemaNameOf(table.Schema()), table.Name())
+		if table.Comment() != "" {
+			b.statement("COMMENT ON TABLE " + qualified + " IS " + sqlStringLiteral(table.Comment()))
+		}
+		for _, col := range sortedColumns(table) {
+			if col.Comment() != "" {
+				b.statement("COMMENT ON COLUMN " + qualified + "." + b.ident(col.Name()) + " IS " + sqlStringLiteral(col.Comment()))
+			}
+		}
+	}
+}
+
+// columnDefinition renders a single column with its type, default and nullability
+func (b *ddlBuilder) columnDefinition(col *dbo.Column) string {
+	var sb strings.Builder
+	sb.WriteString(b.ident(col.Name()))
+	sb.WriteString(" ")
+	sb.WriteString(formatColumnType(col))
+	if b.dialect == SQLDialectMySQL {
+		sb.WriteString(mysqlColumnCharset(col))
+	}
+	if col.IsGenerated() {
+		sb.WriteString(" GENERATED ALWAYS AS (")
+		sb.WriteString(col.GenerationExpression())
+		sb.WriteString(") ")
+		sb.WriteString(strings.ToUpper(string(col.Generation())))
+	} else if col.DefaultValue() != nil {
+		sb.WriteString(" DEFAULT ")
+		if b.dialect == SQLDialectMySQL {
+			sb.WriteString(mysqlDefaultExpression(*col.DefaultValue()))
+		} else {
+			sb.WriteString(*col.DefaultValue())
+		}
+	}
+	if !col.IsNullable() {
+		sb.WriteString(" NOT NULL")
+	}
+	if b.dialect == SQLDialectMySQL && col.Table() != nil {
+		if period := col.Table().SystemPeriod(); period != nil {
+			switch col {
+			case period.StartColumn():
+				sb.WriteString(" GENERATED ALWAYS AS ROW START")
+			case period.EndColumn():
+				sb.WriteString(" GENERATED ALWAYS AS ROW END")
+			}
+		}
+	}
+	if b.dialect == SQLDialectMySQL && col.IsInvisible() {
+		sb.WriteString(" INVISIBLE")
+	}
+	if b.dialect == SQLDialectMySQL && col.Comment() != "" {
+		sb.WriteString(" COMMENT ")
+		sb.WriteString(sqlStringLiteral(col.Comment()))
+	}
+	return sb.String()
+}
+
+// writeIndex writes a CREATE INDEX statement. Indexes backing the primary key or a
+// unique constraint are skipped since the table definition already creates them.
+func (b *ddlBuilder) writeIndex(table *dbo.Table, idx *dbo.Index) {
+	if idx.IsPrimary() || (len(idx.Columns()) == 0 && len(idx.Expressions()) == 0) {
+		return
+	}
+	for _, c := range table.Constraints() {
+		if c.Type() == dbo.ConstraintTypeUnique && c.Name() == idx.Name() {
+			return
+		}
+	}
+	if len(idx.Expressions()) > 0 {
+		b.comment("Skipped index " + idx.Name() + ": expression keys are not reproduced")
+		return
+	}
+	if idx.IsPartial() && b.dialect == SQLDialectMySQL {
+		b.comment("Skipped index " + idx.Name() + ": MySQL has no partial indexes")
+		return
+	}
+
+	indexType := strings.ToUpper(string(idx.IndexType()))
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	switch {
+	case b.dialect == SQLDialectMySQL && (indexType == "FULLTEXT" || indexType == "SPATIAL"):
+		sb.WriteString(indexType)
+		sb.WriteString(" ")
+	case idx.IsUnique():
+		sb.WriteString("UNIQUE ")
+	}
+	sb.WriteString("INDEX ")
+	sb.WriteString(b.ident(idx.Name()))
+	sb.WriteString(" ON ")
+	sb.WriteString(b.qualified(schemaNameOf(table.Schema()), table.Name()))
+	if b.dialect == SQLDialectMySQL {
+		sb.WriteString(" (")
+		sb.WriteString(b.indexKeyList(idx))
+		sb.WriteString(")")
+		if indexType == "BTREE" || indexType == "HASH" {
+			sb.WriteString(" USING ")
+			sb.WriteString(indexType)
+		}
+		if idx.IsInvisible() {
+			sb.WriteString(" INVISIBLE")
+		}
+	} else {
+		if indexType != "" {
+			sb.WriteString(" USING ")
+			sb.WriteString(strings.ToLower(indexType))
+		}
+		sb.WriteString(" (")
+		sb.WriteString(b.identList(idx.Columns()))
+		sb.WriteString(")")
+		if idx.IsPartial() {
+			sb.WriteString(" WHERE ")
+			sb.WriteString(idx.Predicate())
+		}
+	}
+	b.statement(sb.String())
+}
+
+func (b *ddlBuilder) writeForeignKey(table *dbo.Table, fk *dbo.ForeignKey) {
+	if len(fk.Columns()) == 0 || len(fk.ReferencedColumns()) == 0 {
+		b.comment("Skipped foreign key " + fk.Name() + ": column mapping unavailable")
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString("ALTER TABLE ")
+	sb.WriteString(b.qualified(schemaNameOf(table.Schema()), table.Name()))
+	sb.WriteString(" ADD CONSTRAINT ")
+	sb.WriteString(b.ident(fk.Name()))
+	sb.WriteString(" FOREIGN KEY (")
+	sb.WriteString(b.identList(fk.Columns()))
+	sb.WriteString(") REFERENCES ")
+	sb.WriteString(b.qualified(referencedSchemaName(fk), fk.ReferencedTable()))
+	sb.WriteString(" (")
+	sb.WriteString(b.identList(fk.ReferencedColumns()))
+	sb.WriteString(")")
+	if fk.OnDelete() != "" && fk.OnDelete() != dbo.ActionNoAction {
+		sb.WriteString(" ON DELETE ")
+		sb.WriteString(string(fk.OnDelete()))
+	}
+	if fk.OnUpdate() != "" && fk.OnUpdate() != dbo.ActionNoAction {
+		sb.WriteString(" ON UPDATE ")
+		sb.WriteString(string(fk.OnUpdate()))
+	}
+	b.statement(sb.String())
+}
+
+func (b *ddlBuilder) writeView(view *dbo.View) {
+	definition := trimStatement(view.Definition())
+	if definition == "" {
+		b.comment("Skipped view " + view.FullyQualifiedName() + ": definition unavailable")
+		return
+	}
+	b.statement("CREATE VIEW " + b.qualified(schemaNameOf(view.Schema()), view.Name()) + " AS\n" + definition)
+}
+
+// writeFunction writes a function. PostgreSQL definitions are complete CREATE
+// statements; MySQL only exposes the routine body, so the header is rebuilt.
+func (b *ddlBuilder) writeFunction(fn *dbo.Function) {
+	definition := trimStatement(fn.Definition())
+	if definition == "" {
+		b.comment("Skipped function " + fn.FullyQualifiedName() + ": definition unavailable")
+		return
+	}
+	if startsWithCreate(definition) {
+		b.compoundStatement(definition)
+		return
+	}
+	header := "CREATE FUNCTION " + b.qualified(schemaNameOf(fn.Schema()), fn.Name()) + "(" + b.parameterList(fn.Parameters(), false) + ")"
+	if fn.ReturnType() != "" {
+		header += " RETURNS " + fn.ReturnType()
+	}
+	header += routineCharacteristics(fn.IsDeterministic(), fn.SQLDataAccess(), fn.SecurityType())
+	b.compoundStatement(header + "\n" + definition)
+}
+
+func (b *ddlBuilder) writeProcedure(proc *dbo.Procedure) {
+	definition := trimStatement(proc.Definition())
+	if definition == "" {
+		b.comment("Skipped procedure " + proc.FullyQualifiedName() + ": definition unavailable")
+		return
+	}
+	if startsWithCreate(definition) {
+		b.compoundStatement(definition)
+		return
+	}
+	header := "CREATE PROCEDURE " + b.qualified(schemaNameOf(proc.Schema()), proc.Name()) + "(" + b.parameterList(proc.Parameters(), true) + ")"
+	header += routineCharacteristics(proc.IsDeterministic(), proc.SQLDataAccess(), proc.SecurityType())
+	b.compoundStatement(header + "\n" + definition)
+}
+
+// routineCharacteristics renders the characteristics of a MySQL routine that differ
+// from the defaults: NOT DETERMINISTIC, CONTAINS SQL and SQL SECURITY DEFINER. The
+// definer is left out so the script can be run by any account.
+func routineCharacteristics(deterministic bool, sqlDataAccess string, securityType dbo.SecurityType) string {
+	var sb strings.Builder
+	if deterministic {
+		sb.WriteString(" DETERMINISTIC")
+	}
+	if sqlDataAccess != "" && sqlDataAccess != "CONTAINS SQL" {
+		sb.WriteString(" " + sqlDataAccess)
+	}
+	if securityType == dbo.SecurityInvoker {
+		sb.WriteString(" SQL SECURITY INVOKER")
+	}
+	return sb.String()
+}
+
+// parameterList renders routine parameters. MySQL functions only accept IN parameters
+// and do not allow the mode keyword.
+func (b *ddlBuilder) parameterList(params []*dbo.FunctionParameter, withMode bool) string {
+	rendered := make([]string, len(params))
+	for i, p := range params {
+		var sb strings.Builder
+		if withMode && p.Mode() != "" {
+			sb.WriteString(string(p.Mode()))
+			sb.WriteString(" ")
+		}
+		if p.Name() != "" {
+			sb.WriteString(b.ident(p.Name()))
+			sb.WriteString(" ")
+		}
+		sb.WriteString(p.DataType())
+		rendered[i] = sb.String()
+	}
+	return strings.Join(rendered, ", ")
+}
+
+// writeTrigger writes a CREATE TRIGGER statement. The mapped definition holds the
+// triggered action (EXECUTE FUNCTION ... for PostgreSQL, the body for MySQL).
+func (b *ddlBuilder) writeTrigger(table *dbo.Table, trigger *dbo.Trigger) {
+	definition := trimStatement(trigger.Definition())
+	if definition == "" && trigger.Function() != nil {
+		definition = "EXECUTE FUNCTION " + trigger.Function().FullyQualifiedName() + "()"
+	}
+	if definition == "" || len(trigger.Events()) == 0 {
+		b.comment("Skipped trigger " + trigger.Name() + ": definition unavailable")
+		return
+	}
+
+	events := make([]string, len(trigger.Events()))
+	for i, e := range trigger.Events() {
+		events[i] = string(e)
+	}
+	forEach := trigger.ForEach()
+	if forEach == "" {
+		forEach = "ROW"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE TRIGGER ")
+	if b.dialect == SQLDialectMySQL {
+		sb.WriteString(b.qualified(schemaNameOf(table.Schema()), trigger.Name()))
+	} else {
+		sb.WriteString(b.ident(trigger.Name()))
+	}
+	sb.WriteString(" ")
+	sb.WriteString(string(trigger.Timing()))
+	sb.WriteString(" ")
+	sb.WriteString(strings.Join(events, " OR "))
+	sb.WriteString(" ON ")
+	sb.WriteString(b.qualified(schemaNameOf(table.Schema()), table.Name()))
+	sb.WriteString(" FOR EACH ")
+	sb.WriteString(forEach)
+	sb.WriteString("\n")
+	sb.WriteString(definition)
+	b.compoundStatement(sb.String())
+}
+
+// hasEnums reports whether any of the schemas declares an enum type
+func hasEnums(schemas []*dbo.Schema) bool {
+	for _, schema := range schemas {
+		if len(schema.Enums()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// orderViewsByDependency orders views so that every view comes after the views its
+// definition refers to. Independent views keep their input order and reference
+// cycles are broken in input order.
+func orderViewsByDependency(views []*dbo.View) []*dbo.View {
+	deps := make(map[*dbo.View][]*dbo.View, len(views))
+	for _, v := range views {
+		for _, other := range views {
+			if other != v && referencesName(v.Definition(), other.Name()) {
+				deps[v] = append(deps[v], other)
+			}
+		}
+	}
+
+	ordered := make([]*dbo.View, 0, len(views))
+	state := make(map[*dbo.View]int, len(views)) // 0 = new, 1 = visiting, 2 = done
+	var visit func(v *dbo.View)
+	visit = func(v *dbo.View) {
+		if state[v] != 0 {
+			return
+		}
+		state[v] = 1
+		for _, dep := range deps[v] {
+			visit(dep)
+		}
+		state[v] = 2
+		ordered = append(ordered, v)
+	}
+	for _, v := range views {
+		visit(v)
+	}
+	return ordered
+}
+
+// referencesName reports whether an SQL text mentions an identifier as a whole word
+func referencesName(sqlText, name string) bool {
+	if name == "" {
+		return false
+	}
+	pattern := `(?i)(^|[^A-Za-z0-9_$])` + regexp.QuoteMeta(name) + `($|[^A-Za-z0-9_$])`
+	matched, err := regexp.MatchString(pattern, sqlText)
+	return err == nil && matched
+}
+
+// mysqlTableOptions renders the storage engine, default character set, collation and
+// row format of a MySQL table in the order SHOW CREATE TABLE uses
+func mysqlTableOptions(table *dbo.Table) string {
+	var sb strings.Builder
+	if table.Engine() != "" {
+		sb.WriteString(" ENGINE=" + table.Engine())
+	}
+	if table.Charset() != "" {
+		sb.WriteString(" DEFAULT CHARSET=" + table.Charset())
+	}
+	if table.Collation() != "" {
+		sb.WriteString(" COLLATE=" + table.Collation())
+	}
+	if table.RowFormat() != "" {
+		sb.WriteString(" ROW_FORMAT=" + table.RowFormat())
+	}
+	return sb.String()
+}
+
+// mysqlColumnCharset renders the character set and collation of a MySQL column where
+// they differ from the defaults of its table
+func mysqlColumnCharset(col *dbo.Column) string {
+	var tableCharset, tableCollation string
+	if col.Table() != nil {
+		tableCharset, tableCollation = col.Table().Charset(), col.Table().Collation()
+	}
+	if col.Collation() == "" || strings.EqualFold(col.Collation(), tableCollation) {
+		if col.Charset() == "" || strings.EqualFold(col.Charset(), tableCharset) {
+			return ""
+		}
+		return " CHARACTER SET " + col.Charset()
+	}
+	if col.Charset() == "" {
+		return " COLLATE " + col.Collation()
+	}
+	return " CHARACTER SET " + col.Charset() + " COLLATE " + col.Collation()
+}
+
+// mysqlDefaultExpression renders a MySQL COLUMN_DEFAULT value. information_schema
+// reports string defaults unquoted, so anything that is not a number, NULL, a
+// temporal keyword or a parenthesised expression is quoted. The MySQL adapter maps
+// expression defaults in parentheses, so they are kept as expressions.
+func mysqlDefaultExpression(def string) string {
+	value := strings.TrimSpace(def)
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	upper := strings.ToUpper(value)
+	switch {
+	case upper == "NULL", upper == "TRUE", upper == "FALSE":
+		return upper
+	case strings.HasPrefix(upper, "CURRENT_TIMESTAMP"), strings.HasPrefix(upper, "NOW("),
+		upper == "CURRENT_DATE", upper == "CURRENT_TIME", strings.HasPrefix(upper, "LOCALTIMESTAMP"):
+		return value
+	case strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")"):
+		return value
+	case strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) >= 2:
+		return value
+	}
+	return sqlStringLiteral(value)
+}
+
+// sqlStringLiteral returns a single-quoted SQL string literal
+func sqlStringLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// trimStatement removes surrounding whitespace and a trailing semicolon
+func trimStatement(sqlText string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(sqlText), ";"))
+}
+
+// startsWithCreate reports whether an SQL text is already a complete CREATE statement
+func startsWithCreate(sqlText string) bool {
+	return len(sqlText) >= 6 && strings.EqualFold(sqlText[:6], "CREATE")
+}
@@ -0,0 +1,429 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// newDDLTestDatabase extends the diagram fixture with a sequence, views, a function and a trigger
+func newDDLTestDatabase(engine string) *dbo.Database {
+	db := newDiagramTestDatabase()
+	db.SetEngine(engine)
+	schema := db.Schemas()["public"]
+
+	schema.AddSequence(dbo.NewSequence("orders_id_seq", 1, 1))
+
+	// active_users depends on recent_users, which sorts after it by name
+	schema.AddView(dbo.NewView("active_users", " SELECT id FROM recent_users WHERE status = 'active';"))
+	schema.AddView(dbo.NewView("recent_users", " SELECT id, status FROM users;"))
+
+	fn := dbo.NewFunction("touch", "CREATE OR REPLACE FUNCTION public.touch()\n RETURNS trigger\n LANGUAGE plpgsql\nAS $function$ BEGIN RETURN NEW; END; $function$\n")
+	fn.SetReturnType("trigger")
+	schema.AddFunction(fn)
+
+	trigger := dbo.NewTrigger("users_touch", "EXECUTE FUNCTION touch()")
+	trigger.SetTiming(dbo.TriggerTimingBefore)
+	trigger.AddEvent(dbo.TriggerEventInsert)
+	trigger.AddEvent(dbo.TriggerEventUpdate)
+	schema.Tables()["users"].AddTrigger(trigger)
+
+	return db
+}
+
+func TestSQLReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes sql file in the dialect of the mapped engine", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "test.sql")
+
+		writer := &SQLReportWriter{}
+		err := writer.WriteInventoryReport(filePath, newDDLTestDatabase("MySQL"))
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+
+		if !strings.Contains(string(content), "CREATE TABLE `public`.`users`") {
+			t.Errorf("expected MySQL quoting, got %s", string(content))
+		}
+	})
+
+	t.Run("explicit dialect overrides engine", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "test.sql")
+
+		writer := &SQLReportWriter{Dialect: SQLDialectPostgres}
+		if err := writer.WriteInventoryReport(filePath, newDDLTestDatabase("MySQL")); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, _ := os.ReadFile(filePath)
+		if !strings.Contains(string(content), "CREATE TABLE \"public\".\"users\"") {
+			t.Errorf("expected PostgreSQL quoting, got %s", string(content))
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &SQLReportWriter{}
+		db := dbo.NewDatabase("testdb", nil)
+
+		err := writer.WriteInventoryReport("/nonexistent/path/file.sql", db)
+
+		if err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+}
+
+func TestGenerateSQLDDL_Postgres(t *testing.T) {
+	result := GenerateSQLDDL(newDDLTestDatabase("PostgreSQL"), SQLDialectPostgres)
+
+	expectations := []string{
+		"CREATE SCHEMA IF NOT EXISTS \"public\";",
+		"CREATE SEQUENCE \"public\".\"orders_id_seq\" START WITH 1 INCREMENT BY 1",
+		"CREATE TABLE \"public\".\"users\" (\n    \"id\" integer NOT NULL,\n    \"email\" character varying(255) NOT NULL,\n    \"status\" text DEFAULT 'active'::text,\n    CONSTRAINT \"users_pkey\" PRIMARY KEY (\"id\"),\n    CONSTRAINT \"users_status_check\" CHECK (status <> ''::text)\n);",
+		"COMMENT ON TABLE \"public\".\"users\" IS 'registered accounts';",
+		"CREATE UNIQUE INDEX \"users_email_key\" ON \"public\".\"users\" USING btree (\"email\");",
+		"ALTER TABLE \"public\".\"orders\" ADD CONSTRAINT \"orders_user_fk\" FOREIGN KEY (\"user_id\") REFERENCES \"public\".\"users\" (\"id\") ON DELETE CASCADE;",
+		"ALTER TABLE \"public\".\"profiles\" ADD CONSTRAINT \"profiles_user_fk\" FOREIGN KEY (\"user_id\") REFERENCES \"public\".\"users\" (\"id\");",
+		"CREATE VIEW \"public\".\"recent_users\" AS\nSELECT id, status FROM users;",
+		"$function$;\n",
+		"CREATE TRIGGER \"users_touch\" BEFORE INSERT OR UPDATE ON \"public\".\"users\" FOR EACH ROW\nEXECUTE FUNCTION touch();",
+	}
+	for _, expected := range expectations {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+
+	t.Run("statements are dependency ordered", func(t *testing.T) {
+		order := []string{
+			"SET check_function_bodies = false;",
+			"CREATE SCHEMA",
+			"CREATE SEQUENCE",
+			"CREATE OR REPLACE FUNCTION",
+			"CREATE TABLE \"public\".\"orders\"",
+			"CREATE TABLE \"public\".\"users\"",
+			"CREATE UNIQUE INDEX",
+			"ALTER TABLE",
+			"CREATE VIEW \"public\".\"recent_users\"",
+			"CREATE VIEW \"public\".\"active_users\"",
+			"CREATE TRIGGER",
+		}
+		last := -1
+		for _, stmt := range order {
+			idx := strings.Index(result, stmt)
+			if idx <= last {
+				t.Errorf("expected %q after previous statement (index %d, previous %d)", stmt, idx, last)
+			}
+			last = idx
+		}
+	})
+}
+
+func TestGenerateSQLDDL_PartialAndExpressionIndexes(t *testing.T) {
+	db := newDDLTestDatabase("PostgreSQL")
+	users := db.Schemas()["public"].Tables()["users"]
+	partial := dbo.NewIndex("users_status_idx", users, []*dbo.Column{users.Columns()["status"]}, false)
+	partial.SetPredicate("status IS NOT NULL")
+	users.AddIndex(partial)
+	expression := dbo.NewIndex("users_lower_email_idx", users, nil, true)
+	expression.AddExpression("lower(email)")
+	users.AddIndex(expression)
+
+	postgres := GenerateSQLDDL(db, SQLDialectPostgres)
+	for _, expected := range []string{
+		"CREATE INDEX \"users_status_idx\" ON \"public\".\"users\" USING btree (\"status\") WHERE status IS NOT NULL;",
+		"-- Skipped index users_lower_email_idx: expression keys are not reproduced",
+	} {
+		if !strings.Contains(postgres, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, postgres)
+		}
+	}
+
+	if mysql := GenerateSQLDDL(db, SQLDialectMySQL); !strings.Contains(mysql, "-- Skipped index users_status_idx: MySQL has no partial indexes") {
+		t.Errorf("expected the partial index to be skipped for MySQL, got:\n%s", mysql)
+	}
+}
+
+func TestGenerateSQLDDL_Enums(t *testing.T) {
+	db := newDDLTestDatabase("PostgreSQL")
+	schema := db.Schemas()["public"]
+	schema.AddEnum(dbo.NewEnum("mood", []string{"happy", "it's fine"}))
+	schema.Tables()["users"].AddColumn(dbo.NewColumn("mood", "public.mood", true))
+
+	result := GenerateSQLDDL(db, SQLDialectPostgres)
+	create := "CREATE TYPE \"public\".\"mood\" AS ENUM ('happy', 'it''s fine');"
+	if !strings.Contains(result, create) {
+		t.Fatalf("expected %q in:\n%s", create, result)
+	}
+	if strings.Index(result, create) > strings.Index(result, "CREATE TABLE") {
+		t.Error("expected enum types before tables")
+	}
+
+	if strings.Contains(GenerateSQLDDL(newDDLTestDatabase("PostgreSQL"), SQLDialectPostgres), "-- Types") {
+		t.Error("expected no types section without enums")
+	}
+	if strings.Contains(GenerateSQLDDL(db, SQLDialectMySQL), "CREATE TYPE") {
+		t.Error("expected no CREATE TYPE in the MySQL dialect")
+	}
+}
+
+func TestGenerateSQLDDL_MySQL(t *testing.T) {
+	db := dbo.NewDatabase("shop", nil)
+	db.SetEngine("MySQL")
+	schema := dbo.NewSchema("shop", "", nil)
+	db.AddSchema(schema)
+
+	table := dbo.NewTable("products", nil)
+	id := dbo.NewColumn("id", "int", false)
+	id.SetOrdinalPosition(1)
+	name := dbo.NewColumn("name", "varchar", false)
+	name.SetOrdinalPosition(2)
+	name.SetCharMaxLength(100)
+	name.SetDefaultValue("unnamed")
+	name.SetComment("display name")
+	table.AddColumn(id)
+	table.AddColumn(name)
+	table.SetPrimaryKey(dbo.NewPrimaryKey("PRIMARY", table, []*dbo.Column{id}))
+	primary := dbo.NewIndex("PRIMARY", table, []*dbo.Column{id}, true)
+	primary.SetPrimary(true)
+	table.AddIndex(primary)
+	unique := dbo.NewConstraint("products_name_key", dbo.ConstraintTypeUnique)
+	unique.AddColumn(name)
+	table.AddConstraint(unique)
+	table.AddIndex(dbo.NewIndex("products_name_key", table, []*dbo.Column{name}, true))
+	fulltext := dbo.NewIndex("products_name_ft", table, []*dbo.Column{name}, false)
+	fulltext.SetIndexType("FULLTEXT")
+	table.AddIndex(fulltext)
+	notNull := dbo.NewConstraint("products_id_not_null", dbo.ConstraintTypeNotNull)
+	notNull.AddColumn(id)
+	table.AddConstraint(notNull)
+	schema.AddTable(table)
+
+	fn := dbo.NewFunction("double_it", "BEGIN RETURN x * 2; END")
+	fn.SetReturnType("int")
+	fn.AddParameter(dbo.NewFunctionParameter("x", "int", dbo.ParameterModeIn))
+	schema.AddFunction(fn)
+
+	proc := dbo.NewProcedure("archive", "BEGIN DELETE FROM products WHERE id = p_id; END")
+	proc.AddParameter(dbo.NewFunctionParameter("p_id", "int", dbo.ParameterModeIn))
+	proc.SetSecurityType(dbo.SecurityInvoker)
+	proc.SetDefiner("'root'@'localhost'")
+	proc.SetDeterministic(true)
+	proc.SetSQLDataAccess("MODIFIES SQL DATA")
+	schema.AddProcedure(proc)
+
+	result := GenerateSQLDDL(db, SQLDialectMySQL)
+
+	expectations := []string{
+		"CREATE DATABASE IF NOT EXISTS `shop`;",
+		"CREATE TABLE `shop`.`products` (\n    `id` int NOT NULL,\n    `name` varchar(100) DEFAULT 'unnamed' NOT NULL COMMENT 'display name',\n    PRIMARY KEY (`id`),\n    CONSTRAINT `products_name_key` UNIQUE (`name`)\n);",
+		"CREATE FULLTEXT INDEX `products_name_ft` ON `shop`.`products` (`name`);",
+		"DELIMITER $$\nCREATE FUNCTION `shop`.`double_it`(`x` int) RETURNS int\nBEGIN RETURN x * 2; END $$\nDELIMITER ;",
+		"CREATE PROCEDURE `shop`.`archive`(IN `p_id` int) DETERMINISTIC MODIFIES SQL DATA SQL SECURITY INVOKER\nBEGIN",
+	}
+	for _, expected := range expectations {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "check_function_bodies") {
+		t.Error("expected no PostgreSQL settings in the MySQL dialect")
+	}
+	if strings.Contains(result, "CREATE UNIQUE INDEX `products_name_key`") {
+		t.Error("expected index backing a unique constraint to be skipped")
+	}
+	if strings.Contains(result, "INDEX `PRIMARY`") {
+		t.Error("expected primary index to be skipped")
+	}
+}
+
+func TestGenerateSQLDDL_SystemVersioning(t *testing.T) {
+	db := dbo.NewDatabase("shop", nil)
+	db.SetEngine("MariaDB")
+	schema := dbo.NewSchema("shop", "", nil)
+	db.AddSchema(schema)
+
+	table := dbo.NewTable("prices", nil)
+	amount := dbo.NewColumn("amount", "int", false)
+	amount.SetOrdinalPosition(1)
+	start := dbo.NewColumn("row_start", "timestamp", false)
+	start.SetOrdinalPosition(2)
+	end := dbo.NewColumn("row_end", "timestamp", false)
+	end.SetOrdinalPosition(3)
+	table.AddColumn(amount)
+	table.AddColumn(start)
+	table.AddColumn(end)
+	table.AddPeriod(dbo.NewPeriod(dbo.PeriodSystemTime, start, end))
+	table.SetSystemVersioned(true)
+	schema.AddTable(table)
+
+	bookings := dbo.NewTable("bookings", nil)
+	from := dbo.NewColumn("from", "date", false)
+	from.SetOrdinalPosition(1)
+	to := dbo.NewColumn("to", "date", false)
+	to.SetOrdinalPosition(2)
+	bookings.AddColumn(from)
+	bookings.AddColumn(to)
+	bookings.AddPeriod(dbo.NewPeriod("stay period", from, to))
+	schema.AddTable(bookings)
+
+	result := GenerateSQLDDL(db, SQLDialectMySQL)
+	expectations := []string{
+		"CREATE TABLE `shop`.`prices` (\n    `amount` int NOT NULL,\n    `row_start` timestamp NOT NULL GENERATED ALWAYS AS ROW START,\n    `row_end` timestamp NOT NULL GENERATED ALWAYS AS ROW END,\n    PERIOD FOR SYSTEM_TIME (`row_start`, `row_end`)\n) WITH SYSTEM VERSIONING;",
+		"    PERIOD FOR `stay period` (`from`, `to`)\n)",
+	}
+	for _, expected := range expectations {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+
+	if postgres := GenerateSQLDDL(db, SQLDialectPostgres); strings.Contains(postgres, "SYSTEM") {
+		t.Errorf("expected no system versioning in the PostgreSQL dialect, got:\n%s", postgres)
+	}
+}
+
+func TestGenerateSQLDDL_MySQLTableOptions(t *testing.T) {
+	db := dbo.NewDatabase("shop", nil)
+	db.SetEngine("MySQL")
+	schema := dbo.NewSchema("shop", "", nil)
+	db.AddSchema(schema)
+
+	table := dbo.NewTable("sessions", nil)
+	table.SetEngine("MyISAM")
+	table.SetCharset("utf8mb4")
+	table.SetCollation("utf8mb4_0900_ai_ci")
+	table.SetRowFormat("DYNAMIC")
+	token := dbo.NewColumn("token", "varchar(64)", false)
+	token.SetOrdinalPosition(1)
+	token.SetCharset("ascii")
+	token.SetCollation("ascii_bin")
+	data := dbo.NewColumn("data", "text", true)
+	data.SetOrdinalPosition(2)
+	data.SetCharset("utf8mb4")
+	data.SetCollation("utf8mb4_0900_ai_ci")
+	table.AddColumn(token)
+	table.AddColumn(data)
+	schema.AddTable(table)
+
+	result := GenerateSQLDDL(db, SQLDialectMySQL)
+	create := "CREATE TABLE `shop`.`sessions` (\n    `token` varchar(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,\n    `data` text\n) ENGINE=MyISAM DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC;"
+	if !strings.Contains(result, create) {
+		t.Errorf("expected output to contain %q, got:\n%s", create, result)
+	}
+
+	if postgres := GenerateSQLDDL(db, SQLDialectPostgres); strings.Contains(postgres, "ENGINE") || strings.Contains(postgres, "COLLATE") {
+		t.Errorf("expected no MySQL table options in the PostgreSQL dialect, got:\n%s", postgres)
+	}
+}
+
+func TestGenerateSQLDDL_MySQLGeneratedColumnsAndPrefixKeys(t *testing.T) {
+	db := dbo.NewDatabase("shop", nil)
+	db.SetEngine("MySQL")
+	schema := dbo.NewSchema("shop", "", nil)
+	db.AddSchema(schema)
+
+	table := dbo.NewTable("people", nil)
+	name := dbo.NewColumn("name", "text", false)
+	name.SetOrdinalPosition(1)
+	initial := dbo.NewColumn("initial", "char(1)", true)
+	initial.SetOrdinalPosition(2)
+	initial.SetGenerated(dbo.GenerationStored, "left(`name`,1)")
+	initial.SetInvisible(true)
+	table.AddColumn(name)
+	table.AddColumn(initial)
+	pk := dbo.NewIndex("PRIMARY", table, nil, true)
+	pk.SetPrimary(true)
+	pk.AddPrefixColumn(name, 20)
+	table.AddIndex(pk)
+	table.SetPrimaryKey(dbo.NewPrimaryKey("PRIMARY", table, []*dbo.Column{name}))
+	idx := dbo.NewIndex("idx_initial", table, nil, false)
+	idx.SetIndexType("BTREE")
+	idx.AddColumn(initial)
+	idx.AddPrefixColumn(name, 5)
+	idx.SetInvisible(true)
+	table.AddIndex(idx)
+	schema.AddTable(table)
+
+	result := GenerateSQLDDL(db, SQLDialectMySQL)
+	for _, expected := range []string{
+		"    `initial` char(1) GENERATED ALWAYS AS (left(`name`,1)) STORED INVISIBLE,\n",
+		"    PRIMARY KEY (`name`(20))\n",
+		"CREATE INDEX `idx_initial` ON `shop`.`people` (`initial`, `name`(5)) USING BTREE INVISIBLE;",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+}
+
+func TestSQLDialectForEngine(t *testing.T) {
+	tests := []struct {
+		engine   string
+		expected SQLDialect
+	}{
+		{"PostgreSQL", SQLDialectPostgres},
+		{"MySQL", SQLDialectMySQL},
+		{"MariaDB", SQLDialectMySQL},
+		{"", SQLDialectPostgres},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine, func(t *testing.T) {
+			if result := SQLDialectForEngine(tt.engine); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestOrderViewsByDependency(t *testing.T) {
+	a := dbo.NewView("a_report", "SELECT * FROM c_base JOIN b_mid USING (id)")
+	b := dbo.NewView("b_mid", "SELECT * FROM c_base")
+	c := dbo.NewView("c_base", "SELECT * FROM users")
+	cycleX := dbo.NewView("x", "SELECT * FROM y")
+	cycleY := dbo.NewView("y", "SELECT * FROM x")
+
+	result := orderViewsByDependency([]*dbo.View{a, b, c, cycleX, cycleY})
+
+	names := make([]string, len(result))
+	for i, v := range result {
+		names[i] = v.Name()
+	}
+	expected := []string{"c_base", "b_mid", "a_report", "y", "x"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestMySQLDefaultExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0", "0"},
+		{"NULL", "NULL"},
+		{"CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP"},
+		{"(uuid())", "(uuid())"},
+		{"active", "'active'"},
+		{"it's", "'it''s'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := mysqlDefaultExpression(tt.input); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
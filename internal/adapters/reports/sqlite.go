@@ -0,0 +1,388 @@
+package reports
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+	"github.com/jimbot9k/norman/internal/core/rules"
+	"github.com/jimbot9k/norman/internal/version"
+
+	// Pure-Go SQLite driver, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteReportWriter writes the inventory into a normalized SQLite database so it can be
+// queried with plain SQL. Rows reference each other through the same stable IDs as the
+// CSV inventory (e.g. "public.users.id").
+type SQLiteReportWriter struct {
+	findings []rules.Finding
+}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *SQLiteReportWriter) GetReportKeys() []string {
+	return []string{"sqlite"}
+}
+
+// GetReportFileExtension returns the file extension for SQLite reports
+func (w *SQLiteReportWriter) GetReportFileExtension() string {
+	return "sqlite"
+}
+
+// GetReportName returns the name of the SQLite report
+func (w *SQLiteReportWriter) GetReportName() string {
+	return "SQLite Inventory"
+}
+
+// SetFindings records the findings of the audit rules, which are written to the
+// findings table
+func (w *SQLiteReportWriter) SetFindings(findings []rules.Finding) {
+	w.findings = findings
+}
+
+// WriteInventoryReport writes the inventory to a new SQLite database at filePath,
+// replacing any database already there
+func (w *SQLiteReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return WriteSQLiteInventory(filePath, GenerateSQLiteInventory(db, w.findings, time.Now()))
+}
+
+// SQLiteTable is a single table of the SQLite inventory
+type SQLiteTable struct {
+	Name string
+	// Columns holds the column definitions, e.g. "name TEXT NOT NULL"
+	Columns []string
+	Rows    [][]any
+}
+
+// columnNames returns the bare column names of the table definition
+func (t *SQLiteTable) columnNames() []string {
+	names := make([]string, len(t.Columns))
+	for i, def := range t.Columns {
+		names[i], _, _ = strings.Cut(def, " ")
+	}
+	return names
+}
+
+// WriteSQLiteInventory creates the tables in the SQLite database at path and inserts
+// their rows in a single transaction
+func WriteSQLiteInventory(path string, tables []*SQLiteTable) error {
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database %s: %w", path, err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", table.Name, strings.Join(table.Columns, ", "))); err != nil {
+			return fmt.Errorf("failed to create table %s: %w", table.Name, err)
+		}
+		if len(table.Rows) == 0 {
+			continue
+		}
+		names := table.columnNames()
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+		insert, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table.Name, strings.Join(names, ", "), placeholders))
+		if err != nil {
+			return fmt.Errorf("failed to prepare insert into %s: %w", table.Name, err)
+		}
+		for _, row := range table.Rows {
+			if _, err := insert.Exec(row...); err != nil {
+				insert.Close()
+				return fmt.Errorf("failed to insert into %s: %w", table.Name, err)
+			}
+		}
+		insert.Close()
+	}
+	return tx.Commit()
+}
+
+// GenerateSQLiteInventory builds the normalized inventory tables for a database and the
+// findings of the audit rules. Tables are returned in a fixed order and rows are ordered
+// by schema and object name; findings keep their order.
+func GenerateSQLiteInventory(db *dbo.Database, findings []rules.Finding, generatedAt time.Time) []*SQLiteTable {
+	metadata := &SQLiteTable{Name: "metadata", Columns: []string{"key TEXT PRIMARY KEY", "value TEXT"}}
+	schemas := &SQLiteTable{Name: "schemas", Columns: []string{
+		"schema_id TEXT PRIMARY KEY", "name TEXT NOT NULL", "owner TEXT",
+	}}
+	tables := &SQLiteTable{Name: "tables", Columns: []string{
+		"table_id TEXT PRIMARY KEY", "schema_id TEXT NOT NULL REFERENCES schemas (schema_id)", "name TEXT NOT NULL",
+		"primary_key TEXT", "engine TEXT", "row_format TEXT", "charset TEXT", "collation TEXT", "comment TEXT",
+	}}
+	columns := &SQLiteTable{Name: "columns", Columns: []string{
+		"column_id TEXT PRIMARY KEY", "table_id TEXT NOT NULL REFERENCES tables (table_id)", "name TEXT NOT NULL",
+		"ordinal_position INTEGER", "data_type TEXT", "is_nullable INTEGER NOT NULL", "default_value TEXT",
+		"char_max_length INTEGER", "numeric_precision INTEGER", "numeric_scale INTEGER",
+		"is_primary_key INTEGER NOT NULL", "charset TEXT", "collation TEXT", "generation TEXT",
+		"generation_expression TEXT", "is_invisible INTEGER NOT NULL", "comment TEXT",
+	}}
+	indexes := &SQLiteTable{Name: "indexes", Columns: []string{
+		"index_id TEXT PRIMARY KEY", "table_id TEXT NOT NULL REFERENCES tables (table_id)", "name TEXT NOT NULL",
+		"index_type TEXT", "is_unique INTEGER NOT NULL", "is_primary INTEGER NOT NULL", "predicate TEXT",
+		"expressions TEXT", "is_invisible INTEGER NOT NULL",
+	}}
+	indexColumns := &SQLiteTable{Name: "index_columns", Columns: []string{
+		"index_id TEXT NOT NULL REFERENCES indexes (index_id)", "position INTEGER NOT NULL", "column_id TEXT NOT NULL",
+		"prefix_length INTEGER",
+	}}
+	foreignKeys := &SQLiteTable{Name: "foreign_keys", Columns: []string{
+		"fk_id TEXT PRIMARY KEY", "table_id TEXT NOT NULL REFERENCES tables (table_id)", "name TEXT NOT NULL",
+		"referenced_table_id TEXT NOT NULL", "on_delete TEXT", "on_update TEXT",
+	}}
+	fkColumns := &SQLiteTable{Name: "fk_columns", Columns: []string{
+		"fk_id TEXT NOT NULL REFERENCES foreign_keys (fk_id)", "position INTEGER NOT NULL", "column_id TEXT NOT NULL",
+		"referenced_column_id TEXT",
+	}}
+	constraints := &SQLiteTable{Name: "constraints", Columns: []string{
+		"constraint_id TEXT PRIMARY KEY", "table_id TEXT NOT NULL REFERENCES tables (table_id)", "name TEXT NOT NULL",
+		"type TEXT NOT NULL", "check_expression TEXT",
+	}}
+	constraintColumns := &SQLiteTable{Name: "constraint_columns", Columns: []string{
+		"constraint_id TEXT NOT NULL REFERENCES constraints (constraint_id)", "position INTEGER NOT NULL", "column_id TEXT NOT NULL",
+	}}
+	triggers := &SQLiteTable{Name: "triggers", Columns: []string{
+		"trigger_id TEXT PRIMARY KEY", "table_id TEXT NOT NULL REFERENCES tables (table_id)", "name TEXT NOT NULL",
+		"timing TEXT", "events TEXT", "for_each TEXT", "routine_id TEXT", "definition TEXT",
+	}}
+	views := &SQLiteTable{Name: "views", Columns: []string{
+		"view_id TEXT PRIMARY KEY", "schema_id TEXT NOT NULL REFERENCES schemas (schema_id)", "name TEXT NOT NULL",
+		"definition TEXT",
+	}}
+	sequences := &SQLiteTable{Name: "sequences", Columns: []string{
+		"sequence_id TEXT PRIMARY KEY", "schema_id TEXT NOT NULL REFERENCES schemas (schema_id)", "name TEXT NOT NULL",
+		"start_value INTEGER", "increment INTEGER", "min_value INTEGER", "max_value INTEGER", "cache INTEGER",
+		"cycle INTEGER NOT NULL",
+	}}
+	routines := &SQLiteTable{Name: "routines", Columns: []string{
+		"routine_id TEXT NOT NULL", "schema_id TEXT NOT NULL REFERENCES schemas (schema_id)", "name TEXT NOT NULL",
+		"kind TEXT NOT NULL", "language TEXT", "return_type TEXT", "security_type TEXT", "definer TEXT",
+		"is_deterministic INTEGER NOT NULL", "sql_data_access TEXT", "sql_mode TEXT", "definition TEXT",
+	}}
+	// MySQL allows a function and a procedure to share a name, so routines are keyed by ID and kind
+	routineParameters := &SQLiteTable{Name: "routine_parameters", Columns: []string{
+		"routine_id TEXT NOT NULL", "kind TEXT NOT NULL", "position INTEGER NOT NULL", "name TEXT",
+		"data_type TEXT", "mode TEXT",
+	}}
+	enums := &SQLiteTable{Name: "enums", Columns: []string{
+		"enum_id TEXT PRIMARY KEY", "schema_id TEXT NOT NULL REFERENCES schemas (schema_id)", "name TEXT NOT NULL",
+	}}
+	enumValues := &SQLiteTable{Name: "enum_values", Columns: []string{
+		"enum_id TEXT NOT NULL REFERENCES enums (enum_id)", "position INTEGER NOT NULL", "value TEXT NOT NULL",
+	}}
+	roles := &SQLiteTable{Name: "roles", Columns: []string{
+		"name TEXT PRIMARY KEY", "is_superuser INTEGER NOT NULL", "can_login INTEGER NOT NULL",
+		"can_create_db INTEGER NOT NULL", "can_create_role INTEGER NOT NULL",
+	}}
+	roleMembers := &SQLiteTable{Name: "role_members", Columns: []string{
+		"role TEXT NOT NULL REFERENCES roles (name)", "member_of TEXT NOT NULL", "is_default INTEGER NOT NULL",
+	}}
+	// Grants name the schema, table or column they are on by its ID; global grants have none
+	grants := &SQLiteTable{Name: "grants", Columns: []string{
+		"name TEXT NOT NULL", "definition TEXT", "grantee TEXT", "privilege TEXT", "level TEXT", "object_id TEXT",
+		"is_grantable INTEGER NOT NULL",
+	}}
+	// Findings name the table or column they concern by its ID
+	findingsTable := &SQLiteTable{Name: "findings", Columns: []string{
+		"rule TEXT NOT NULL", "severity TEXT NOT NULL", "confidence TEXT NOT NULL", "object_id TEXT NOT NULL",
+		"message TEXT NOT NULL",
+	}}
+	for _, f := range findings {
+		findingsTable.Rows = append(findingsTable.Rows, []any{f.Rule, string(f.Severity), string(f.Confidence), f.Object, f.Message})
+	}
+
+	metadata.Rows = [][]any{
+		{"database", db.Name()},
+		{"engine", db.Engine()},
+		{"generated_at", generatedAt.UTC().Format(time.RFC3339)},
+		{"generator_version", version.Version},
+		{"generator_commit", version.Commit},
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(db.Roles())) {
+		role := db.Roles()[name]
+		roles.Rows = append(roles.Rows, []any{
+			name, role.IsSuperuser(), role.CanLogin(), role.CanCreateDB(), role.CanCreateRole(),
+		})
+		for _, memberOf := range role.MemberOf() {
+			isDefault := slices.Contains(role.DefaultRoles(), memberOf)
+			roleMembers.Rows = append(roleMembers.Rows, []any{name, memberOf.Name(), isDefault})
+		}
+		// A default role that was not granted is kept, as the server would refuse to activate it
+		for _, defaultRole := range role.DefaultRoles() {
+			if !slices.Contains(role.MemberOf(), defaultRole) {
+				roleMembers.Rows = append(roleMembers.Rows, []any{name, defaultRole.Name(), true})
+			}
+		}
+	}
+	for _, g := range db.Grants() {
+		var objectID any
+		schemaID := dbo.ObjectID("", g.SchemaName())
+		switch g.Level() {
+		case dbo.GrantLevelSchema:
+			objectID = schemaID
+		case dbo.GrantLevelTable:
+			objectID = dbo.ObjectID(schemaID, g.TableName())
+		case dbo.GrantLevelColumn:
+			objectID = dbo.ObjectID(dbo.ObjectID(schemaID, g.TableName()), g.ColumnName())
+		}
+		grants.Rows = append(grants.Rows, []any{
+			g.Name(), sqliteText(g.Definition()), sqliteText(g.Grantee()), sqliteText(g.Privilege()), string(g.Level()),
+			objectID, g.IsGrantable(),
+		})
+	}
+
+	for _, schema := range sortedSchemas(db) {
+		schemaID := dbo.ObjectID("", schema.Name())
+		schemas.Rows = append(schemas.Rows, []any{schemaID, schema.Name(), sqliteText(schema.Owner())})
+
+		for _, table := range sortedTables(schema) {
+			tableID := dbo.ObjectID(schemaID, table.Name())
+			var pkName any
+			if table.PrimaryKey() != nil {
+				pkName = table.PrimaryKey().Name()
+			}
+			tables.Rows = append(tables.Rows, []any{
+				tableID, schemaID, table.Name(), pkName, sqliteText(table.Engine()), sqliteText(table.RowFormat()),
+				sqliteText(table.Charset()), sqliteText(table.Collation()), sqliteText(table.Comment()),
+			})
+
+			for _, col := range sortedColumns(table) {
+				columns.Rows = append(columns.Rows, []any{
+					dbo.ObjectID(tableID, col.Name()), tableID, col.Name(), col.OrdinalPosition(), col.DataType(),
+					col.IsNullable(), col.DefaultValue(), col.CharMaxLength(), col.NumericPrecision(), col.NumericScale(),
+					isPrimaryKeyColumn(table, col.Name()), sqliteText(col.Charset()), sqliteText(col.Collation()),
+					sqliteText(string(col.Generation())), sqliteText(col.GenerationExpression()), col.IsInvisible(),
+					sqliteText(col.Comment()),
+				})
+			}
+
+			for _, idx := range table.Indexes() {
+				indexID := dbo.ObjectID(tableID, idx.Name())
+				indexes.Rows = append(indexes.Rows, []any{
+					indexID, tableID, idx.Name(), sqliteText(string(idx.IndexType())), idx.IsUnique(), idx.IsPrimary(),
+					sqliteText(idx.Predicate()), sqliteText(strings.Join(idx.Expressions(), ";")), idx.IsInvisible(),
+				})
+				for i, col := range idx.Columns() {
+					var prefixLength any
+					if length := idx.PrefixLength(i); length > 0 {
+						prefixLength = length
+					}
+					indexColumns.Rows = append(indexColumns.Rows, []any{indexID, i + 1, dbo.ObjectID(tableID, col.Name()), prefixLength})
+				}
+			}
+
+			for _, fk := range table.ForeignKeys() {
+				fkID := dbo.ObjectID(tableID, fk.Name())
+				refTableID := dbo.ObjectID(dbo.ObjectID("", referencedSchemaName(fk)), fk.ReferencedTable())
+				foreignKeys.Rows = append(foreignKeys.Rows, []any{
+					fkID, tableID, fk.Name(), refTableID, sqliteText(string(fk.OnDelete())), sqliteText(string(fk.OnUpdate())),
+				})
+				for i, col := range fk.Columns() {
+					var refColumnID any
+					if i < len(fk.ReferencedColumns()) {
+						refColumnID = dbo.ObjectID(refTableID, fk.ReferencedColumns()[i].Name())
+					}
+					fkColumns.Rows = append(fkColumns.Rows, []any{fkID, i + 1, dbo.ObjectID(tableID, col.Name()), refColumnID})
+				}
+			}
+
+			for _, c := range table.Constraints() {
+				constraintID := dbo.ObjectID(tableID, c.Name())
+				constraints.Rows = append(constraints.Rows, []any{
+					constraintID, tableID, c.Name(), string(c.Type()), sqliteText(c.CheckExpression()),
+				})
+				for i, col := range c.Columns() {
+					constraintColumns.Rows = append(constraintColumns.Rows, []any{constraintID, i + 1, dbo.ObjectID(tableID, col.Name())})
+				}
+			}
+
+			for _, trigger := range table.Triggers() {
+				events := make([]string, len(trigger.Events()))
+				for i, e := range trigger.Events() {
+					events[i] = string(e)
+				}
+				var routineID any
+				if fn := trigger.Function(); fn != nil {
+					routineID = dbo.RoutineID(dbo.ObjectID("", schemaNameOf(fn.Schema())), fn.Name(), fn.Parameters())
+				}
+				triggers.Rows = append(triggers.Rows, []any{
+					dbo.ObjectID(tableID, trigger.Name()), tableID, trigger.Name(), sqliteText(string(trigger.Timing())),
+					sqliteText(strings.Join(events, ";")), sqliteText(trigger.ForEach()), routineID, sqliteText(trigger.Definition()),
+				})
+			}
+		}
+
+		for _, view := range sortedViews(schema) {
+			views.Rows = append(views.Rows, []any{dbo.ObjectID(schemaID, view.Name()), schemaID, view.Name(), sqliteText(view.Definition())})
+		}
+
+		for _, seq := range sortedSequences(schema) {
+			sequences.Rows = append(sequences.Rows, []any{
+				dbo.ObjectID(schemaID, seq.Name()), schemaID, seq.Name(), seq.StartValue(), seq.Increment(),
+				seq.MinValue(), seq.MaxValue(), seq.Cache(), seq.Cycle(),
+			})
+		}
+
+		addParameters := func(routineID, kind string, params []*dbo.FunctionParameter) {
+			for i, p := range params {
+				routineParameters.Rows = append(routineParameters.Rows, []any{
+					routineID, kind, i + 1, sqliteText(p.Name()), sqliteText(p.DataType()), sqliteText(string(p.Mode())),
+				})
+			}
+		}
+		for _, fn := range sortedFunctions(schema) {
+			routineID := dbo.RoutineID(schemaID, fn.Name(), fn.Parameters())
+			routines.Rows = append(routines.Rows, []any{
+				routineID, schemaID, fn.Name(), "function", sqliteText(fn.Language()), sqliteText(fn.ReturnType()),
+				sqliteText(string(fn.SecurityType())), sqliteText(fn.Definer()), fn.IsDeterministic(),
+				sqliteText(fn.SQLDataAccess()), sqliteText(fn.SQLMode()), sqliteText(fn.Definition()),
+			})
+			addParameters(routineID, "function", fn.Parameters())
+		}
+		for _, proc := range sortedProcedures(schema) {
+			routineID := dbo.RoutineID(schemaID, proc.Name(), proc.Parameters())
+			routines.Rows = append(routines.Rows, []any{
+				routineID, schemaID, proc.Name(), "procedure", sqliteText(proc.Language()), nil,
+				sqliteText(string(proc.SecurityType())), sqliteText(proc.Definer()), proc.IsDeterministic(),
+				sqliteText(proc.SQLDataAccess()), sqliteText(proc.SQLMode()), sqliteText(proc.Definition()),
+			})
+			addParameters(routineID, "procedure", proc.Parameters())
+		}
+
+		for _, enum := range sortedEnums(schema) {
+			enumID := dbo.ObjectID(schemaID, enum.Name())
+			enums.Rows = append(enums.Rows, []any{enumID, schemaID, enum.Name()})
+			for i, value := range enum.Values() {
+				enumValues.Rows = append(enumValues.Rows, []any{enumID, i + 1, value})
+			}
+		}
+	}
+
+	return []*SQLiteTable{
+		metadata, schemas, tables, columns, indexes, indexColumns, foreignKeys, fkColumns, constraints,
+		constraintColumns, triggers, views, sequences, routines, routineParameters, enums, enumValues, roles,
+		roleMembers, grants, findingsTable,
+	}
+}
+
+// sqliteText stores an empty string as NULL so that unset values can be found with IS NULL
+func sqliteText(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
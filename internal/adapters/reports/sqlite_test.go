@@ -0,0 +1,182 @@
+package reports
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+	"github.com/jimbot9k/norman/internal/core/rules"
+)
+
+// openTestSQLite opens a SQLite database written by a test, closing it when the test ends
+func openTestSQLite(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestSQLiteReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("writes a queryable inventory", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "shop_SQLite_Inventory.sqlite")
+
+		writer := &SQLiteReportWriter{}
+		if err := writer.WriteInventoryReport(filePath, newDDLTestDatabase("PostgreSQL")); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		conn := openTestSQLite(t, filePath)
+		rows, err := conn.Query(`
+			SELECT c.column_id
+			FROM fk_columns fc
+			JOIN columns c ON c.column_id = fc.column_id
+			JOIN tables t ON t.table_id = c.table_id
+			WHERE c.is_nullable AND t.schema_id LIKE 'pub%'
+			ORDER BY c.column_id`)
+		if err != nil {
+			t.Fatalf("failed to query inventory: %v", err)
+		}
+		defer rows.Close()
+		var nullable []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				t.Fatal(err)
+			}
+			nullable = append(nullable, id)
+		}
+		if len(nullable) != 1 || nullable[0] != "public.orders.coupon_id" {
+			t.Errorf("expected the nullable coupon FK column, got %v", nullable)
+		}
+
+		var engine string
+		if err := conn.QueryRow("SELECT value FROM metadata WHERE key = 'engine'").Scan(&engine); err != nil || engine != "PostgreSQL" {
+			t.Errorf("expected engine metadata, got %q (%v)", engine, err)
+		}
+	})
+
+	t.Run("replaces an existing database", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "inventory.sqlite")
+		if err := os.WriteFile(filePath, []byte("not a database"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		writer := &SQLiteReportWriter{}
+		for range 2 {
+			if err := writer.WriteInventoryReport(filePath, newDiagramTestDatabase()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+
+		var count int
+		if err := openTestSQLite(t, filePath).QueryRow("SELECT count(*) FROM tables").Scan(&count); err != nil || count != 3 {
+			t.Errorf("expected 3 tables, got %d (%v)", count, err)
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &SQLiteReportWriter{}
+		db := dbo.NewDatabase("testdb", nil)
+
+		if err := writer.WriteInventoryReport("/nonexistent/path/file.sqlite", db); err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+}
+
+func TestGenerateSQLiteInventory(t *testing.T) {
+	findings := []rules.Finding{{
+		Rule: "mysql-non-transactional-engine", Severity: rules.SeverityWarning, Confidence: rules.ConfidenceHigh,
+		Object: "public.orders", Message: "table uses the MyISAM engine",
+	}}
+	db := newDDLTestDatabase("PostgreSQL")
+	public := db.Schemas()["public"]
+	public.Tables()["users"].Triggers()[0].SetFunction(public.Functions()["touch"])
+	reader := dbo.NewRole("reader")
+	app := dbo.NewRole("app")
+	app.AddMemberOf(reader)
+	app.AddDefaultRole(reader)
+	db.AddRole(reader)
+	db.AddRole(app)
+	grant := dbo.NewGrant("UPDATE", "")
+	grant.SetObject("public", "users", "email")
+	db.AddGrant(grant)
+	global := dbo.NewGrant("SUPER", "")
+	db.AddGrant(global)
+	tables := GenerateSQLiteInventory(db, findings, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	byName := make(map[string]*SQLiteTable)
+	for _, table := range tables {
+		byName[table.Name] = table
+	}
+
+	tests := []struct {
+		table string
+		rows  int
+	}{
+		{"schemas", 1},
+		{"tables", 3},
+		{"columns", 7},
+		{"foreign_keys", 3},
+		{"views", 2},
+		{"sequences", 1},
+		{"routines", 1},
+		{"triggers", 1},
+		{"roles", 2},
+		{"role_members", 1},
+		{"grants", 2},
+		{"findings", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			table, ok := byName[tt.table]
+			if !ok {
+				t.Fatalf("expected table %s", tt.table)
+			}
+			if len(table.Rows) != tt.rows {
+				t.Errorf("expected %d rows, got %d", tt.rows, len(table.Rows))
+			}
+			for _, row := range table.Rows {
+				if len(row) != len(table.Columns) {
+					t.Errorf("row %v does not match columns %v", row, table.Columns)
+				}
+			}
+		})
+	}
+
+	t.Run("grants name the object they are on", func(t *testing.T) {
+		rows := byName["grants"].Rows
+		if rows[0][4] != "COLUMN" || rows[0][5] != "public.users.email" {
+			t.Errorf("expected a column grant on public.users.email, got %v", rows[0])
+		}
+		if rows[1][4] != "GLOBAL" || rows[1][5] != nil {
+			t.Errorf("expected a global grant without object, got %v", rows[1])
+		}
+		if member := byName["role_members"].Rows[0]; member[0] != "app" || member[1] != "reader" || member[2] != true {
+			t.Errorf("expected app to be a member of reader by default, got %v", member)
+		}
+	})
+
+	t.Run("routines are identified by their signature", func(t *testing.T) {
+		routineID := byName["routines"].Rows[0][0]
+		if routineID != "public.touch()" {
+			t.Errorf("expected routine ID public.touch(), got %v", routineID)
+		}
+		if trigger := byName["triggers"].Rows[0]; trigger[6] != routineID {
+			t.Errorf("expected the trigger to reference %v, got %v", routineID, trigger[6])
+		}
+	})
+
+	t.Run("empty strings are stored as NULL", func(t *testing.T) {
+		for _, row := range byName["tables"].Rows {
+			if row[0] == "public.orders" && (row[4] != nil || row[8] != nil) {
+				t.Errorf("expected NULL engine and comment, got %v and %v", row[4], row[8])
+			}
+		}
+	})
+}
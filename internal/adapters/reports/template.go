@@ -0,0 +1,199 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// TemplateReportWriter renders a user-supplied Go template against the mapped database.
+// Templates ending in .html or .htm (optionally followed by .tmpl, .tpl or .gotmpl) are
+// executed with html/template, all others with text/template.
+type TemplateReportWriter struct {
+	TemplatePath string
+}
+
+// GetReportKeys returns the report keys supported by this writer
+func (w *TemplateReportWriter) GetReportKeys() []string {
+	return []string{"template"}
+}
+
+// GetReportFileExtension returns the extension of the rendered template, e.g. "md"
+// for "dictionary.md.tmpl", or "txt" when the template name does not carry one
+func (w *TemplateReportWriter) GetReportFileExtension() string {
+	if ext := strings.TrimPrefix(filepath.Ext(templateOutputName(w.TemplatePath)), "."); ext != "" {
+		return ext
+	}
+	return "txt"
+}
+
+// GetReportName returns the name of the template report
+func (w *TemplateReportWriter) GetReportName() string {
+	return "Template Report"
+}
+
+// WriteInventoryReport renders the template and writes the result to the specified file
+func (w *TemplateReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	data, err := GenerateTemplateReport(w.TemplatePath, db)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// GenerateTemplateReport renders the template file at templatePath with the database as
+// its data and the helpers of TemplateFuncs available
+func GenerateTemplateReport(templatePath string, db *dbo.Database) ([]byte, error) {
+	if templatePath == "" {
+		return nil, fmt.Errorf("no template file given")
+	}
+	source, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	name := filepath.Base(templatePath)
+	var buf bytes.Buffer
+	if isHTMLTemplate(templatePath) {
+		tmpl, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(TemplateFuncs(db))).Parse(string(source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template: %w", err)
+		}
+		if err := tmpl.Execute(&buf, db); err != nil {
+			return nil, fmt.Errorf("failed to render template: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	tmpl, err := texttemplate.New(name).Funcs(texttemplate.FuncMap(TemplateFuncs(db))).Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := tmpl.Execute(&buf, db); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// TemplateFuncs returns the helper functions available to report templates. Lookups
+// that need the whole model (e.g. incoming foreign keys) are bound to db.
+func TemplateFuncs(db *dbo.Database) map[string]any {
+	return map[string]any{
+		// Ordering
+		"sortedSchemas":    sortedSchemas,
+		"sortedTables":     sortedTables,
+		"sortedViews":      sortedViews,
+		"sortedColumns":    sortedColumns,
+		"sortedSequences":  sortedSequences,
+		"sortedFunctions":  sortedFunctions,
+		"sortedProcedures": sortedProcedures,
+		"sortedEnums":      sortedEnums,
+
+		// Names and types
+		"qualifiedName": templateQualifiedName,
+		"columnNames":   columnNames,
+		"columnType":    formatColumnType,
+		"deref":         templateDeref,
+
+		// Keys
+		"isPrimaryKey": func(table *dbo.Table, col *dbo.Column) bool { return isPrimaryKeyColumn(table, col.Name()) },
+		"isForeignKey": func(table *dbo.Table, col *dbo.Column) bool { return isForeignKeyColumn(table, col.Name()) },
+		"isUnique":     isUniqueColumn,
+		"foreignKeysOf": func(table *dbo.Table, col *dbo.Column) []*dbo.ForeignKey {
+			var fks []*dbo.ForeignKey
+			for _, fk := range table.ForeignKeys() {
+				for _, fkCol := range fk.Columns() {
+					if fkCol.Name() == col.Name() {
+						fks = append(fks, fk)
+						break
+					}
+				}
+			}
+			return fks
+		},
+		"referencedTable": func(fk *dbo.ForeignKey) *dbo.Table { return resolveReferencedTable(db, fk) },
+		"referencedBy":    func(table *dbo.Table) []*dbo.ForeignKey { return referencingForeignKeys(db, table) },
+
+		// Strings
+		"join":  strings.Join,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+}
+
+// referencingForeignKeys returns the foreign keys of the database that reference the
+// given table, ordered by referencing schema and table
+func referencingForeignKeys(db *dbo.Database, table *dbo.Table) []*dbo.ForeignKey {
+	var fks []*dbo.ForeignKey
+	for _, schema := range sortedSchemas(db) {
+		for _, other := range sortedTables(schema) {
+			for _, fk := range other.ForeignKeys() {
+				if resolveReferencedTable(db, fk) == table {
+					fks = append(fks, fk)
+				}
+			}
+		}
+	}
+	return fks
+}
+
+// templateQualifiedName returns the schema-qualified name of a database object.
+// Columns are qualified by their table.
+func templateQualifiedName(object any) (string, error) {
+	switch o := object.(type) {
+	case *dbo.Column:
+		if o.Table() != nil {
+			return o.Table().FullyQualifiedName() + "." + o.Name(), nil
+		}
+		return o.Name(), nil
+	case interface{ FullyQualifiedName() string }:
+		return o.FullyQualifiedName(), nil
+	default:
+		return "", fmt.Errorf("qualifiedName: unsupported type %T", object)
+	}
+}
+
+// templateDeref returns the value behind an optional string or integer, or an empty
+// string when it is unset
+func templateDeref(value any) any {
+	switch v := value.(type) {
+	case *string:
+		if v != nil {
+			return *v
+		}
+	case *int:
+		if v != nil {
+			return *v
+		}
+	case *int64:
+		if v != nil {
+			return *v
+		}
+	default:
+		return value
+	}
+	return ""
+}
+
+// templateOutputName strips a template suffix such as ".tmpl" from a template path
+func templateOutputName(templatePath string) string {
+	base := filepath.Base(templatePath)
+	for _, suffix := range []string{".tmpl", ".tpl", ".gotmpl"} {
+		if strings.HasSuffix(base, suffix) {
+			return strings.TrimSuffix(base, suffix)
+		}
+	}
+	return base
+}
+
+// isHTMLTemplate reports whether a template renders HTML and needs contextual escaping
+func isHTMLTemplate(templatePath string) bool {
+	ext := strings.ToLower(filepath.Ext(templateOutputName(templatePath)))
+	return ext == ".html" || ext == ".htm"
+}
@@ -0,0 +1,151 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// writeTestTemplate writes a template file into a temporary directory
+func writeTestTemplate(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	return path
+}
+
+func TestTemplateReportWriter_WriteInventoryReport(t *testing.T) {
+	t.Run("renders template to file", func(t *testing.T) {
+		templatePath := writeTestTemplate(t, "dict.md.tmpl", "# {{.Name}}\n")
+		filePath := filepath.Join(t.TempDir(), "out.md")
+
+		writer := &TemplateReportWriter{TemplatePath: templatePath}
+		if err := writer.WriteInventoryReport(filePath, newDiagramTestDatabase()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		if string(content) != "# shop\n" {
+			t.Errorf("expected rendered template, got %q", content)
+		}
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		writer := &TemplateReportWriter{TemplatePath: writeTestTemplate(t, "t.txt", "x")}
+		if err := writer.WriteInventoryReport("/nonexistent/path/file.txt", dbo.NewDatabase("testdb", nil)); err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+
+	t.Run("returns error without template", func(t *testing.T) {
+		writer := &TemplateReportWriter{}
+		if err := writer.WriteInventoryReport(filepath.Join(t.TempDir(), "out.txt"), dbo.NewDatabase("testdb", nil)); err == nil {
+			t.Error("expected error when no template is configured")
+		}
+	})
+}
+
+func TestTemplateReportWriter_GetReportFileExtension(t *testing.T) {
+	tests := []struct {
+		templatePath string
+		expected     string
+	}{
+		{"dict.md.tmpl", "md"},
+		{"/templates/wiki.html", "html"},
+		{"confluence.xml.gotmpl", "xml"},
+		{"report.tmpl", "txt"},
+		{"", "txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.templatePath, func(t *testing.T) {
+			writer := &TemplateReportWriter{TemplatePath: tt.templatePath}
+			if got := writer.GetReportFileExtension(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGenerateTemplateReport(t *testing.T) {
+	db := newDiagramTestDatabase()
+
+	t.Run("helpers", func(t *testing.T) {
+		templatePath := writeTestTemplate(t, "dict.md.tmpl", `{{- range sortedSchemas . }}{{ range sortedTables . -}}
+## {{ qualifiedName . }}
+{{ range sortedColumns . }}{{ $t := .Table }}- {{ .Name }} {{ columnType . }}
+{{- if isPrimaryKey $t . }} PK{{ end }}
+{{- if isUnique $t . }} UNIQUE{{ end }}
+{{- range foreignKeysOf $t . }} -> {{ qualifiedName (referencedTable .) }}{{ end }}
+{{- with deref .DefaultValue }} default {{ . }}{{ end }}
+{{ end }}
+{{- with referencedBy . }}referenced by: {{ range . }}{{ .Name }} {{ end }}
+{{ end }}{{ end }}{{ end }}`)
+
+		data, err := GenerateTemplateReport(templatePath, db)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		result := string(data)
+
+		for _, expected := range []string{
+			"## public.orders\n- id integer PK UNIQUE\n- user_id integer -> public.users\n- coupon_id integer -> public.users\n",
+			"## public.users\n- id integer PK UNIQUE\n- email character varying(255) UNIQUE\n- status text default 'active'::text\n",
+			"referenced by: orders_user_fk orders_coupon_fk profiles_user_fk \n",
+		} {
+			if !strings.Contains(result, expected) {
+				t.Errorf("expected %q in:\n%s", expected, result)
+			}
+		}
+		if strings.Index(result, "## public.orders") > strings.Index(result, "## public.users") {
+			t.Errorf("expected tables in name order, got:\n%s", result)
+		}
+	})
+
+	t.Run("html templates escape content", func(t *testing.T) {
+		table := db.Schemas()["public"].Tables()["users"]
+		table.SetComment("<b>accounts</b>")
+		defer table.SetComment("registered accounts")
+
+		templatePath := writeTestTemplate(t, "wiki.html.tmpl", `{{ range sortedSchemas . }}{{ range sortedTables . }}<p>{{ .Comment }}</p>{{ end }}{{ end }}`)
+		data, err := GenerateTemplateReport(templatePath, db)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !strings.Contains(string(data), "<p>&lt;b&gt;accounts&lt;/b&gt;</p>") {
+			t.Errorf("expected escaped comment, got %s", data)
+		}
+	})
+
+	t.Run("text templates do not escape", func(t *testing.T) {
+		templatePath := writeTestTemplate(t, "raw.txt", `{{ "<b>" }}`)
+		data, err := GenerateTemplateReport(templatePath, db)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if string(data) != "<b>" {
+			t.Errorf("expected unescaped output, got %q", data)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := map[string]string{
+			"parse":   writeTestTemplate(t, "bad.txt", "{{ .Name "),
+			"execute": writeTestTemplate(t, "exec.txt", "{{ qualifiedName .Name }}"),
+			"missing": filepath.Join(t.TempDir(), "missing.tmpl"),
+		}
+		for name, path := range cases {
+			if _, err := GenerateTemplateReport(path, db); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jimbot9k/norman/internal/version"
+)
+
+// ManifestFileName is the name of the manifest stored at the root of a report bundle
+const ManifestFileName = "manifest.json"
+
+// BundleFindingsFileName is the name of the findings of the audit rules stored at the
+// root of a report bundle, listed in the manifest like the reports
+const BundleFindingsFileName = "findings.json"
+
+// Bundle formats, chosen from the extension of the bundle path
+const (
+	BundleFormatTarGz = "tar.gz"
+	BundleFormatZip   = "zip"
+)
+
+// Manifest describes the reports of a single run packaged into a bundle
+type Manifest struct {
+	Generator   ManifestGenerator `json:"generator"`
+	Adapter     string            `json:"adapter"`
+	Database    string            `json:"database"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+	SignedBy    string            `json:"signedBy,omitempty"`
+	Files       []ManifestFile    `json:"files"`
+}
+
+// ManifestGenerator identifies the Norman build that produced a bundle
+type ManifestGenerator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// ManifestFile is a report file inside a bundle with its report type and checksum
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Report string `json:"report"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// NewManifest creates an empty manifest for a run of the current Norman build
+func NewManifest(adapter string, database string, generatedAt time.Time) *Manifest {
+	return &Manifest{
+		Generator:   ManifestGenerator{Name: "norman", Version: version.Version, Commit: version.Commit},
+		Adapter:     adapter,
+		Database:    database,
+		GeneratedAt: generatedAt.UTC(),
+		Files:       []ManifestFile{},
+	}
+}
+
+// AddFile records the file at relPath below dir, computing its size and SHA-256 checksum
+func (m *Manifest) AddFile(dir string, relPath string, report string) error {
+	file, err := os.Open(filepath.Join(dir, relPath))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return fmt.Errorf("failed to checksum %s: %w", relPath, err)
+	}
+	m.Files = append(m.Files, ManifestFile{
+		Path:   filepath.ToSlash(relPath),
+		Report: report,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	})
+	return nil
+}
+
+// BundleFormat returns the archive format for a bundle path ending in .tar.gz, .tgz or .zip
+func BundleFormat(path string) (string, error) {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return BundleFormatTarGz, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return BundleFormatZip, nil
+	default:
+		return "", fmt.Errorf("unsupported bundle %s, use a .tar.gz, .tgz or .zip file", path)
+	}
+}
+
+// bundleEntry is an archive member held in memory
+type bundleEntry struct {
+	name string
+	data []byte
+}
+
+// WriteBundle archives the manifest followed by the files it lists, read from dir.
+// When key is set the manifest records the key's fingerprint and its Ed25519
+// signature is stored next to it as ManifestSignatureFileName.
+func WriteBundle(path string, dir string, manifest *Manifest, key ed25519.PrivateKey) error {
+	format, err := BundleFormat(path)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		manifest.SignedBy = KeyFingerprint(key.Public().(ed25519.PublicKey))
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	leading := []bundleEntry{{name: ManifestFileName, data: manifestData}}
+	if key != nil {
+		leading = append(leading, bundleEntry{name: ManifestSignatureFileName, data: SignManifest(key, manifestData)})
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if format == BundleFormatZip {
+		err = writeZipBundle(file, dir, manifest, leading)
+	} else {
+		err = writeTarGzBundle(file, dir, manifest, leading)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write bundle %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// writeZipBundle writes the leading entries and report files as a zip archive
+func writeZipBundle(out io.Writer, dir string, manifest *Manifest, leading []bundleEntry) error {
+	archive := zip.NewWriter(out)
+	add := func(name string, modified time.Time, content io.Reader) error {
+		entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(entry, content)
+		return err
+	}
+
+	for _, entry := range leading {
+		if err := add(entry.name, manifest.GeneratedAt, bytes.NewReader(entry.data)); err != nil {
+			return err
+		}
+	}
+	for _, f := range manifest.Files {
+		if err := addBundleFile(dir, f, func(content io.Reader) error {
+			return add(f.Path, manifest.GeneratedAt, content)
+		}); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// writeTarGzBundle writes the leading entries and report files as a gzip-compressed tar archive
+func writeTarGzBundle(out io.Writer, dir string, manifest *Manifest, leading []bundleEntry) error {
+	compressed := gzip.NewWriter(out)
+	archive := tar.NewWriter(compressed)
+	add := func(name string, size int64, modified time.Time, content io.Reader) error {
+		header := &tar.Header{Name: name, Mode: 0600, Size: size, ModTime: modified, Typeflag: tar.TypeReg}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := io.Copy(archive, content)
+		return err
+	}
+
+	for _, entry := range leading {
+		if err := add(entry.name, int64(len(entry.data)), manifest.GeneratedAt, bytes.NewReader(entry.data)); err != nil {
+			return err
+		}
+	}
+	for _, f := range manifest.Files {
+		if err := addBundleFile(dir, f, func(content io.Reader) error {
+			return add(f.Path, f.Size, manifest.GeneratedAt, content)
+		}); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return compressed.Close()
+}
+
+// addBundleFile opens a manifest file below dir and passes its content to add
+func addBundleFile(dir string, f ManifestFile, add func(io.Reader) error) error {
+	content, err := os.Open(filepath.Join(dir, filepath.FromSlash(f.Path)))
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	return add(content)
+}
+
+// readBundle returns the members of a .tar.gz or .zip bundle in archive order. Members
+// other than regular files and zip directories are errors.
+func readBundle(path string) ([]bundleEntry, error) {
+	format, err := BundleFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []bundleEntry
+
+	if format == BundleFormatZip {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer archive.Close()
+		for _, f := range archive.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			if !f.Mode().IsRegular() {
+				return nil, fmt.Errorf("%s is not a regular file", f.Name)
+			}
+			content, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(content)
+			content.Close()
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, bundleEntry{name: f.Name, data: data})
+		}
+		return entries, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	compressed, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	archive := tar.NewReader(compressed)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Links and other special members are never written, and would escape the
+		// checksums of the manifest
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%s is not a regular file", header.Name)
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, bundleEntry{name: header.Name, data: data})
+	}
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// bundleContents returns the member names of a bundle in archive order and their contents
+func bundleContents(t *testing.T, path string) ([]string, map[string][]byte) {
+	t.Helper()
+	entries, err := readBundle(path)
+	if err != nil {
+		t.Fatalf("failed to read bundle: %v", err)
+	}
+	var names []string
+	contents := make(map[string][]byte)
+	for _, entry := range entries {
+		names = append(names, entry.name)
+		contents[entry.name] = entry.data
+	}
+	return names, contents
+}
+
+func TestBundleFormat(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		wantErr  bool
+	}{
+		{"audit.tar.gz", BundleFormatTarGz, false},
+		{"audit.TGZ", BundleFormatTarGz, false},
+		{"out/audit.zip", BundleFormatZip, false},
+		{"audit.tar", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := BundleFormat(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "csv"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{"shop.json": "{}", filepath.Join("csv", "tables.csv"): "id\n"}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifest := NewManifest("PostgreSQL-1.0", "shop", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err := manifest.AddFile(dir, "shop.json", "json"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := manifest.AddFile(dir, filepath.Join("csv", "tables.csv"), "csv"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := manifest.AddFile(dir, "missing.txt", "json"); err == nil {
+		t.Error("expected error for a missing file")
+	}
+
+	for _, name := range []string{"audit.tar.gz", "audit.zip"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := WriteBundle(path, dir, manifest, nil); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			names, contents := bundleContents(t, path)
+			expected := []string{ManifestFileName, "shop.json", "csv/tables.csv"}
+			if len(names) != len(expected) {
+				t.Fatalf("expected entries %v, got %v", expected, names)
+			}
+			for i := range expected {
+				if names[i] != expected[i] {
+					t.Errorf("expected entry %d to be %q, got %q", i, expected[i], names[i])
+				}
+			}
+
+			var read Manifest
+			if err := json.Unmarshal(contents[ManifestFileName], &read); err != nil {
+				t.Fatalf("failed to unmarshal manifest: %v", err)
+			}
+			if read.Adapter != "PostgreSQL-1.0" || read.Database != "shop" || read.Generator.Name != "norman" {
+				t.Errorf("unexpected manifest header %+v", read)
+			}
+			for _, f := range read.Files {
+				sum := sha256.Sum256(contents[f.Path])
+				if hex.EncodeToString(sum[:]) != f.SHA256 {
+					t.Errorf("checksum mismatch for %s", f.Path)
+				}
+				if int64(len(contents[f.Path])) != f.Size {
+					t.Errorf("size mismatch for %s", f.Path)
+				}
+			}
+		})
+	}
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		if err := WriteBundle("/nonexistent/path/audit.zip", dir, manifest, nil); err == nil {
+			t.Error("expected error for invalid path")
+		}
+	})
+
+	t.Run("returns error for unknown format", func(t *testing.T) {
+		if err := WriteBundle(filepath.Join(t.TempDir(), "audit.rar"), dir, manifest, nil); err == nil {
+			t.Error("expected error for unknown format")
+		}
+	})
+}
@@ -2,6 +2,16 @@ package dbobjects
 
 import "encoding/json"
 
+// Generation is how the value of a generated column is kept
+type Generation string
+
+const (
+	// GenerationVirtual columns are computed when they are read
+	GenerationVirtual Generation = "virtual"
+	// GenerationStored columns are computed when the row is written and stored with it
+	GenerationStored Generation = "stored"
+)
+
 type Column struct {
 	name             string
 	dataType         string
@@ -11,31 +21,89 @@ type Column struct {
 	charMaxLength    *int
 	numericPrecision *int
 	numericScale     *int
-	table            *Table
+	charset          string
+	collation        string
+	// generation is empty for columns that are not generated
+	generation           Generation
+	generationExpression string
+	invisible            bool
+	comment              string
+	table                *Table
 }
 
 func (c *Column) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Name             string  `json:"name"`
-		DataType         string  `json:"dataType"`
-		Nullable         bool    `json:"nullable"`
-		DefaultValue     *string `json:"defaultValue,omitempty"`
-		OrdinalPosition  int     `json:"ordinalPosition"`
-		CharMaxLength    *int    `json:"charMaxLength,omitempty"`
-		NumericPrecision *int    `json:"numericPrecision,omitempty"`
-		NumericScale     *int    `json:"numericScale,omitempty"`
+		Name                 string     `json:"name"`
+		DataType             string     `json:"dataType"`
+		Nullable             bool       `json:"nullable"`
+		DefaultValue         *string    `json:"defaultValue,omitempty"`
+		OrdinalPosition      int        `json:"ordinalPosition"`
+		CharMaxLength        *int       `json:"charMaxLength,omitempty"`
+		NumericPrecision     *int       `json:"numericPrecision,omitempty"`
+		NumericScale         *int       `json:"numericScale,omitempty"`
+		Charset              string     `json:"charset,omitempty"`
+		Collation            string     `json:"collation,omitempty"`
+		Generation           Generation `json:"generation,omitempty"`
+		GenerationExpression string     `json:"generationExpression,omitempty"`
+		Invisible            bool       `json:"invisible,omitempty"`
+		Comment              string     `json:"comment,omitempty"`
 	}{
-		Name:             c.name,
-		DataType:         c.dataType,
-		Nullable:         c.nullable,
-		DefaultValue:     c.defaultValue,
-		OrdinalPosition:  c.ordinalPosition,
-		CharMaxLength:    c.charMaxLength,
-		NumericPrecision: c.numericPrecision,
-		NumericScale:     c.numericScale,
+		Name:                 c.name,
+		DataType:             c.dataType,
+		Nullable:             c.nullable,
+		DefaultValue:         c.defaultValue,
+		OrdinalPosition:      c.ordinalPosition,
+		CharMaxLength:        c.charMaxLength,
+		NumericPrecision:     c.numericPrecision,
+		NumericScale:         c.numericScale,
+		Charset:              c.charset,
+		Collation:            c.collation,
+		Generation:           c.generation,
+		GenerationExpression: c.generationExpression,
+		Invisible:            c.invisible,
+		Comment:              c.comment,
 	})
 }
 
+func (c *Column) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name                 string     `json:"name"`
+		DataType             string     `json:"dataType"`
+		Nullable             bool       `json:"nullable"`
+		DefaultValue         *string    `json:"defaultValue"`
+		OrdinalPosition      int        `json:"ordinalPosition"`
+		CharMaxLength        *int       `json:"charMaxLength"`
+		NumericPrecision     *int       `json:"numericPrecision"`
+		NumericScale         *int       `json:"numericScale"`
+		Charset              string     `json:"charset"`
+		Collation            string     `json:"collation"`
+		Generation           Generation `json:"generation"`
+		GenerationExpression string     `json:"generationExpression"`
+		Invisible            bool       `json:"invisible"`
+		Comment              string     `json:"comment"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*c = Column{
+		name:                 aux.Name,
+		dataType:             aux.DataType,
+		nullable:             aux.Nullable,
+		defaultValue:         aux.DefaultValue,
+		ordinalPosition:      aux.OrdinalPosition,
+		charMaxLength:        aux.CharMaxLength,
+		numericPrecision:     aux.NumericPrecision,
+		numericScale:         aux.NumericScale,
+		charset:              aux.Charset,
+		collation:            aux.Collation,
+		generation:           aux.Generation,
+		generationExpression: aux.GenerationExpression,
+		invisible:            aux.Invisible,
+		comment:              aux.Comment,
+	}
+	return nil
+}
+
 func NewColumn(name string, dataType string, nullable bool) *Column {
 	return &Column{
 		name:     name,
@@ -52,10 +120,23 @@ func (c *Column) DataType() string {
 	return c.dataType
 }
 
+// SetDataType changes the data type. The length, precision and scale belong to the old
+// type and are cleared.
+func (c *Column) SetDataType(dataType string) {
+	c.dataType = dataType
+	c.charMaxLength = nil
+	c.numericPrecision = nil
+	c.numericScale = nil
+}
+
 func (c *Column) IsNullable() bool {
 	return c.nullable
 }
 
+func (c *Column) SetNullable(nullable bool) {
+	c.nullable = nullable
+}
+
 func (c *Column) DefaultValue() *string {
 	return c.defaultValue
 }
@@ -64,6 +145,10 @@ func (c *Column) SetDefaultValue(value string) {
 	c.defaultValue = &value
 }
 
+func (c *Column) ClearDefaultValue() {
+	c.defaultValue = nil
+}
+
 func (c *Column) OrdinalPosition() int {
 	return c.ordinalPosition
 }
@@ -96,6 +181,64 @@ func (c *Column) SetNumericScale(scale int) {
 	c.numericScale = &scale
 }
 
+// Charset returns the character set of a text column
+func (c *Column) Charset() string {
+	return c.charset
+}
+
+func (c *Column) SetCharset(charset string) {
+	c.charset = charset
+}
+
+// Collation returns the collation of a text column, which decides how its values are
+// compared and sorted
+func (c *Column) Collation() string {
+	return c.collation
+}
+
+func (c *Column) SetCollation(collation string) {
+	c.collation = collation
+}
+
+// Generation returns how the value of a generated column is kept, or "" for a column
+// that is not generated
+func (c *Column) Generation() Generation {
+	return c.generation
+}
+
+// GenerationExpression returns the expression a generated column is computed from
+func (c *Column) GenerationExpression() string {
+	return c.generationExpression
+}
+
+// SetGenerated makes the column a generated column computed from expression
+func (c *Column) SetGenerated(generation Generation, expression string) {
+	c.generation = generation
+	c.generationExpression = expression
+}
+
+func (c *Column) IsGenerated() bool {
+	return c.generation != ""
+}
+
+// IsInvisible reports whether the column is left out of SELECT * and must be named to
+// be read, as MySQL and MariaDB INVISIBLE columns are
+func (c *Column) IsInvisible() bool {
+	return c.invisible
+}
+
+func (c *Column) SetInvisible(invisible bool) {
+	c.invisible = invisible
+}
+
+func (c *Column) Comment() string {
+	return c.comment
+}
+
+func (c *Column) SetComment(comment string) {
+	c.comment = comment
+}
+
 func (c *Column) Table() *Table {
 	return c.table
 }
@@ -76,6 +76,59 @@ func TestColumnSetNumericScale(t *testing.T) {
 	}
 }
 
+func TestColumnSetComment(t *testing.T) {
+	col := NewColumn("email", "varchar", false)
+
+	if col.Comment() != "" {
+		t.Errorf("expected empty comment initially, got '%s'", col.Comment())
+	}
+
+	col.SetComment("primary contact address")
+
+	if col.Comment() != "primary contact address" {
+		t.Errorf("expected comment 'primary contact address', got '%s'", col.Comment())
+	}
+}
+
+func TestColumnSetCharsetAndCollation(t *testing.T) {
+	col := NewColumn("email", "varchar", false)
+	col.SetCharset("utf8mb4")
+	col.SetCollation("utf8mb4_0900_ai_ci")
+
+	data, err := json.Marshal(col)
+	if err != nil {
+		t.Fatalf("failed to marshal column: %v", err)
+	}
+	var decoded Column
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal column: %v", err)
+	}
+	if decoded.Charset() != "utf8mb4" || decoded.Collation() != "utf8mb4_0900_ai_ci" {
+		t.Errorf("expected utf8mb4 / utf8mb4_0900_ai_ci, got %q / %q", decoded.Charset(), decoded.Collation())
+	}
+}
+
+func TestColumnSetGenerated(t *testing.T) {
+	col := NewColumn("full_name", "varchar", true)
+	if col.IsGenerated() || col.IsInvisible() {
+		t.Fatal("expected a plain visible column initially")
+	}
+	col.SetGenerated(GenerationStored, "concat(first, ' ', last)")
+	col.SetInvisible(true)
+
+	data, err := json.Marshal(col)
+	if err != nil {
+		t.Fatalf("failed to marshal column: %v", err)
+	}
+	var decoded Column
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal column: %v", err)
+	}
+	if decoded.Generation() != GenerationStored || decoded.GenerationExpression() != "concat(first, ' ', last)" || !decoded.IsInvisible() {
+		t.Errorf("unexpected column after a round trip: %s", data)
+	}
+}
+
 func TestColumnSetTable(t *testing.T) {
 	col := NewColumn("user_id", "integer", false)
 	table := NewTable("users", make(map[string]*Column))
@@ -147,4 +200,38 @@ func TestColumnNullableFieldsOmitEmpty(t *testing.T) {
 	if _, exists := result["numericScale"]; exists {
 		t.Error("expected numericScale to be omitted")
 	}
+	if _, exists := result["comment"]; exists {
+		t.Error("expected comment to be omitted")
+	}
+}
+
+func TestColumnSetDataType(t *testing.T) {
+	col := NewColumn("price", "numeric", false)
+	col.SetNumericPrecision(10)
+	col.SetNumericScale(2)
+
+	col.SetDataType("varchar")
+	if col.DataType() != "varchar" {
+		t.Errorf("expected data type 'varchar', got %q", col.DataType())
+	}
+	if col.NumericPrecision() != nil || col.NumericScale() != nil || col.CharMaxLength() != nil {
+		t.Error("expected the modifiers of the old type to be cleared")
+	}
+}
+
+func TestColumnSetNullable(t *testing.T) {
+	col := NewColumn("email", "varchar", false)
+	col.SetNullable(true)
+	if !col.IsNullable() {
+		t.Error("expected column to be nullable")
+	}
+}
+
+func TestColumnClearDefaultValue(t *testing.T) {
+	col := NewColumn("status", "varchar", false)
+	col.SetDefaultValue("'active'")
+	col.ClearDefaultValue()
+	if col.DefaultValue() != nil {
+		t.Errorf("expected no default, got %q", *col.DefaultValue())
+	}
 }
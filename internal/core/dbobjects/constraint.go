@@ -36,6 +36,27 @@ func (c *Constraint) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON decodes the constraint columns as placeholders, which the table
+// replaces with its own columns
+func (c *Constraint) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name            string         `json:"name"`
+		Type            ConstraintType `json:"type"`
+		Columns         []string       `json:"columns"`
+		CheckExpression string         `json:"checkExpression"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*c = Constraint{
+		name:            aux.Name,
+		constraintType:  aux.Type,
+		columns:         columnRefs(aux.Columns),
+		checkExpression: aux.CheckExpression,
+	}
+	return nil
+}
+
 func NewConstraint(name string, constraintType ConstraintType) *Constraint {
 	return &Constraint{
 		name:           name,
@@ -48,6 +69,10 @@ func (c *Constraint) Name() string {
 	return c.name
 }
 
+func (c *Constraint) SetName(name string) {
+	c.name = name
+}
+
 func (c *Constraint) Type() ConstraintType {
 	return c.constraintType
 }
@@ -164,3 +164,11 @@ func TestConstraintMarshalJSONEmptyColumns(t *testing.T) {
 		t.Errorf("expected empty columns, got %v", columns)
 	}
 }
+
+func TestConstraintSetName(t *testing.T) {
+	c := NewConstraint("chk_old", ConstraintTypeCheck)
+	c.SetName("chk_new")
+	if c.Name() != "chk_new" {
+		t.Errorf("expected name 'chk_new', got %q", c.Name())
+	}
+}
@@ -1,22 +1,118 @@
 package dbobjects
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
 
 type Database struct {
-	name    string
-	schemas map[string]*Schema
+	name         string
+	engine       string
+	schemas      map[string]*Schema
+	dependencies []*Dependency
+	roles        map[string]*Role
+	grants       []*Grant
 }
 
 func (d *Database) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Name    string             `json:"name"`
-		Schemas map[string]*Schema `json:"schemas"`
+		Name         string             `json:"name"`
+		Engine       string             `json:"engine,omitempty"`
+		Schemas      map[string]*Schema `json:"schemas"`
+		Dependencies []*Dependency      `json:"dependencies,omitempty"`
+		Roles        map[string]*Role   `json:"roles,omitempty"`
+		Grants       []*Grant           `json:"grants,omitempty"`
 	}{
-		Name:    d.name,
-		Schemas: d.schemas,
+		Name:         d.name,
+		Engine:       d.engine,
+		Schemas:      d.schemas,
+		Dependencies: d.dependencies,
+		Roles:        d.roles,
+		Grants:       d.grants,
 	})
 }
 
+// UnmarshalJSON decodes a database from either its own JSON or the database of a json
+// report. Besides the links each schema and table restores, it links foreign keys to
+// the columns they reference, triggers to functions of other schemas and roles to the
+// roles they are members of.
+func (d *Database) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name         string          `json:"name"`
+		Engine       string          `json:"engine"`
+		Schemas      json.RawMessage `json:"schemas"`
+		Dependencies []*Dependency   `json:"dependencies"`
+		Roles        json.RawMessage `json:"roles"`
+		Grants       []*Grant        `json:"grants"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	schemas, err := decodeList[Schema](aux.Schemas)
+	if err != nil {
+		return err
+	}
+	roles, err := decodeList[Role](aux.Roles)
+	if err != nil {
+		return err
+	}
+
+	*d = *NewDatabase(aux.Name, nil)
+	d.engine = aux.Engine
+	for _, schema := range schemas {
+		d.AddSchema(schema)
+	}
+	for _, dependency := range aux.Dependencies {
+		if dependency != nil {
+			d.AddDependency(dependency)
+		}
+	}
+	for _, role := range roles {
+		d.AddRole(role)
+	}
+	for _, role := range d.roles {
+		d.resolveRoles(role.memberOf)
+		d.resolveRoles(role.defaultRoles)
+	}
+	for _, grant := range aux.Grants {
+		if grant != nil {
+			d.AddGrant(grant)
+		}
+	}
+
+	schemaNames := slices.Sorted(maps.Keys(d.schemas))
+	for _, schema := range d.schemas {
+		for _, table := range schema.tables {
+			for _, fk := range table.foreignKeys {
+				refSchema := schema
+				if fk.referencedSchema != "" {
+					refSchema = d.schemas[fk.referencedSchema]
+				}
+				if refSchema == nil {
+					continue
+				}
+				if refTable, ok := refSchema.tables[fk.referencedTable]; ok {
+					refTable.resolveColumns(fk.referencedColumns)
+				}
+			}
+
+			for _, trigger := range table.triggers {
+				if trigger.function == nil || trigger.function.Schema() != nil {
+					continue
+				}
+				for _, name := range schemaNames {
+					if function, ok := d.schemas[name].functions[trigger.function.Name()]; ok {
+						trigger.SetFunction(function)
+						break
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func NewDatabase(name string, schemas map[string]*Schema) *Database {
 	if schemas == nil {
 		schemas = make(map[string]*Schema)
@@ -24,6 +120,7 @@ func NewDatabase(name string, schemas map[string]*Schema) *Database {
 	return &Database{
 		name:    name,
 		schemas: schemas,
+		roles:   make(map[string]*Role),
 	}
 }
 
@@ -31,6 +128,15 @@ func (d *Database) Name() string {
 	return d.name
 }
 
+// Engine returns the database product the model was mapped from, e.g. "PostgreSQL"
+func (d *Database) Engine() string {
+	return d.engine
+}
+
+func (d *Database) SetEngine(engine string) {
+	d.engine = engine
+}
+
 func (d *Database) Schemas() map[string]*Schema {
 	return d.schemas
 }
@@ -39,3 +145,30 @@ func (d *Database) AddSchema(schema *Schema) {
 	schema.SetDatabase(d)
 	d.schemas[schema.Name()] = schema
 }
+
+// Dependencies returns the non-foreign-key dependencies between objects of the database
+func (d *Database) Dependencies() []*Dependency {
+	return d.dependencies
+}
+
+func (d *Database) AddDependency(dependency *Dependency) {
+	d.dependencies = append(d.dependencies, dependency)
+}
+
+// Roles returns the roles and accounts of the server, keyed by name
+func (d *Database) Roles() map[string]*Role {
+	return d.roles
+}
+
+func (d *Database) AddRole(role *Role) {
+	d.roles[role.Name()] = role
+}
+
+// Grants returns the privileges held by the roles of the server
+func (d *Database) Grants() []*Grant {
+	return d.grants
+}
+
+func (d *Database) AddGrant(grant *Grant) {
+	d.grants = append(d.grants, grant)
+}
@@ -35,6 +35,20 @@ func TestNewDatabaseWithSchemas(t *testing.T) {
 	}
 }
 
+func TestDatabaseSetEngine(t *testing.T) {
+	db := NewDatabase("testdb", nil)
+
+	if db.Engine() != "" {
+		t.Errorf("expected empty engine initially, got %q", db.Engine())
+	}
+
+	db.SetEngine("PostgreSQL")
+
+	if db.Engine() != "PostgreSQL" {
+		t.Errorf("expected engine 'PostgreSQL', got %q", db.Engine())
+	}
+}
+
 func TestDatabaseAddSchema(t *testing.T) {
 	db := NewDatabase("testdb", nil)
 	schema1 := NewSchema("public", "owner1", nil)
@@ -0,0 +1,162 @@
+package dbobjects
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type ObjectType string
+
+const (
+	ObjectTypeTable            ObjectType = "TABLE"
+	ObjectTypeView             ObjectType = "VIEW"
+	ObjectTypeMaterializedView ObjectType = "MATERIALIZED VIEW"
+	ObjectTypeColumn           ObjectType = "COLUMN"
+	ObjectTypeSequence         ObjectType = "SEQUENCE"
+	ObjectTypeFunction         ObjectType = "FUNCTION"
+	ObjectTypeProcedure        ObjectType = "PROCEDURE"
+	ObjectTypeTrigger          ObjectType = "TRIGGER"
+)
+
+// ObjectReference identifies a database object by kind and name. Columns and
+// triggers carry the name of their table as parent.
+type ObjectReference struct {
+	objectType ObjectType
+	schema     string
+	parent     string
+	name       string
+}
+
+func (r *ObjectReference) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   ObjectType `json:"type"`
+		Schema string     `json:"schema,omitempty"`
+		Parent string     `json:"parent,omitempty"`
+		Name   string     `json:"name"`
+	}{
+		Type:   r.objectType,
+		Schema: r.schema,
+		Parent: r.parent,
+		Name:   r.name,
+	})
+}
+
+func (r *ObjectReference) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Type   ObjectType `json:"type"`
+		Schema string     `json:"schema"`
+		Parent string     `json:"parent"`
+		Name   string     `json:"name"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*r = ObjectReference{
+		objectType: aux.Type,
+		schema:     aux.Schema,
+		parent:     aux.Parent,
+		name:       aux.Name,
+	}
+	return nil
+}
+
+func NewObjectReference(objectType ObjectType, schema string, name string) *ObjectReference {
+	return &ObjectReference{
+		objectType: objectType,
+		schema:     schema,
+		name:       name,
+	}
+}
+
+func (r *ObjectReference) Type() ObjectType {
+	return r.objectType
+}
+
+func (r *ObjectReference) Schema() string {
+	return r.schema
+}
+
+func (r *ObjectReference) Parent() string {
+	return r.parent
+}
+
+func (r *ObjectReference) SetParent(parent string) {
+	r.parent = parent
+}
+
+func (r *ObjectReference) Name() string {
+	return r.name
+}
+
+// FullyQualifiedName returns schema.name, or schema.parent.name for columns and triggers
+func (r *ObjectReference) FullyQualifiedName() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{r.schema, r.parent, r.name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// Dependency records that one object uses another outside of a foreign key, e.g. a
+// view selecting from a table or a column default calling nextval on a sequence
+type Dependency struct {
+	dependent  *ObjectReference
+	referenced *ObjectReference
+	inferred   bool
+}
+
+func (d *Dependency) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Dependent  *ObjectReference `json:"dependent"`
+		Referenced *ObjectReference `json:"referenced"`
+		Inferred   bool             `json:"inferred,omitempty"`
+	}{
+		Dependent:  d.dependent,
+		Referenced: d.referenced,
+		Inferred:   d.inferred,
+	})
+}
+
+func (d *Dependency) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Dependent  *ObjectReference `json:"dependent"`
+		Referenced *ObjectReference `json:"referenced"`
+		Inferred   bool             `json:"inferred"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*d = Dependency{
+		dependent:  aux.Dependent,
+		referenced: aux.Referenced,
+		inferred:   aux.Inferred,
+	}
+	return nil
+}
+
+func NewDependency(dependent *ObjectReference, referenced *ObjectReference) *Dependency {
+	return &Dependency{
+		dependent:  dependent,
+		referenced: referenced,
+	}
+}
+
+func (d *Dependency) Dependent() *ObjectReference {
+	return d.dependent
+}
+
+func (d *Dependency) Referenced() *ObjectReference {
+	return d.referenced
+}
+
+// Inferred reports whether the dependency was derived from object definitions rather
+// than read from the database catalog
+func (d *Dependency) Inferred() bool {
+	return d.inferred
+}
+
+func (d *Dependency) SetInferred(inferred bool) {
+	d.inferred = inferred
+}
@@ -0,0 +1,100 @@
+package dbobjects
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewObjectReference(t *testing.T) {
+	ref := NewObjectReference(ObjectTypeView, "public", "active_users")
+
+	if ref.Type() != ObjectTypeView {
+		t.Errorf("expected type %q, got %q", ObjectTypeView, ref.Type())
+	}
+	if ref.Schema() != "public" {
+		t.Errorf("expected schema 'public', got %q", ref.Schema())
+	}
+	if ref.Name() != "active_users" {
+		t.Errorf("expected name 'active_users', got %q", ref.Name())
+	}
+	if ref.Parent() != "" {
+		t.Errorf("expected empty parent, got %q", ref.Parent())
+	}
+}
+
+func TestObjectReferenceFullyQualifiedName(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      func() *ObjectReference
+		expected string
+	}{
+		{"schema object", func() *ObjectReference {
+			return NewObjectReference(ObjectTypeTable, "public", "users")
+		}, "public.users"},
+		{"column with parent", func() *ObjectReference {
+			ref := NewObjectReference(ObjectTypeColumn, "public", "email")
+			ref.SetParent("users")
+			return ref
+		}, "public.users.email"},
+		{"no schema", func() *ObjectReference {
+			return NewObjectReference(ObjectTypeFunction, "", "now")
+		}, "now"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ref().FullyQualifiedName(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDependencyMarshalJSON(t *testing.T) {
+	column := NewObjectReference(ObjectTypeColumn, "public", "id")
+	column.SetParent("orders")
+	dep := NewDependency(column, NewObjectReference(ObjectTypeSequence, "public", "orders_id_seq"))
+
+	data, err := json.Marshal(dep)
+	if err != nil {
+		t.Fatalf("failed to marshal dependency: %v", err)
+	}
+
+	expected := `{"dependent":{"type":"COLUMN","schema":"public","parent":"orders","name":"id"},"referenced":{"type":"SEQUENCE","schema":"public","name":"orders_id_seq"}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	dep.SetInferred(true)
+	if !dep.Inferred() {
+		t.Error("expected dependency to be inferred")
+	}
+	data, err = json.Marshal(dep)
+	if err != nil {
+		t.Fatalf("failed to marshal dependency: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("failed to unmarshal json: %v", err)
+	}
+	if result["inferred"] != true {
+		t.Errorf("expected inferred flag in JSON, got %v", result["inferred"])
+	}
+}
+
+func TestDatabaseAddDependency(t *testing.T) {
+	db := NewDatabase("testdb", nil)
+	if len(db.Dependencies()) != 0 {
+		t.Fatalf("expected no dependencies, got %d", len(db.Dependencies()))
+	}
+
+	dep := NewDependency(
+		NewObjectReference(ObjectTypeView, "public", "active_users"),
+		NewObjectReference(ObjectTypeTable, "public", "users"),
+	)
+	db.AddDependency(dep)
+
+	if len(db.Dependencies()) != 1 || db.Dependencies()[0] != dep {
+		t.Errorf("expected dependency to be added, got %v", db.Dependencies())
+	}
+}
@@ -1,10 +1,35 @@
 package dbobjects
 
+import "encoding/json"
+
 type Enum struct {
 	name   string
+	schema *Schema
 	values []string
 }
 
+func (e *Enum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	}{
+		Name:   e.name,
+		Values: e.values,
+	})
+}
+
+func (e *Enum) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*e = *NewEnum(aux.Name, aux.Values)
+	return nil
+}
+
 func NewEnum(name string, values []string) *Enum {
 	return &Enum{
 		name:   name,
@@ -19,3 +44,19 @@ func (e *Enum) Name() string {
 func (e *Enum) Values() []string {
 	return e.values
 }
+
+func (e *Enum) Schema() *Schema {
+	return e.schema
+}
+
+func (e *Enum) SetSchema(schema *Schema) {
+	e.schema = schema
+}
+
+// FullyQualifiedName returns schema.enum format if schema is set
+func (e *Enum) FullyQualifiedName() string {
+	if e.schema != nil {
+		return e.schema.Name() + "." + e.name
+	}
+	return e.name
+}
@@ -1,6 +1,9 @@
 package dbobjects
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestNewEnum(t *testing.T) {
 	values := []string{"pending", "approved", "rejected"}
@@ -56,3 +59,15 @@ func TestEnumWithEmptyValues(t *testing.T) {
 		t.Errorf("expected empty values, got %d", len(e.Values()))
 	}
 }
+
+func TestEnumMarshalJSON(t *testing.T) {
+	e := NewEnum("priority_enum", []string{"low", "high"})
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("failed to marshal enum: %v", err)
+	}
+	if string(data) != `{"name":"priority_enum","values":["low","high"]}` {
+		t.Errorf("unexpected json %s", data)
+	}
+}
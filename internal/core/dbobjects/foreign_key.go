@@ -51,6 +51,34 @@ func (fk *ForeignKey) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON decodes the local and referenced columns as placeholders. The table
+// replaces the local ones; the database replaces the referenced ones once the
+// referenced table is decoded.
+func (fk *ForeignKey) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name              string            `json:"name"`
+		Columns           []string          `json:"columns"`
+		ReferencedSchema  string            `json:"referencedSchema"`
+		ReferencedTable   string            `json:"referencedTable"`
+		ReferencedColumns []string          `json:"referencedColumns"`
+		OnDelete          ReferentialAction `json:"onDelete"`
+		OnUpdate          ReferentialAction `json:"onUpdate"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*fk = ForeignKey{
+		name:              aux.Name,
+		columns:           columnRefs(aux.Columns),
+		referencedSchema:  aux.ReferencedSchema,
+		referencedTable:   aux.ReferencedTable,
+		referencedColumns: columnRefs(aux.ReferencedColumns),
+		onDelete:          aux.OnDelete,
+		onUpdate:          aux.OnUpdate,
+	}
+	return nil
+}
+
 func NewForeignKey(name string, referencedTable string) *ForeignKey {
 	return &ForeignKey{
 		name:              name,
@@ -66,6 +94,10 @@ func (fk *ForeignKey) Name() string {
 	return fk.name
 }
 
+func (fk *ForeignKey) SetName(name string) {
+	fk.name = name
+}
+
 func (fk *ForeignKey) Table() *Table {
 	return fk.table
 }
@@ -216,3 +216,11 @@ func TestForeignKeyMarshalJSONEmptyColumns(t *testing.T) {
 		t.Errorf("expected empty referencedColumns, got %v", refColumns)
 	}
 }
+
+func TestForeignKeySetName(t *testing.T) {
+	fk := NewForeignKey("fk_old", "users")
+	fk.SetName("fk_new")
+	if fk.Name() != "fk_new" {
+		t.Errorf("expected name 'fk_new', got %q", fk.Name())
+	}
+}
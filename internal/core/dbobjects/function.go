@@ -10,6 +10,14 @@ const (
 	ParameterModeInOut ParameterMode = "INOUT"
 )
 
+// SecurityType is whose privileges a routine runs with
+type SecurityType string
+
+const (
+	SecurityDefiner SecurityType = "DEFINER"
+	SecurityInvoker SecurityType = "INVOKER"
+)
+
 type FunctionParameter struct {
 	name     string
 	dataType string
@@ -28,6 +36,19 @@ func (p *FunctionParameter) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (p *FunctionParameter) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name     string        `json:"name"`
+		DataType string        `json:"dataType"`
+		Mode     ParameterMode `json:"mode"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*p = *NewFunctionParameter(aux.Name, aux.DataType, aux.Mode)
+	return nil
+}
+
 func NewFunctionParameter(name string, dataType string, mode ParameterMode) *FunctionParameter {
 	return &FunctionParameter{
 		name:     name,
@@ -49,30 +70,75 @@ func (p *FunctionParameter) Mode() ParameterMode {
 }
 
 type Function struct {
-	name       string
-	schema     *Schema
-	definition string
-	returnType string
-	parameters []*FunctionParameter
-	language   string
+	name          string
+	schema        *Schema
+	definition    string
+	returnType    string
+	parameters    []*FunctionParameter
+	language      string
+	securityType  SecurityType
+	definer       string
+	deterministic bool
+	sqlDataAccess string
+	sqlMode       string
 }
 
 func (f *Function) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Name       string               `json:"name"`
-		Definition string               `json:"definition"`
-		ReturnType string               `json:"returnType"`
-		Parameters []*FunctionParameter `json:"parameters,omitempty"`
-		Language   string               `json:"language"`
+		Name          string               `json:"name"`
+		Definition    string               `json:"definition"`
+		ReturnType    string               `json:"returnType"`
+		Parameters    []*FunctionParameter `json:"parameters,omitempty"`
+		Language      string               `json:"language"`
+		SecurityType  SecurityType         `json:"securityType,omitempty"`
+		Definer       string               `json:"definer,omitempty"`
+		Deterministic bool                 `json:"deterministic,omitempty"`
+		SQLDataAccess string               `json:"sqlDataAccess,omitempty"`
+		SQLMode       string               `json:"sqlMode,omitempty"`
 	}{
-		Name:       f.name,
-		Definition: f.definition,
-		ReturnType: f.returnType,
-		Parameters: f.parameters,
-		Language:   f.language,
+		Name:          f.name,
+		Definition:    f.definition,
+		ReturnType:    f.returnType,
+		Parameters:    f.parameters,
+		Language:      f.language,
+		SecurityType:  f.securityType,
+		Definer:       f.definer,
+		Deterministic: f.deterministic,
+		SQLDataAccess: f.sqlDataAccess,
+		SQLMode:       f.sqlMode,
 	})
 }
 
+func (f *Function) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name          string               `json:"name"`
+		Definition    string               `json:"definition"`
+		ReturnType    string               `json:"returnType"`
+		Parameters    []*FunctionParameter `json:"parameters"`
+		Language      string               `json:"language"`
+		SecurityType  SecurityType         `json:"securityType"`
+		Definer       string               `json:"definer"`
+		Deterministic bool                 `json:"deterministic"`
+		SQLDataAccess string               `json:"sqlDataAccess"`
+		SQLMode       string               `json:"sqlMode"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*f = *NewFunction(aux.Name, aux.Definition)
+	f.returnType = aux.ReturnType
+	f.language = aux.Language
+	f.securityType = aux.SecurityType
+	f.definer = aux.Definer
+	f.deterministic = aux.Deterministic
+	f.sqlDataAccess = aux.SQLDataAccess
+	f.sqlMode = aux.SQLMode
+	for _, param := range aux.Parameters {
+		f.AddParameter(param)
+	}
+	return nil
+}
+
 func NewFunction(name string, definition string) *Function {
 	return &Function{
 		name:       name,
@@ -126,6 +192,53 @@ func (f *Function) SetLanguage(language string) {
 	f.language = language
 }
 
+// SecurityType returns whose privileges the function runs with, or "" when unknown
+func (f *Function) SecurityType() SecurityType {
+	return f.securityType
+}
+
+func (f *Function) SetSecurityType(securityType SecurityType) {
+	f.securityType = securityType
+}
+
+// Definer returns the name of the role that owns the function, as in the roles of its
+// database
+func (f *Function) Definer() string {
+	return f.definer
+}
+
+func (f *Function) SetDefiner(definer string) {
+	f.definer = definer
+}
+
+// IsDeterministic reports whether the function is declared to return the same result for
+// the same arguments
+func (f *Function) IsDeterministic() bool {
+	return f.deterministic
+}
+
+func (f *Function) SetDeterministic(deterministic bool) {
+	f.deterministic = deterministic
+}
+
+// SQLDataAccess returns what the function is declared to do with data, e.g. "READS SQL DATA"
+func (f *Function) SQLDataAccess() string {
+	return f.sqlDataAccess
+}
+
+func (f *Function) SetSQLDataAccess(sqlDataAccess string) {
+	f.sqlDataAccess = sqlDataAccess
+}
+
+// SQLMode returns the SQL mode the function was created with, which it runs under
+func (f *Function) SQLMode() string {
+	return f.sqlMode
+}
+
+func (f *Function) SetSQLMode(sqlMode string) {
+	f.sqlMode = sqlMode
+}
+
 // FullyQualifiedName returns schema.function format if schema is set
 func (f *Function) FullyQualifiedName() string {
 	if f.schema != nil {
@@ -224,3 +224,31 @@ func TestFunctionMarshalJSONOmitsEmptyParameters(t *testing.T) {
 		}
 	}
 }
+
+func TestFunctionSecurityContext(t *testing.T) {
+	f := NewFunction("next_code", "RETURN UUID()")
+	if f.SecurityType() != "" || f.Definer() != "" || f.IsDeterministic() {
+		t.Error("expected no security context by default")
+	}
+
+	f.SetSecurityType(SecurityDefiner)
+	f.SetDefiner("'app'@'%'")
+	f.SetDeterministic(true)
+	f.SetSQLDataAccess("READS SQL DATA")
+	f.SetSQLMode("STRICT_TRANS_TABLES")
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("failed to marshal function: %v", err)
+	}
+	var decoded Function
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal function: %v", err)
+	}
+	if decoded.SecurityType() != SecurityDefiner || decoded.Definer() != "'app'@'%'" || !decoded.IsDeterministic() {
+		t.Errorf("expected the security context to round trip, got %s", data)
+	}
+	if decoded.SQLDataAccess() != "READS SQL DATA" || decoded.SQLMode() != "STRICT_TRANS_TABLES" {
+		t.Errorf("expected the SQL data access and mode to round trip, got %s", data)
+	}
+}
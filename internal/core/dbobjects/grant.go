@@ -1,8 +1,77 @@
 package dbobjects
 
+import "encoding/json"
+
+// GrantLevel is the scope of the objects a privilege is granted on
+type GrantLevel string
+
+const (
+	GrantLevelGlobal GrantLevel = "GLOBAL"
+	GrantLevelSchema GrantLevel = "SCHEMA"
+	GrantLevelTable  GrantLevel = "TABLE"
+	GrantLevelColumn GrantLevel = "COLUMN"
+)
+
+// Grant is a privilege held by a role. Grants on the whole server have no schema,
+// grants on a schema no table and grants on a table no column.
 type Grant struct {
-	name       string
-	definition string
+	name        string
+	definition  string
+	grantee     string
+	privilege   string
+	schema      string
+	table       string
+	column      string
+	isGrantable bool
+}
+
+func (g *Grant) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name        string `json:"name"`
+		Definition  string `json:"definition"`
+		Grantee     string `json:"grantee,omitempty"`
+		Privilege   string `json:"privilege,omitempty"`
+		Schema      string `json:"schema,omitempty"`
+		Table       string `json:"table,omitempty"`
+		Column      string `json:"column,omitempty"`
+		IsGrantable bool   `json:"isGrantable,omitempty"`
+	}{
+		Name:        g.name,
+		Definition:  g.definition,
+		Grantee:     g.grantee,
+		Privilege:   g.privilege,
+		Schema:      g.schema,
+		Table:       g.table,
+		Column:      g.column,
+		IsGrantable: g.isGrantable,
+	})
+}
+
+func (g *Grant) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name        string `json:"name"`
+		Definition  string `json:"definition"`
+		Grantee     string `json:"grantee"`
+		Privilege   string `json:"privilege"`
+		Schema      string `json:"schema"`
+		Table       string `json:"table"`
+		Column      string `json:"column"`
+		IsGrantable bool   `json:"isGrantable"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*g = Grant{
+		name:        aux.Name,
+		definition:  aux.Definition,
+		grantee:     aux.Grantee,
+		privilege:   aux.Privilege,
+		schema:      aux.Schema,
+		table:       aux.Table,
+		column:      aux.Column,
+		isGrantable: aux.IsGrantable,
+	}
+	return nil
 }
 
 func NewGrant(name string, definition string) *Grant {
@@ -19,3 +88,68 @@ func (g *Grant) Name() string {
 func (g *Grant) Definition() string {
 	return g.definition
 }
+
+func (g *Grant) SetDefinition(definition string) {
+	g.definition = definition
+}
+
+// Grantee returns the name of the role holding the privilege
+func (g *Grant) Grantee() string {
+	return g.grantee
+}
+
+func (g *Grant) SetGrantee(grantee string) {
+	g.grantee = grantee
+}
+
+// Privilege returns the privilege granted, e.g. "SELECT"
+func (g *Grant) Privilege() string {
+	return g.privilege
+}
+
+func (g *Grant) SetPrivilege(privilege string) {
+	g.privilege = privilege
+}
+
+// SetObject sets the schema, table and column the privilege is granted on. Empty
+// names widen the grant to the enclosing level.
+func (g *Grant) SetObject(schema, table, column string) {
+	g.schema = schema
+	g.table = table
+	g.column = column
+}
+
+func (g *Grant) SchemaName() string {
+	return g.schema
+}
+
+func (g *Grant) TableName() string {
+	return g.table
+}
+
+func (g *Grant) ColumnName() string {
+	return g.column
+}
+
+// Level returns the scope of the grant from the object it is granted on
+func (g *Grant) Level() GrantLevel {
+	switch {
+	case g.schema == "":
+		return GrantLevelGlobal
+	case g.table == "":
+		return GrantLevelSchema
+	case g.column == "":
+		return GrantLevelTable
+	default:
+		return GrantLevelColumn
+	}
+}
+
+// IsGrantable reports whether the grantee may grant the privilege to other roles
+func (g *Grant) IsGrantable() bool {
+	return g.isGrantable
+}
+
+func (g *Grant) SetGrantable(isGrantable bool) {
+	g.isGrantable = isGrantable
+}
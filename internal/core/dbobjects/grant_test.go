@@ -28,3 +28,36 @@ func TestGrantDefinition(t *testing.T) {
 		t.Errorf("expected definition, got %q", g.Definition())
 	}
 }
+
+func TestGrantLevel(t *testing.T) {
+	tests := []struct {
+		schema, table, column string
+		want                  GrantLevel
+	}{
+		{"", "", "", GrantLevelGlobal},
+		{"shop", "", "", GrantLevelSchema},
+		{"shop", "orders", "", GrantLevelTable},
+		{"shop", "orders", "total", GrantLevelColumn},
+	}
+	for _, tt := range tests {
+		g := NewGrant("SELECT", "")
+		g.SetObject(tt.schema, tt.table, tt.column)
+		if got := g.Level(); got != tt.want {
+			t.Errorf("Level() on %q.%q.%q = %q, want %q", tt.schema, tt.table, tt.column, got, tt.want)
+		}
+		if g.SchemaName() != tt.schema || g.TableName() != tt.table || g.ColumnName() != tt.column {
+			t.Errorf("expected object %q.%q.%q, got %q.%q.%q", tt.schema, tt.table, tt.column, g.SchemaName(), g.TableName(), g.ColumnName())
+		}
+	}
+}
+
+func TestGrantGranteeAndPrivilege(t *testing.T) {
+	g := NewGrant("SELECT", "GRANT SELECT ON *.* TO 'app'@'%' WITH GRANT OPTION")
+	g.SetGrantee("'app'@'%'")
+	g.SetPrivilege("SELECT")
+	g.SetGrantable(true)
+
+	if g.Grantee() != "'app'@'%'" || g.Privilege() != "SELECT" || !g.IsGrantable() {
+		t.Errorf("unexpected grant %q %q %v", g.Grantee(), g.Privilege(), g.IsGrantable())
+	}
+}
@@ -0,0 +1,39 @@
+package dbobjects
+
+import "strings"
+
+// ObjectID appends the name of an object to the ID of its parent, as in public.users.id,
+// giving the stable identifiers of the inventory reports and of findings. Names holding
+// a dot, a double quote or a parenthesis are double quoted like SQL identifiers, so no
+// two objects share an ID. An empty parent ID (an object without a known parent) is
+// dropped.
+func ObjectID(parentID, name string) string {
+	if strings.ContainsAny(name, `."()`) {
+		name = `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	if parentID == "" {
+		return name
+	}
+	return parentID + "." + name
+}
+
+// RoutineID is the ObjectID of a function or procedure followed by the data types of
+// its input parameters, as in public.area(int,int), since overloaded routines share a
+// name
+func RoutineID(schemaID, name string, params []*FunctionParameter) string {
+	var types []string
+	for _, p := range params {
+		if p.Mode() != ParameterModeOut {
+			types = append(types, p.DataType())
+		}
+	}
+	return ObjectID(schemaID, name) + "(" + strings.Join(types, ",") + ")"
+}
+
+// TableID returns the ObjectID of a table
+func TableID(table *Table) string {
+	if table.Schema() == nil {
+		return ObjectID("", table.Name())
+	}
+	return ObjectID(ObjectID("", table.Schema().Name()), table.Name())
+}
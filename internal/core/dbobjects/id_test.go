@@ -0,0 +1,50 @@
+package dbobjects
+
+import "testing"
+
+func TestObjectID(t *testing.T) {
+	tests := []struct {
+		parentID, name, want string
+	}{
+		{"public", "users", "public.users"},
+		{"", "users", "users"},
+		{"public.users", "id", "public.users.id"},
+		{"public", "a.b", `public."a.b"`},
+		{"", `say "hi"`, `"say ""hi"""`},
+		{"public", "f(x)", `public."f(x)"`},
+	}
+	for _, tt := range tests {
+		if got := ObjectID(tt.parentID, tt.name); got != tt.want {
+			t.Errorf("ObjectID(%q, %q) = %q, want %q", tt.parentID, tt.name, got, tt.want)
+		}
+	}
+	if ObjectID("a", "b.c") == ObjectID("a.b", "c") {
+		t.Error("expected a dotted name not to collide with a nested one")
+	}
+}
+
+func TestRoutineID(t *testing.T) {
+	params := []*FunctionParameter{
+		NewFunctionParameter("w", "int", ParameterModeIn),
+		NewFunctionParameter("h", "numeric(10,2)", ParameterModeInOut),
+		NewFunctionParameter("result", "int", ParameterModeOut),
+	}
+	if got := RoutineID("public", "area", params); got != "public.area(int,numeric(10,2))" {
+		t.Errorf("unexpected routine ID %q", got)
+	}
+	if got := RoutineID("public", "area", nil); got != "public.area()" {
+		t.Errorf("unexpected routine ID %q", got)
+	}
+}
+
+func TestTableID(t *testing.T) {
+	schema := NewSchema("my.schema", "", nil)
+	table := NewTable("users", nil)
+	schema.AddTable(table)
+	if got := TableID(table); got != `"my.schema".users` {
+		t.Errorf("unexpected table ID %q", got)
+	}
+	if got := TableID(NewTable("users", nil)); got != "users" {
+		t.Errorf("unexpected table ID %q", got)
+	}
+}
@@ -16,6 +16,16 @@ type Index struct {
 	isUnique  bool
 	isPrimary bool
 	indexType IndexType
+	// predicate is the WHERE clause of a partial index
+	predicate string
+	// expressions holds the key expressions of an expression index, in key order
+	expressions []string
+	// prefixLengths holds how many leading characters or bytes of each column are
+	// indexed, in column order, with 0 for the whole value. It is nil when every column
+	// is indexed whole.
+	prefixLengths []int
+	// invisible indexes are maintained but not used by the query planner
+	invisible bool
 }
 
 func (i *Index) MarshalJSON() ([]byte, error) {
@@ -24,20 +34,61 @@ func (i *Index) MarshalJSON() ([]byte, error) {
 		columnNames[idx] = col.Name()
 	}
 	return json.Marshal(struct {
-		Name      string    `json:"name"`
-		Columns   []string  `json:"columns"`
-		IsUnique  bool      `json:"isUnique"`
-		IsPrimary bool      `json:"isPrimary"`
-		IndexType IndexType `json:"indexType"`
+		Name          string    `json:"name"`
+		Columns       []string  `json:"columns"`
+		IsUnique      bool      `json:"isUnique"`
+		IsPrimary     bool      `json:"isPrimary"`
+		IndexType     IndexType `json:"indexType"`
+		Predicate     string    `json:"predicate,omitempty"`
+		Expressions   []string  `json:"expressions,omitempty"`
+		PrefixLengths []int     `json:"prefixLengths,omitempty"`
+		Invisible     bool      `json:"invisible,omitempty"`
 	}{
-		Name:      i.name,
-		Columns:   columnNames,
-		IsUnique:  i.isUnique,
-		IsPrimary: i.isPrimary,
-		IndexType: i.indexType,
+		Name:          i.name,
+		Columns:       columnNames,
+		IsUnique:      i.isUnique,
+		IsPrimary:     i.isPrimary,
+		IndexType:     i.indexType,
+		Predicate:     i.predicate,
+		Expressions:   i.expressions,
+		PrefixLengths: i.prefixLengths,
+		Invisible:     i.invisible,
 	})
 }
 
+// UnmarshalJSON decodes the index columns as placeholders, which the table replaces
+// with its own columns
+func (i *Index) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name          string    `json:"name"`
+		Columns       []string  `json:"columns"`
+		IsUnique      bool      `json:"isUnique"`
+		IsPrimary     bool      `json:"isPrimary"`
+		IndexType     IndexType `json:"indexType"`
+		Predicate     string    `json:"predicate"`
+		Expressions   []string  `json:"expressions"`
+		PrefixLengths []int     `json:"prefixLengths"`
+		Invisible     bool      `json:"invisible"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*i = Index{
+		name:        aux.Name,
+		columns:     columnRefs(aux.Columns),
+		isUnique:    aux.IsUnique,
+		isPrimary:   aux.IsPrimary,
+		indexType:   aux.IndexType,
+		predicate:   aux.Predicate,
+		expressions: aux.Expressions,
+		invisible:   aux.Invisible,
+	}
+	if len(aux.PrefixLengths) == len(aux.Columns) {
+		i.prefixLengths = aux.PrefixLengths
+	}
+	return nil
+}
+
 func NewIndex(name string, table *Table, columns []*Column, isUnique bool) *Index {
 	return &Index{
 		name:      name,
@@ -52,6 +103,10 @@ func (i *Index) Name() string {
 	return i.name
 }
 
+func (i *Index) SetName(name string) {
+	i.name = name
+}
+
 func (i *Index) Table() *Table {
 	return i.table
 }
@@ -66,6 +121,34 @@ func (i *Index) Columns() []*Column {
 
 func (i *Index) AddColumn(column *Column) {
 	i.columns = append(i.columns, column)
+	if i.prefixLengths != nil {
+		i.prefixLengths = append(i.prefixLengths, 0)
+	}
+}
+
+// AddPrefixColumn adds a column of which only the first length characters, or bytes
+// for binary types, are indexed
+func (i *Index) AddPrefixColumn(column *Column, length int) {
+	if i.prefixLengths == nil {
+		i.prefixLengths = make([]int, len(i.columns))
+	}
+	i.columns = append(i.columns, column)
+	i.prefixLengths = append(i.prefixLengths, length)
+}
+
+// PrefixLengths returns the indexed prefix length of each column, in column order, with
+// 0 for a column indexed whole, or nil when every column is indexed whole
+func (i *Index) PrefixLengths() []int {
+	return i.prefixLengths
+}
+
+// PrefixLength returns the indexed prefix length of the column at position, or 0 when
+// the whole value is indexed
+func (i *Index) PrefixLength(position int) int {
+	if position < 0 || position >= len(i.prefixLengths) {
+		return 0
+	}
+	return i.prefixLengths[position]
 }
 
 func (i *Index) IsUnique() bool {
@@ -91,3 +174,34 @@ func (i *Index) IndexType() IndexType {
 func (i *Index) SetIndexType(indexType IndexType) {
 	i.indexType = indexType
 }
+
+func (i *Index) Predicate() string {
+	return i.predicate
+}
+
+func (i *Index) SetPredicate(predicate string) {
+	i.predicate = predicate
+}
+
+func (i *Index) Expressions() []string {
+	return i.expressions
+}
+
+func (i *Index) AddExpression(expression string) {
+	i.expressions = append(i.expressions, expression)
+}
+
+// IsInvisible reports whether the index is hidden from the query planner. An invisible
+// unique index still enforces uniqueness.
+func (i *Index) IsInvisible() bool {
+	return i.invisible
+}
+
+func (i *Index) SetInvisible(invisible bool) {
+	i.invisible = invisible
+}
+
+// IsPartial reports whether the index only covers the rows matching its predicate
+func (i *Index) IsPartial() bool {
+	return i.predicate != ""
+}
@@ -190,3 +190,77 @@ func TestIndexMarshalJSONEmptyColumns(t *testing.T) {
 		t.Errorf("expected empty columns, got %v", columns)
 	}
 }
+
+func TestIndexPartialExpression(t *testing.T) {
+	table := NewTable("users", nil)
+	idx := NewIndex("users_lower_email", table, nil, true)
+
+	if idx.IsPartial() {
+		t.Error("expected index not to be partial by default")
+	}
+
+	idx.SetPredicate("deleted_at IS NULL")
+	idx.AddExpression("lower(email)")
+
+	if !idx.IsPartial() || idx.Predicate() != "deleted_at IS NULL" {
+		t.Errorf("expected predicate, got %q", idx.Predicate())
+	}
+	if len(idx.Expressions()) != 1 || idx.Expressions()[0] != "lower(email)" {
+		t.Errorf("expected expression, got %v", idx.Expressions())
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		t.Fatalf("failed to marshal index: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("failed to unmarshal json: %v", err)
+	}
+	if result["predicate"] != "deleted_at IS NULL" {
+		t.Errorf("expected predicate in JSON, got %v", result["predicate"])
+	}
+	if expressions, ok := result["expressions"].([]interface{}); !ok || len(expressions) != 1 {
+		t.Errorf("expected expressions in JSON, got %v", result["expressions"])
+	}
+}
+
+func TestIndexPrefixColumns(t *testing.T) {
+	table := NewTable("users", nil)
+	tenant := NewColumn("tenant_id", "int", false)
+	email := NewColumn("email", "varchar", false)
+	table.AddColumn(tenant)
+	table.AddColumn(email)
+
+	idx := NewIndex("idx_tenant_email", table, nil, true)
+	idx.AddColumn(tenant)
+	if idx.PrefixLengths() != nil {
+		t.Errorf("expected no prefix lengths, got %v", idx.PrefixLengths())
+	}
+	idx.AddPrefixColumn(email, 10)
+	idx.SetInvisible(true)
+
+	if len(idx.Columns()) != 2 || idx.PrefixLength(0) != 0 || idx.PrefixLength(1) != 10 || idx.PrefixLength(2) != 0 {
+		t.Errorf("unexpected prefix lengths %v", idx.PrefixLengths())
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		t.Fatalf("failed to marshal index: %v", err)
+	}
+	var decoded Index
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal index: %v", err)
+	}
+	if decoded.PrefixLength(1) != 10 || !decoded.IsInvisible() {
+		t.Errorf("expected prefix length 10 and an invisible index after a round trip, got %s", data)
+	}
+}
+
+func TestIndexSetName(t *testing.T) {
+	idx := NewIndex("idx_old", nil, nil, false)
+	idx.SetName("idx_new")
+	if idx.Name() != "idx_new" {
+		t.Errorf("expected name 'idx_new', got %q", idx.Name())
+	}
+}
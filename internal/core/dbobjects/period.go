@@ -0,0 +1,77 @@
+package dbobjects
+
+import "encoding/json"
+
+// PeriodSystemTime is the name of the period that holds the row validity of a
+// system-versioned table
+const PeriodSystemTime = "SYSTEM_TIME"
+
+// Period is a named time range of a table, bounded by a start and an end column, as in
+// PERIOD FOR SYSTEM_TIME (row_start, row_end).
+type Period struct {
+	name        string
+	table       *Table
+	startColumn *Column
+	endColumn   *Column
+}
+
+func (p *Period) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name        string `json:"name"`
+		StartColumn string `json:"startColumn"`
+		EndColumn   string `json:"endColumn"`
+	}{
+		Name:        p.name,
+		StartColumn: p.startColumn.Name(),
+		EndColumn:   p.endColumn.Name(),
+	})
+}
+
+// UnmarshalJSON decodes the bounding columns as placeholders, which the table replaces
+// with its own columns
+func (p *Period) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name        string `json:"name"`
+		StartColumn string `json:"startColumn"`
+		EndColumn   string `json:"endColumn"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*p = *NewPeriod(aux.Name, NewColumn(aux.StartColumn, "", false), NewColumn(aux.EndColumn, "", false))
+	return nil
+}
+
+func NewPeriod(name string, startColumn, endColumn *Column) *Period {
+	return &Period{
+		name:        name,
+		startColumn: startColumn,
+		endColumn:   endColumn,
+	}
+}
+
+func (p *Period) Name() string {
+	return p.name
+}
+
+func (p *Period) Table() *Table {
+	return p.table
+}
+
+func (p *Period) SetTable(table *Table) {
+	p.table = table
+}
+
+func (p *Period) StartColumn() *Column {
+	return p.startColumn
+}
+
+func (p *Period) EndColumn() *Column {
+	return p.endColumn
+}
+
+// IsSystemTime reports whether the period holds the row validity of a system-versioned
+// table
+func (p *Period) IsSystemTime() bool {
+	return p.name == PeriodSystemTime
+}
@@ -0,0 +1,82 @@
+package dbobjects
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewPeriod(t *testing.T) {
+	start := NewColumn("row_start", "timestamp", false)
+	end := NewColumn("row_end", "timestamp", false)
+	period := NewPeriod(PeriodSystemTime, start, end)
+
+	if period.Name() != "SYSTEM_TIME" {
+		t.Errorf("expected name 'SYSTEM_TIME', got %q", period.Name())
+	}
+	if period.StartColumn() != start || period.EndColumn() != end {
+		t.Error("expected start and end columns to be set")
+	}
+	if !period.IsSystemTime() {
+		t.Error("expected SYSTEM_TIME period to be the system period")
+	}
+	if NewPeriod("valid", start, end).IsSystemTime() {
+		t.Error("expected application-time period not to be the system period")
+	}
+}
+
+func TestTableSystemPeriod(t *testing.T) {
+	table := NewTable("prices", nil)
+	if table.SystemPeriod() != nil {
+		t.Error("expected no system period on a new table")
+	}
+
+	start := NewColumn("valid_from", "date", false)
+	end := NewColumn("valid_to", "date", false)
+	table.AddPeriod(NewPeriod("valid", start, end))
+	system := NewPeriod(PeriodSystemTime, NewColumn("row_start", "timestamp", false), NewColumn("row_end", "timestamp", false))
+	table.AddPeriod(system)
+	table.SetSystemVersioned(true)
+
+	if table.SystemPeriod() != system {
+		t.Error("expected SystemPeriod to return the SYSTEM_TIME period")
+	}
+	if system.Table() != table {
+		t.Error("expected AddPeriod to set the period's table")
+	}
+	if !table.IsSystemVersioned() {
+		t.Error("expected table to be system-versioned")
+	}
+}
+
+func TestPeriodJSONRoundTrip(t *testing.T) {
+	table := NewTable("prices", nil)
+	start := NewColumn("row_start", "timestamp", false)
+	end := NewColumn("row_end", "timestamp", false)
+	table.AddColumn(start)
+	table.AddColumn(end)
+	table.AddPeriod(NewPeriod(PeriodSystemTime, start, end))
+	table.SetSystemVersioned(true)
+
+	data, err := json.Marshal(table)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var decoded Table
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if !decoded.IsSystemVersioned() {
+		t.Error("expected systemVersioned to survive the round trip")
+	}
+	period := decoded.SystemPeriod()
+	if period == nil {
+		t.Fatal("expected the system period to survive the round trip")
+	}
+	if period.StartColumn() != decoded.Columns()["row_start"] || period.EndColumn() != decoded.Columns()["row_end"] {
+		t.Error("expected period bounds to be linked to the table's columns")
+	}
+	if period.Table() != &decoded {
+		t.Error("expected period to be linked to the table")
+	}
+}
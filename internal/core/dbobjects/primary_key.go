@@ -22,6 +22,20 @@ func (pk *PrimaryKey) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON decodes the key columns as placeholders, which the table replaces with
+// its own columns
+func (pk *PrimaryKey) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name    string   `json:"name"`
+		Columns []string `json:"columns"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*pk = *NewPrimaryKey(aux.Name, nil, columnRefs(aux.Columns))
+	return nil
+}
+
 func NewPrimaryKey(name string, table *Table, columns []*Column) *PrimaryKey {
 	if columns == nil {
 		columns = []*Column{}
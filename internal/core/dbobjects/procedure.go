@@ -3,27 +3,70 @@ package dbobjects
 import "encoding/json"
 
 type Procedure struct {
-	name       string
-	schema     *Schema
-	definition string
-	parameters []*FunctionParameter
-	language   string
+	name          string
+	schema        *Schema
+	definition    string
+	parameters    []*FunctionParameter
+	language      string
+	securityType  SecurityType
+	definer       string
+	deterministic bool
+	sqlDataAccess string
+	sqlMode       string
 }
 
 func (p *Procedure) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Name       string               `json:"name"`
-		Definition string               `json:"definition"`
-		Parameters []*FunctionParameter `json:"parameters,omitempty"`
-		Language   string               `json:"language"`
+		Name          string               `json:"name"`
+		Definition    string               `json:"definition"`
+		Parameters    []*FunctionParameter `json:"parameters,omitempty"`
+		Language      string               `json:"language"`
+		SecurityType  SecurityType         `json:"securityType,omitempty"`
+		Definer       string               `json:"definer,omitempty"`
+		Deterministic bool                 `json:"deterministic,omitempty"`
+		SQLDataAccess string               `json:"sqlDataAccess,omitempty"`
+		SQLMode       string               `json:"sqlMode,omitempty"`
 	}{
-		Name:       p.name,
-		Definition: p.definition,
-		Parameters: p.parameters,
-		Language:   p.language,
+		Name:          p.name,
+		Definition:    p.definition,
+		Parameters:    p.parameters,
+		Language:      p.language,
+		SecurityType:  p.securityType,
+		Definer:       p.definer,
+		Deterministic: p.deterministic,
+		SQLDataAccess: p.sqlDataAccess,
+		SQLMode:       p.sqlMode,
 	})
 }
 
+func (p *Procedure) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name          string               `json:"name"`
+		Definition    string               `json:"definition"`
+		Parameters    []*FunctionParameter `json:"parameters"`
+		Language      string               `json:"language"`
+		SecurityType  SecurityType         `json:"securityType"`
+		Definer       string               `json:"definer"`
+		Deterministic bool                 `json:"deterministic"`
+		SQLDataAccess string               `json:"sqlDataAccess"`
+		SQLMode       string               `json:"sqlMode"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*p = *NewProcedure(aux.Name, aux.Definition)
+	p.language = aux.Language
+	p.securityType = aux.SecurityType
+	p.definer = aux.Definer
+	p.deterministic = aux.Deterministic
+	p.sqlDataAccess = aux.SQLDataAccess
+	p.sqlMode = aux.SQLMode
+	for _, param := range aux.Parameters {
+		p.AddParameter(param)
+	}
+	return nil
+}
+
 func NewProcedure(name string, definition string) *Procedure {
 	return &Procedure{
 		name:       name,
@@ -69,6 +112,53 @@ func (p *Procedure) SetLanguage(language string) {
 	p.language = language
 }
 
+// SecurityType returns whose privileges the procedure runs with, or "" when unknown
+func (p *Procedure) SecurityType() SecurityType {
+	return p.securityType
+}
+
+func (p *Procedure) SetSecurityType(securityType SecurityType) {
+	p.securityType = securityType
+}
+
+// Definer returns the name of the role that owns the procedure, as in the roles of its
+// database
+func (p *Procedure) Definer() string {
+	return p.definer
+}
+
+func (p *Procedure) SetDefiner(definer string) {
+	p.definer = definer
+}
+
+// IsDeterministic reports whether the procedure is declared to return the same result for
+// the same arguments
+func (p *Procedure) IsDeterministic() bool {
+	return p.deterministic
+}
+
+func (p *Procedure) SetDeterministic(deterministic bool) {
+	p.deterministic = deterministic
+}
+
+// SQLDataAccess returns what the procedure is declared to do with data, e.g. "READS SQL DATA"
+func (p *Procedure) SQLDataAccess() string {
+	return p.sqlDataAccess
+}
+
+func (p *Procedure) SetSQLDataAccess(sqlDataAccess string) {
+	p.sqlDataAccess = sqlDataAccess
+}
+
+// SQLMode returns the SQL mode the procedure was created with, which it runs under
+func (p *Procedure) SQLMode() string {
+	return p.sqlMode
+}
+
+func (p *Procedure) SetSQLMode(sqlMode string) {
+	p.sqlMode = sqlMode
+}
+
 // FullyQualifiedName returns schema.procedure format if schema is set
 func (p *Procedure) FullyQualifiedName() string {
 	if p.schema != nil {
@@ -152,3 +152,17 @@ func TestProcedureMarshalJSONEmptyParameters(t *testing.T) {
 		}
 	}
 }
+
+func TestProcedureSecurityContext(t *testing.T) {
+	p := NewProcedure("archive", "BEGIN END")
+	p.SetSecurityType(SecurityInvoker)
+	p.SetDefiner("'root'@'localhost'")
+	p.SetSQLDataAccess("MODIFIES SQL DATA")
+
+	if p.SecurityType() != SecurityInvoker || p.Definer() != "'root'@'localhost'" || p.IsDeterministic() {
+		t.Errorf("unexpected security context %q %q %v", p.SecurityType(), p.Definer(), p.IsDeterministic())
+	}
+	if p.SQLDataAccess() != "MODIFIES SQL DATA" {
+		t.Errorf("expected MODIFIES SQL DATA, got %q", p.SQLDataAccess())
+	}
+}
@@ -1,5 +1,7 @@
 package dbobjects
 
+import "encoding/json"
+
 type Role struct {
 	name          string
 	isSuperuser   bool
@@ -7,6 +9,57 @@ type Role struct {
 	canCreateDB   bool
 	canCreateRole bool
 	memberOf      []*Role
+	defaultRoles  []*Role
+}
+
+// MarshalJSON names the roles a role is a member of rather than nesting them
+func (r *Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name          string   `json:"name"`
+		IsSuperuser   bool     `json:"isSuperuser"`
+		CanLogin      bool     `json:"canLogin"`
+		CanCreateDB   bool     `json:"canCreateDB"`
+		CanCreateRole bool     `json:"canCreateRole"`
+		MemberOf      []string `json:"memberOf,omitempty"`
+		DefaultRoles  []string `json:"defaultRoles,omitempty"`
+	}{
+		Name:          r.name,
+		IsSuperuser:   r.isSuperuser,
+		CanLogin:      r.canLogin,
+		CanCreateDB:   r.canCreateDB,
+		CanCreateRole: r.canCreateRole,
+		MemberOf:      roleNames(r.memberOf),
+		DefaultRoles:  roleNames(r.defaultRoles),
+	})
+}
+
+// UnmarshalJSON decodes memberships and default roles as placeholder roles, which the
+// enclosing database replaces with the roles they name
+func (r *Role) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name          string   `json:"name"`
+		IsSuperuser   bool     `json:"isSuperuser"`
+		CanLogin      bool     `json:"canLogin"`
+		CanCreateDB   bool     `json:"canCreateDB"`
+		CanCreateRole bool     `json:"canCreateRole"`
+		MemberOf      []string `json:"memberOf"`
+		DefaultRoles  []string `json:"defaultRoles"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*r = *NewRole(aux.Name)
+	r.isSuperuser = aux.IsSuperuser
+	r.canLogin = aux.CanLogin
+	r.canCreateDB = aux.CanCreateDB
+	r.canCreateRole = aux.CanCreateRole
+	for _, name := range aux.MemberOf {
+		r.AddMemberOf(NewRole(name))
+	}
+	for _, name := range aux.DefaultRoles {
+		r.AddDefaultRole(NewRole(name))
+	}
+	return nil
 }
 
 func NewRole(name string) *Role {
@@ -59,3 +112,21 @@ func (r *Role) MemberOf() []*Role {
 func (r *Role) AddMemberOf(role *Role) {
 	r.memberOf = append(r.memberOf, role)
 }
+
+// DefaultRoles returns the roles activated when the role logs in, for servers where
+// membership alone does not activate them
+func (r *Role) DefaultRoles() []*Role {
+	return r.defaultRoles
+}
+
+func (r *Role) AddDefaultRole(role *Role) {
+	r.defaultRoles = append(r.defaultRoles, role)
+}
+
+func roleNames(roles []*Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name()
+	}
+	return names
+}
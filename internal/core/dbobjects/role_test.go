@@ -124,3 +124,15 @@ func TestRoleAllPermissions(t *testing.T) {
 		t.Error("expected CanCreateRole to be true")
 	}
 }
+
+func TestRoleDefaultRoles(t *testing.T) {
+	r := NewRole("'app'@'%'")
+	if len(r.DefaultRoles()) != 0 {
+		t.Errorf("expected no default roles, got %d", len(r.DefaultRoles()))
+	}
+
+	r.AddDefaultRole(NewRole("'reader'@'%'"))
+	if len(r.DefaultRoles()) != 1 || r.DefaultRoles()[0].Name() != "'reader'@'%'" {
+		t.Errorf("expected default role 'reader'@'%%', got %v", r.DefaultRoles())
+	}
+}
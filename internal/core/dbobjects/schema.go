@@ -11,6 +11,7 @@ type Schema struct {
 	functions  map[string]*Function
 	procedures map[string]*Procedure
 	sequences  map[string]*Sequence
+	enums      map[string]*Enum
 }
 
 func (s *Schema) MarshalJSON() ([]byte, error) {
@@ -22,6 +23,7 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		Functions  map[string]*Function  `json:"functions"`
 		Procedures map[string]*Procedure `json:"procedures"`
 		Sequences  map[string]*Sequence  `json:"sequences"`
+		Enums      map[string]*Enum      `json:"enums,omitempty"`
 	}{
 		Name:       s.name,
 		Owner:      s.owner,
@@ -30,9 +32,85 @@ func (s *Schema) MarshalJSON() ([]byte, error) {
 		Functions:  s.functions,
 		Procedures: s.procedures,
 		Sequences:  s.sequences,
+		Enums:      s.enums,
 	})
 }
 
+// UnmarshalJSON decodes a schema from either its own JSON or the schema of a json
+// report and links its objects to it. Trigger functions are linked to the functions of
+// the schema; ones defined in other schemas are linked by the enclosing database.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name       string          `json:"name"`
+		Owner      string          `json:"owner"`
+		Tables     json.RawMessage `json:"tables"`
+		Views      json.RawMessage `json:"views"`
+		Functions  json.RawMessage `json:"functions"`
+		Procedures json.RawMessage `json:"procedures"`
+		Sequences  json.RawMessage `json:"sequences"`
+		Enums      json.RawMessage `json:"enums"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	tables, err := decodeList[Table](aux.Tables)
+	if err != nil {
+		return err
+	}
+	views, err := decodeList[View](aux.Views)
+	if err != nil {
+		return err
+	}
+	functions, err := decodeList[Function](aux.Functions)
+	if err != nil {
+		return err
+	}
+	procedures, err := decodeList[Procedure](aux.Procedures)
+	if err != nil {
+		return err
+	}
+	sequences, err := decodeList[Sequence](aux.Sequences)
+	if err != nil {
+		return err
+	}
+	enums, err := decodeList[Enum](aux.Enums)
+	if err != nil {
+		return err
+	}
+
+	*s = *NewSchema(aux.Name, aux.Owner, nil)
+	for _, table := range tables {
+		s.AddTable(table)
+	}
+	for _, view := range views {
+		s.AddView(view)
+	}
+	for _, function := range functions {
+		s.AddFunction(function)
+	}
+	for _, procedure := range procedures {
+		s.AddProcedure(procedure)
+	}
+	for _, sequence := range sequences {
+		s.AddSequence(sequence)
+	}
+	for _, enum := range enums {
+		s.AddEnum(enum)
+	}
+
+	for _, table := range s.tables {
+		for _, trigger := range table.triggers {
+			if trigger.function == nil {
+				continue
+			}
+			if function, ok := s.functions[trigger.function.Name()]; ok {
+				trigger.SetFunction(function)
+			}
+		}
+	}
+	return nil
+}
+
 func NewSchema(name string, owner string, tables map[string]*Table) *Schema {
 	if tables == nil {
 		tables = make(map[string]*Table)
@@ -45,6 +123,7 @@ func NewSchema(name string, owner string, tables map[string]*Table) *Schema {
 		functions:  make(map[string]*Function),
 		procedures: make(map[string]*Procedure),
 		sequences:  make(map[string]*Sequence),
+		enums:      make(map[string]*Enum),
 	}
 }
 
@@ -109,6 +188,15 @@ func (s *Schema) AddSequence(sequence *Sequence) {
 	s.sequences[sequence.Name()] = sequence
 }
 
+func (s *Schema) Enums() map[string]*Enum {
+	return s.enums
+}
+
+func (s *Schema) AddEnum(enum *Enum) {
+	enum.SetSchema(s)
+	s.enums[enum.Name()] = enum
+}
+
 // FullyQualifiedName returns database.schema format if database is set
 func (s *Schema) FullyQualifiedName() string {
 	if s.database != nil {
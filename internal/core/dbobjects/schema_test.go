@@ -178,6 +178,23 @@ func TestSchemaAddSequence(t *testing.T) {
 	}
 }
 
+func TestSchemaAddEnum(t *testing.T) {
+	s := NewSchema("public", "admin", nil)
+	e := NewEnum("order_status", []string{"pending", "paid"})
+
+	s.AddEnum(e)
+
+	if s.Enums()["order_status"] != e {
+		t.Fatal("expected order_status enum to exist")
+	}
+	if e.Schema() != s {
+		t.Error("expected enum to have schema reference")
+	}
+	if e.FullyQualifiedName() != "public.order_status" {
+		t.Errorf("expected 'public.order_status', got %q", e.FullyQualifiedName())
+	}
+}
+
 func TestSchemaFullyQualifiedName(t *testing.T) {
 	t.Run("without database", func(t *testing.T) {
 		s := NewSchema("public", "admin", nil)
@@ -276,4 +293,7 @@ func TestSchemaMarshalJSONEmpty(t *testing.T) {
 	if len(tables) != 0 {
 		t.Errorf("expected empty tables, got %d", len(tables))
 	}
+	if _, ok := result["enums"]; ok {
+		t.Error("expected enums to be omitted when empty")
+	}
 }
@@ -33,6 +33,31 @@ func (s *Sequence) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (s *Sequence) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name       string `json:"name"`
+		StartValue int64  `json:"startValue"`
+		Increment  int64  `json:"increment"`
+		MinValue   int64  `json:"minValue"`
+		MaxValue   int64  `json:"maxValue"`
+		Cache      int64  `json:"cache"`
+		Cycle      bool   `json:"cycle"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*s = Sequence{
+		name:       aux.Name,
+		startValue: aux.StartValue,
+		increment:  aux.Increment,
+		minValue:   aux.MinValue,
+		maxValue:   aux.MaxValue,
+		cache:      aux.Cache,
+		cycle:      aux.Cycle,
+	}
+	return nil
+}
+
 func NewSequence(name string, startValue int64, increment int64) *Sequence {
 	return &Sequence{
 		name:       name,
@@ -1,6 +1,9 @@
 package dbobjects
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"slices"
+)
 
 type Table struct {
 	name        string
@@ -11,28 +14,119 @@ type Table struct {
 	indexes     []*Index
 	constraints []*Constraint
 	triggers    []*Trigger
+	periods     []*Period
+	// systemVersioned tables keep the history of every row, as in MariaDB
+	// WITH SYSTEM VERSIONING
+	systemVersioned bool
+	// engine, rowFormat, charset and collation are MySQL table options
+	engine    string
+	rowFormat string
+	charset   string
+	collation string
+	comment   string
 }
 
 func (t *Table) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Name        string             `json:"name"`
-		Columns     map[string]*Column `json:"columns"`
-		PrimaryKey  *PrimaryKey        `json:"primaryKey,omitempty"`
-		ForeignKeys []*ForeignKey      `json:"foreignKeys,omitempty"`
-		Indexes     []*Index           `json:"indexes,omitempty"`
-		Constraints []*Constraint      `json:"constraints,omitempty"`
-		Triggers    []*Trigger         `json:"triggers,omitempty"`
+		Name            string             `json:"name"`
+		Columns         map[string]*Column `json:"columns"`
+		PrimaryKey      *PrimaryKey        `json:"primaryKey,omitempty"`
+		ForeignKeys     []*ForeignKey      `json:"foreignKeys,omitempty"`
+		Indexes         []*Index           `json:"indexes,omitempty"`
+		Constraints     []*Constraint      `json:"constraints,omitempty"`
+		Triggers        []*Trigger         `json:"triggers,omitempty"`
+		Periods         []*Period          `json:"periods,omitempty"`
+		SystemVersioned bool               `json:"systemVersioned,omitempty"`
+		Engine          string             `json:"engine,omitempty"`
+		RowFormat       string             `json:"rowFormat,omitempty"`
+		Charset         string             `json:"charset,omitempty"`
+		Collation       string             `json:"collation,omitempty"`
+		Comment         string             `json:"comment,omitempty"`
 	}{
-		Name:        t.name,
-		Columns:     t.columns,
-		PrimaryKey:  t.primaryKey,
-		ForeignKeys: t.foreignKeys,
-		Indexes:     t.indexes,
-		Constraints: t.constraints,
-		Triggers:    t.triggers,
+		Name:            t.name,
+		Columns:         t.columns,
+		PrimaryKey:      t.primaryKey,
+		ForeignKeys:     t.foreignKeys,
+		Indexes:         t.indexes,
+		Constraints:     t.constraints,
+		Triggers:        t.triggers,
+		Periods:         t.periods,
+		SystemVersioned: t.systemVersioned,
+		Engine:          t.engine,
+		RowFormat:       t.rowFormat,
+		Charset:         t.charset,
+		Collation:       t.collation,
+		Comment:         t.comment,
 	})
 }
 
+// UnmarshalJSON decodes a table and links its columns, keys, indexes, constraints and
+// triggers to it. Foreign key referenced columns and trigger functions are linked by
+// the enclosing database and schema.
+func (t *Table) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name            string          `json:"name"`
+		Columns         json.RawMessage `json:"columns"`
+		PrimaryKey      *PrimaryKey     `json:"primaryKey"`
+		ForeignKeys     []*ForeignKey   `json:"foreignKeys"`
+		Indexes         []*Index        `json:"indexes"`
+		Constraints     []*Constraint   `json:"constraints"`
+		Triggers        []*Trigger      `json:"triggers"`
+		Periods         []*Period       `json:"periods"`
+		SystemVersioned bool            `json:"systemVersioned"`
+		Engine          string          `json:"engine"`
+		RowFormat       string          `json:"rowFormat"`
+		Charset         string          `json:"charset"`
+		Collation       string          `json:"collation"`
+		Comment         string          `json:"comment"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	columns, err := decodeList[Column](aux.Columns)
+	if err != nil {
+		return err
+	}
+
+	*t = *NewTable(aux.Name, nil)
+	t.comment = aux.Comment
+	t.systemVersioned = aux.SystemVersioned
+	t.engine = aux.Engine
+	t.rowFormat = aux.RowFormat
+	t.charset = aux.Charset
+	t.collation = aux.Collation
+	for _, col := range columns {
+		t.AddColumn(col)
+	}
+	if aux.PrimaryKey != nil {
+		t.resolveColumns(aux.PrimaryKey.columns)
+		aux.PrimaryKey.SetTable(t)
+		t.SetPrimaryKey(aux.PrimaryKey)
+	}
+	for _, fk := range aux.ForeignKeys {
+		t.resolveColumns(fk.columns)
+		t.AddForeignKey(fk)
+	}
+	for _, index := range aux.Indexes {
+		t.resolveColumns(index.columns)
+		t.AddIndex(index)
+	}
+	for _, constraint := range aux.Constraints {
+		t.resolveColumns(constraint.columns)
+		t.AddConstraint(constraint)
+	}
+	for _, trigger := range aux.Triggers {
+		t.AddTrigger(trigger)
+	}
+	for _, period := range aux.Periods {
+		bounds := []*Column{period.startColumn, period.endColumn}
+		t.resolveColumns(bounds)
+		period.startColumn, period.endColumn = bounds[0], bounds[1]
+		t.AddPeriod(period)
+	}
+	return nil
+}
+
 func NewTable(name string, columns map[string]*Column) *Table {
 	if columns == nil {
 		columns = make(map[string]*Column)
@@ -44,6 +138,7 @@ func NewTable(name string, columns map[string]*Column) *Table {
 		indexes:     []*Index{},
 		constraints: []*Constraint{},
 		triggers:    []*Trigger{},
+		periods:     []*Period{},
 	}
 }
 
@@ -51,6 +146,12 @@ func (t *Table) Name() string {
 	return t.name
 }
 
+// SetName renames the table. Schemas key their tables by name, so a table that belongs
+// to a schema must be added to it again.
+func (t *Table) SetName(name string) {
+	t.name = name
+}
+
 func (t *Table) Schema() *Schema {
 	return t.schema
 }
@@ -68,6 +169,29 @@ func (t *Table) AddColumn(column *Column) {
 	t.columns[column.Name()] = column
 }
 
+// RenameColumn renames a column and reports whether the table has it. Keys, indexes and
+// constraints share the column and see the new name.
+func (t *Table) RenameColumn(oldName, newName string) bool {
+	column, ok := t.columns[oldName]
+	if !ok {
+		return false
+	}
+	delete(t.columns, oldName)
+	column.name = newName
+	t.columns[newName] = column
+	return true
+}
+
+// RemoveColumn removes a column and reports whether the table had it. Keys, indexes and
+// constraints on the column are left to the caller.
+func (t *Table) RemoveColumn(name string) bool {
+	if _, ok := t.columns[name]; !ok {
+		return false
+	}
+	delete(t.columns, name)
+	return true
+}
+
 func (t *Table) PrimaryKey() *PrimaryKey {
 	return t.primaryKey
 }
@@ -85,6 +209,14 @@ func (t *Table) AddForeignKey(fk *ForeignKey) {
 	t.foreignKeys = append(t.foreignKeys, fk)
 }
 
+// RemoveForeignKey removes the foreign key with the given name and reports whether
+// the table had it
+func (t *Table) RemoveForeignKey(name string) bool {
+	before := len(t.foreignKeys)
+	t.foreignKeys = slices.DeleteFunc(t.foreignKeys, func(fk *ForeignKey) bool { return fk.Name() == name })
+	return len(t.foreignKeys) < before
+}
+
 func (t *Table) Indexes() []*Index {
 	return t.indexes
 }
@@ -94,6 +226,14 @@ func (t *Table) AddIndex(index *Index) {
 	t.indexes = append(t.indexes, index)
 }
 
+// RemoveIndex removes the index with the given name and reports whether
+// the table had it
+func (t *Table) RemoveIndex(name string) bool {
+	before := len(t.indexes)
+	t.indexes = slices.DeleteFunc(t.indexes, func(index *Index) bool { return index.Name() == name })
+	return len(t.indexes) < before
+}
+
 func (t *Table) Constraints() []*Constraint {
 	return t.constraints
 }
@@ -103,6 +243,14 @@ func (t *Table) AddConstraint(constraint *Constraint) {
 	t.constraints = append(t.constraints, constraint)
 }
 
+// RemoveConstraint removes the constraint with the given name and reports whether
+// the table had it
+func (t *Table) RemoveConstraint(name string) bool {
+	before := len(t.constraints)
+	t.constraints = slices.DeleteFunc(t.constraints, func(constraint *Constraint) bool { return constraint.Name() == name })
+	return len(t.constraints) < before
+}
+
 func (t *Table) Triggers() []*Trigger {
 	return t.triggers
 }
@@ -112,6 +260,84 @@ func (t *Table) AddTrigger(trigger *Trigger) {
 	t.triggers = append(t.triggers, trigger)
 }
 
+// RemoveTrigger removes the trigger with the given name and reports whether
+// the table had it
+func (t *Table) RemoveTrigger(name string) bool {
+	before := len(t.triggers)
+	t.triggers = slices.DeleteFunc(t.triggers, func(trigger *Trigger) bool { return trigger.Name() == name })
+	return len(t.triggers) < before
+}
+
+func (t *Table) Periods() []*Period {
+	return t.periods
+}
+
+func (t *Table) AddPeriod(period *Period) {
+	period.SetTable(t)
+	t.periods = append(t.periods, period)
+}
+
+// SystemPeriod returns the SYSTEM_TIME period of a system-versioned table, or nil
+func (t *Table) SystemPeriod() *Period {
+	for _, period := range t.periods {
+		if period.IsSystemTime() {
+			return period
+		}
+	}
+	return nil
+}
+
+func (t *Table) IsSystemVersioned() bool {
+	return t.systemVersioned
+}
+
+func (t *Table) SetSystemVersioned(systemVersioned bool) {
+	t.systemVersioned = systemVersioned
+}
+
+// Engine returns the storage engine of a MySQL table, such as InnoDB or MyISAM
+func (t *Table) Engine() string {
+	return t.engine
+}
+
+func (t *Table) SetEngine(engine string) {
+	t.engine = engine
+}
+
+func (t *Table) RowFormat() string {
+	return t.rowFormat
+}
+
+func (t *Table) SetRowFormat(rowFormat string) {
+	t.rowFormat = rowFormat
+}
+
+// Charset returns the default character set of the table's text columns
+func (t *Table) Charset() string {
+	return t.charset
+}
+
+func (t *Table) SetCharset(charset string) {
+	t.charset = charset
+}
+
+// Collation returns the default collation of the table's text columns
+func (t *Table) Collation() string {
+	return t.collation
+}
+
+func (t *Table) SetCollation(collation string) {
+	t.collation = collation
+}
+
+func (t *Table) Comment() string {
+	return t.comment
+}
+
+func (t *Table) SetComment(comment string) {
+	t.comment = comment
+}
+
 // FullyQualifiedName returns schema.table format if schema is set
 func (t *Table) FullyQualifiedName() string {
 	if t.schema != nil {
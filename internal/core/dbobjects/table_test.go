@@ -280,6 +280,43 @@ func TestTableMarshalJSON(t *testing.T) {
 	}
 }
 
+func TestTableSetComment(t *testing.T) {
+	tbl := NewTable("users", nil)
+
+	if tbl.Comment() != "" {
+		t.Errorf("expected empty comment initially, got %q", tbl.Comment())
+	}
+
+	tbl.SetComment("registered accounts")
+
+	if tbl.Comment() != "registered accounts" {
+		t.Errorf("expected comment 'registered accounts', got %q", tbl.Comment())
+	}
+}
+
+func TestTableStorageOptions(t *testing.T) {
+	tbl := NewTable("sessions", nil)
+	tbl.SetEngine("MyISAM")
+	tbl.SetRowFormat("DYNAMIC")
+	tbl.SetCharset("utf8mb3")
+	tbl.SetCollation("utf8mb3_general_ci")
+
+	data, err := json.Marshal(tbl)
+	if err != nil {
+		t.Fatalf("failed to marshal table: %v", err)
+	}
+	var decoded Table
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal table: %v", err)
+	}
+	if decoded.Engine() != "MyISAM" || decoded.RowFormat() != "DYNAMIC" {
+		t.Errorf("expected MyISAM / DYNAMIC, got %q / %q", decoded.Engine(), decoded.RowFormat())
+	}
+	if decoded.Charset() != "utf8mb3" || decoded.Collation() != "utf8mb3_general_ci" {
+		t.Errorf("expected utf8mb3 / utf8mb3_general_ci, got %q / %q", decoded.Charset(), decoded.Collation())
+	}
+}
+
 func TestTableMarshalJSONOmitsEmpty(t *testing.T) {
 	tbl := NewTable("empty_table", nil)
 
@@ -306,3 +343,68 @@ func TestTableMarshalJSONOmitsEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestTableRenameColumn(t *testing.T) {
+	tbl := NewTable("users", nil)
+	col := NewColumn("mail", "varchar", false)
+	tbl.AddColumn(col)
+	idx := NewIndex("idx_mail", tbl, []*Column{col}, true)
+	tbl.AddIndex(idx)
+
+	if !tbl.RenameColumn("mail", "email") {
+		t.Fatal("expected rename of existing column to succeed")
+	}
+	if tbl.Columns()["email"] != col || tbl.Columns()["mail"] != nil {
+		t.Errorf("expected column keyed by its new name, got %v", tbl.Columns())
+	}
+	if idx.Columns()[0].Name() != "email" {
+		t.Errorf("expected index to see the new name, got %q", idx.Columns()[0].Name())
+	}
+	if tbl.RenameColumn("missing", "other") {
+		t.Error("expected rename of missing column to fail")
+	}
+}
+
+func TestTableRemove(t *testing.T) {
+	tbl := NewTable("orders", nil)
+	tbl.AddColumn(NewColumn("id", "integer", false))
+	tbl.AddForeignKey(NewForeignKey("fk_customer", "customers"))
+	tbl.AddIndex(NewIndex("idx_created", tbl, nil, false))
+	tbl.AddConstraint(NewConstraint("chk_total", ConstraintTypeCheck))
+	tbl.AddTrigger(NewTrigger("trg_audit", ""))
+
+	tests := []struct {
+		name   string
+		remove func(name string) bool
+		target string
+		count  func() int
+	}{
+		{"column", tbl.RemoveColumn, "id", func() int { return len(tbl.Columns()) }},
+		{"foreign key", tbl.RemoveForeignKey, "fk_customer", func() int { return len(tbl.ForeignKeys()) }},
+		{"index", tbl.RemoveIndex, "idx_created", func() int { return len(tbl.Indexes()) }},
+		{"constraint", tbl.RemoveConstraint, "chk_total", func() int { return len(tbl.Constraints()) }},
+		{"trigger", tbl.RemoveTrigger, "trg_audit", func() int { return len(tbl.Triggers()) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.remove("missing") {
+				t.Error("expected removal of missing name to fail")
+			}
+			if !tt.remove(tt.target) {
+				t.Errorf("expected removal of %q to succeed", tt.target)
+			}
+			if n := tt.count(); n != 0 {
+				t.Errorf("expected nothing left, got %d", n)
+			}
+		})
+	}
+}
+
+func TestTableSetName(t *testing.T) {
+	tbl := NewTable("users", nil)
+	tbl.SetName("accounts")
+	if tbl.Name() != "accounts" {
+		t.Errorf("expected name 'accounts', got %q", tbl.Name())
+	}
+}
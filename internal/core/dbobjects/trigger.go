@@ -50,6 +50,36 @@ func (t *Trigger) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON decodes the trigger function as a placeholder without a schema, which
+// the schema or database replaces with the function of that name
+func (t *Trigger) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name       string         `json:"name"`
+		Definition string         `json:"definition"`
+		Timing     TriggerTiming  `json:"timing"`
+		Events     []TriggerEvent `json:"events"`
+		Function   string         `json:"function"`
+		ForEach    string         `json:"forEach"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*t = Trigger{
+		name:       aux.Name,
+		definition: aux.Definition,
+		timing:     aux.Timing,
+		events:     aux.Events,
+		forEach:    aux.ForEach,
+	}
+	if t.events == nil {
+		t.events = []TriggerEvent{}
+	}
+	if aux.Function != "" {
+		t.function = NewFunction(aux.Function, "")
+	}
+	return nil
+}
+
 func NewTrigger(name string, definition string) *Trigger {
 	return &Trigger{
 		name:       name,
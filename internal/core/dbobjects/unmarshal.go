@@ -0,0 +1,67 @@
+package dbobjects
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// The UnmarshalJSON methods accept the output of the MarshalJSON methods as well as the
+// database of a json report, which lists collections instead of keying them by name.
+// References by name (key columns, referenced columns, trigger functions) are decoded
+// as placeholders and replaced with the objects they name once the enclosing table,
+// schema or database is decoded; parent pointers are restored through the Add methods.
+
+// decodeList decodes a collection that is either a JSON array or an object keyed by
+// name. Objects are returned in key order.
+func decodeList[T any](data json.RawMessage) ([]*T, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	var items []*T
+	if data[0] == '{' {
+		var byName map[string]*T
+		if err := json.Unmarshal(data, &byName); err != nil {
+			return nil, err
+		}
+		for _, name := range slices.Sorted(maps.Keys(byName)) {
+			items = append(items, byName[name])
+		}
+	} else if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(items, func(item *T) bool { return item == nil }), nil
+}
+
+// columnRefs returns placeholder columns for the column names of a key, index or
+// constraint
+func columnRefs(names []string) []*Column {
+	columns := make([]*Column, len(names))
+	for i, name := range names {
+		columns[i] = NewColumn(name, "", false)
+	}
+	return columns
+}
+
+// resolveColumns replaces placeholder columns with the columns of the table that have
+// the same names. Names the table does not have keep their placeholder.
+func (t *Table) resolveColumns(columns []*Column) {
+	for i, col := range columns {
+		if resolved, ok := t.columns[col.Name()]; ok {
+			columns[i] = resolved
+		}
+	}
+}
+
+// resolveRoles replaces placeholder roles with the roles of the database that have the
+// same names. Names the database does not have keep their placeholder.
+func (d *Database) resolveRoles(roles []*Role) {
+	for i, role := range roles {
+		if resolved, ok := d.roles[role.Name()]; ok {
+			roles[i] = resolved
+		}
+	}
+}
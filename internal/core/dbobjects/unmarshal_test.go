@@ -0,0 +1,204 @@
+package dbobjects
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func buildLinkedDatabase() *Database {
+	db := NewDatabase("shop", nil)
+	db.SetEngine("PostgreSQL")
+
+	audit := NewSchema("audit", "admin", nil)
+	db.AddSchema(audit)
+	audit.AddFunction(NewFunction("log_change", "BEGIN RETURN NEW; END"))
+
+	public := NewSchema("public", "admin", nil)
+	db.AddSchema(public)
+
+	customers := NewTable("customers", nil)
+	public.AddTable(customers)
+	customerID := NewColumn("id", "integer", false)
+	customers.AddColumn(customerID)
+	customers.SetPrimaryKey(NewPrimaryKey("customers_pkey", customers, []*Column{customerID}))
+
+	orders := NewTable("orders", nil)
+	public.AddTable(orders)
+	orderID := NewColumn("id", "integer", false)
+	orders.AddColumn(orderID)
+	orderCustomer := NewColumn("customer_id", "integer", true)
+	orders.AddColumn(orderCustomer)
+	orders.SetPrimaryKey(NewPrimaryKey("orders_pkey", orders, []*Column{orderID}))
+	orders.AddIndex(NewIndex("orders_customer_idx", orders, []*Column{orderCustomer}, false))
+
+	fk := NewForeignKey("orders_customer_id_fkey", "customers")
+	fk.AddColumn(orderCustomer)
+	fk.SetReferencedSchema("public")
+	fk.AddReferencedColumn(customerID)
+	orders.AddForeignKey(fk)
+
+	trigger := NewTrigger("orders_audit", "")
+	trigger.SetFunction(audit.Functions()["log_change"])
+	orders.AddTrigger(trigger)
+
+	db.AddDependency(NewDependency(
+		NewObjectReference(ObjectTypeView, "public", "order_totals"),
+		NewObjectReference(ObjectTypeTable, "public", "orders"),
+	))
+
+	reader := NewRole("reader")
+	app := NewRole("app")
+	app.SetCanLogin(true)
+	app.AddMemberOf(reader)
+	app.AddDefaultRole(reader)
+	db.AddRole(reader)
+	db.AddRole(app)
+	grant := NewGrant("SELECT", "GRANT SELECT ON public.orders TO reader")
+	grant.SetGrantee("reader")
+	grant.SetPrivilege("SELECT")
+	grant.SetObject("public", "orders", "")
+	db.AddGrant(grant)
+	return db
+}
+
+func TestDatabaseUnmarshalJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(buildLinkedDatabase())
+	if err != nil {
+		t.Fatalf("failed to marshal database: %v", err)
+	}
+
+	var db Database
+	if err := json.Unmarshal(data, &db); err != nil {
+		t.Fatalf("failed to unmarshal database: %v", err)
+	}
+
+	if db.Name() != "shop" || db.Engine() != "PostgreSQL" {
+		t.Errorf("expected shop/PostgreSQL, got %s/%s", db.Name(), db.Engine())
+	}
+	public := db.Schemas()["public"]
+	if public == nil || public.Database() != &db {
+		t.Fatal("expected public schema linked to the database")
+	}
+
+	orders := public.Tables()["orders"]
+	if orders == nil || orders.Schema() != public {
+		t.Fatal("expected orders table linked to the public schema")
+	}
+	customerID := orders.Columns()["customer_id"]
+	if customerID.Table() != orders {
+		t.Error("expected column linked to its table")
+	}
+	if pk := orders.PrimaryKey(); pk.Table() != orders || pk.Columns()[0] != orders.Columns()["id"] {
+		t.Error("expected primary key linked to the table and its column")
+	}
+	if index := orders.Indexes()[0]; index.Table() != orders || index.Columns()[0] != customerID {
+		t.Error("expected index linked to the table and its column")
+	}
+
+	fk := orders.ForeignKeys()[0]
+	if fk.Table() != orders || fk.Columns()[0] != customerID {
+		t.Error("expected foreign key linked to the table and its column")
+	}
+	if fk.ReferencedColumns()[0] != public.Tables()["customers"].Columns()["id"] {
+		t.Error("expected foreign key linked to the referenced column")
+	}
+
+	trigger := orders.Triggers()[0]
+	if trigger.Table() != orders {
+		t.Error("expected trigger linked to its table")
+	}
+	if trigger.Function() != db.Schemas()["audit"].Functions()["log_change"] {
+		t.Error("expected trigger linked to the function of the audit schema")
+	}
+
+	if len(db.Dependencies()) != 1 || db.Dependencies()[0].Referenced().Name() != "orders" {
+		t.Errorf("expected the dependency to be restored, got %v", db.Dependencies())
+	}
+
+	app, reader := db.Roles()["app"], db.Roles()["reader"]
+	if app == nil || !app.CanLogin() || len(app.MemberOf()) != 1 || app.MemberOf()[0] != reader {
+		t.Error("expected app linked to the reader role it is a member of")
+	}
+	if len(app.DefaultRoles()) != 1 || app.DefaultRoles()[0] != reader {
+		t.Error("expected app linked to its default role")
+	}
+	if len(db.Grants()) != 1 || db.Grants()[0].Level() != GrantLevelTable || db.Grants()[0].Grantee() != "reader" {
+		t.Errorf("expected the table grant to be restored, got %v", db.Grants())
+	}
+}
+
+func TestDatabaseUnmarshalJSONLists(t *testing.T) {
+	data := []byte(`{
+		"name": "shop",
+		"schemas": [{
+			"name": "public",
+			"owner": "admin",
+			"tables": [
+				{"name": "customers", "columns": [{"name": "id", "dataType": "integer"}]},
+				{
+					"name": "orders",
+					"columns": [{"name": "customer_id", "dataType": "integer", "nullable": true}],
+					"foreignKeys": [{
+						"name": "orders_customer_id_fkey",
+						"columns": ["customer_id"],
+						"referencedTable": "customers",
+						"referencedColumns": ["id"]
+					}]
+				}
+			],
+			"views": [],
+			"functions": [],
+			"procedures": [],
+			"sequences": [{"name": "orders_id_seq", "startValue": 1, "increment": 1}]
+		}]
+	}`)
+
+	var db Database
+	if err := json.Unmarshal(data, &db); err != nil {
+		t.Fatalf("failed to unmarshal database: %v", err)
+	}
+
+	public := db.Schemas()["public"]
+	if public == nil || len(public.Tables()) != 2 || len(public.Sequences()) != 1 {
+		t.Fatalf("expected 2 tables and 1 sequence in public, got %v", public)
+	}
+	if public.Sequences()["orders_id_seq"].Schema() != public {
+		t.Error("expected sequence linked to its schema")
+	}
+	fk := public.Tables()["orders"].ForeignKeys()[0]
+	if fk.ReferencedColumns()[0] != public.Tables()["customers"].Columns()["id"] {
+		t.Error("expected foreign key without a referenced schema to resolve in its own schema")
+	}
+}
+
+func TestDatabaseUnmarshalJSONUnresolvedReferences(t *testing.T) {
+	data := []byte(`{
+		"name": "shop",
+		"schemas": {"public": {"name": "public", "tables": {"orders": {
+			"name": "orders",
+			"columns": {},
+			"foreignKeys": [{"name": "fk", "columns": ["gone"], "referencedTable": "missing", "referencedColumns": ["id"]}],
+			"triggers": [{"name": "t", "function": "missing_fn"}]
+		}}}}
+	}`)
+
+	var db Database
+	if err := json.Unmarshal(data, &db); err != nil {
+		t.Fatalf("failed to unmarshal database: %v", err)
+	}
+
+	orders := db.Schemas()["public"].Tables()["orders"]
+	if name := orders.ForeignKeys()[0].ReferencedColumns()[0].Name(); name != "id" {
+		t.Errorf("expected unresolved referenced column to keep its name, got %q", name)
+	}
+	if name := orders.Triggers()[0].Function().Name(); name != "missing_fn" {
+		t.Errorf("expected unresolved trigger function to keep its name, got %q", name)
+	}
+}
+
+func TestDatabaseUnmarshalJSONInvalid(t *testing.T) {
+	var db Database
+	if err := json.Unmarshal([]byte(`{"name": "shop", "schemas": [{"tables": 3}]}`), &db); err == nil {
+		t.Error("expected error for malformed schema")
+	}
+}
@@ -21,6 +21,22 @@ func (v *View) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (v *View) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Name       string    `json:"name"`
+		Definition string    `json:"definition"`
+		Columns    []*Column `json:"columns"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*v = *NewView(aux.Name, aux.Definition)
+	for _, col := range aux.Columns {
+		v.AddColumn(col)
+	}
+	return nil
+}
+
 func NewView(name string, definition string) *View {
 	return &View{
 		name:       name,
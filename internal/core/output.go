@@ -0,0 +1,40 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// OutputStdout is the output path that streams a single report to standard output
+const OutputStdout = "-"
+
+// DefaultOutputTemplate is the report file name used inside the output directory
+// when no explicit output path is given
+const DefaultOutputTemplate = "{database}_{report}.{ext}"
+
+// OutputTimestampFormat is the layout of the {timestamp} placeholder (UTC)
+const OutputTimestampFormat = "20060102T150405Z"
+
+// OutputNameValues holds the values substituted into an output path template
+type OutputNameValues struct {
+	Database  string
+	Report    string
+	Extension string
+	Adapter   string
+	Timestamp time.Time
+}
+
+// ExpandOutputTemplate replaces the {database}, {report}, {ext}, {adapter} and
+// {timestamp} placeholders of an output path template. Substituted values have
+// spaces and path separators replaced by underscores so that they always form a
+// single path segment.
+func ExpandOutputTemplate(template string, values OutputNameValues) string {
+	segment := strings.NewReplacer(" ", "_", "/", "_", "\\", "_")
+	return strings.NewReplacer(
+		"{database}", segment.Replace(values.Database),
+		"{report}", segment.Replace(values.Report),
+		"{ext}", segment.Replace(values.Extension),
+		"{adapter}", segment.Replace(values.Adapter),
+		"{timestamp}", values.Timestamp.UTC().Format(OutputTimestampFormat),
+	).Replace(template)
+}
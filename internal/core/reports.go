@@ -1,6 +1,11 @@
 package core
 
-import dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+import (
+	"time"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+	"github.com/jimbot9k/norman/internal/core/rules"
+)
 
 type InventoryReportWriter interface {
 	WriteInventoryReport(filePath string, db *dbo.Database) error
@@ -8,3 +13,15 @@ type InventoryReportWriter interface {
 	GetReportFileExtension() string
 	GetReportName() string
 }
+
+// MappingStatsReceiver is implemented by report writers that report on the mapping
+// run itself. The runner passes the stats before the report is written.
+type MappingStatsReceiver interface {
+	SetMappingStats(duration time.Duration, errs []error)
+}
+
+// FindingsReceiver is implemented by report writers that report the findings of the
+// audit rules. The runner passes the findings before the report is written.
+type FindingsReceiver interface {
+	SetFindings(findings []rules.Finding)
+}
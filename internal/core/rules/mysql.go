@@ -0,0 +1,222 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// NonTransactionalEngineRule finds MySQL tables stored in MyISAM or MEMORY. Neither
+// engine enforces foreign keys or rolls back a failed statement, MyISAM tables can be
+// corrupted by a crash and MEMORY tables lose their rows on every restart.
+type NonTransactionalEngineRule struct{}
+
+func (r *NonTransactionalEngineRule) ID() string {
+	return "mysql-non-transactional-engine"
+}
+
+func (r *NonTransactionalEngineRule) Description() string {
+	return "MyISAM and MEMORY tables do not enforce foreign keys and are not crash-safe"
+}
+
+func (r *NonTransactionalEngineRule) Evaluate(db *dbo.Database) []Finding {
+	tables := sortedTables(db)
+	referenced := make(map[string]int)
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys() {
+			schema := fk.ReferencedSchema()
+			if schema == "" && table.Schema() != nil {
+				schema = table.Schema().Name()
+			}
+			referenced[schema+"."+fk.ReferencedTable()]++
+		}
+	}
+
+	var findings []Finding
+	for _, table := range tables {
+		var message string
+		switch strings.ToUpper(table.Engine()) {
+		case "MYISAM":
+			message = "table uses the MyISAM engine, which has no transactions, does not enforce foreign keys and can be corrupted by a crash"
+		case "MEMORY", "HEAP":
+			message = "table uses the MEMORY engine, which loses every row on restart and does not enforce foreign keys"
+		default:
+			continue
+		}
+
+		severity := SeverityWarning
+		if n := len(table.ForeignKeys()) + referenced[table.FullyQualifiedName()]; n > 0 {
+			severity = SeverityCritical
+			message += fmt.Sprintf("; %d foreign keys from or to it are declared but never checked", n)
+		}
+		findings = append(findings, Finding{
+			Rule:       r.ID(),
+			Severity:   severity,
+			Confidence: ConfidenceHigh,
+			Object:     dbo.TableID(table),
+			Message:    message,
+		})
+	}
+	return findings
+}
+
+// DeprecatedUTF8MB3Rule finds MySQL tables and columns in the deprecated utf8mb3
+// character set, also known as utf8, which cannot store characters outside the Basic
+// Multilingual Plane such as emoji
+type DeprecatedUTF8MB3Rule struct{}
+
+func (r *DeprecatedUTF8MB3Rule) ID() string {
+	return "mysql-deprecated-utf8mb3"
+}
+
+func (r *DeprecatedUTF8MB3Rule) Description() string {
+	return "utf8mb3 is deprecated and cannot store 4-byte characters; use utf8mb4"
+}
+
+func (r *DeprecatedUTF8MB3Rule) Evaluate(db *dbo.Database) []Finding {
+	var findings []Finding
+	for _, table := range sortedTables(db) {
+		var columns []*dbo.Column
+		for _, col := range sortedColumns(table) {
+			if isUTF8MB3(col.Charset()) {
+				columns = append(columns, col)
+			}
+		}
+
+		// Columns that inherit a utf8mb3 table default are reported with the table
+		if isUTF8MB3(table.Charset()) {
+			findings = append(findings, Finding{
+				Rule:       r.ID(),
+				Severity:   SeverityWarning,
+				Confidence: ConfidenceHigh,
+				Object:     dbo.TableID(table),
+				Message:    fmt.Sprintf("table defaults to the deprecated utf8mb3 character set, used by %d of its columns", len(columns)),
+			})
+			continue
+		}
+		for _, col := range columns {
+			findings = append(findings, Finding{
+				Rule:       r.ID(),
+				Severity:   SeverityWarning,
+				Confidence: ConfidenceHigh,
+				Object:     columnName(col),
+				Message:    "column uses the deprecated utf8mb3 character set",
+			})
+		}
+	}
+	return findings
+}
+
+// isUTF8MB3 reports whether a character set is utf8mb3, which MySQL before 8.0.30
+// reports as utf8
+func isUTF8MB3(charset string) bool {
+	charset = strings.ToLower(charset)
+	return charset == "utf8mb3" || charset == "utf8"
+}
+
+// CollationMismatchRule finds columns that are compared with each other but have
+// different collations. MySQL then converts one side for every comparison, which keeps
+// it from using the index on that side, and fails with "Illegal mix of collations"
+// where it cannot. Foreign key columns are compared by declaration; other join columns
+// are recognised by name, either the key column's own name or the referenced table's
+// name followed by it, as in customer_code for customers.code.
+type CollationMismatchRule struct{}
+
+func (r *CollationMismatchRule) ID() string {
+	return "mysql-collation-mismatch"
+}
+
+func (r *CollationMismatchRule) Description() string {
+	return "Foreign key and join columns should share a collation"
+}
+
+func (r *CollationMismatchRule) Evaluate(db *dbo.Database) []Finding {
+	tables := sortedTables(db)
+	var findings []Finding
+
+	// Columns already compared through a foreign key are not reported again by name
+	compared := make(map[[2]*dbo.Column]bool)
+	for _, table := range tables {
+		for _, fk := range table.ForeignKeys() {
+			for i, col := range fk.Columns() {
+				if i >= len(fk.ReferencedColumns()) {
+					break
+				}
+				ref := fk.ReferencedColumns()[i]
+				compared[[2]*dbo.Column{col, ref}] = true
+				if !collationsDiffer(col, ref) {
+					continue
+				}
+				findings = append(findings, Finding{
+					Rule:       r.ID(),
+					Severity:   SeverityWarning,
+					Confidence: ConfidenceHigh,
+					Object:     columnName(col),
+					Message: fmt.Sprintf("foreign key %s compares collation %s with %s of %s.%s.%s",
+						fk.Name(), col.Collation(), ref.Collation(), referencedSchema(table, fk), fk.ReferencedTable(), ref.Name()),
+				})
+			}
+		}
+	}
+
+	for _, keyTable := range tables {
+		key := singleKeyColumn(keyTable)
+		if key == nil || key.Collation() == "" {
+			continue
+		}
+		prefixed := map[string]bool{
+			keyTable.Name() + "_" + key.Name():                          true,
+			strings.TrimSuffix(keyTable.Name(), "s") + "_" + key.Name(): true,
+		}
+		for _, table := range tables {
+			if table == keyTable {
+				continue
+			}
+			for _, col := range sortedColumns(table) {
+				if !prefixed[col.Name()] && (col.Name() != key.Name() || col == singleKeyColumn(table)) {
+					continue
+				}
+				if compared[[2]*dbo.Column{col, key}] || !collationsDiffer(col, key) {
+					continue
+				}
+				confidence := ConfidenceLow
+				if prefixed[col.Name()] {
+					confidence = ConfidenceMedium
+				}
+				findings = append(findings, Finding{
+					Rule:       r.ID(),
+					Severity:   SeverityWarning,
+					Confidence: confidence,
+					Object:     columnName(col),
+					Message: fmt.Sprintf("column looks like a join column for %s but has collation %s instead of %s",
+						columnName(key), col.Collation(), key.Collation()),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// collationsDiffer reports whether two columns both have a known collation and the
+// collations are not the same
+func collationsDiffer(a, b *dbo.Column) bool {
+	return a.Collation() != "" && b.Collation() != "" && !strings.EqualFold(a.Collation(), b.Collation())
+}
+
+// singleKeyColumn returns the column of a single-column primary key, or nil
+func singleKeyColumn(table *dbo.Table) *dbo.Column {
+	if pk := table.PrimaryKey(); pk != nil && len(pk.Columns()) == 1 {
+		return pk.Columns()[0]
+	}
+	return nil
+}
+
+// referencedSchema returns the schema of the table a foreign key references, which
+// defaults to the schema of its own table
+func referencedSchema(table *dbo.Table, fk *dbo.ForeignKey) string {
+	if fk.ReferencedSchema() != "" || table.Schema() == nil {
+		return fk.ReferencedSchema()
+	}
+	return table.Schema().Name()
+}
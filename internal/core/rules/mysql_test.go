@@ -0,0 +1,136 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// newRulesTestDatabase builds a shop schema with a customers table and an orders table
+// whose customer_id references it
+func newRulesTestDatabase() (*dbo.Database, *dbo.Table, *dbo.Table) {
+	db := dbo.NewDatabase("shop", nil)
+	db.SetEngine("MySQL")
+	schema := dbo.NewSchema("shop", "", nil)
+	db.AddSchema(schema)
+
+	customers := dbo.NewTable("customers", nil)
+	id := dbo.NewColumn("id", "varchar", false)
+	id.SetOrdinalPosition(1)
+	id.SetCharset("utf8mb4")
+	id.SetCollation("utf8mb4_0900_ai_ci")
+	customers.AddColumn(id)
+	customers.SetPrimaryKey(dbo.NewPrimaryKey("PRIMARY", customers, []*dbo.Column{id}))
+	schema.AddTable(customers)
+
+	orders := dbo.NewTable("orders", nil)
+	orderID := dbo.NewColumn("id", "int", false)
+	orderID.SetOrdinalPosition(1)
+	customerID := dbo.NewColumn("customer_id", "varchar", false)
+	customerID.SetOrdinalPosition(2)
+	customerID.SetCharset("utf8mb4")
+	customerID.SetCollation("utf8mb4_0900_ai_ci")
+	orders.AddColumn(orderID)
+	orders.AddColumn(customerID)
+	orders.SetPrimaryKey(dbo.NewPrimaryKey("PRIMARY", orders, []*dbo.Column{orderID}))
+	schema.AddTable(orders)
+	return db, customers, orders
+}
+
+// addCustomerForeignKey declares orders.customer_id as referencing customers.id
+func addCustomerForeignKey(customers, orders *dbo.Table) {
+	fk := dbo.NewForeignKey("orders_ibfk_1", "customers")
+	fk.SetReferencedSchema("shop")
+	fk.AddColumn(orders.Columns()["customer_id"])
+	fk.AddReferencedColumn(customers.Columns()["id"])
+	orders.AddForeignKey(fk)
+}
+
+func TestNonTransactionalEngineRule(t *testing.T) {
+	db, customers, orders := newRulesTestDatabase()
+	customers.SetEngine("InnoDB")
+	orders.SetEngine("MEMORY")
+
+	rule := &NonTransactionalEngineRule{}
+	findings := rule.Evaluate(db)
+	if len(findings) != 1 || findings[0].Object != "shop.orders" || findings[0].Severity != SeverityWarning {
+		t.Fatalf("expected a warning for shop.orders, got %+v", findings)
+	}
+
+	// A foreign key the engine cannot enforce makes the finding critical
+	customers.SetEngine("MyISAM")
+	addCustomerForeignKey(customers, orders)
+	findings = rule.Evaluate(db)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	for _, f := range findings {
+		if f.Severity != SeverityCritical || !strings.Contains(f.Message, "1 foreign keys") {
+			t.Errorf("expected a critical finding about 1 foreign key, got %+v", f)
+		}
+	}
+}
+
+func TestDeprecatedUTF8MB3Rule(t *testing.T) {
+	db, customers, orders := newRulesTestDatabase()
+	customers.SetCharset("utf8mb3")
+	customers.Columns()["id"].SetCharset("utf8mb3")
+	orders.Columns()["customer_id"].SetCharset("utf8")
+
+	findings := (&DeprecatedUTF8MB3Rule{}).Evaluate(db)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	if findings[0].Object != "shop.customers" || !strings.Contains(findings[0].Message, "1 of its columns") {
+		t.Errorf("expected a table finding for shop.customers, got %+v", findings[0])
+	}
+	if findings[1].Object != "shop.orders.customer_id" {
+		t.Errorf("expected a column finding for shop.orders.customer_id, got %+v", findings[1])
+	}
+}
+
+func TestCollationMismatchRule(t *testing.T) {
+	t.Run("matching collations", func(t *testing.T) {
+		db, _, _ := newRulesTestDatabase()
+		if findings := (&CollationMismatchRule{}).Evaluate(db); len(findings) != 0 {
+			t.Errorf("expected no findings, got %+v", findings)
+		}
+	})
+
+	t.Run("join column by name", func(t *testing.T) {
+		db, _, orders := newRulesTestDatabase()
+		orders.Columns()["customer_id"].SetCollation("utf8mb4_bin")
+
+		findings := (&CollationMismatchRule{}).Evaluate(db)
+		if len(findings) != 1 || findings[0].Object != "shop.orders.customer_id" || findings[0].Confidence != ConfidenceMedium {
+			t.Errorf("expected a medium-confidence finding for shop.orders.customer_id, got %+v", findings)
+		}
+	})
+
+	t.Run("foreign key", func(t *testing.T) {
+		db, customers, orders := newRulesTestDatabase()
+		orders.Columns()["customer_id"].SetCollation("utf8mb4_bin")
+		addCustomerForeignKey(customers, orders)
+
+		findings := (&CollationMismatchRule{}).Evaluate(db)
+		if len(findings) != 1 || findings[0].Confidence != ConfidenceHigh || !strings.Contains(findings[0].Message, "orders_ibfk_1") {
+			t.Errorf("expected one high-confidence foreign key finding, got %+v", findings)
+		}
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	db, customers, orders := newRulesTestDatabase()
+	customers.SetCharset("utf8mb3")
+	orders.SetEngine("MyISAM")
+	addCustomerForeignKey(customers, orders)
+
+	findings := Evaluate(db, DefaultRules())
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	if findings[0].Severity != SeverityCritical || findings[1].Severity != SeverityWarning {
+		t.Errorf("expected findings ordered by severity, got %+v", findings)
+	}
+}
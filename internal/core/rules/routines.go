@@ -0,0 +1,286 @@
+package rules
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// serverPrivileges are the global privileges that let an account act beyond its own
+// data: administer the server, manage accounts, read files or write to every database,
+// including the grant tables of the mysql schema
+var serverPrivileges = map[string]bool{
+	"ALL PRIVILEGES": true, "SUPER": true, "CREATE USER": true, "GRANT OPTION": true, "FILE": true,
+	"PROCESS": true, "RELOAD": true, "SHUTDOWN": true, "SYSTEM_USER": true, "SET_USER_ID": true,
+	"SET_ANY_DEFINER": true, "ROLE_ADMIN": true, "SYSTEM_VARIABLES_ADMIN": true,
+	"INSERT": true, "UPDATE": true, "DELETE": true, "CREATE": true, "DROP": true, "ALTER": true,
+}
+
+// PrivilegedDefinerRule finds routines that run with the privileges of their definer
+// (SQL SECURITY DEFINER) where the definer is a superuser or holds server-wide
+// privileges. Anyone allowed to call such a routine borrows those privileges, so a
+// flaw in its body, such as SQL built from its arguments, exposes the whole server.
+// The privileges of the definer's default roles count as its own, as they are active
+// when the routine runs.
+type PrivilegedDefinerRule struct{}
+
+func (r *PrivilegedDefinerRule) ID() string {
+	return "mysql-privileged-definer"
+}
+
+func (r *PrivilegedDefinerRule) Description() string {
+	return "SQL SECURITY DEFINER routines should not be owned by privileged accounts"
+}
+
+func (r *PrivilegedDefinerRule) Evaluate(db *dbo.Database) []Finding {
+	grants := make(map[string][]*dbo.Grant)
+	for _, g := range db.Grants() {
+		grants[g.Grantee()] = append(grants[g.Grantee()], g)
+	}
+
+	var findings []Finding
+	for _, rt := range sortedRoutines(db) {
+		if rt.securityType != dbo.SecurityDefiner {
+			continue
+		}
+		definer, ok := db.Roles()[rt.definer]
+		if !ok {
+			continue
+		}
+		privileges := serverPrivilegesOf(definer, grants)
+		if !definer.IsSuperuser() && len(privileges) == 0 {
+			continue
+		}
+
+		message := fmt.Sprintf("%s runs with the privileges of its definer %s", rt.kind, definer.Name())
+		if definer.IsSuperuser() {
+			message += ", a superuser"
+		}
+		if len(privileges) > 0 {
+			message += fmt.Sprintf(", who holds %s on all databases", strings.Join(privileges, ", "))
+		}
+		findings = append(findings, Finding{
+			Rule:       r.ID(),
+			Severity:   SeverityWarning,
+			Confidence: ConfidenceHigh,
+			Object:     rt.name,
+			Message:    message,
+		})
+	}
+	return findings
+}
+
+// serverPrivilegesOf returns the server-wide privileges a role holds itself or through
+// its default roles, in name order
+func serverPrivilegesOf(role *dbo.Role, grants map[string][]*dbo.Grant) []string {
+	var privileges []string
+	for _, holder := range append([]*dbo.Role{role}, role.DefaultRoles()...) {
+		for _, g := range grants[holder.Name()] {
+			if g.Level() == dbo.GrantLevelGlobal && serverPrivileges[g.Privilege()] && !slices.Contains(privileges, g.Privilege()) {
+				privileges = append(privileges, g.Privilege())
+			}
+		}
+	}
+	slices.Sort(privileges)
+	return privileges
+}
+
+// MissingDefinerRule finds routines whose definer account no longer exists. MySQL
+// refuses to run such a routine with SQL SECURITY DEFINER, and whoever later creates
+// an account of that name takes it over. Accounts are only known when the mapped
+// roles were read from the server's account list, which gives accounts that can log
+// in; without any, the rule reports nothing rather than every routine.
+type MissingDefinerRule struct{}
+
+func (r *MissingDefinerRule) ID() string {
+	return "mysql-missing-definer"
+}
+
+func (r *MissingDefinerRule) Description() string {
+	return "Routines should be defined by an account that exists"
+}
+
+func (r *MissingDefinerRule) Evaluate(db *dbo.Database) []Finding {
+	if !slices.ContainsFunc(slices.Collect(maps.Values(db.Roles())), (*dbo.Role).CanLogin) {
+		return nil
+	}
+
+	var findings []Finding
+	for _, rt := range sortedRoutines(db) {
+		if rt.definer == "" {
+			continue
+		}
+		if _, ok := db.Roles()[rt.definer]; ok {
+			continue
+		}
+
+		severity := SeverityInfo
+		message := fmt.Sprintf("%s is defined by %s, which no longer exists", rt.kind, rt.definer)
+		if rt.securityType == dbo.SecurityDefiner {
+			severity = SeverityCritical
+			message += "; every call fails, and an account created with that name would own it"
+		}
+		findings = append(findings, Finding{
+			Rule:       r.ID(),
+			Severity:   severity,
+			Confidence: ConfidenceHigh,
+			Object:     rt.name,
+			Message:    message,
+		})
+	}
+	return findings
+}
+
+// nondeterministicBuiltins are the built-in functions whose result changes between
+// calls with the same arguments. The CURRENT_ and LOCAL ones may be used without
+// parentheses.
+var nondeterministicBuiltins = map[string]bool{
+	"RAND": true, "UUID": true, "UUID_SHORT": true, "NOW": true, "SYSDATE": true, "CURDATE": true, "CURTIME": true,
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "LOCALTIME": true, "LOCALTIMESTAMP": true,
+	"UTC_DATE": true, "UTC_TIME": true, "UTC_TIMESTAMP": true, "CONNECTION_ID": true, "CURRENT_USER": true,
+	"USER": true, "SESSION_USER": true, "SYSTEM_USER": true, "DATABASE": true, "SCHEMA": true,
+	"LAST_INSERT_ID": true, "FOUND_ROWS": true, "ROW_COUNT": true, "GET_LOCK": true, "SLEEP": true,
+}
+
+var (
+	// callPattern matches a possibly qualified and backquoted name followed by an
+	// opening parenthesis, or a bare word
+	callPattern = regexp.MustCompile("((?:`[^`]+`|[A-Za-z_][A-Za-z0-9_$]*)(?:\\.(?:`[^`]+`|[A-Za-z_][A-Za-z0-9_$]*))?)(\\s*\\()?")
+	// stringLiteralPattern matches a quoted string, whose contents are not calls
+	stringLiteralPattern = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"`)
+)
+
+// NondeterministicGeneratedColumnRule finds generated columns whose expression calls a
+// nondeterministic built-in, such as NOW() or RAND(), or a stored function that is not
+// declared DETERMINISTIC. MariaDB allows them in virtual columns, whose value then
+// changes from one read to the next. Stored columns keep the value of the moment the
+// row was written, which replicas compute differently, and an index on the column
+// disagrees with the rows it points to. Stored functions are only judged when their
+// characteristics were mapped.
+type NondeterministicGeneratedColumnRule struct{}
+
+func (r *NondeterministicGeneratedColumnRule) ID() string {
+	return "mysql-nondeterministic-generated-column"
+}
+
+func (r *NondeterministicGeneratedColumnRule) Description() string {
+	return "Generated columns should only use deterministic functions"
+}
+
+func (r *NondeterministicGeneratedColumnRule) Evaluate(db *dbo.Database) []Finding {
+	var findings []Finding
+	for _, table := range sortedTables(db) {
+		for _, col := range sortedColumns(table) {
+			if !col.IsGenerated() {
+				continue
+			}
+			calls := nondeterministicCalls(db, table, col.GenerationExpression())
+			if len(calls) == 0 {
+				continue
+			}
+
+			severity := SeverityWarning
+			message := fmt.Sprintf("generated column calls %s, so its value", strings.Join(calls, ", "))
+			switch {
+			case isIndexed(table, col):
+				severity = SeverityCritical
+				message += " drifts from the index on it"
+			case col.Generation() == dbo.GenerationStored:
+				severity = SeverityCritical
+				message += " depends on when and where the row was written"
+			default:
+				message += " changes from one read to the next"
+			}
+			findings = append(findings, Finding{
+				Rule:       r.ID(),
+				Severity:   severity,
+				Confidence: ConfidenceHigh,
+				Object:     columnName(col),
+				Message:    message,
+			})
+		}
+	}
+	return findings
+}
+
+// nondeterministicCalls returns the nondeterministic functions an expression calls, in
+// the order they first appear. Stored functions are looked up in their own schema or,
+// unqualified, in the schema of the table.
+func nondeterministicCalls(db *dbo.Database, table *dbo.Table, expr string) []string {
+	expr = stringLiteralPattern.ReplaceAllString(expr, "''")
+	var calls []string
+	for _, match := range callPattern.FindAllStringSubmatch(expr, -1) {
+		name, isCall := match[1], match[2] != ""
+		schemaName, fnName, qualified := strings.Cut(name, ".")
+		if !qualified {
+			schemaName, fnName = "", name
+			if table.Schema() != nil {
+				schemaName = table.Schema().Name()
+			}
+		}
+		schemaName, fnName = strings.Trim(schemaName, "`"), strings.Trim(fnName, "`")
+
+		var call string
+		if schema, ok := db.Schemas()[schemaName]; ok && isCall {
+			if fn, ok := schema.Functions()[fnName]; ok && fn.SecurityType() != "" && !fn.IsDeterministic() {
+				call = fn.FullyQualifiedName()
+			}
+		}
+		builtin := strings.ToUpper(fnName)
+		if call == "" && !qualified && !strings.HasPrefix(name, "`") && nondeterministicBuiltins[builtin] &&
+			(isCall || strings.HasPrefix(builtin, "CURRENT_") || strings.HasPrefix(builtin, "LOCAL")) {
+			call = builtin + "()"
+		}
+		if call != "" && !slices.Contains(calls, call) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// isIndexed reports whether a column is part of an index or the primary key of its table
+func isIndexed(table *dbo.Table, col *dbo.Column) bool {
+	if pk := table.PrimaryKey(); pk != nil && slices.Contains(pk.Columns(), col) {
+		return true
+	}
+	for _, idx := range table.Indexes() {
+		if slices.Contains(idx.Columns(), col) {
+			return true
+		}
+	}
+	return false
+}
+
+// routineInfo is what the routine rules need of a function or procedure
+type routineInfo struct {
+	kind         string
+	name         string
+	securityType dbo.SecurityType
+	definer      string
+}
+
+// sortedRoutines returns the functions and procedures of a database ordered by
+// qualified name and kind
+func sortedRoutines(db *dbo.Database) []routineInfo {
+	var routines []routineInfo
+	for _, schema := range db.Schemas() {
+		schemaID := dbo.ObjectID("", schema.Name())
+		for _, fn := range schema.Functions() {
+			name := dbo.RoutineID(schemaID, fn.Name(), fn.Parameters())
+			routines = append(routines, routineInfo{"function", name, fn.SecurityType(), fn.Definer()})
+		}
+		for _, proc := range schema.Procedures() {
+			name := dbo.RoutineID(schemaID, proc.Name(), proc.Parameters())
+			routines = append(routines, routineInfo{"procedure", name, proc.SecurityType(), proc.Definer()})
+		}
+	}
+	slices.SortFunc(routines, func(a, b routineInfo) int {
+		return cmp.Or(cmp.Compare(a.name, b.name), cmp.Compare(a.kind, b.kind))
+	})
+	return routines
+}
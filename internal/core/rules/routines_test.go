@@ -0,0 +1,128 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// addRoutine adds a function to the shop schema with the given security context
+func addRoutine(db *dbo.Database, name string, securityType dbo.SecurityType, definer string) *dbo.Function {
+	fn := dbo.NewFunction(name, "RETURN 1")
+	fn.SetSecurityType(securityType)
+	fn.SetDefiner(definer)
+	db.Schemas()["shop"].AddFunction(fn)
+	return fn
+}
+
+// addAccount adds a role that can log in
+func addAccount(db *dbo.Database, name string) *dbo.Role {
+	role := dbo.NewRole(name)
+	role.SetCanLogin(true)
+	db.AddRole(role)
+	return role
+}
+
+// addGlobalGrant grants a privilege on all databases
+func addGlobalGrant(db *dbo.Database, grantee, privilege string) {
+	grant := dbo.NewGrant(privilege, "")
+	grant.SetGrantee(grantee)
+	grant.SetPrivilege(privilege)
+	db.AddGrant(grant)
+}
+
+func TestPrivilegedDefinerRule(t *testing.T) {
+	db, _, _ := newRulesTestDatabase()
+	root := addAccount(db, "'root'@'localhost'")
+	root.SetSuperuser(true)
+	addAccount(db, "'app'@'%'")
+	admin := dbo.NewRole("'admin'")
+	db.AddRole(admin)
+	ops := addAccount(db, "'ops'@'%'")
+	ops.AddMemberOf(admin)
+	ops.AddDefaultRole(admin)
+	addGlobalGrant(db, "'admin'", "FILE")
+	addGlobalGrant(db, "'app'@'%'", "SELECT")
+
+	addRoutine(db, "as_root", dbo.SecurityDefiner, "'root'@'localhost'")
+	addRoutine(db, "as_app", dbo.SecurityDefiner, "'app'@'%'")
+	addRoutine(db, "as_ops", dbo.SecurityDefiner, "'ops'@'%'")
+	addRoutine(db, "as_caller", dbo.SecurityInvoker, "'root'@'localhost'")
+
+	findings := (&PrivilegedDefinerRule{}).Evaluate(db)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	if findings[0].Object != "shop.as_ops()" || !strings.Contains(findings[0].Message, "FILE") {
+		t.Errorf("expected shop.as_ops to borrow FILE from its default role, got %+v", findings[0])
+	}
+	if findings[1].Object != "shop.as_root()" || !strings.Contains(findings[1].Message, "superuser") {
+		t.Errorf("expected shop.as_root to run as a superuser, got %+v", findings[1])
+	}
+}
+
+func TestMissingDefinerRule(t *testing.T) {
+	db, _, _ := newRulesTestDatabase()
+	addRoutine(db, "orphan", dbo.SecurityDefiner, "'gone'@'%'")
+	addRoutine(db, "orphan_invoker", dbo.SecurityInvoker, "'gone'@'%'")
+	addRoutine(db, "owned", dbo.SecurityDefiner, "'app'@'%'")
+
+	// Roles mapped from grantees alone say nothing about which accounts exist
+	db.AddRole(dbo.NewRole("'app'@'%'"))
+	if findings := (&MissingDefinerRule{}).Evaluate(db); len(findings) != 0 {
+		t.Fatalf("expected no findings without the account list, got %+v", findings)
+	}
+
+	addAccount(db, "'app'@'%'")
+	findings := (&MissingDefinerRule{}).Evaluate(db)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	if findings[0].Object != "shop.orphan()" || findings[0].Severity != SeverityCritical {
+		t.Errorf("expected a critical finding for shop.orphan, got %+v", findings[0])
+	}
+	if findings[1].Object != "shop.orphan_invoker()" || findings[1].Severity != SeverityInfo {
+		t.Errorf("expected an info finding for shop.orphan_invoker, got %+v", findings[1])
+	}
+}
+
+func TestNondeterministicGeneratedColumnRule(t *testing.T) {
+	db, customers, orders := newRulesTestDatabase()
+	addRoutine(db, "next_code", dbo.SecurityDefiner, "'app'@'%'")
+	stable := addRoutine(db, "normalize", dbo.SecurityDefiner, "'app'@'%'")
+	stable.SetDeterministic(true)
+
+	addGenerated := func(table *dbo.Table, name string, gen dbo.Generation, expr string) *dbo.Column {
+		col := dbo.NewColumn(name, "varchar", true)
+		col.SetOrdinalPosition(len(table.Columns()) + 1)
+		col.SetGenerated(gen, expr)
+		table.AddColumn(col)
+		return col
+	}
+	addGenerated(customers, "age", dbo.GenerationVirtual, "timestampdiff(YEAR,`born`,curdate())")
+	addGenerated(customers, "label", dbo.GenerationVirtual, "concat('now()',`now`,`shop`.`normalize`(`id`))")
+	addGenerated(orders, "code", dbo.GenerationStored, "next_code()")
+	stamped := addGenerated(orders, "stamped", dbo.GenerationVirtual, "CURRENT_TIMESTAMP")
+	orders.AddIndex(dbo.NewIndex("orders_stamped", orders, []*dbo.Column{stamped}, false))
+
+	findings := (&NondeterministicGeneratedColumnRule{}).Evaluate(db)
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %+v", findings)
+	}
+	tests := []struct {
+		object   string
+		severity Severity
+		call     string
+	}{
+		{"shop.customers.age", SeverityWarning, "CURDATE()"},
+		{"shop.orders.code", SeverityCritical, "shop.next_code"},
+		{"shop.orders.stamped", SeverityCritical, "CURRENT_TIMESTAMP()"},
+	}
+	for i, tt := range tests {
+		f := findings[i]
+		if f.Object != tt.object || f.Severity != tt.severity || !strings.Contains(f.Message, tt.call) {
+			t.Errorf("expected a %s finding for %s calling %s, got %+v", tt.severity, tt.object, tt.call, f)
+		}
+	}
+}
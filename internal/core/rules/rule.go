@@ -0,0 +1,123 @@
+// Package rules audits a mapped database. Each rule looks for one kind of risk and
+// reports a finding for every object it concerns.
+package rules
+
+import (
+	"cmp"
+	"slices"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+)
+
+// Severity is how much a finding matters
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// rank orders severities from the least to the most severe
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	}
+	return 0
+}
+
+// Confidence is how sure a rule is that a finding is real. Findings that rest on
+// naming conventions rather than declared structure have a lower confidence.
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+// Finding is a risk a rule found on one database object
+type Finding struct {
+	Rule       string     `json:"rule"`
+	Severity   Severity   `json:"severity"`
+	Confidence Confidence `json:"confidence"`
+	// Object is the ID of the table, column or routine concerned, as in the inventory
+	// reports
+	Object  string `json:"object"`
+	Message string `json:"message"`
+}
+
+// Rule audits a database for one kind of risk
+type Rule interface {
+	// ID identifies the rule in reports, e.g. "mysql-deprecated-utf8mb3"
+	ID() string
+	Description() string
+	Evaluate(db *dbo.Database) []Finding
+}
+
+// DefaultRules returns the rules evaluated on every run
+func DefaultRules() []Rule {
+	return []Rule{
+		&NonTransactionalEngineRule{},
+		&DeprecatedUTF8MB3Rule{},
+		&CollationMismatchRule{},
+		&PrivilegedDefinerRule{},
+		&MissingDefinerRule{},
+		&NondeterministicGeneratedColumnRule{},
+	}
+}
+
+// Evaluate runs the rules against a database and returns their findings, most severe
+// first and then by rule and object
+func Evaluate(db *dbo.Database, rules []Rule) []Finding {
+	var findings []Finding
+	for _, rule := range rules {
+		findings = append(findings, rule.Evaluate(db)...)
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(b.Severity.rank(), a.Severity.rank()),
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Object, b.Object),
+		)
+	})
+	return findings
+}
+
+// sortedTables returns the tables of a database ordered by schema and name, so that
+// rules report findings in a stable order
+func sortedTables(db *dbo.Database) []*dbo.Table {
+	var tables []*dbo.Table
+	for _, schema := range db.Schemas() {
+		for _, table := range schema.Tables() {
+			tables = append(tables, table)
+		}
+	}
+	slices.SortFunc(tables, func(a, b *dbo.Table) int {
+		return cmp.Compare(a.FullyQualifiedName(), b.FullyQualifiedName())
+	})
+	return tables
+}
+
+// sortedColumns returns the columns of a table in ordinal order
+func sortedColumns(table *dbo.Table) []*dbo.Column {
+	columns := make([]*dbo.Column, 0, len(table.Columns()))
+	for _, col := range table.Columns() {
+		columns = append(columns, col)
+	}
+	slices.SortFunc(columns, func(a, b *dbo.Column) int {
+		return cmp.Or(cmp.Compare(a.OrdinalPosition(), b.OrdinalPosition()), cmp.Compare(a.Name(), b.Name()))
+	})
+	return columns
+}
+
+// columnName returns the qualified name of a column, as the ID of the inventory
+func columnName(col *dbo.Column) string {
+	if col.Table() == nil {
+		return dbo.ObjectID("", col.Name())
+	}
+	return dbo.ObjectID(dbo.TableID(col.Table()), col.Name())
+}
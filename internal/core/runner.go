@@ -1,9 +1,20 @@
 package core
 
 import (
+	"crypto/ed25519"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+	"github.com/jimbot9k/norman/internal/core/rules"
 )
 
 type Runner struct {
@@ -11,6 +22,9 @@ type Runner struct {
 	connectionString              string
 	reportOutputDir               string
 	inventoryReportWriterRegistry map[string]*InventoryReportWriter
+	// progress receives status messages; it is switched to stderr when a report is streamed to stdout
+	progress io.Writer
+	stdout   io.Writer
 }
 
 func NewRunner(adapters []Adapter, reports []InventoryReportWriter) *Runner {
@@ -25,11 +39,18 @@ func NewRunner(adapters []Adapter, reports []InventoryReportWriter) *Runner {
 	var r = &Runner{
 		adapterManager:                NewAdapterManager(adapters),
 		inventoryReportWriterRegistry: reportOptionsMap,
+		progress:                      os.Stdout,
+		stdout:                        os.Stdout,
 	}
 	return r
 }
 
-func (r *Runner) Run(connStr *string, outputDir *string, reportCsv *string) error {
+// Run maps the database behind connStr and writes the selected reports. Reports are
+// written to outputDir using DefaultOutputTemplate unless output is set, in which case
+// it is used as the path template; OutputStdout streams a single report to stdout.
+// When bundle is set, the reports are packaged with a manifest into that archive instead,
+// and signKey names an optional Ed25519 key that signs the manifest.
+func (r *Runner) Run(connStr *string, outputDir *string, output *string, bundle *string, signKey *string, reportCsv *string) error {
 
 	if *connStr == "" {
 		return fmt.Errorf("connection string is required")
@@ -39,18 +60,48 @@ func (r *Runner) Run(connStr *string, outputDir *string, reportCsv *string) erro
 		*outputDir += "/"
 	}
 
+	streaming := *output == OutputStdout
+	if streaming {
+		r.progress = os.Stderr
+	}
+
 	r.connectionString = *connStr
 	r.reportOutputDir = *outputDir
 	selectedReports := r.parseReportArgument(*reportCsv)
+	if *bundle != "" {
+		if streaming {
+			return fmt.Errorf("a bundle cannot be streamed to stdout")
+		}
+		if _, err := BundleFormat(*bundle); err != nil {
+			return err
+		}
+	}
+	var signingKey ed25519.PrivateKey
+	if *signKey != "" {
+		if *bundle == "" {
+			return fmt.Errorf("signing requires a bundle, set --bundle")
+		}
+		key, err := LoadSigningKey(*signKey)
+		if err != nil {
+			return err
+		}
+		signingKey = key
+	}
+	if streaming && len(selectedReports) != 1 {
+		return fmt.Errorf("streaming to stdout supports exactly one report type, %d selected", len(selectedReports))
+	}
 
+	startedAt := time.Now()
 	activeAdapter, err := r.adapterManager.Connect(r.connectionString)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Connected using adapter: %s\n", activeAdapter.UniqueSignature())
-	fmt.Println("Mapping database...")
+	fmt.Fprintf(r.progress, "Connected using adapter: %s\n", activeAdapter.UniqueSignature())
+	fmt.Fprintln(r.progress, "Mapping database...")
+	mappingStarted := time.Now()
 	db, errs := activeAdapter.MapDatabase()
+	mappingDuration := time.Since(mappingStarted)
 	if len(errs) > 0 {
 		for _, e := range errs {
 			// Log warnings but continue
@@ -58,27 +109,245 @@ func (r *Runner) Run(connStr *string, outputDir *string, reportCsv *string) erro
 		}
 	}
 
-	fmt.Printf("Mapped Database: %s\n", db.Name())
+	fmt.Fprintf(r.progress, "Mapped Database: %s\n", db.Name())
+	findings := rules.Evaluate(db, rules.DefaultRules())
+	fmt.Fprintf(r.progress, "Findings: %s\n", summarizeFindings(findings))
 	if len(selectedReports) == 0 {
-		fmt.Println("No report types specified, skipping report generation.")
-	} else {
-		err := os.MkdirAll(r.reportOutputDir, os.ModePerm)
-		if err != nil {
-			return err
+		fmt.Fprintln(r.progress, "No report types specified, skipping report generation.")
+		return nil
+	}
+
+	writers := sortedReportWriters(selectedReports)
+	for _, writer := range writers {
+		if receiver, ok := (*writer).(MappingStatsReceiver); ok {
+			receiver.SetMappingStats(mappingDuration, errs)
+		}
+		if receiver, ok := (*writer).(FindingsReceiver); ok {
+			receiver.SetFindings(findings)
+		}
+	}
+	nameValues := func(writer InventoryReportWriter) OutputNameValues {
+		return OutputNameValues{
+			Database:  db.Name(),
+			Report:    writer.GetReportName(),
+			Extension: writer.GetReportFileExtension(),
+			Adapter:   activeAdapter.UniqueSignature(),
+			Timestamp: startedAt,
+		}
+	}
+
+	if streaming {
+		writer := *writers[0]
+		fmt.Fprintf(r.progress, "Generating report: %s\n", writer.GetReportName())
+		return r.streamReport(writer, db, nameValues(writer))
+	}
+
+	if *bundle != "" {
+		template := *output
+		if template == "" {
+			template = DefaultOutputTemplate
 		}
+		manifest := NewManifest(activeAdapter.UniqueSignature(), db.Name(), startedAt)
+		return r.writeBundle(*bundle, template, writers, db, findings, nameValues, manifest, signingKey)
 	}
-	for writer := range selectedReports {
-		fmt.Printf("Generating report: %s\n", (*writer).GetReportName())
-		err := (*writer).WriteInventoryReport(strings.ReplaceAll(fmt.Sprintf("%s%s_%s.%s", r.reportOutputDir, db.Name(), (*writer).GetReportName(), (*writer).GetReportFileExtension()), " ", "_"), db)
+
+	template := *output
+	if template == "" {
+		template = r.reportOutputDir + DefaultOutputTemplate
+	}
+	paths, err := resolveOutputPaths(template, writers, nameValues)
+	if err != nil {
+		return err
+	}
+
+	for _, writer := range writers {
+		path := paths[writer]
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		fmt.Fprintf(r.progress, "Generating report: %s\n", (*writer).GetReportName())
+		err := (*writer).WriteInventoryReport(path, db)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error generating report %s: %v\n", (*writer).GetReportName(), err)
 		} else {
-			fmt.Printf("Report %s generated successfully.\n", (*writer).GetReportName())
+			fmt.Fprintf(r.progress, "Report %s generated successfully.\n", (*writer).GetReportName())
+		}
+	}
+	return nil
+}
+
+// summarizeFindings counts findings by severity, e.g. "3 (1 critical, 2 warning)"
+func summarizeFindings(findings []rules.Finding) string {
+	if len(findings) == 0 {
+		return "none"
+	}
+	counts := make(map[rules.Severity]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	var parts []string
+	for _, severity := range []rules.Severity{rules.SeverityCritical, rules.SeverityWarning, rules.SeverityInfo} {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	return fmt.Sprintf("%d (%s)", len(findings), strings.Join(parts, ", "))
+}
+
+// resolveOutputPaths expands the output template for every writer and rejects
+// templates that would write two reports to the same path
+func resolveOutputPaths(template string, writers []*InventoryReportWriter, nameValues func(InventoryReportWriter) OutputNameValues) (map[*InventoryReportWriter]string, error) {
+	paths := make(map[*InventoryReportWriter]string, len(writers))
+	written := make(map[string]string, len(writers))
+	for _, writer := range writers {
+		path := ExpandOutputTemplate(template, nameValues(*writer))
+		if other, exists := written[path]; exists {
+			return nil, fmt.Errorf("reports %s and %s would both be written to %s, add {report} or {ext} to the output template", other, (*writer).GetReportName(), path)
+		}
+		written[path] = (*writer).GetReportName()
+		paths[writer] = path
+	}
+	return paths, nil
+}
+
+// writeBundle writes the reports into a scratch directory, records every file each
+// writer produced in the manifest and packages them into a single archive. The findings
+// are added as BundleFindingsFileName, so that a signature covers them too. The output
+// template names the files inside the archive and must stay within it.
+func (r *Runner) writeBundle(bundlePath string, template string, writers []*InventoryReportWriter, db *dbo.Database, findings []rules.Finding, nameValues func(InventoryReportWriter) OutputNameValues, manifest *Manifest, key ed25519.PrivateKey) error {
+	paths, err := resolveOutputPaths(template, writers, nameValues)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if !filepath.IsLocal(path) {
+			return fmt.Errorf("bundled report path %s must be relative and stay inside the bundle", path)
+		}
+		if filepath.ToSlash(filepath.Clean(path)) == BundleFindingsFileName {
+			return fmt.Errorf("bundled report path %s is reserved for the findings", path)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "norman-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	for _, writer := range writers {
+		path := filepath.Join(dir, paths[writer])
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		before, err := listFiles(dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(r.progress, "Generating report: %s\n", (*writer).GetReportName())
+		if err := (*writer).WriteInventoryReport(path, db); err != nil {
+			fmt.Fprintf(os.Stderr, "error generating report %s: %v\n", (*writer).GetReportName(), err)
+			continue
+		}
+		after, err := listFiles(dir)
+		if err != nil {
+			return err
+		}
+		for _, file := range after {
+			if !slices.Contains(before, file) {
+				if err := manifest.AddFile(dir, file, (*writer).GetReportKeys()[0]); err != nil {
+					return err
+				}
+			}
 		}
+		fmt.Fprintf(r.progress, "Report %s generated successfully.\n", (*writer).GetReportName())
+	}
+
+	if findings == nil {
+		findings = []rules.Finding{}
+	}
+	findingsData, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, BundleFindingsFileName), findingsData, 0600); err != nil {
+		return err
+	}
+	if err := manifest.AddFile(dir, BundleFindingsFileName, "findings"); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(bundlePath), os.ModePerm); err != nil {
+		return err
+	}
+	if err := WriteBundle(bundlePath, dir, manifest, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.progress, "Bundle written to %s (%d files)\n", bundlePath, len(manifest.Files))
+	if key != nil {
+		fmt.Fprintf(r.progress, "Manifest signed with key %s\n", manifest.SignedBy)
 	}
 	return nil
 }
 
+// listFiles returns the paths of all regular files below dir, relative to dir and sorted
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	return files, err
+}
+
+// streamReport writes a report into a scratch directory and copies it to stdout.
+// Writers that spread a report over several files cannot be streamed.
+func (r *Runner) streamReport(writer InventoryReportWriter, db *dbo.Database, values OutputNameValues) error {
+	dir, err := os.MkdirTemp("", "norman-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ExpandOutputTemplate(DefaultOutputTemplate, values))
+	if err := writer.WriteInventoryReport(path, db); err != nil {
+		return fmt.Errorf("error generating report %s: %w", writer.GetReportName(), err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("report %s is written as several files and cannot be streamed to stdout", writer.GetReportName())
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(r.stdout, file); err != nil {
+		return err
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 1 {
+		fmt.Fprintf(os.Stderr, "warning: report %s wrote %d additional files that were not streamed\n", writer.GetReportName(), len(entries)-1)
+	}
+	return nil
+}
+
+// sortedReportWriters orders the selected writers by report name so that reports are
+// generated in a stable order
+func sortedReportWriters(selected map[*InventoryReportWriter]struct{}) []*InventoryReportWriter {
+	writers := make([]*InventoryReportWriter, 0, len(selected))
+	for writer := range selected {
+		writers = append(writers, writer)
+	}
+	sort.Slice(writers, func(i, j int) bool {
+		return (*writers[i]).GetReportName() < (*writers[j]).GetReportName()
+	})
+	return writers
+}
+
 func (r *Runner) parseReportArgument(reportArg string) map[*InventoryReportWriter]struct{} {
 	var selectedReports = map[*InventoryReportWriter]struct{}{}
 	for _, reportKey := range strings.Split(reportArg, ",") {
@@ -0,0 +1,341 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dbo "github.com/jimbot9k/norman/internal/core/dbobjects"
+	"github.com/jimbot9k/norman/internal/core/rules"
+)
+
+// fakeAdapter maps a fixed, empty database for any "fake://" connection string
+type fakeAdapter struct {
+	connected bool
+}
+
+func (a *fakeAdapter) Name() string            { return "Fake" }
+func (a *fakeAdapter) Version() string         { return "1" }
+func (a *fakeAdapter) UniqueSignature() string { return "Fake-1" }
+func (a *fakeAdapter) IsConnectionStringCompatible(connString string) bool {
+	return strings.HasPrefix(connString, "fake://")
+}
+func (a *fakeAdapter) Connect(connString string) error { a.connected = true; return nil }
+func (a *fakeAdapter) Close() error                    { a.connected = false; return nil }
+func (a *fakeAdapter) IsConnected() bool               { return a.connected }
+func (a *fakeAdapter) MapDatabase() (*dbo.Database, []error) {
+	return dbo.NewDatabase("shop", nil), nil
+}
+
+// fakeReportWriter writes its content to the given path, plus an extra sibling file
+// when sibling is set
+type fakeReportWriter struct {
+	key     string
+	name    string
+	content string
+	sibling bool
+}
+
+func (w *fakeReportWriter) GetReportKeys() []string        { return []string{w.key} }
+func (w *fakeReportWriter) GetReportFileExtension() string { return w.key }
+func (w *fakeReportWriter) GetReportName() string          { return w.name }
+func (w *fakeReportWriter) WriteInventoryReport(filePath string, db *dbo.Database) error {
+	if w.sibling {
+		if err := os.WriteFile(filePath+".extra", []byte("extra"), 0600); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filePath, []byte(w.content+" "+db.Name()), 0600)
+}
+
+// newTestRunner builds a runner over the fake adapter and the given writers
+func newTestRunner(writers ...InventoryReportWriter) (*Runner, *bytes.Buffer, *bytes.Buffer) {
+	runner := NewRunner([]Adapter{&fakeAdapter{}}, writers)
+	var progress, stdout bytes.Buffer
+	runner.progress = &progress
+	runner.stdout = &stdout
+	return runner, &progress, &stdout
+}
+
+func TestExpandOutputTemplate(t *testing.T) {
+	values := OutputNameValues{
+		Database:  "shop",
+		Report:    "Mermaid ERD",
+		Extension: "mmd",
+		Adapter:   "PostgreSQL-1.0",
+		Timestamp: time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600)),
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{DefaultOutputTemplate, "shop_Mermaid_ERD.mmd"},
+		{"out/{adapter}/{database}-{timestamp}.{ext}", "out/PostgreSQL-1.0/shop-20260304T040607Z.mmd"},
+		{"plain.txt", "plain.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if got := ExpandOutputTemplate(tt.template, values); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("values cannot add path segments", func(t *testing.T) {
+		got := ExpandOutputTemplate("{database}.{ext}", OutputNameValues{Database: "../etc/x", Extension: "a b"})
+		if got != ".._etc_x.a_b" {
+			t.Errorf("expected sanitized name, got %q", got)
+		}
+	})
+}
+
+func TestRunnerRun(t *testing.T) {
+	conn := "fake://db"
+
+	t.Run("writes reports to the output directory", func(t *testing.T) {
+		runner, progress, _ := newTestRunner(&fakeReportWriter{key: "a", name: "Report A", content: "A"})
+		outputDir := filepath.Join(t.TempDir(), "reports")
+		bundle := ""
+		signKey := ""
+		output, reports := "", "all"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(outputDir, "shop_Report_A.a"))
+		if err != nil {
+			t.Fatalf("expected default report name: %v", err)
+		}
+		if string(content) != "A shop" {
+			t.Errorf("unexpected content %q", content)
+		}
+		if !strings.Contains(progress.String(), "Report Report A generated successfully.") {
+			t.Errorf("expected progress output, got %q", progress.String())
+		}
+	})
+
+	t.Run("output template", func(t *testing.T) {
+		runner, _, _ := newTestRunner(
+			&fakeReportWriter{key: "a", name: "Report A", content: "A"},
+			&fakeReportWriter{key: "b", name: "Report B", content: "B"},
+		)
+		dir := t.TempDir()
+		outputDir := "ignored"
+		bundle := ""
+		signKey := ""
+		output, reports := filepath.Join(dir, "{adapter}", "{database}-{report}.{ext}"), "a,b"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, name := range []string{"shop-Report_A.a", "shop-Report_B.b"} {
+			if _, err := os.Stat(filepath.Join(dir, "Fake-1", name)); err != nil {
+				t.Errorf("expected %s: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("colliding output paths are rejected", func(t *testing.T) {
+		runner, _, _ := newTestRunner(
+			&fakeReportWriter{key: "a", name: "Report A"},
+			&fakeReportWriter{key: "b", name: "Report B"},
+		)
+		outputDir := ""
+		bundle := ""
+		signKey := ""
+		output, reports := filepath.Join(t.TempDir(), "{database}.out"), "all"
+
+		err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports)
+		if err == nil || !strings.Contains(err.Error(), "would both be written") {
+			t.Errorf("expected collision error, got %v", err)
+		}
+	})
+
+	t.Run("streams a single report to stdout", func(t *testing.T) {
+		runner, progress, stdout := newTestRunner(&fakeReportWriter{key: "a", name: "Report A", content: "A", sibling: true})
+		outputDir := t.TempDir()
+		bundle := ""
+		signKey := ""
+		output, reports := OutputStdout, "a"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if stdout.String() != "A shop" {
+			t.Errorf("expected report on stdout, got %q", stdout.String())
+		}
+		if progress.Len() != 0 {
+			t.Errorf("expected progress to move off the configured writer, got %q", progress.String())
+		}
+		if entries, _ := os.ReadDir(outputDir); len(entries) != 0 {
+			t.Errorf("expected no files in the output directory, got %d", len(entries))
+		}
+	})
+
+	t.Run("bundles reports with a manifest", func(t *testing.T) {
+		runner, _, _ := newTestRunner(
+			&fakeReportWriter{key: "a", name: "Report A", content: "A", sibling: true},
+			&fakeReportWriter{key: "b", name: "Report B", content: "B"},
+		)
+		outputDir := t.TempDir()
+		bundle := filepath.Join(t.TempDir(), "runs", "audit.zip")
+		signKey := ""
+		output, reports := "", "all"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if entries, _ := os.ReadDir(outputDir); len(entries) != 0 {
+			t.Errorf("expected no files in the output directory, got %d", len(entries))
+		}
+
+		_, contents := bundleContents(t, bundle)
+		var manifest Manifest
+		if err := json.Unmarshal(contents[ManifestFileName], &manifest); err != nil {
+			t.Fatalf("failed to unmarshal manifest: %v", err)
+		}
+		var files []string
+		for _, f := range manifest.Files {
+			files = append(files, f.Report+":"+f.Path)
+		}
+		expected := "a:shop_Report_A.a,a:shop_Report_A.a.extra,b:shop_Report_B.b,findings:findings.json"
+		if strings.Join(files, ",") != expected {
+			t.Errorf("expected files %s, got %s", expected, strings.Join(files, ","))
+		}
+		var findings []rules.Finding
+		if err := json.Unmarshal(contents[BundleFindingsFileName], &findings); err != nil || findings == nil {
+			t.Errorf("expected the findings as a JSON array, got %q (%v)", contents[BundleFindingsFileName], err)
+		}
+		if manifest.Adapter != "Fake-1" || manifest.Database != "shop" {
+			t.Errorf("unexpected manifest %+v", manifest)
+		}
+	})
+
+	t.Run("bundle paths stay inside the archive", func(t *testing.T) {
+		runner, _, _ := newTestRunner(&fakeReportWriter{key: "a", name: "Report A"})
+		outputDir := ""
+		bundle := filepath.Join(t.TempDir(), "audit.tar.gz")
+		signKey := ""
+		output, reports := "../{report}.{ext}", "a"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err == nil {
+			t.Error("expected error for a path outside the bundle")
+		}
+	})
+
+	t.Run("bundle paths leave room for the findings", func(t *testing.T) {
+		runner, _, _ := newTestRunner(&fakeReportWriter{key: "a", name: "Report A"})
+		outputDir := ""
+		bundle := filepath.Join(t.TempDir(), "audit.tar.gz")
+		signKey := ""
+		output, reports := "findings.json", "a"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err == nil {
+			t.Error("expected error for a report written over the findings")
+		}
+	})
+
+	t.Run("signed bundle verifies", func(t *testing.T) {
+		runner, _, _ := newTestRunner(&fakeReportWriter{key: "a", name: "Report A", content: "A"})
+		signKey, publicKey, _ := writeTestKeys(t)
+		bundle := filepath.Join(t.TempDir(), "audit.tar.gz")
+		outputDir, output, reports := "", "", "a"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		key, err := LoadVerificationKey(publicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := VerifyBundle(bundle, key); err != nil {
+			t.Errorf("expected bundle to verify, got %v", err)
+		}
+	})
+
+	t.Run("signing requires a bundle", func(t *testing.T) {
+		runner, _, _ := newTestRunner(&fakeReportWriter{key: "a", name: "Report A"})
+		signKey, _, _ := writeTestKeys(t)
+		outputDir, output, bundle, reports := t.TempDir(), "", "", "a"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err == nil {
+			t.Error("expected error when signing without a bundle")
+		}
+	})
+
+	t.Run("unknown bundle format", func(t *testing.T) {
+		runner, _, _ := newTestRunner(&fakeReportWriter{key: "a", name: "Report A"})
+		outputDir, output, signKey, reports := "", "", "", "a"
+		bundle := "audit.rar"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err == nil {
+			t.Error("expected error for an unknown bundle format")
+		}
+	})
+
+	t.Run("streaming requires exactly one report", func(t *testing.T) {
+		runner, _, _ := newTestRunner(
+			&fakeReportWriter{key: "a", name: "Report A"},
+			&fakeReportWriter{key: "b", name: "Report B"},
+		)
+		outputDir := ""
+		bundle := ""
+		signKey := ""
+		output, reports := OutputStdout, "all"
+
+		if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err == nil {
+			t.Error("expected error when streaming several reports")
+		}
+	})
+}
+
+// statsReportWriter records the mapping stats it receives
+type statsReportWriter struct {
+	fakeReportWriter
+	received bool
+	errs     []error
+}
+
+func (w *statsReportWriter) SetMappingStats(duration time.Duration, errs []error) {
+	w.received = true
+	w.errs = errs
+}
+
+func TestRunnerPassesMappingStats(t *testing.T) {
+	writer := &statsReportWriter{fakeReportWriter: fakeReportWriter{key: "a", name: "Report A"}}
+	runner, _, _ := newTestRunner(writer)
+	conn := "fake://db"
+	outputDir := t.TempDir()
+	output, bundle, signKey, reports := "", "", "", "a"
+
+	if err := runner.Run(&conn, &outputDir, &output, &bundle, &signKey, &reports); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !writer.received {
+		t.Error("expected the writer to receive the mapping stats")
+	}
+	if len(writer.errs) != 0 {
+		t.Errorf("expected no mapping errors, got %v", writer.errs)
+	}
+}
+
+func TestSummarizeFindings(t *testing.T) {
+	findings := []rules.Finding{
+		{Severity: rules.SeverityWarning},
+		{Severity: rules.SeverityCritical},
+		{Severity: rules.SeverityWarning},
+	}
+	if got := summarizeFindings(findings); got != "3 (1 critical, 2 warning)" {
+		t.Errorf("summarizeFindings() = %q", got)
+	}
+	if got := summarizeFindings(nil); got != "none" {
+		t.Errorf("summarizeFindings(nil) = %q, want none", got)
+	}
+}
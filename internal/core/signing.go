@@ -0,0 +1,149 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ManifestSignatureFileName is the name of the detached manifest signature in a signed bundle
+const ManifestSignatureFileName = "manifest.sig"
+
+// LoadSigningKey reads an Ed25519 private key from a PKCS #8 PEM file, as written by
+// "openssl genpkey -algorithm ed25519"
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s holds a %s, expected an Ed25519 PRIVATE KEY", path, block.Type)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+// LoadVerificationKey reads an Ed25519 public key from a PKIX PEM file. A private key
+// file is accepted too, in which case its public half is used.
+func LoadVerificationKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "PRIVATE KEY" {
+		key, err := LoadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return key.Public().(ed25519.PublicKey), nil
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("%s holds a %s, expected an Ed25519 PUBLIC KEY", path, block.Type)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse verification key: %w", err)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+// KeyFingerprint returns the hex SHA-256 digest of a public key, used to identify the
+// key that signed a bundle
+func KeyFingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignManifest returns the base64-encoded Ed25519 signature of the exact manifest bytes
+func SignManifest(key ed25519.PrivateKey, manifestData []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, manifestData)) + "\n")
+}
+
+// VerifyBundle checks the manifest signature of a bundle against key and every listed
+// file against its checksum. Files missing from the manifest or the archive, or present
+// more than once, are errors.
+func VerifyBundle(path string, key ed25519.PublicKey) (*Manifest, error) {
+	entries, err := readBundle(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	contents := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		// A second member of the same name would be checked in place of the first,
+		// while archive tools extract either one
+		if _, ok := contents[entry.name]; ok {
+			return nil, fmt.Errorf("%s appears more than once in the bundle", entry.name)
+		}
+		contents[entry.name] = entry.data
+	}
+
+	manifestData, ok := contents[ManifestFileName]
+	if !ok {
+		return nil, fmt.Errorf("bundle has no %s", ManifestFileName)
+	}
+	signature, ok := contents[ManifestSignatureFileName]
+	if !ok {
+		return nil, fmt.Errorf("bundle is not signed, %s is missing", ManifestSignatureFileName)
+	}
+	rawSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	if !ed25519.Verify(key, manifestData, rawSignature) {
+		return nil, fmt.Errorf("manifest signature does not match key %s", KeyFingerprint(key))
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	listed := map[string]bool{ManifestFileName: true, ManifestSignatureFileName: true}
+	for _, f := range manifest.Files {
+		listed[f.Path] = true
+		data, ok := contents[f.Path]
+		if !ok {
+			return nil, fmt.Errorf("%s is listed in the manifest but missing from the bundle", f.Path)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != f.SHA256 || int64(len(data)) != f.Size {
+			return nil, fmt.Errorf("%s does not match its manifest checksum", f.Path)
+		}
+	}
+	for _, entry := range entries {
+		if !listed[entry.name] {
+			return nil, fmt.Errorf("%s is not listed in the manifest", entry.name)
+		}
+	}
+	return &manifest, nil
+}
+
+// readPEMFile reads the first PEM block of a file
+func readPEMFile(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	return block, nil
+}
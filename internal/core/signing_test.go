@@ -0,0 +1,317 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTestKeys generates an Ed25519 key pair and writes it as PEM files
+func writeTestKeys(t *testing.T) (string, string, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privatePath, publicPath := filepath.Join(dir, "signing.pem"), filepath.Join(dir, "signing.pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath, private
+}
+
+// writeSignedTestBundle writes a signed bundle holding a single report file, in the
+// format the bundle name gives
+func writeSignedTestBundle(t *testing.T, key ed25519.PrivateKey, name string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shop.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	manifest := NewManifest("PostgreSQL-1.0", "shop", time.Now())
+	if err := manifest.AddFile(dir, "shop.json", "json"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := WriteBundle(path, dir, manifest, key); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return path
+}
+
+// rewriteTestBundle copies a zip bundle, letting edit change or drop members and
+// append extra ones
+func rewriteTestBundle(t *testing.T, path string, edit func(name string, data []byte) []byte, extra map[string]string) string {
+	t.Helper()
+	entries, err := readBundle(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "tampered.zip")
+	file, err := os.Create(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	archive := zip.NewWriter(file)
+	write := func(name string, data []byte) {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, entry := range entries {
+		if data := edit(entry.name, entry.data); data != nil {
+			write(entry.name, data)
+		}
+	}
+	for name, data := range extra {
+		write(name, []byte(data))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestLoadKeys(t *testing.T) {
+	privatePath, publicPath, private := writeTestKeys(t)
+
+	signing, err := LoadSigningKey(privatePath)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !signing.Equal(private) {
+		t.Error("expected the generated private key")
+	}
+
+	for _, path := range []string{publicPath, privatePath} {
+		verification, err := LoadVerificationKey(path)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", path, err)
+		}
+		if !verification.Equal(private.Public()) {
+			t.Errorf("%s: expected the generated public key", path)
+		}
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		notPEM := filepath.Join(t.TempDir(), "key.txt")
+		if err := os.WriteFile(notPEM, []byte("not a key"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSigningKey(publicPath); err == nil {
+			t.Error("expected error signing with a public key")
+		}
+		if _, err := LoadSigningKey(notPEM); err == nil {
+			t.Error("expected error for a non-PEM file")
+		}
+		if _, err := LoadVerificationKey(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+			t.Error("expected error for a missing file")
+		}
+	})
+}
+
+func TestVerifyBundle(t *testing.T) {
+	_, _, key := writeTestKeys(t)
+	public := key.Public().(ed25519.PublicKey)
+	path := writeSignedTestBundle(t, key, "audit.zip")
+
+	manifest, err := VerifyBundle(path, public)
+	if err != nil {
+		t.Fatalf("expected signed bundle to verify, got %v", err)
+	}
+	if manifest.SignedBy != KeyFingerprint(public) || len(manifest.Files) != 1 {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+
+	unchanged := func(name string, data []byte) []byte { return data }
+	tests := []struct {
+		name     string
+		bundle   string
+		key      ed25519.PublicKey
+		expected string
+	}{
+		{
+			name:     "other key",
+			bundle:   path,
+			key:      newTestPublicKey(t),
+			expected: "signature does not match",
+		},
+		{
+			name: "edited report",
+			bundle: rewriteTestBundle(t, path, func(name string, data []byte) []byte {
+				if name == "shop.json" {
+					return []byte("{\"edited\":true}")
+				}
+				return data
+			}, nil),
+			key:      public,
+			expected: "does not match its manifest checksum",
+		},
+		{
+			name: "edited manifest",
+			bundle: rewriteTestBundle(t, path, func(name string, data []byte) []byte {
+				if name == ManifestFileName {
+					return []byte(strings.Replace(string(data), "shop", "shop2", 1))
+				}
+				return data
+			}, nil),
+			key:      public,
+			expected: "signature does not match",
+		},
+		{
+			name:     "added file",
+			bundle:   rewriteTestBundle(t, path, unchanged, map[string]string{"extra.txt": "x"}),
+			key:      public,
+			expected: "not listed in the manifest",
+		},
+		{
+			name:     "duplicate file",
+			bundle:   rewriteTestBundle(t, path, unchanged, map[string]string{"shop.json": "{\"edited\":true}"}),
+			key:      public,
+			expected: "appears more than once",
+		},
+		{
+			name: "removed file",
+			bundle: rewriteTestBundle(t, path, func(name string, data []byte) []byte {
+				if name == "shop.json" {
+					return nil
+				}
+				return data
+			}, nil),
+			key:      public,
+			expected: "missing from the bundle",
+		},
+		{
+			name: "unsigned",
+			bundle: rewriteTestBundle(t, path, func(name string, data []byte) []byte {
+				if name == ManifestSignatureFileName {
+					return nil
+				}
+				return data
+			}, nil),
+			key:      public,
+			expected: "not signed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyBundle(tt.bundle, tt.key)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// addTestSymlink copies a bundle and appends a symbolic link to it
+func addTestSymlink(t *testing.T, path, name, target string) string {
+	t.Helper()
+	entries, err := readBundle(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), filepath.Base(path))
+	file, err := os.Create(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(path, ".zip") {
+		archive := zip.NewWriter(file)
+		for _, entry := range entries {
+			w, err := archive.Create(entry.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write(entry.data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		header := &zip.FileHeader{Name: name}
+		header.SetMode(os.ModeSymlink | 0777)
+		w, err := archive.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(target)); err != nil {
+			t.Fatal(err)
+		}
+		if err := archive.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	compressed := gzip.NewWriter(file)
+	archive := tar.NewWriter(compressed)
+	for _, entry := range entries {
+		if err := archive.WriteHeader(&tar.Header{Name: entry.name, Mode: 0600, Size: int64(len(entry.data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write(entry.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.WriteHeader(&tar.Header{Name: name, Linkname: target, Mode: 0777, Typeflag: tar.TypeSymlink}); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestVerifyBundleRejectsLinks(t *testing.T) {
+	_, _, key := writeTestKeys(t)
+	public := key.Public().(ed25519.PublicKey)
+	for _, name := range []string{"audit.zip", "audit.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := addTestSymlink(t, writeSignedTestBundle(t, key, name), "latest.json", "shop.json")
+			_, err := VerifyBundle(path, public)
+			if err == nil || !strings.Contains(err.Error(), "latest.json is not a regular file") {
+				t.Errorf("expected the link to be rejected, got %v", err)
+			}
+		})
+	}
+}
+
+// newTestPublicKey returns the public half of a freshly generated key
+func newTestPublicKey(t *testing.T) ed25519.PublicKey {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return public
+}
@@ -4,9 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/jimbot9k/norman/internal/adapters/database/jsonreport"
 	"github.com/jimbot9k/norman/internal/adapters/database/mysql"
 	"github.com/jimbot9k/norman/internal/adapters/database/postgres"
+	"github.com/jimbot9k/norman/internal/adapters/database/sqldump"
+	"github.com/jimbot9k/norman/internal/adapters/database/sqlite"
 	"github.com/jimbot9k/norman/internal/adapters/reports"
 	"github.com/jimbot9k/norman/internal/core"
 	"github.com/jimbot9k/norman/internal/version"
@@ -17,25 +23,100 @@ func main() {
 	adapters := []core.Adapter{
 		&postgres.PostgresAdapter{},
 		&mysql.MySqlAdapter{},
+		&sqlite.SQLiteAdapter{},
+		&sqldump.SQLDumpAdapter{},
+		&sqldump.MigrationsAdapter{},
+		&jsonreport.JSONReportAdapter{},
 	}
-	reports := []core.InventoryReportWriter{
+	mermaidWriter := &reports.MermaidReportWriter{}
+	plantUMLWriter := &reports.PlantUMLReportWriter{}
+	templateWriter := &reports.TemplateReportWriter{}
+	codegenWriter := &reports.CodegenReportWriter{}
+	reportWriters := []core.InventoryReportWriter{
 		&reports.JSONReportWriter{},
-		&reports.MermaidReportWriter{},
+		mermaidWriter,
+		plantUMLWriter,
+		&reports.DBMLReportWriter{},
+		&reports.SQLReportWriter{},
+		&reports.CSVReportWriter{},
+		&reports.SQLiteReportWriter{},
+		&reports.DependencyReportWriter{},
+		&reports.APISchemaReportWriter{},
+		codegenWriter,
+		&reports.PrometheusReportWriter{},
+		templateWriter,
 	}
 
 	var showVersion = flag.Bool("version", false, "print version information and exit")
+	var showJSONSchema = flag.Bool("json-schema", false, "print the JSON Schema of the json report and exit")
 	var outputDir = flag.String("output-dir", "./norman/", "Directory to output reports to")
-	var connStr = flag.String("conn", "", "Database connection string " + driverOptionHelperString(adapters) + " (required)")
-	var reportCsv = flag.String("report-types", "all", "Comma-separated list of report types to generate " + reportOptionHelperString(reports))
+	var output = flag.String("output", "", "Report path template with {database}, {report}, {ext}, {adapter} and {timestamp} placeholders, or - to stream a single report to stdout (overrides --output-dir)")
+	var bundle = flag.String("bundle", "", "Package the reports with a manifest of SHA-256 checksums into a single .tar.gz, .tgz or .zip archive")
+	var signKey = flag.String("sign-key", "", "Ed25519 private key (PEM) that signs the --bundle manifest; with --verify, the public or private key to check against")
+	var verify = flag.String("verify", "", "Verify the signature and checksums of a signed bundle and exit")
+	var connStr = flag.String("conn", "", "Database connection string "+driverOptionHelperString(adapters)+" (required)")
+	var reportCsv = flag.String("report-types", "all", "Comma-separated list of report types to generate "+reportOptionHelperString(reportWriters))
+	var perSchema = flag.Bool("per-schema", false, "write one diagram per schema for the mermaid and plantuml reports")
+	var focus = flag.String("focus", "", "limit the mermaid and plantuml diagrams to the neighbourhood of a table (schema.table)")
+	var depth = flag.Int("depth", 1, "number of foreign key hops around the --focus table to include")
+	var templatePath = flag.String("template", "", "Go text/template or html/template file rendered by the template report")
+	var goPackage = flag.String("go-package", "models", "package name of the Go file written by the codegen report")
+	var goNullable = flag.String("go-nullable", reports.GoNullableSQL, "Go type of nullable columns in the codegen report: sql (sql.Null*) or pointer")
 	flag.Parse()
 
+	diagramOptions := reports.DiagramOptions{PerSchema: *perSchema, Focus: *focus, Depth: *depth}
+	mermaidWriter.Options = diagramOptions
+	plantUMLWriter.Options = diagramOptions
+	codegenWriter.GoPackage = *goPackage
+	codegenWriter.GoNullable = *goNullable
+
+	// The template report only runs when a template is given, so that "all" keeps working without one
+	templateWriter.TemplatePath = *templatePath
+	if *templatePath == "" {
+		for _, key := range strings.Split(*reportCsv, ",") {
+			if strings.TrimSpace(key) == "template" {
+				fmt.Fprintln(os.Stderr, "Error: the template report requires --template")
+				os.Exit(1)
+			}
+		}
+		reportWriters = slices.DeleteFunc(reportWriters, func(w core.InventoryReportWriter) bool {
+			return w == core.InventoryReportWriter(templateWriter)
+		})
+	}
+
 	if *showVersion {
 		fmt.Printf("norman %s\ncommit: %s\nbuilt:  %s\n", version.Version, version.Commit, version.Date)
 		return
 	}
 
-	runner := core.NewRunner(adapters, reports)
-	err := runner.Run(connStr, outputDir, reportCsv)
+	if *showJSONSchema {
+		fmt.Print(string(reports.JSONReportSchema()))
+		return
+	}
+
+	if *verify != "" {
+		if *signKey == "" {
+			fmt.Fprintln(os.Stderr, "Error: --verify requires --sign-key")
+			os.Exit(1)
+		}
+		key, err := core.LoadVerificationKey(*signKey)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		manifest, err := core.VerifyBundle(*verify, key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Verification failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Verified %s: %d files from %s (%s) generated %s by norman %s, signed by %s\n",
+			*verify, len(manifest.Files), manifest.Database, manifest.Adapter,
+			manifest.GeneratedAt.Format(time.RFC3339), manifest.Generator.Version, manifest.SignedBy)
+		return
+	}
+
+	runner := core.NewRunner(adapters, reportWriters)
+	err := runner.Run(connStr, outputDir, output, bundle, signKey, reportCsv)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -55,7 +136,6 @@ func driverOptionHelperString(adapters []core.Adapter) string {
 	return driverOptionsString
 }
 
-
 func reportOptionHelperString(reports []core.InventoryReportWriter) string {
 	var reportOptionsString = "("
 	for i, r := range reports {
@@ -67,13 +147,11 @@ func reportOptionHelperString(reports []core.InventoryReportWriter) string {
 			reportOptionsString += key
 		}
 
-		if i == len(reports) - 1 {
+		if i == len(reports)-1 {
 			reportOptionsString += ","
 		}
 	}
 
-
-
 	reportOptionsString += " all)"
 	return reportOptionsString
 }